@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -142,6 +143,12 @@ func alterChangefeedPlanHook(
 		if err != nil {
 			return err
 		}
+		switchingToAvro := isSwitchingToAvro(prevDetails.Opts, newOptions.AsMap())
+		if switchingToAvro {
+			if err := validateSwitchToAvro(newOptions); err != nil {
+				return err
+			}
+		}
 
 		st, err := newOptions.GetInitialScanType()
 		if err != nil {
@@ -151,19 +158,27 @@ func alterChangefeedPlanHook(
 			return err
 		}
 
+		newSelect, err := getAddedSelect(alterChangefeedStmt.Cmds)
+		if err != nil {
+			return err
+		}
+
 		newTargets, newProgress, newStatementTime, originalSpecs, err := generateAndValidateNewTargets(
 			ctx, exprEval, p,
 			alterChangefeedStmt.Cmds,
 			newOptions.AsMap(), // TODO: Remove .AsMap()
 			prevDetails, job.Progress(),
 			newSinkURI,
+			newSelect != nil,
 		)
 		if err != nil {
 			return err
 		}
 		newChangefeedStmt.Targets = newTargets
 
-		if prevDetails.Select != "" {
+		if newSelect != nil {
+			newChangefeedStmt.Select = newSelect
+		} else if prevDetails.Select != "" {
 			query, err := cdceval.ParseChangefeedExpression(prevDetails.Select)
 			if err != nil {
 				return err
@@ -215,6 +230,25 @@ func alterChangefeedPlanHook(
 		newDetails := jobRecord.Details.(jobspb.ChangefeedDetails)
 		newDetails.Opts[changefeedbase.OptInitialScan] = ``
 
+		// Changes up to resolveTime were emitted with one message per row, and
+		// changes after it are emitted with one message per column family, to
+		// the topic of that family. Let the user know where the boundary lies
+		// so that consumers can switch over to the new topics.
+		if isSplittingColumnFamilies(prevDetails.Opts, newDetails.Opts) {
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				`changefeed will emit changes after %s to a topic per column family`,
+				resolveTime.AsOfSystemTime()))
+		}
+
+		// Similarly, changes after resolveTime are encoded as avro, and the
+		// schemas are registered with the schema registry once the changefeed
+		// is resumed.
+		if switchingToAvro {
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				`changefeed will emit changes after %s in %s format`,
+				resolveTime.AsOfSystemTime(), changefeedbase.OptFormatAvro))
+		}
+
 		// newStatementTime will either be the StatementTime of the job prior to the
 		// alteration, or it will be the high watermark of the job.
 		newDetails.StatementTime = newStatementTime
@@ -291,6 +325,22 @@ func getTargetDesc(
 	return desc, found, nil
 }
 
+// getAddedSelect returns the changefeed expression added by an
+// ADD (SELECT ...) command, if any.
+func getAddedSelect(alterCmds tree.AlterChangefeedCmds) (*tree.SelectClause, error) {
+	var sc *tree.SelectClause
+	for _, cmd := range alterCmds {
+		if v, ok := cmd.(*tree.AlterChangefeedAddTarget); ok && v.Select != nil {
+			if sc != nil {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					`cannot add more than one changefeed expression`)
+			}
+			sc = v.Select
+		}
+	}
+	return sc, nil
+}
+
 func generateNewOpts(
 	ctx context.Context,
 	exprEval exprutil.Evaluator,
@@ -364,6 +414,82 @@ func generateNewOpts(
 	return changefeedbase.MakeStatementOptions(newOptions), sinkURI, nil
 }
 
+// isSplittingColumnFamilies returns true if the changefeed is being altered to
+// set split_column_families.
+func isSplittingColumnFamilies(prevOpts, newOpts map[string]string) bool {
+	_, wasSplit := prevOpts[changefeedbase.OptSplitColumnFamilies]
+	_, isSplit := newOpts[changefeedbase.OptSplitColumnFamilies]
+	return isSplit && !wasSplit
+}
+
+// isSwitchingToAvro returns true if the changefeed is being altered to emit
+// avro rather than some other format.
+func isSwitchingToAvro(prevOpts, newOpts map[string]string) bool {
+	isAvro := func(opts map[string]string) bool {
+		format := opts[changefeedbase.OptFormat]
+		return format == string(changefeedbase.OptFormatAvro) ||
+			format == changefeedbase.DeprecatedOptFormatAvro
+	}
+	return isAvro(newOpts) && !isAvro(prevOpts)
+}
+
+// validateSwitchToAvro checks that the options of a changefeed which is being
+// altered to emit avro are compatible with avro. The schema registry must be
+// configured, either previously or in the same statement, and options which
+// only apply to other formats must be unset.
+func validateSwitchToAvro(opts changefeedbase.StatementOptions) error {
+	if !opts.IsSet(changefeedbase.OptConfluentSchemaRegistry) {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			`cannot alter %s to %s without %s`,
+			changefeedbase.OptFormat, changefeedbase.OptFormatAvro,
+			changefeedbase.OptConfluentSchemaRegistry)
+	}
+	for _, opt := range []string{
+		changefeedbase.OptKeyInValue,
+		changefeedbase.OptTopicInValue,
+		changefeedbase.OptIncludeSource,
+		changefeedbase.OptSQLTableName,
+	} {
+		if opts.IsSet(opt) {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				`cannot alter %s to %s while %s is set`,
+				changefeedbase.OptFormat, changefeedbase.OptFormatAvro, opt)
+		}
+	}
+	return nil
+}
+
+// splitTargetColumnFamilies updates the existing targets of a changefeed which
+// is being altered to set split_column_families, so that targets which watched
+// the primary family of a table with multiple column families instead watch
+// each of its families, emitting to a topic per family. At least one of the
+// targeted tables must have multiple column families.
+func splitTargetColumnFamilies(
+	originalSpecs map[tree.ChangefeedTarget]jobspb.ChangefeedTargetSpecification,
+	tableDescs map[descpb.ID]catalog.Descriptor,
+) error {
+	hasMultipleFamilies := false
+	for target, spec := range originalSpecs {
+		desc, ok := tableDescs[spec.TableID].(catalog.TableDescriptor)
+		if !ok || desc.NumFamilies() < 2 {
+			continue
+		}
+		hasMultipleFamilies = true
+		if spec.Type == jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY {
+			spec.Type = jobspb.ChangefeedTargetSpecification_EACH_FAMILY
+			originalSpecs[target] = spec
+		}
+	}
+	if !hasMultipleFamilies {
+		return pgerror.Newf(
+			pgcode.InvalidParameterValue,
+			`cannot set %s: none of the tables watched by the changefeed have multiple column families`,
+			changefeedbase.OptSplitColumnFamilies,
+		)
+	}
+	return nil
+}
+
 func generateAndValidateNewTargets(
 	ctx context.Context,
 	exprEval exprutil.Evaluator,
@@ -373,6 +499,7 @@ func generateAndValidateNewTargets(
 	prevDetails jobspb.ChangefeedDetails,
 	prevProgress jobspb.Progress,
 	sinkURI string,
+	addsSelect bool,
 ) (
 	tree.ChangefeedTargets,
 	*jobspb.Progress,
@@ -482,8 +609,16 @@ func generateAndValidateNewTargets(
 		return nil, nil, hlc.Timestamp{}, nil, err
 	}
 
+	if isSplittingColumnFamilies(prevDetails.Opts, opts) {
+		if err := splitTargetColumnFamilies(originalSpecs, newTableDescs); err != nil {
+			return nil, nil, hlc.Timestamp{}, nil, err
+		}
+	}
+
+	// The targets of a CDC query changefeed may only be modified along with its
+	// expression, which must then select from the only remaining target.
 	checkIfCommandAllowed := func() error {
-		if prevDetails.Select == "" {
+		if prevDetails.Select == "" || addsSelect {
 			return nil
 		}
 		return errors.WithIssueLink(
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net/url"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -277,6 +278,105 @@ func TestAlterChangefeedAddTargetFamily(t *testing.T) {
 	cdcTest(t, testFn, feedTestForceSink("kafka"), feedTestNoExternalConnection)
 }
 
+func TestAlterChangefeedAddTargetFamilies(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c STRING, FAMILY onlya (a), FAMILY onlyb (b), FAMILY onlyc (c))`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo FAMILY onlya`)
+		defer closeFeed(t, testFeed)
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES(1, 'hello', 'hola')`)
+		assertPayloads(t, testFeed, []string{
+			`foo.onlya: [1]->{"after": {"a": 1}}`,
+		})
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+
+		_ = telemetry.GetFeatureCounts(telemetry.Raw, telemetry.ResetCounts)
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d ADD foo FAMILY (onlyb, onlyc)`, feed.JobID()))
+		counts := telemetry.GetFeatureCounts(telemetry.Raw, telemetry.ResetCounts)
+		require.Equal(t, int32(1), counts[`changefeed.alter.added_targets.2`])
+
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES(2, 'goodbye', 'adios')`)
+		assertPayloads(t, testFeed, []string{
+			`foo.onlyb: [1]->{"after": {"b": "hello"}}`,
+			`foo.onlyc: [1]->{"after": {"c": "hola"}}`,
+			`foo.onlya: [2]->{"after": {"a": 2}}`,
+			`foo.onlyb: [2]->{"after": {"b": "goodbye"}}`,
+			`foo.onlyc: [2]->{"after": {"c": "adios"}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"), feedTestNoExternalConnection)
+}
+
+func TestAlterChangefeedAddTargetSelect(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, b STRING, c INT)`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo WITH envelope='wrapped'`)
+		defer closeFeed(t, testFeed)
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+
+		t.Run("errors", func(t *testing.T) {
+			sqlDB.ExpectErr(t, "CDC expressions require single table",
+				fmt.Sprintf(`ALTER CHANGEFEED %d ADD (SELECT a, b FROM bar)`, feed.JobID()))
+			sqlDB.ExpectErr(t, "cannot add more than one changefeed expression",
+				fmt.Sprintf(`ALTER CHANGEFEED %d DROP foo ADD (SELECT a FROM bar) ADD (SELECT b FROM bar)`,
+					feed.JobID()))
+		})
+
+		// Replace the target of the changefeed by a projection of another table.
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d DROP foo ADD (SELECT a, b FROM bar WHERE a > 0)`,
+			feed.JobID()))
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (-1, 'skipped', 1), (1, 'emitted', 2)`)
+		assertPayloads(t, testFeed, []string{
+			`bar: [1]->{"after": {"a": 1, "b": "emitted"}}`,
+		})
+
+		// Adding an expression on the same table replaces its filter.
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d ADD (SELECT a, c FROM bar WHERE a > 1)`,
+			feed.JobID()))
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		sqlDB.Exec(t, `UPDATE bar SET c = 3 WHERE a = 1`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (2, 'emitted', 4)`)
+		assertPayloads(t, testFeed, []string{
+			`bar: [2]->{"after": {"a": 2, "c": 4}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks, feedTestNoExternalConnection)
+}
+
 func TestAlterChangefeedSwitchFamily(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -448,6 +548,104 @@ func TestAlterChangefeedSetDiffOption(t *testing.T) {
 	cdcTest(t, testFn, feedTestEnterpriseSinks, feedTestNoExternalConnection)
 }
 
+func TestAlterChangefeedSetTopicOverride(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (b INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1)`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo, bar`)
+		defer closeFeed(t, testFeed)
+		assertPayloads(t, testFeed, []string{
+			`foo: [0]->{"after": {"a": 0}}`,
+			`bar: [1]->{"after": {"b": 1}}`,
+		})
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+
+		sqlDB.ExpectErr(t, `option topic_override references table baz, which is not watched by the changefeed`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d SET topic_override='foo:events,baz:events'`, feed.JobID()))
+		sqlDB.ExpectErr(t, `maps table foo to illegal topic name "a/b"`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d SET topic_override='foo:a/b'`, feed.JobID()))
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d SET topic_override='foo:events,bar:events'`, feed.JobID()))
+
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (3)`)
+		assertPayloads(t, testFeed, []string{
+			`events: [2]->{"after": {"a": 2}}`,
+			`events: [3]->{"after": {"b": 3}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("cloudstorage"), feedTestNoExternalConnection)
+}
+
+func TestAlterChangefeedSetPartitionFormat(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, region STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'east')`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo WITH format=parquet, partition_format='hourly'`)
+		defer closeFeed(t, testFeed)
+		assertPayloads(t, testFeed, []string{
+			`foo: [0]->{"after": {"a": 0, "region": "east"}}`,
+		})
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+
+		sqlDB.ExpectErr(t, `option partition_format references column city, which does not exist in table foo`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d SET partition_format='column=city'`, feed.JobID()))
+		sqlDB.ExpectErr(t, `invalid partition_format of weekly`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d SET partition_format='weekly'`, feed.JobID()))
+		sqlDB.ExpectErr(t, `partition_format=column=region is only usable with format=parquet`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d SET partition_format='column=region', format=json`, feed.JobID()))
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d SET partition_format='column=region'`, feed.JobID()))
+
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'east'), (2, 'west')`)
+		assertPayloads(t, testFeed, []string{
+			`foo: [1]->{"after": {"a": 1, "region": "east"}}`,
+			`foo: [2]->{"after": {"a": 2, "region": "west"}}`,
+		})
+
+		// The files written since the changefeed resumed are partitioned by
+		// region.
+		cf := testFeed.(*cloudFeed)
+		dirs := make(map[string]struct{})
+		for file := range cf.seenFiles {
+			dir, err := filepath.Rel(cf.dir, filepath.Dir(file))
+			require.NoError(t, err)
+			dirs[dir] = struct{}{}
+		}
+		require.Contains(t, dirs, `region=east`)
+		require.Contains(t, dirs, `region=west`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("cloudstorage"), feedTestNoExternalConnection)
+}
+
 func TestAlterChangefeedRespectsCDCQuery(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -522,6 +720,59 @@ func TestAlterChangefeedUnsetDiffOption(t *testing.T) {
 	cdcTest(t, testFn, feedTestForceSink("kafka"), feedTestNoExternalConnection)
 }
 
+func TestAlterChangefeedSetUnsetUpdatedOption(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo WITH resolved='10ms', min_checkpoint_frequency='10ms'`)
+		defer closeFeed(t, testFeed)
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+
+		alterAndResume := func(alterStmt string) {
+			sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+			waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+			sqlDB.Exec(t, fmt.Sprintf(alterStmt, feed.JobID()))
+			sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+			waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+		}
+
+		alterAndResume(`ALTER CHANGEFEED %d SET updated`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+		msgs, err := readNextMessages(context.Background(), testFeed, 1)
+		require.NoError(t, err)
+		updated, err := extractUpdatedFromValue(msgs[0].Value)
+		require.NoError(t, err)
+		require.Greater(t, updated, float64(0))
+
+		// Wait for the high-water to pass the insert, so that it is not
+		// re-emitted after the feed is resumed below.
+		var tsStr string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&tsStr)
+		ts := parseTimeToHLC(t, tsStr)
+		testutils.SucceedsSoon(t, func() error {
+			if hw := loadProgress(t, feed, registry).GetHighWater(); hw == nil || hw.Less(ts) {
+				return errors.Newf("waiting for high-water to reach %s", ts)
+			}
+			return nil
+		})
+
+		alterAndResume(`ALTER CHANGEFEED %d UNSET updated`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'second')`)
+		assertPayloads(t, testFeed, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "second"}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"), feedTestNoExternalConnection)
+}
+
 func TestAlterChangefeedErrors(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -1101,6 +1352,116 @@ func TestAlterChangefeedColumnFamilyDatabaseScope(t *testing.T) {
 	cdcTest(t, testFn, feedTestEnterpriseSinks, feedTestNoExternalConnection, feedTestUseRootUserConnection)
 }
 
+func TestAlterChangefeedSetSplitColumnFamilies(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE DATABASE movr`)
+		sqlDB.Exec(t, `CREATE TABLE movr.drivers (id INT PRIMARY KEY, name STRING)`)
+
+		sqlDB.Exec(t,
+			`INSERT INTO movr.drivers VALUES (1, 'Alice')`,
+		)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR movr.drivers WITH schema_change_policy='nobackfill'`)
+		defer closeFeed(t, testFeed)
+
+		assertPayloads(t, testFeed, []string{
+			`drivers: [1]->{"after": {"id": 1, "name": "Alice"}}`,
+		})
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		require.NoError(t, feed.Pause())
+
+		// The table only has a single column family.
+		sqlDB.ExpectErr(t,
+			`cannot set split_column_families: none of the tables watched by the changefeed have multiple column families`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d SET split_column_families`, feed.JobID()),
+		)
+
+		sqlDB.Exec(t, `ALTER TABLE movr.drivers ADD COLUMN age INT CREATE FAMILY onlyage`)
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d SET split_column_families`, feed.JobID()))
+
+		require.NoError(t, feed.Resume())
+
+		sqlDB.Exec(t,
+			`INSERT INTO movr.drivers VALUES (2, 'Bob', 30)`,
+		)
+
+		assertPayloads(t, testFeed, []string{
+			`drivers.primary: [2]->{"after": {"id": 2, "name": "Bob"}}`,
+			`drivers.onlyage: [2]->{"after": {"age": 30}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks, feedTestNoExternalConnection, feedTestUseRootUserConnection)
+}
+
+func TestAlterChangefeedSwitchFormatToAvro(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo WITH key_in_value`)
+		defer closeFeed(t, testFeed)
+
+		assertPayloads(t, testFeed, []string{
+			`foo: [1]->{"after": {"a": 1}, "key": [1]}`,
+		})
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+		require.NoError(t, feed.Pause())
+
+		reg := cdctest.StartTestSchemaRegistry()
+		defer reg.Close()
+
+		sqlDB.ExpectErr(t,
+			`cannot alter format to avro without confluent_schema_registry`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d SET format='avro'`, feed.JobID()),
+		)
+		sqlDB.ExpectErr(t,
+			`cannot alter format to avro while key_in_value is set`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d SET format='avro', confluent_schema_registry='%s'`,
+				feed.JobID(), reg.URL()),
+		)
+		sqlDB.Exec(t, fmt.Sprintf(
+			`ALTER CHANGEFEED %d SET format='avro', confluent_schema_registry='%s' UNSET key_in_value`,
+			feed.JobID(), reg.URL()))
+
+		require.NoError(t, feed.Resume())
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2)`)
+
+		// Messages emitted after the changefeed is resumed are avro-encoded
+		// using the schemas registered with the new schema registry.
+		var msg *cdctest.TestFeedMessage
+		for {
+			var err error
+			msg, err = testFeed.Next()
+			require.NoError(t, err)
+			if len(msg.Key) > 0 && msg.Key[0] == 0 {
+				break
+			}
+		}
+		key, err := reg.AvroToJSON(msg.Key)
+		require.NoError(t, err)
+		value, err := reg.AvroToJSON(msg.Value)
+		require.NoError(t, err)
+		require.Equal(t, `{"a":{"long":2}}`, string(key))
+		require.Equal(t, `{"after":{"foo":{"a":{"long":2}}}}`, string(value))
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"), feedTestNoExternalConnection)
+}
+
 func TestAlterChangefeedAlterTableName(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -1515,6 +1876,61 @@ func TestAlterChangefeedInitialScan(t *testing.T) {
 	}
 }
 
+// TestAlterChangefeedSkipBackfill verifies that
+// crdb_internal.changefeed_skip_backfill abandons the initial scan of a target
+// added with initial_scan, so that only live changes are emitted for it.
+func TestAlterChangefeedSkipBackfill(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1), (2), (3)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1), (2), (3)`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo WITH resolved = '1s', no_initial_scan`)
+		defer closeFeed(t, testFeed)
+
+		expectResolvedTimestamp(t, testFeed)
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		const skipQuery = `SELECT crdb_internal.changefeed_skip_backfill($1, $2)`
+
+		// The changefeed must be paused.
+		sqlDB.ExpectErr(t, `must be paused to skip a backfill`, skipQuery, feed.JobID(), `foo`)
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+
+		// There is no backfill to skip until bar is added.
+		sqlDB.ExpectErr(t, `is not performing an initial scan`, skipQuery, feed.JobID(), `foo`)
+
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d ADD bar WITH initial_scan`, feed.JobID()))
+
+		sqlDB.ExpectErr(t, `target "baz" is not watched`, skipQuery, feed.JobID(), `baz`)
+		sqlDB.ExpectErr(t, `initial scan of target "foo" of changefeed \d+ has already completed`,
+			skipQuery, feed.JobID(), `foo`)
+		sqlDB.Exec(t, skipQuery, feed.JobID(), `bar`)
+
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		// The existing rows of bar are not emitted, but new ones are.
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (4)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (4)`)
+		assertPayloads(t, testFeed, []string{
+			`bar: [4]->{"after": {"a": 4}}`,
+			`foo: [4]->{"after": {"a": 4}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"), feedTestNoExternalConnection)
+}
+
 // This test checks that the time used to get table descriptors in alter
 // changefeed is the time from which changefeed will resume (check
 // validateNewTargets for more info on how this time is calculated).
@@ -138,6 +138,54 @@ type avroSchemaField struct {
 	// "union key" value. nativeEncodedSecondaryType supports unions of two types (plus null).
 	nativeEncoded              map[string]interface{}
 	nativeEncodedSecondaryType map[string]interface{}
+
+	// omitDefault omits the default from the serialized schema. A union's
+	// default must match its first member, so a null default is only valid
+	// when null comes first.
+	omitDefault bool
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (f *avroSchemaField) MarshalJSON() ([]byte, error) {
+	type plainField avroSchemaField
+	if !f.omitDefault {
+		return json.Marshal((*plainField)(f))
+	}
+	return json.Marshal(struct {
+		*plainField
+		Default *string `json:"default,omitempty"`
+	}{plainField: (*plainField)(f)})
+}
+
+// avroSchemaOpts controls how SQL types are mapped to avro schemas.
+type avroSchemaOpts struct {
+	// unionNullLast places null last, rather than first, in the unions that
+	// make every field optional. See changefeedbase.OptAvroUnionNullFirst.
+	unionNullLast bool
+	// spatialAsGeoJSON maps GEOGRAPHY and GEOMETRY values to strings holding
+	// their GeoJSON rather than to bytes holding their EWKB. See
+	// changefeedbase.OptSpatialFormat.
+	spatialAsGeoJSON bool
+}
+
+// nullableUnion returns the union of null and the given types, ordered
+// according to opts.
+func (opts avroSchemaOpts) nullableUnion(members ...avroSchemaType) []avroSchemaType {
+	if opts.unionNullLast {
+		return append(members, avroSchemaNull)
+	}
+	return append([]avroSchemaType{avroSchemaNull}, members...)
+}
+
+// avroUnionMainType returns the first non-null member of a nullable union.
+func avroUnionMainType(union []avroSchemaType) avroSchemaType {
+	for _, t := range union {
+		if s, ok := t.(string); ok && s == avroSchemaNull {
+			continue
+		}
+		return t
+	}
+	return nil
 }
 
 // avroRecord is our representation of the schema of an avro record. Serializing
@@ -181,8 +229,18 @@ type avroEnvelopeRecord struct {
 	before, after, record *avroDataRecord
 }
 
+// avroKeyValueRecord is an `avroRecord` that nests the key of a changed SQL
+// row and the envelope of the change in a single record, for consumers which
+// expect one record to carry both.
+type avroKeyValueRecord struct {
+	avroRecord
+
+	key   *avroDataRecord
+	value *avroEnvelopeRecord
+}
+
 // typeToAvroSchema converts a database type to an avro field
-func typeToAvroSchema(typ *types.T) (*avroSchemaField, error) {
+func typeToAvroSchema(typ *types.T, opts avroSchemaOpts) (*avroSchemaField, error) {
 	schema := &avroSchemaField{
 		typ: typ,
 	}
@@ -199,7 +257,7 @@ func typeToAvroSchema(typ *types.T) (*avroSchemaField, error) {
 	) {
 		// The default for a union type is the default for the first element of
 		// the union.
-		schema.SchemaType = []avroSchemaType{avroSchemaNull, avroType}
+		schema.SchemaType = opts.nullableUnion(avroType)
 		unionKey := avroUnionKey(avroType)
 		schema.nativeEncoded = map[string]interface{}{unionKey: nil}
 		schema.encodeDatum = encoder
@@ -230,7 +288,7 @@ func typeToAvroSchema(typ *types.T) (*avroSchemaField, error) {
 		encoder datumToNativeFn,
 		decoder func(interface{}) (tree.Datum, error),
 	) {
-		schema.SchemaType = []avroSchemaType{avroSchemaNull, avroType, avroSchemaString}
+		schema.SchemaType = opts.nullableUnion(avroType, avroSchemaString)
 		mainUnionKey := avroUnionKey(avroType)
 		stringUnionKey := avroUnionKey(avroSchemaString)
 		schema.nativeEncoded = map[string]interface{}{mainUnionKey: nil}
@@ -364,6 +422,22 @@ func typeToAvroSchema(typ *types.T) (*avroSchemaField, error) {
 			},
 		)
 	case types.GeographyFamily:
+		if opts.spatialAsGeoJSON {
+			setNullable(
+				avroSchemaString,
+				func(d tree.Datum, _ interface{}) (interface{}, error) {
+					return spatialObjectToGeoJSON(d.(*tree.DGeography).SpatialObject())
+				},
+				func(x interface{}) (tree.Datum, error) {
+					g, err := geo.ParseGeographyFromGeoJSON([]byte(x.(string)))
+					if err != nil {
+						return nil, err
+					}
+					return &tree.DGeography{Geography: g}, nil
+				},
+			)
+			break
+		}
 		setNullable(
 			avroSchemaBytes,
 			func(d tree.Datum, _ interface{}) (interface{}, error) {
@@ -378,6 +452,22 @@ func typeToAvroSchema(typ *types.T) (*avroSchemaField, error) {
 			},
 		)
 	case types.GeometryFamily:
+		if opts.spatialAsGeoJSON {
+			setNullable(
+				avroSchemaString,
+				func(d tree.Datum, _ interface{}) (interface{}, error) {
+					return spatialObjectToGeoJSON(d.(*tree.DGeometry).SpatialObject())
+				},
+				func(x interface{}) (tree.Datum, error) {
+					g, err := geo.ParseGeometryFromGeoJSON([]byte(x.(string)))
+					if err != nil {
+						return nil, err
+					}
+					return &tree.DGeometry{Geometry: g}, nil
+				},
+			)
+			break
+		}
 		setNullable(
 			avroSchemaBytes,
 			func(d tree.Datum, _ interface{}) (interface{}, error) {
@@ -645,12 +735,12 @@ func typeToAvroSchema(typ *types.T) (*avroSchemaField, error) {
 			},
 		)
 	case types.ArrayFamily:
-		itemSchema, err := typeToAvroSchema(typ.ArrayContents())
+		itemSchema, err := typeToAvroSchema(typ.ArrayContents(), opts)
 		if err != nil {
 			return nil, changefeedbase.WithTerminalError(
 				errors.Wrapf(err, `could not create item schema for %s`, typ))
 		}
-		itemUnionKey := avroUnionKey(itemSchema.SchemaType.([]avroSchemaType)[1])
+		itemUnionKey := avroUnionKey(avroUnionMainType(itemSchema.SchemaType.([]avroSchemaType)))
 
 		setNullable(
 			avroArrayType{
@@ -737,14 +827,17 @@ func typeToAvroSchema(typ *types.T) (*avroSchemaField, error) {
 
 // columnToAvroSchema converts a column descriptor into its corresponding
 // avro field schema.
-func columnToAvroSchema(col cdcevent.ResultColumn) (*avroSchemaField, error) {
-	schema, err := typeToAvroSchema(col.Typ)
+func columnToAvroSchema(
+	col cdcevent.ResultColumn, opts avroSchemaOpts,
+) (*avroSchemaField, error) {
+	schema, err := typeToAvroSchema(col.Typ, opts)
 	if err != nil {
 		return nil, changefeedbase.WithTerminalError(errors.Wrapf(err, "column %s", col.Name))
 	}
 	schema.Name = SQLNameToAvroName(col.Name)
 	schema.Metadata = col.SQLStringNotHumanReadable()
 	schema.Default = nil
+	schema.omitDefault = opts.unionNullLast
 
 	return schema, nil
 }
@@ -753,7 +846,7 @@ func columnToAvroSchema(col cdcevent.ResultColumn) (*avroSchemaField, error) {
 // Only columns returned by Iterator as used to popoulate schema fields.
 // sqlName can be any string but should uniquely identify a schema.
 func newSchemaForRow(
-	it cdcevent.Iterator, sqlName string, namespace string,
+	it cdcevent.Iterator, sqlName string, namespace string, opts avroSchemaOpts,
 ) (*avroDataRecord, error) {
 	schema := &avroDataRecord{
 		avroRecord: avroRecord{
@@ -766,7 +859,7 @@ func newSchemaForRow(
 	}
 
 	if err := it.Col(func(col cdcevent.ResultColumn) error {
-		field, err := columnToAvroSchema(col)
+		field, err := columnToAvroSchema(col, opts)
 		if err != nil {
 			return err
 		}
@@ -794,9 +887,9 @@ func newSchemaForRow(
 
 // primaryIndexToAvroSchema constructs schema for primary index.
 func primaryIndexToAvroSchema(
-	row cdcevent.Row, sqlName string, namespace string,
+	row cdcevent.Row, sqlName string, namespace string, opts avroSchemaOpts,
 ) (*avroDataRecord, error) {
-	return newSchemaForRow(row.ForEachKeyColumn(), SQLNameToAvroName(sqlName), namespace)
+	return newSchemaForRow(row.ForEachKeyColumn(), SQLNameToAvroName(sqlName), namespace, opts)
 }
 
 const (
@@ -809,7 +902,7 @@ const (
 // If a name suffix is provided (as opposed to avroSchemaNoSuffix), it will be
 // appended to the end of the avro record's name.
 func tableToAvroSchema(
-	row cdcevent.Row, nameSuffix string, namespace string,
+	row cdcevent.Row, nameSuffix string, namespace string, opts avroSchemaOpts,
 ) (*avroDataRecord, error) {
 	var sqlName string
 	// Even though we now always specify a family,
@@ -823,7 +916,7 @@ func tableToAvroSchema(
 	if nameSuffix != avroSchemaNoSuffix {
 		sqlName = sqlName + `_` + nameSuffix
 	}
-	return newSchemaForRow(row.ForEachColumn(), sqlName, namespace)
+	return newSchemaForRow(row.ForEachColumn(), sqlName, namespace, opts)
 }
 
 // BinaryFromRow encodes the given row data into avro's defined binary format.
@@ -997,6 +1090,16 @@ func envelopeToAvroSchema(
 func (r *avroEnvelopeRecord) BinaryFromRow(
 	buf []byte, meta avroMetadata, beforeRow, afterRow, recordRow cdcevent.Row,
 ) ([]byte, error) {
+	native, err := r.nativeFromRow(meta, beforeRow, afterRow, recordRow)
+	if err != nil {
+		return nil, err
+	}
+	return r.codec.BinaryFromNative(buf, native)
+}
+
+func (r *avroEnvelopeRecord) nativeFromRow(
+	meta avroMetadata, beforeRow, afterRow, recordRow cdcevent.Row,
+) (map[string]interface{}, error) {
 	native := map[string]interface{}{}
 	if r.opts.beforeField {
 		if beforeRow.HasValues() && !beforeRow.IsDeleted() {
@@ -1075,7 +1178,78 @@ func (r *avroEnvelopeRecord) BinaryFromRow(
 	for k := range meta {
 		return nil, changefeedbase.WithTerminalError(errors.AssertionFailedf(`unhandled meta key: %s`, k))
 	}
-	return r.codec.BinaryFromNative(buf, native)
+	return native, nil
+}
+
+// refreshTypeMetadata refreshes the metadata for user-defined types on the
+// cached schemas of the rows in the envelope.
+func (r *avroEnvelopeRecord) refreshTypeMetadata(prevRow, updatedRow cdcevent.Row) error {
+	if prevRow.IsInitialized() && r.before != nil {
+		if err := r.before.refreshTypeMetadata(prevRow); err != nil {
+			return err
+		}
+	}
+	if r.after != nil {
+		if err := r.after.refreshTypeMetadata(updatedRow); err != nil {
+			return err
+		}
+	}
+	if r.record != nil {
+		if err := r.record.refreshTypeMetadata(updatedRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyValueToAvroSchema creates an avro record schema for a record containing
+// the key of a changed row and the envelope of the change.
+func keyValueToAvroSchema(
+	topic string, key *avroDataRecord, value *avroEnvelopeRecord, namespace string,
+) (*avroKeyValueRecord, error) {
+	schema := &avroKeyValueRecord{
+		avroRecord: avroRecord{
+			Name:       SQLNameToAvroName(topic) + `_key_value`,
+			SchemaType: `record`,
+			Namespace:  namespace,
+			// Neither field is nullable, so neither can default to null.
+			Fields: []*avroSchemaField{
+				{Name: `key`, SchemaType: key, omitDefault: true},
+				{Name: `value`, SchemaType: value, omitDefault: true},
+			},
+		},
+		key:   key,
+		value: value,
+	}
+
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	schema.codec, err = goavro.NewCodec(string(schemaJSON))
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// BinaryFromRow encodes the given key, metadata and row data into avro's
+// defined binary format.
+func (r *avroKeyValueRecord) BinaryFromRow(
+	buf []byte, key cdcevent.Iterator, meta avroMetadata, beforeRow, afterRow, recordRow cdcevent.Row,
+) ([]byte, error) {
+	keyNative, err := r.key.nativeFromRow(key)
+	if err != nil {
+		return nil, err
+	}
+	valueNative, err := r.value.nativeFromRow(meta, beforeRow, afterRow, recordRow)
+	if err != nil {
+		return nil, err
+	}
+	return r.codec.BinaryFromNative(buf, map[string]interface{}{
+		`key`:   keyNative,
+		`value`: valueNative,
+	})
 }
 
 // Refresh the metadata for user-defined types on a cached schema
@@ -1092,6 +1266,16 @@ func (r *avroDataRecord) refreshTypeMetadata(row cdcevent.Row) error {
 // decimalToRat converts one of our apd decimals to the format expected by the
 // avro library we use. If the column has a fixed scale (which is always true if
 // precision is set) this is roundtripable without information loss.
+// spatialObjectToGeoJSON returns the GeoJSON of the given spatial object, at
+// full precision, as rendered by the JSON encoder.
+func spatialObjectToGeoJSON(so geopb.SpatialObject) (string, error) {
+	j, err := geo.SpatialObjectToGeoJSON(so, geo.FullPrecisionGeoJSON, geo.SpatialObjectToGeoJSONFlagZero)
+	if err != nil {
+		return ``, err
+	}
+	return string(j), nil
+}
+
 func decimalToRat(dec apd.Decimal, scale int32) (big.Rat, error) {
 	if dec.Form != apd.Finite {
 		return big.Rat{}, changefeedbase.WithTerminalError(
@@ -162,7 +162,7 @@ func parseAvroSchema(t *testing.T, evalCtx *eval.Context, j string) (*avroDataRe
 	return tableToAvroSchema(
 		cdcevent.TestingMakeEventRow(
 			tabledesc.NewBuilder(&tableDesc).BuildImmutableTable(), 0, nil, false,
-		), "", "")
+		), "", "", avroSchemaOpts{})
 }
 
 func avroFieldMetadataToColDesc(
@@ -394,7 +394,7 @@ func TestAvroSchema(t *testing.T) {
 			require.NoError(t, err)
 			origSchema, err := tableToAvroSchema(
 				cdcevent.TestingMakeEventRow(tableDesc, 0, nil, false),
-				avroSchemaNoSuffix, "")
+				avroSchemaNoSuffix, "", avroSchemaOpts{})
 			require.NoError(t, err)
 			jsonSchema := origSchema.codec.Schema()
 			roundtrippedSchema, err := parseAvroSchema(t, evalCtx, jsonSchema)
@@ -434,7 +434,7 @@ func TestAvroSchema(t *testing.T) {
 		tableDesc, err := parseTableDesc(`CREATE TABLE "☃" (🍦 INT PRIMARY KEY)`)
 		require.NoError(t, err)
 		tableSchema, err := tableToAvroSchema(
-			cdcevent.TestingMakeEventRow(tableDesc, 0, nil, false), avroSchemaNoSuffix, "")
+			cdcevent.TestingMakeEventRow(tableDesc, 0, nil, false), avroSchemaNoSuffix, "", avroSchemaOpts{})
 		require.NoError(t, err)
 		require.Equal(t,
 			`{"type":"record","name":"_u2603_","fields":[`+
@@ -442,7 +442,7 @@ func TestAvroSchema(t *testing.T) {
 				`"__crdb__":"🍦 INT8 NOT NULL"}]}`,
 			tableSchema.codec.Schema())
 		indexSchema, err := primaryIndexToAvroSchema(
-			cdcevent.TestingMakeEventRow(tableDesc, 0, nil, false), tableDesc.GetName(), "")
+			cdcevent.TestingMakeEventRow(tableDesc, 0, nil, false), tableDesc.GetName(), "", avroSchemaOpts{})
 		require.NoError(t, err)
 		require.Equal(t,
 			`{"type":"record","name":"_u2603_","fields":[`+
@@ -451,6 +451,42 @@ func TestAvroSchema(t *testing.T) {
 			indexSchema.codec.Schema())
 	})
 
+	t.Run("union_null_last", func(t *testing.T) {
+		opts := avroSchemaOpts{unionNullLast: true}
+		for colType, expected := range map[string]string{
+			`INT8`:         `["long","null"]`,
+			`BOOL[]`:       `[{"type":"array","items":["boolean","null"]},"null"]`,
+			`DECIMAL(3,2)`: `[{"type":"bytes","logicalType":"decimal","precision":3,"scale":2},"string","null"]`,
+		} {
+			tableDesc, err := parseTableDesc(`CREATE TABLE foo (pk INT PRIMARY KEY, a ` + colType + `)`)
+			require.NoError(t, err)
+			field, err := columnToAvroSchema(
+				cdcevent.ResultColumn{ResultColumn: colinfo.ResultColumn{Typ: tableDesc.PublicColumns()[1].GetType()}},
+				opts,
+			)
+			require.NoError(t, err)
+			schema, err := json.Marshal(field.SchemaType)
+			require.NoError(t, err)
+			require.Equal(t, expected, string(schema), colType)
+		}
+
+		// A null default is only valid when null is the first member of the
+		// union, so it is omitted.
+		tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b BOOL[])`)
+		require.NoError(t, err)
+		tableSchema, err := tableToAvroSchema(
+			cdcevent.TestingMakeEventRow(tableDesc, 0, nil, false), avroSchemaNoSuffix, "", opts)
+		require.NoError(t, err)
+		var record struct {
+			Fields []map[string]interface{} `json:"fields"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(tableSchema.codec.Schema()), &record))
+		require.Len(t, record.Fields, 2)
+		for _, f := range record.Fields {
+			require.NotContains(t, f, "default")
+		}
+	})
+
 	// This test shows what avro schema each sql column maps to, for easy
 	// reference.
 	t.Run("type_goldens", func(t *testing.T) {
@@ -497,6 +533,7 @@ func TestAvroSchema(t *testing.T) {
 				require.NoError(t, err)
 				field, err := columnToAvroSchema(
 					cdcevent.ResultColumn{ResultColumn: colinfo.ResultColumn{Typ: tableDesc.PublicColumns()[1].GetType()}},
+					avroSchemaOpts{},
 				)
 				require.NoError(t, err)
 				schema, err := json.Marshal(field.SchemaType)
@@ -678,7 +715,7 @@ func TestAvroSchema(t *testing.T) {
 
 			row := cdcevent.TestingMakeEventRow(tableDesc, 0, encDatums[0], false)
 			schema, err := tableToAvroSchema(
-				row, avroSchemaNoSuffix, "")
+				row, avroSchemaNoSuffix, "", avroSchemaOpts{})
 			require.NoError(t, err)
 			if test.numRawBytes > 0 {
 				overhead := 4
@@ -735,7 +772,7 @@ func TestAvroSchema(t *testing.T) {
 			require.NoError(t, err)
 
 			row := cdcevent.TestingMakeEventRow(tableDesc, 0, encDatums[0], false)
-			schema, err := tableToAvroSchema(row, avroSchemaNoSuffix, "")
+			schema, err := tableToAvroSchema(row, avroSchemaNoSuffix, "", avroSchemaOpts{})
 			require.NoError(t, err)
 			textual, err := schema.textualFromRow(row)
 			require.NoError(t, err)
@@ -839,13 +876,13 @@ func TestAvroMigration(t *testing.T) {
 				fmt.Sprintf(`CREATE TABLE "%s" %s`, test.name, test.writerSchema))
 			require.NoError(t, err)
 			writerSchema, err := tableToAvroSchema(
-				cdcevent.TestingMakeEventRow(writerDesc, 0, nil, false), avroSchemaNoSuffix, "")
+				cdcevent.TestingMakeEventRow(writerDesc, 0, nil, false), avroSchemaNoSuffix, "", avroSchemaOpts{})
 			require.NoError(t, err)
 			readerDesc, err := parseTableDesc(
 				fmt.Sprintf(`CREATE TABLE "%s" %s`, test.name, test.readerSchema))
 			require.NoError(t, err)
 			readerSchema, err := tableToAvroSchema(
-				cdcevent.TestingMakeEventRow(readerDesc, 0, nil, false), avroSchemaNoSuffix, "")
+				cdcevent.TestingMakeEventRow(readerDesc, 0, nil, false), avroSchemaNoSuffix, "", avroSchemaOpts{})
 			require.NoError(t, err)
 
 			writerRows, err := parseValues(writerDesc, `VALUES `+test.writerValues)
@@ -923,7 +960,7 @@ func benchmarkEncodeType(b *testing.B, typ *types.T, encRow rowenc.EncDatumRow)
 		fmt.Sprintf(`CREATE TABLE bench_table (bench_field %s)`, typ.SQLString()))
 	require.NoError(b, err)
 	row := cdcevent.TestingMakeEventRow(tableDesc, 0, encRow, false)
-	schema, err := tableToAvroSchema(row, "suffix", "namespace")
+	schema, err := tableToAvroSchema(row, "suffix", "namespace", avroSchemaOpts{})
 	require.NoError(b, err)
 
 	b.ReportAllocs()
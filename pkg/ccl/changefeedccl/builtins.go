@@ -0,0 +1,475 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// runningFrontiers tracks the changeFrontier processors running on this node,
+// keyed by job ID. It is the control channel used by builtins which need to
+// interact with a running changefeed. Only changefeeds with a job are tracked.
+var runningFrontiers = struct {
+	syncutil.Mutex
+	m map[jobspb.JobID]*changeFrontier
+}{m: make(map[jobspb.JobID]*changeFrontier)}
+
+func registerRunningFrontier(cf *changeFrontier) {
+	runningFrontiers.Lock()
+	defer runningFrontiers.Unlock()
+	runningFrontiers.m[cf.spec.JobID] = cf
+}
+
+// unregisterRunningFrontier removes cf from the set of running frontiers. It
+// is idempotent, and does nothing if cf has already been replaced by a newer
+// frontier for the same job.
+func unregisterRunningFrontier(cf *changeFrontier) {
+	runningFrontiers.Lock()
+	defer runningFrontiers.Unlock()
+	if runningFrontiers.m[cf.spec.JobID] == cf {
+		delete(runningFrontiers.m, cf.spec.JobID)
+	}
+}
+
+func lookupRunningFrontier(jobID jobspb.JobID) (*changeFrontier, error) {
+	runningFrontiers.Lock()
+	defer runningFrontiers.Unlock()
+	cf, ok := runningFrontiers.m[jobID]
+	if !ok {
+		return nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"changefeed job %d is not running on this node; "+
+				"run this function on the node coordinating the job", jobID)
+	}
+	return cf, nil
+}
+
+// sinkStatus is the status of a changefeed's sink, published by the
+// changefeed's resumer for crdb_internal.changefeed_sinks. It outlives the
+// individual attempts to run the changefeed's flow, so that it can report the
+// error which caused the last attempt to be retried.
+type sinkStatus struct {
+	sinkType string
+
+	mu struct {
+		syncutil.Mutex
+		// lastEmit is the last time the changefeed's sinks were flushed, as
+		// observed by the change frontier.
+		lastEmit time.Time
+		lastErr  error
+	}
+}
+
+func (s *sinkStatus) recordEmit(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.lastEmit = t
+}
+
+func (s *sinkStatus) recordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.lastErr = err
+}
+
+// runningSinks tracks the status of the sinks of the changefeeds whose
+// resumers are running on this node, keyed by job ID.
+var runningSinks = struct {
+	syncutil.Mutex
+	m map[jobspb.JobID]*sinkStatus
+}{m: make(map[jobspb.JobID]*sinkStatus)}
+
+// registerSinkStatus publishes the status of the sink of the given changefeed
+// job, and returns a function which unpublishes it.
+func registerSinkStatus(jobID jobspb.JobID, sinkURI string) (*sinkStatus, func()) {
+	status := &sinkStatus{sinkType: `unknown`}
+	if u, err := url.Parse(sinkURI); err == nil && u.Scheme != `` {
+		status.sinkType = u.Scheme
+	}
+	runningSinks.Lock()
+	defer runningSinks.Unlock()
+	runningSinks.m[jobID] = status
+	return status, func() {
+		runningSinks.Lock()
+		defer runningSinks.Unlock()
+		if runningSinks.m[jobID] == status {
+			delete(runningSinks.m, jobID)
+		}
+	}
+}
+
+// lookupSinkStatus returns the published status of the sink of the given
+// changefeed job, or nil if the job's resumer is not running on this node.
+func lookupSinkStatus(jobID jobspb.JobID) *sinkStatus {
+	runningSinks.Lock()
+	defer runningSinks.Unlock()
+	return runningSinks.m[jobID]
+}
+
+// sinkStatusGenerator is the generator for crdb_internal.changefeed_sinks. It
+// snapshots the published sink statuses when it is started.
+type sinkStatusGenerator struct {
+	rows []tree.Datums
+	cur  int
+}
+
+var sinkStatusGeneratorType = types.MakeLabeledTuple(
+	[]*types.T{types.Int, types.String, types.TimestampTZ, types.String},
+	[]string{"job_id", "sink_type", "last_emit_ts", "last_error"},
+)
+
+var _ eval.ValueGenerator = (*sinkStatusGenerator)(nil)
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *sinkStatusGenerator) ResolvedType() *types.T {
+	return sinkStatusGeneratorType
+}
+
+// Start implements the eval.ValueGenerator interface.
+func (g *sinkStatusGenerator) Start(_ context.Context, _ *kv.Txn) error {
+	runningSinks.Lock()
+	defer runningSinks.Unlock()
+	g.rows = g.rows[:0]
+	g.cur = -1
+	for jobID, status := range runningSinks.m {
+		row := tree.Datums{
+			tree.NewDInt(tree.DInt(jobID)), tree.NewDString(status.sinkType), tree.DNull, tree.DNull,
+		}
+		status.mu.Lock()
+		if !status.mu.lastEmit.IsZero() {
+			ts, err := tree.MakeDTimestampTZ(status.mu.lastEmit, time.Microsecond)
+			if err != nil {
+				status.mu.Unlock()
+				return err
+			}
+			row[2] = ts
+		}
+		if status.mu.lastErr != nil {
+			row[3] = tree.NewDString(status.mu.lastErr.Error())
+		}
+		status.mu.Unlock()
+		g.rows = append(g.rows, row)
+	}
+	sort.Slice(g.rows, func(i, j int) bool {
+		return *g.rows[i][0].(*tree.DInt) < *g.rows[j][0].(*tree.DInt)
+	})
+	return nil
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *sinkStatusGenerator) Next(_ context.Context) (bool, error) {
+	g.cur++
+	return g.cur < len(g.rows), nil
+}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *sinkStatusGenerator) Values() (tree.Datums, error) {
+	return g.rows[g.cur], nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *sinkStatusGenerator) Close(_ context.Context) {}
+
+// checkChangefeedControlPrivilege ensures the current user is allowed to
+// control changefeed jobs.
+func checkChangefeedControlPrivilege(ctx context.Context, evalCtx *eval.Context) error {
+	ok, err := evalCtx.SessionAccessor.HasGlobalPrivilegeOrRoleOption(ctx, privilege.CONTROLJOB)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"user %s does not have %s privilege", evalCtx.SessionData().User(), privilege.CONTROLJOB)
+	}
+	return nil
+}
+
+// checkChangefeedViewPrivilege ensures the current user is allowed to view
+// changefeed jobs.
+func checkChangefeedViewPrivilege(ctx context.Context, evalCtx *eval.Context) error {
+	for _, kind := range []privilege.Kind{privilege.VIEWJOB, privilege.CONTROLJOB} {
+		ok, err := evalCtx.SessionAccessor.HasGlobalPrivilegeOrRoleOption(ctx, kind)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return pgerror.Newf(pgcode.InsufficientPrivilege,
+		"user %s does not have %s privilege", evalCtx.SessionData().User(), privilege.VIEWJOB)
+}
+
+// replayChangefeedSpan marks the given span of a paused changefeed for
+// re-emission once the changefeed is resumed. The span is recorded in the
+// changefeed's progress, along with its high-water: when the changefeed
+// resumes, the aggregators re-scan it as of the high-water before starting
+// their rangefeeds, and the replay is cleared once the high-water advances.
+func replayChangefeedSpan(
+	ctx context.Context, execCtx sql.JobExecContext, jobID jobspb.JobID, replay roachpb.Span,
+) error {
+	if !replay.Valid() {
+		return pgerror.Newf(pgcode.InvalidParameterValue, "invalid span %s", replay)
+	}
+	execCfg := execCtx.ExecCfg()
+	j, err := execCfg.JobRegistry.LoadJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	details, ok := j.Details().(jobspb.ChangefeedDetails)
+	if !ok {
+		return pgerror.Newf(pgcode.InvalidParameterValue, "job %d is not a changefeed", jobID)
+	}
+	highWater := j.Progress().GetHighWater()
+	if highWater == nil || highWater.IsEmpty() {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"changefeed %d has not resolved a high-water yet", jobID)
+	}
+
+	// The span must be watched by the changefeed once it resumes, i.e. as of
+	// the timestamp following its high-water.
+	schemaTS := highWater.Next()
+	tableDescs, err := fetchTableDescriptors(ctx, execCfg, AllTargets(details), schemaTS)
+	if err != nil {
+		return err
+	}
+	trackedSpans, err := fetchSpansForTables(ctx, execCtx, tableDescs, details, schemaTS)
+	if err != nil {
+		return err
+	}
+	var tracked roachpb.SpanGroup
+	tracked.Add(trackedSpans...)
+	if !tracked.Encloses(replay) {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"span %s is not watched by changefeed %d", replay, jobID)
+	}
+
+	return j.NoTxn().Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+		if md.Status != jobs.StatusPaused {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"changefeed %d must be paused to replay a span, but is %s", jobID, md.Status)
+		}
+		if hw := md.Progress.GetHighWater(); hw == nil || !hw.Equal(*highWater) {
+			return pgerror.Newf(pgcode.SerializationFailure,
+				"the high-water of changefeed %d changed while replaying a span", jobID)
+		}
+		progress := md.Progress.GetChangefeed()
+		if progress == nil {
+			progress = &jobspb.ChangefeedProgress{}
+		}
+
+		// Spans checkpointed ahead of the high-water are skipped by the scans
+		// which follow a resumption, so the replayed span resumes from the
+		// high-water instead.
+		if progress.Checkpoint != nil && len(progress.Checkpoint.Spans) != 0 {
+			var checkpoint roachpb.SpanGroup
+			checkpoint.Add(progress.Checkpoint.Spans...)
+			checkpoint.Sub(replay)
+			progress.Checkpoint.Spans = checkpoint.Slice()
+		}
+
+		var replaySpans roachpb.SpanGroup
+		if progress.Replay != nil && progress.Replay.Timestamp.Equal(*highWater) {
+			replaySpans.Add(progress.Replay.Spans...)
+		}
+		replaySpans.Add(replay)
+		progress.Replay = &jobspb.ChangefeedProgress_Replay{
+			Spans:     replaySpans.Slice(),
+			Timestamp: *highWater,
+		}
+		md.Progress.Details = jobspb.WrapProgressDetails(*progress)
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
+// skipChangefeedBackfill abandons the remainder of the initial scan of the
+// given target of a paused changefeed, e.g. one added with ALTER CHANGEFEED
+// ADD ... WITH initial_scan. The target's spans are added to the checkpoint,
+// as they would be had the target been added with no_initial_scan, so that once
+// the changefeed is resumed only the changes made after the scan time are
+// emitted for it. The target is named as in the changefeed's topics.
+func skipChangefeedBackfill(
+	ctx context.Context, execCfg *sql.ExecutorConfig, jobID jobspb.JobID, target string,
+) error {
+	j, err := execCfg.JobRegistry.LoadJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	return j.NoTxn().Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+		details := md.Payload.GetChangefeed()
+		if details == nil {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "job %d is not a changefeed", jobID)
+		}
+		if md.Status != jobs.StatusPaused {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"changefeed %d must be paused to skip a backfill, but is %s", jobID, md.Status)
+		}
+		opts := changefeedbase.MakeStatementOptions(details.Opts)
+		initialScanType, err := opts.GetInitialScanType()
+		if err != nil {
+			return err
+		}
+		if initialScanType == changefeedbase.OnlyInitialScan {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot skip the backfill of changefeed %d with %s='only'", jobID, changefeedbase.OptInitialScan)
+		}
+		if highWater := md.Progress.GetHighWater(); highWater != nil && !highWater.IsEmpty() {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"changefeed %d is not performing an initial scan", jobID)
+		}
+
+		var skipped roachpb.SpanGroup
+		for _, ts := range details.TargetSpecifications {
+			if ts.StatementTimeName == target {
+				prefix := execCfg.Codec.TablePrefix(uint32(ts.TableID))
+				skipped.Add(roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+			}
+		}
+		if skipped.Len() == 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"target %q is not watched by changefeed %d", target, jobID)
+		}
+
+		progress := md.Progress.GetChangefeed()
+		if progress == nil {
+			progress = &jobspb.ChangefeedProgress{}
+		}
+		if progress.Checkpoint == nil {
+			progress.Checkpoint = &jobspb.ChangefeedProgress_Checkpoint{}
+		}
+		var checkpoint roachpb.SpanGroup
+		checkpoint.Add(progress.Checkpoint.Spans...)
+		if checkpoint.Encloses(skipped.Slice()...) {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"the initial scan of target %q of changefeed %d has already completed", target, jobID)
+		}
+		checkpoint.Add(skipped.Slice()...)
+
+		progress.Checkpoint.Spans = checkpoint.Slice()
+		md.Progress.Details = jobspb.WrapProgressDetails(*progress)
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
+func init() {
+	utilccl.RegisterCCLBuiltin("crdb_internal.changefeed_checkpoint_now",
+		`Forces the changefeed with the given job ID to persist its current frontier to the job record immediately, and returns the checkpointed high-water timestamp (NULL if the changefeed has not yet resolved a high-water). Must be run on the node coordinating the changefeed.`,
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "job_id", Typ: types.Int}},
+			ReturnType: tree.FixedReturnType(types.Decimal),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if err := checkChangefeedControlPrivilege(ctx, evalCtx); err != nil {
+					return nil, err
+				}
+				jobID := jobspb.JobID(tree.MustBeDInt(args[0]))
+				cf, err := lookupRunningFrontier(jobID)
+				if err != nil {
+					return nil, err
+				}
+				highWater, err := cf.requestCheckpoint(ctx)
+				if err != nil {
+					return nil, err
+				}
+				if highWater.IsEmpty() {
+					return tree.DNull, nil
+				}
+				return eval.TimestampToDecimalDatum(highWater), nil
+			},
+			Class:      tree.NormalClass,
+			Volatility: volatility.Volatile,
+		})
+
+	utilccl.RegisterCCLBuiltin("crdb_internal.changefeed_replay_span",
+		`Marks the span [start_key, end_key) of the paused changefeed with the given job ID for re-emission. When the changefeed is resumed, the rows in that span are re-scanned as of the changefeed's high-water, while the rest of the changefeed is unaffected.`,
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "job_id", Typ: types.Int},
+				{Name: "start_key", Typ: types.Bytes},
+				{Name: "end_key", Typ: types.Bytes},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if err := checkChangefeedControlPrivilege(ctx, evalCtx); err != nil {
+					return nil, err
+				}
+				jobID := jobspb.JobID(tree.MustBeDInt(args[0]))
+				replay := roachpb.Span{
+					Key:    roachpb.Key(tree.MustBeDBytes(args[1])),
+					EndKey: roachpb.Key(tree.MustBeDBytes(args[2])),
+				}
+				execCtx := evalCtx.JobExecContext.(sql.JobExecContext)
+				if err := replayChangefeedSpan(ctx, execCtx, jobID, replay); err != nil {
+					return nil, err
+				}
+				return tree.DBoolTrue, nil
+			},
+			Class:      tree.NormalClass,
+			Volatility: volatility.Volatile,
+		})
+
+	utilccl.RegisterCCLBuiltin("crdb_internal.changefeed_skip_backfill",
+		`Abandons the remainder of the initial scan of the given target of the paused changefeed with the given job ID. When the changefeed is resumed, only the changes made to the target after the scan time are emitted, as if the target had been added with no_initial_scan.`,
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "job_id", Typ: types.Int},
+				{Name: "target", Typ: types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if err := checkChangefeedControlPrivilege(ctx, evalCtx); err != nil {
+					return nil, err
+				}
+				jobID := jobspb.JobID(tree.MustBeDInt(args[0]))
+				target := string(tree.MustBeDString(args[1]))
+				execCfg := evalCtx.JobExecContext.(sql.JobExecContext).ExecCfg()
+				if err := skipChangefeedBackfill(ctx, execCfg, jobID, target); err != nil {
+					return nil, err
+				}
+				return tree.DBoolTrue, nil
+			},
+			Class:      tree.NormalClass,
+			Volatility: volatility.Volatile,
+		})
+
+	utilccl.RegisterCCLBuiltin("crdb_internal.changefeed_sinks",
+		`Returns the sink type of each changefeed running on this node, along with the last time its sinks were flushed and the last error which caused the changefeed to be retried.`,
+		tree.Overload{
+			Types:      tree.ParamTypes{},
+			ReturnType: tree.FixedReturnType(sinkStatusGeneratorType),
+			Generator: func(ctx context.Context, evalCtx *eval.Context, _ tree.Datums) (eval.ValueGenerator, error) {
+				if err := checkChangefeedViewPrivilege(ctx, evalCtx); err != nil {
+					return nil, err
+				}
+				return &sinkStatusGenerator{}, nil
+			},
+			Class:      tree.GeneratorClass,
+			Volatility: volatility.Volatile,
+		})
+}
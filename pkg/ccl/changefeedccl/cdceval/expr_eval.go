@@ -32,6 +32,11 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
+// ErrRowEvaluation marks the errors returned by Evaluator.Eval when the
+// expression failed to evaluate for the row, for example because of a division
+// by zero or a failed cast. Subsequent rows may still evaluate successfully.
+var ErrRowEvaluation = errors.New("error evaluating CDC expression for row")
+
 // Evaluator is a responsible for evaluating expressions in CDC.
 type Evaluator struct {
 	sc *tree.SelectClause
@@ -220,6 +225,14 @@ func (e *familyEvaluator) eval(
 	case <-ctx.Done():
 		return cdcevent.Row{}, ctx.Err()
 	case err := <-e.errCh:
+		if err != nil && ctx.Err() == nil {
+			// The expression failed to evaluate for this row, which also shut
+			// down the execution pipeline. Tear it down so that it is planned
+			// again for the next row.
+			_ = e.closeErr()
+			e.currDesc, e.prevDesc = nil, nil
+			err = errors.Mark(err, ErrRowEvaluation)
+		}
 		return cdcevent.Row{}, err
 	case row := <-e.rowCh:
 		filter, err := tree.GetBool(row[0])
@@ -0,0 +1,181 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdctest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// ClickHouseInsert is an insert request received by MockClickHouseSink.
+type ClickHouseInsert struct {
+	// Table is the unquoted name of the table inserted into.
+	Table string
+	// Database is the database parameter of the request, if any.
+	Database string
+	// User is the user the request was authenticated as, if any.
+	User string
+	// Rows are the rows inserted, in JSONEachRow format.
+	Rows []string
+}
+
+// ClickHouseRow is a row inserted into MockClickHouseSink.
+type ClickHouseRow struct {
+	Table string
+	Row   string
+}
+
+// clickhouseInsertRegexp matches the queries of the inserts sent by the
+// clickhouse sink.
+var clickhouseInsertRegexp = regexp.MustCompile("^INSERT INTO `((?:[^`\\\\]|\\\\.)+)` FORMAT JSONEachRow$")
+
+// MockClickHouseSink is an HTTP server accepting the insert requests sent by
+// the clickhouse sink, used in tests. Requests which aren't well-formed
+// inserts are rejected with a 400 status.
+type MockClickHouseSink struct {
+	server *httptest.Server
+	mu     struct {
+		syncutil.Mutex
+		statusCodes      []int
+		statusCodesIndex int
+		inserts          []ClickHouseInsert
+		rows             []ClickHouseRow
+		notify           chan struct{}
+	}
+}
+
+// StartMockClickHouseSink creates and starts a mock clickhouse sink for tests.
+func StartMockClickHouseSink() *MockClickHouseSink {
+	s := &MockClickHouseSink{}
+	s.mu.statusCodes = []int{http.StatusOK}
+	s.server = httptest.NewServer(http.HandlerFunc(s.requestHandler))
+	return s
+}
+
+// Host returns the address of this mock clickhouse sink.
+func (s *MockClickHouseSink) Host() string {
+	return s.server.Listener.Addr().String()
+}
+
+// Close closes the mock clickhouse sink.
+func (s *MockClickHouseSink) Close() {
+	s.server.Close()
+	s.server.CloseClientConnections()
+}
+
+// SetStatusCodes sets the list of HTTP status codes (in order) to use when
+// responding to well-formed inserts (wraps around after completion). The
+// rows of an insert are only recorded if its status is 200.
+func (s *MockClickHouseSink) SetStatusCodes(statusCodes []int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.statusCodes = statusCodes
+	s.mu.statusCodesIndex = 0
+}
+
+// Inserts returns the successful inserts received so far.
+func (s *MockClickHouseSink) Inserts() []ClickHouseInsert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ClickHouseInsert(nil), s.mu.inserts...)
+}
+
+// Pop deletes and returns the oldest row inserted into MockClickHouseSink, or
+// false if there are no rows.
+func (s *MockClickHouseSink) Pop() (ClickHouseRow, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.mu.rows) > 0 {
+		oldest := s.mu.rows[0]
+		s.mu.rows = s.mu.rows[1:]
+		return oldest, true
+	}
+	return ClickHouseRow{}, false
+}
+
+// NotifyMessage arranges for channel to be closed when a row is inserted.
+func (s *MockClickHouseSink) NotifyMessage() chan struct{} {
+	c := make(chan struct{})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.mu.rows) > 0 {
+		close(c)
+	} else {
+		s.mu.notify = c
+	}
+	return c
+}
+
+func (s *MockClickHouseSink) requestHandler(w http.ResponseWriter, r *http.Request) {
+	insert, err := parseClickHouseInsert(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statusCode := s.mu.statusCodes[s.mu.statusCodesIndex]
+	s.mu.statusCodesIndex = (s.mu.statusCodesIndex + 1) % len(s.mu.statusCodes)
+	if statusCode != http.StatusOK {
+		http.Error(w, http.StatusText(statusCode), statusCode)
+		return
+	}
+	s.mu.inserts = append(s.mu.inserts, insert)
+	for _, row := range insert.Rows {
+		s.mu.rows = append(s.mu.rows, ClickHouseRow{Table: insert.Table, Row: row})
+	}
+	if s.mu.notify != nil {
+		close(s.mu.notify)
+		s.mu.notify = nil
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// parseClickHouseInsert parses an insert request, returning an error if it
+// isn't well-formed.
+func parseClickHouseInsert(r *http.Request) (ClickHouseInsert, error) {
+	var insert ClickHouseInsert
+	if r.Method != http.MethodPost {
+		return insert, fmt.Errorf("unexpected method %s", r.Method)
+	}
+	m := clickhouseInsertRegexp.FindStringSubmatch(r.URL.Query().Get("query"))
+	if m == nil {
+		return insert, fmt.Errorf("unexpected query %q", r.URL.Query().Get("query"))
+	}
+	insert.Table = regexp.MustCompile(`\\(.)`).ReplaceAllString(m[1], "$1")
+	insert.Database = r.URL.Query().Get("database")
+	insert.User, _, _ = r.BasicAuth()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return insert, err
+	}
+	if len(body) == 0 || body[len(body)-1] != '\n' {
+		return insert, fmt.Errorf("expected newline-terminated rows, found %q", body)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		row := scanner.Text()
+		if !strings.HasPrefix(row, "{") || !json.Valid([]byte(row)) {
+			return insert, fmt.Errorf("expected a JSON object, found %q", row)
+		}
+		insert.Rows = append(insert.Rows, row)
+	}
+	return insert, scanner.Err()
+}
@@ -0,0 +1,275 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdctest
+
+import (
+	"bufio"
+	"crypto/tls"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// MockSyslogSink is a syslog server, receiving octet-counted messages over
+// TCP, used in tests.
+type MockSyslogSink struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+	mu       struct {
+		syncutil.Mutex
+		conns  []net.Conn
+		rows   []string
+		err    error
+		notify chan struct{}
+	}
+}
+
+// StartMockSyslogSink creates and starts a mock syslog sink for tests. If
+// certificate is not nil, the sink only accepts TLS connections.
+func StartMockSyslogSink(certificate *tls.Certificate) (*MockSyslogSink, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	if certificate != nil {
+		listener = tls.NewListener(listener, &tls.Config{
+			Certificates: []tls.Certificate{*certificate},
+		})
+	}
+	s := &MockSyslogSink{listener: listener}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+// Addr returns the host:port address of this mock syslog sink.
+func (s *MockSyslogSink) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close closes the mock syslog sink.
+func (s *MockSyslogSink) Close() {
+	_ = s.listener.Close()
+	s.mu.Lock()
+	for _, conn := range s.mu.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Err returns the first malformed frame received by the sink, if any.
+func (s *MockSyslogSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.err
+}
+
+// Pop deletes and returns the oldest message from MockSyslogSink.
+func (s *MockSyslogSink) Pop() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.mu.rows) > 0 {
+		oldest := s.mu.rows[0]
+		s.mu.rows = s.mu.rows[1:]
+		return oldest
+	}
+	return ""
+}
+
+// NotifyMessage arranges for channel to be closed when message arrives.
+func (s *MockSyslogSink) NotifyMessage() chan struct{} {
+	c := make(chan struct{})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.mu.rows) > 0 {
+		close(c)
+	} else {
+		s.mu.notify = c
+	}
+	return c
+}
+
+func (s *MockSyslogSink) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.mu.conns = append(s.mu.conns, conn)
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.serve(conn)
+	}
+}
+
+// serve reads the octet-counted frames, as described by RFC 6587, sent over
+// the connection.
+func (s *MockSyslogSink) serve(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		length, err := r.ReadString(' ')
+		if err != nil {
+			if err != io.EOF && length != "" {
+				s.setErr(errors.Wrap(err, "reading frame length"))
+			}
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+		if err != nil || n <= 0 {
+			s.setErr(errors.Newf("malformed frame length %q", length))
+			return
+		}
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			s.setErr(errors.Wrap(err, "reading frame"))
+			return
+		}
+		s.mu.Lock()
+		s.mu.rows = append(s.mu.rows, string(msg))
+		if s.mu.notify != nil {
+			close(s.mu.notify)
+			s.mu.notify = nil
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *MockSyslogSink) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.err == nil {
+		s.mu.err = err
+	}
+}
+
+// SyslogMessage is a parsed RFC 5424 syslog message.
+type SyslogMessage struct {
+	Priority  int
+	Timestamp time.Time
+	Hostname  string
+	AppName   string
+	ProcID    string
+	MsgID     string
+	// StructuredData maps the ID of each structured data element to its
+	// parameters.
+	StructuredData map[string]map[string]string
+	Msg            []byte
+}
+
+// ParseSyslogMessage parses an RFC 5424 syslog message, returning an error if
+// it is not well-formed.
+func ParseSyslogMessage(msg string) (SyslogMessage, error) {
+	var m SyslogMessage
+	if !strings.HasPrefix(msg, "<") {
+		return m, errors.Newf("missing priority: %q", msg)
+	}
+	end := strings.IndexByte(msg, '>')
+	if end < 0 {
+		return m, errors.Newf("malformed priority: %q", msg)
+	}
+	pri, err := strconv.Atoi(msg[1:end])
+	if err != nil || pri < 0 || pri > 191 {
+		return m, errors.Newf("malformed priority: %q", msg)
+	}
+	m.Priority = pri
+
+	header := strings.SplitN(msg[end+1:], " ", 7)
+	if len(header) != 7 {
+		return m, errors.Newf("malformed header: %q", msg)
+	}
+	if header[0] != "1" {
+		return m, errors.Newf("unsupported version %q", header[0])
+	}
+	if m.Timestamp, err = time.Parse(time.RFC3339Nano, header[1]); err != nil {
+		return m, errors.Wrap(err, "malformed timestamp")
+	}
+	m.Hostname, m.AppName, m.ProcID, m.MsgID = header[2], header[3], header[4], header[5]
+	for _, field := range header[2:6] {
+		if field == "" {
+			return m, errors.Newf("malformed header: %q", msg)
+		}
+	}
+
+	rest := header[6]
+	m.StructuredData = make(map[string]map[string]string)
+	if strings.HasPrefix(rest, "-") {
+		rest = rest[1:]
+	} else {
+		for strings.HasPrefix(rest, "[") {
+			var id string
+			var params map[string]string
+			if id, params, rest, err = parseSyslogSDElement(rest); err != nil {
+				return m, err
+			}
+			m.StructuredData[id] = params
+		}
+		if len(m.StructuredData) == 0 {
+			return m, errors.Newf("malformed structured data: %q", msg)
+		}
+	}
+	if rest != "" {
+		if !strings.HasPrefix(rest, " ") {
+			return m, errors.Newf("malformed message: %q", msg)
+		}
+		m.Msg = []byte(rest[1:])
+	}
+	return m, nil
+}
+
+// parseSyslogSDElement parses the structured data element at the start of s,
+// returning its ID, its parameters and the remainder of s.
+func parseSyslogSDElement(s string) (id string, params map[string]string, rest string, _ error) {
+	s = s[1:]
+	idEnd := strings.IndexAny(s, " ]")
+	if idEnd <= 0 {
+		return "", nil, "", errors.Newf("malformed structured data element: %q", s)
+	}
+	id, s = s[:idEnd], s[idEnd:]
+	params = make(map[string]string)
+	for strings.HasPrefix(s, " ") {
+		eq := strings.Index(s, `="`)
+		if eq <= 1 {
+			return "", nil, "", errors.Newf("malformed structured data parameter: %q", s)
+		}
+		name := s[1:eq]
+		s = s[eq+2:]
+		var value strings.Builder
+		for {
+			if s == "" {
+				return "", nil, "", errors.Newf("unterminated value of parameter %q", name)
+			}
+			c := s[0]
+			s = s[1:]
+			if c == '"' {
+				break
+			}
+			if c == '\\' && s != "" && (s[0] == '"' || s[0] == '\\' || s[0] == ']') {
+				c = s[0]
+				s = s[1:]
+			}
+			value.WriteByte(c)
+		}
+		params[name] = value.String()
+	}
+	if !strings.HasPrefix(s, "]") {
+		return "", nil, "", errors.Newf("unterminated structured data element %q", id)
+	}
+	return id, params, s[1:], nil
+}
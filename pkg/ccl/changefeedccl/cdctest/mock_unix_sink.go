@@ -0,0 +1,156 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdctest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// UnixSinkMessage is a message received by MockUnixSink. Resolved timestamps
+// have an empty key.
+type UnixSinkMessage struct {
+	Topic string
+	Key   []byte
+	Value []byte
+}
+
+// MockUnixSink is a Unix domain socket server, receiving the length-delimited
+// frames written by the unix sink, used in tests.
+type MockUnixSink struct {
+	dir      string
+	listener net.Listener
+	wg       sync.WaitGroup
+	mu       struct {
+		syncutil.Mutex
+		conns  []net.Conn
+		rows   []UnixSinkMessage
+		notify chan struct{}
+	}
+}
+
+// StartMockUnixSink creates and starts a mock unix sink for tests, listening
+// on a socket in a new temporary directory.
+func StartMockUnixSink() (*MockUnixSink, error) {
+	dir, err := os.MkdirTemp("", "unix-sink")
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", filepath.Join(dir, "sink.sock"))
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	s := &MockUnixSink{dir: dir, listener: listener}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+// Path returns the path of the socket of this mock unix sink.
+func (s *MockUnixSink) Path() string {
+	return s.listener.Addr().String()
+}
+
+// Close closes the mock unix sink.
+func (s *MockUnixSink) Close() {
+	_ = s.listener.Close()
+	s.DropConnections()
+	s.wg.Wait()
+	_ = os.RemoveAll(s.dir)
+}
+
+// DropConnections closes the connections accepted so far, as a restarting
+// consumer would, while continuing to accept new ones.
+func (s *MockUnixSink) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.mu.conns {
+		_ = conn.Close()
+	}
+	s.mu.conns = nil
+}
+
+// Pop deletes and returns the oldest message from MockUnixSink, or false if
+// there are no messages.
+func (s *MockUnixSink) Pop() (UnixSinkMessage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.mu.rows) > 0 {
+		oldest := s.mu.rows[0]
+		s.mu.rows = s.mu.rows[1:]
+		return oldest, true
+	}
+	return UnixSinkMessage{}, false
+}
+
+// NotifyMessage arranges for channel to be closed when message arrives.
+func (s *MockUnixSink) NotifyMessage() chan struct{} {
+	c := make(chan struct{})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.mu.rows) > 0 {
+		close(c)
+	} else {
+		s.mu.notify = c
+	}
+	return c
+}
+
+func (s *MockUnixSink) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.mu.conns = append(s.mu.conns, conn)
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.serve(conn)
+	}
+}
+
+// serve reads the frames sent over the connection. A frame which is cut
+// short by the connection being closed is discarded.
+func (s *MockUnixSink) serve(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		var fields [3][]byte
+		for i := range fields {
+			var length [4]byte
+			if _, err := io.ReadFull(r, length[:]); err != nil {
+				return
+			}
+			fields[i] = make([]byte, binary.BigEndian.Uint32(length[:]))
+			if _, err := io.ReadFull(r, fields[i]); err != nil {
+				return
+			}
+		}
+		s.mu.Lock()
+		s.mu.rows = append(s.mu.rows, UnixSinkMessage{
+			Topic: string(fields[0]), Key: fields[1], Value: fields[2],
+		})
+		if s.mu.notify != nil {
+			close(s.mu.notify)
+			s.mu.notify = nil
+		}
+		s.mu.Unlock()
+	}
+}
@@ -44,6 +44,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metamorphic"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -86,6 +87,18 @@ func distChangefeedFlow(
 	opts := changefeedbase.MakeStatementOptions(details.Opts)
 	progress := localState.progress
 
+	// Emission is held by not starting the flow until the emit_after time.
+	// Once started, the flow emits everything since the high water.
+	if opts.HasEmitAfter() {
+		emitAfter, err := hlc.ParseHLC(opts.GetEmitAfter())
+		if err != nil {
+			return err
+		}
+		if err := waitForEmitAfter(ctx, execCtx.ExecCfg().Clock, emitAfter); err != nil {
+			return err
+		}
+	}
+
 	// NB: A non-empty high water indicates that we have checkpointed a resolved
 	// timestamp. Skipping the initial scan is equivalent to starting the
 	// changefeed from a checkpoint at its start time. Initialize the progress
@@ -224,6 +237,25 @@ func fetchSpansForTables(
 }
 
 // startDistChangefeed starts distributed changefeed execution.
+// waitForEmitAfter blocks until the clock reaches emitAfter.
+func waitForEmitAfter(ctx context.Context, clock *hlc.Clock, emitAfter hlc.Timestamp) error {
+	wait := emitAfter.GoTime().Sub(clock.PhysicalTime())
+	if wait <= 0 {
+		return nil
+	}
+	log.Infof(ctx, "holding changefeed emission until %s", emitAfter.GoTime())
+	var timer timeutil.Timer
+	defer timer.Stop()
+	timer.Reset(wait)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		timer.Read = true
+		return nil
+	}
+}
+
 func startDistChangefeed(
 	ctx context.Context,
 	execCtx sql.JobExecContext,
@@ -259,11 +291,30 @@ func startDistChangefeed(
 	evalCtx := execCtx.ExtendedEvalContext()
 
 	var checkpoint *jobspb.ChangefeedProgress_Checkpoint
-	if progress := localState.progress.GetChangefeed(); progress != nil && progress.Checkpoint != nil {
+	var topicSequences map[string]int64
+	var replaySpans []roachpb.Span
+	if progress := localState.progress.GetChangefeed(); progress != nil {
 		checkpoint = progress.Checkpoint
+		topicSequences = progress.TopicSequences
+		if progress.Replay != nil {
+			replaySpans = progress.Replay.Spans
+		}
+	}
+	if uri := details.Opts[changefeedbase.OptExternalCheckpoint]; uri != "" {
+		// The job record only holds the highwater; restore the rest of the
+		// frontier from the external checkpoint.
+		highWater, externalCheckpoint, err := restoreExternalCheckpoint(
+			ctx, execCfg, execCtx.User(), uri, jobID, trackedSpans, initialHighWater)
+		if err != nil {
+			return err
+		}
+		initialHighWater = highWater
+		if externalCheckpoint != nil {
+			checkpoint = externalCheckpoint
+		}
 	}
 	p, planCtx, err := makePlan(execCtx, jobID, details, initialHighWater,
-		trackedSpans, checkpoint, localState.drainingNodes)(ctx, dsp)
+		trackedSpans, checkpoint, topicSequences, replaySpans, localState.drainingNodes)(ctx, dsp)
 	if err != nil {
 		return err
 	}
@@ -372,6 +423,22 @@ var useBulkOracle = settings.RegisterBoolSetting(
 	"randomize the selection of which replica backs up each range",
 	true)
 
+// aggregatorMaxEmitRate returns the number of rows per second each of the
+// changefeed's aggregators may emit. The max_emit_rate option caps the rate of
+// the changefeed as a whole, so it is divided evenly among the aggregators,
+// each of which may emit at least one row per second. It returns zero if the
+// option isn't set.
+func aggregatorMaxEmitRate(details jobspb.ChangefeedDetails, numAggregators int) (int64, error) {
+	rate, ok, err := changefeedbase.MakeStatementOptions(details.Opts).GetMaxEmitRate()
+	if err != nil || !ok || numAggregators == 0 {
+		return 0, err
+	}
+	if perAggregator := int64(rate / numAggregators); perAggregator > 0 {
+		return perAggregator, nil
+	}
+	return 1, nil
+}
+
 func makePlan(
 	execCtx sql.JobExecContext,
 	jobID jobspb.JobID,
@@ -379,6 +446,8 @@ func makePlan(
 	initialHighWater hlc.Timestamp,
 	trackedSpans []roachpb.Span,
 	checkpoint *jobspb.ChangefeedProgress_Checkpoint,
+	topicSequences map[string]int64,
+	replaySpans []roachpb.Span,
 	drainingNodes []roachpb.NodeID,
 ) func(context.Context, *sql.DistSQLPlanner) (*sql.PhysicalPlan, *sql.PlanningCtx, error) {
 	return func(ctx context.Context, dsp *sql.DistSQLPlanner) (*sql.PhysicalPlan, *sql.PlanningCtx, error) {
@@ -391,6 +460,11 @@ func makePlan(
 			// Sinkless feeds get one ChangeAggregator on this node.
 			distMode = sql.LocalDistribution
 		}
+		if _, ok := details.Opts[changefeedbase.OptEmitSequence]; ok {
+			// Sequence numbers are assigned by the aggregator, so they can only
+			// be gapless if there is a single one.
+			distMode = sql.LocalDistribution
+		}
 
 		var locFilter roachpb.Locality
 		if loc := details.Opts[changefeedbase.OptExecutionLocality]; loc != "" {
@@ -461,6 +535,11 @@ func makePlan(
 			log.Infof(ctx, "aggregator checkpoint: %s", aggregatorCheckpoint)
 		}
 
+		maxEmitRate, err := aggregatorMaxEmitRate(details, len(spanPartitions))
+		if err != nil {
+			return nil, nil, err
+		}
+
 		aggregatorSpecs := make([]*execinfrapb.ChangeAggregatorSpec, len(spanPartitions))
 		for i, sp := range spanPartitions {
 			if log.ExpensiveLogEnabled(ctx, 2) {
@@ -479,12 +558,15 @@ func makePlan(
 			}
 
 			aggregatorSpecs[i] = &execinfrapb.ChangeAggregatorSpec{
-				Watches:    watches,
-				Checkpoint: aggregatorCheckpoint,
-				Feed:       details,
-				UserProto:  execCtx.User().EncodeProto(),
-				JobID:      jobID,
-				Select:     execinfrapb.Expression{Expr: details.Select},
+				Watches:        watches,
+				Checkpoint:     aggregatorCheckpoint,
+				Feed:           details,
+				UserProto:      execCtx.User().EncodeProto(),
+				JobID:          jobID,
+				Select:         execinfrapb.Expression{Expr: details.Select},
+				TopicSequences: topicSequences,
+				ReplaySpans:    replaySpans,
+				MaxEmitRate:    maxEmitRate,
 			}
 		}
 
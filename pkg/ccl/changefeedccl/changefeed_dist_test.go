@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/desctestutils"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
@@ -608,6 +609,75 @@ func TestChangefeedWithSimpleDistributionStrategyAndConstrainedLocality(t *testi
 	require.Equal(t, totalRanges, 64)
 }
 
+// TestChangefeedMaxEmitRateMultiNode verifies that the max_emit_rate of a
+// changefeed whose ranges are spread across several nodes is divided among
+// its aggregators, so that it caps the rate of the changefeed as a whole.
+func TestChangefeedMaxEmitRateMultiNode(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	skip.UnderDuress(t, "multinode setup")
+
+	specsCh := make(chan []*execinfrapb.ChangeAggregatorSpec, 1)
+	const nodes = 3
+	args := base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+		ServerArgs: base.TestServerArgs{
+			DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+			Knobs: base.TestingKnobs{
+				JobsTestingKnobs: jobs.NewTestingKnobsWithShortIntervals(),
+				DistSQL: &execinfra.TestingKnobs{
+					Changefeed: &TestingKnobs{
+						OnDistflowSpec: func(
+							aggregatorSpecs []*execinfrapb.ChangeAggregatorSpec, _ *execinfrapb.ChangeFrontierSpec,
+						) {
+							select {
+							case specsCh <- aggregatorSpecs:
+							default:
+							}
+						},
+					},
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, nodes, args)
+	defer tc.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+	sqlDB.Exec(t, "SET CLUSTER SETTING kv.rangefeed.enabled = true")
+	sqlDB.Exec(t, "SET CLUSTER SETTING changefeed.default_range_distribution_strategy = 'default'")
+	sqlDB.Exec(t, "SET CLUSTER SETTING changefeed.random_replica_selection.enabled = false")
+	sqlDB.ExecMultiple(t,
+		"CREATE TABLE x (id INT PRIMARY KEY)",
+		"INSERT INTO x SELECT generate_series(0, 2)",
+		"ALTER TABLE x SPLIT AT SELECT id FROM x WHERE id > 0",
+	)
+	// Place each range on its own node, so that each node runs an aggregator.
+	sqlDB.ExecSucceedsSoon(t, `ALTER TABLE x RELOCATE SELECT ARRAY[id+1], id FROM x`)
+
+	sqlDB.Exec(t, "CREATE CHANGEFEED FOR x INTO 'null://' WITH initial_scan='no', max_emit_rate='30'")
+	var specs []*execinfrapb.ChangeAggregatorSpec
+	testutils.SucceedsSoon(t, func() error {
+		select {
+		case specs = <-specsCh:
+			return nil
+		default:
+			return errors.New("no aggregator specs found")
+		}
+	})
+	require.Len(t, specs, nodes)
+	for _, spec := range specs {
+		require.EqualValues(t, 10, spec.MaxEmitRate)
+	}
+
+	// Each aggregator emits at least one row per second.
+	rate, err := aggregatorMaxEmitRate(specs[0].Feed, 31)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, rate)
+}
+
 // TestDistSenderAllRangeSpans tests (*distserver).AllRangeSpans.
 func TestDistSenderAllRangeSpans(t *testing.T) {
 	defer leaktest.AfterTest(t)()
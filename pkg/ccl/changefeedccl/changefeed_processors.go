@@ -77,6 +77,9 @@ type changeAggregator struct {
 	// sink is the Sink to write rows to. Resolved timestamps are never written
 	// by changeAggregator.
 	sink EventSink
+	// sequences, if non-nil, numbers the rows emitted to each topic. Its
+	// sequence numbers are reported along with resolved spans.
+	sequences *sequenceSink
 	// changedRowBuf, if non-nil, contains changed rows to be emitted. Anything
 	// queued in `resolvedSpanBuf` is dependent on these having been emitted, so
 	// this one must be empty before moving on to that one.
@@ -353,6 +356,24 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		ca.changedRowBuf = &b.buf
 	}
 
+	if opts.EmitSequence() {
+		encodingOpts, err := opts.GetEncodingOptions()
+		if err != nil {
+			ca.MoveToDraining(err)
+			ca.cancel()
+			return
+		}
+		topicNamer, err := MakeTopicNamer(AllTargets(ca.spec.Feed),
+			WithDeleteSuffix(encodingOpts.DeleteTopicSuffix))
+		if err != nil {
+			ca.MoveToDraining(err)
+			ca.cancel()
+			return
+		}
+		ca.sequences = newSequenceSink(ca.sink, topicNamer, ca.spec.TopicSequences)
+		ca.sink = ca.sequences
+	}
+
 	// If the initial scan was disabled the highwater would've already been forwarded
 	needsInitialScan := ca.frontier.Frontier().IsEmpty()
 
@@ -371,12 +392,27 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		pool = ca.knobs.MemMonitor
 	}
 	limit := changefeedbase.PerChangefeedMemLimit.Get(&ca.FlowCtx.Cfg.Settings.SV)
+	maxBuffer, ok, err := opts.GetMaxBuffer()
+	if err != nil {
+		ca.MoveToDraining(err)
+		ca.cancel()
+		return
+	}
+	if ok && maxBuffer < limit {
+		limit = maxBuffer
+	}
 	ca.eventProducer, ca.kvFeedDoneCh, ca.errCh, err = ca.startKVFeed(ctx, spans, kvFeedHighWater, needsInitialScan, feed, pool, limit, opts)
 	if err != nil {
 		ca.MoveToDraining(err)
 		ca.cancel()
 		return
 	}
+	if ca.spec.MaxEmitRate > 0 {
+		ca.sink = newRateLimitedSink(ca.sink, int(ca.spec.MaxEmitRate))
+	}
+	if opts.IsSet(changefeedbase.OptSortWithinFlush) {
+		ca.sink = newSortedFlushSink(ca.sink)
+	}
 	ca.sink = &errorWrapperSink{wrapped: ca.sink}
 	ca.eventConsumer, ca.sink, err = newEventConsumer(
 		ctx, ca.FlowCtx.Cfg, ca.spec, feed, ca.frontier, kvFeedHighWater,
@@ -405,10 +441,22 @@ func (ca *changeAggregator) startKVFeed(
 	opts changefeedbase.StatementOptions,
 ) (kvevent.Reader, chan struct{}, chan error, error) {
 	cfg := ca.FlowCtx.Cfg
+	delivery, err := opts.GetDelivery()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	kvFeedMemMon := mon.NewMonitorInheritWithLimit("kvFeed", memLimit, parentMemMon, false /* longLiving */)
 	kvFeedMemMon.StartNoReserved(ctx, parentMemMon)
-	buf := kvevent.NewThrottlingBuffer(
-		kvevent.NewMemBuffer(kvFeedMemMon.MakeBoundAccount(), &cfg.Settings.SV, &ca.metrics.KVFeedMetrics.AggregatorBufferMetricsWithCompat),
+	var memBuf kvevent.Buffer
+	if delivery == changefeedbase.OptDeliveryAtMostOnce {
+		memBuf = kvevent.NewDroppingMemBuffer(kvFeedMemMon.MakeBoundAccount(), &cfg.Settings.SV,
+			&ca.metrics.KVFeedMetrics.AggregatorBufferMetricsWithCompat, ca.sliMetrics.makeUnackedAllocCallback(),
+			ca.sliMetrics.makeDroppedMessagesCallback())
+	} else {
+		memBuf = kvevent.NewMemBufferWithAllocCallback(kvFeedMemMon.MakeBoundAccount(), &cfg.Settings.SV,
+			&ca.metrics.KVFeedMetrics.AggregatorBufferMetricsWithCompat, ca.sliMetrics.makeUnackedAllocCallback())
+	}
+	buf := kvevent.NewThrottlingBuffer(memBuf,
 		cdcutils.NodeLevelThrottler(&cfg.Settings.SV, &ca.metrics.ThrottleMetrics))
 
 	// KVFeed takes ownership of the kvevent.Writer portion of the buffer, while
@@ -476,28 +524,59 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		return kvfeed.Config{}, err
 	}
 
+	initialScanParallelism, _, err := config.Opts.GetInitialScanParallelism()
+	if err != nil {
+		return kvfeed.Config{}, err
+	}
+
+	initialScanConsistency, err := config.Opts.GetInitialScanConsistency()
+	if err != nil {
+		return kvfeed.Config{}, err
+	}
+
+	snapshotInterval, err := config.Opts.GetSnapshotInterval()
+	if err != nil {
+		return kvfeed.Config{}, err
+	}
+
+	var initialScanAt hlc.Timestamp
+	if config.Opts.HasInitialScanAt() {
+		initialScanAt, err = hlc.ParseHLC(config.Opts.GetInitialScanAt())
+		if err != nil {
+			return kvfeed.Config{}, err
+		}
+	}
+
 	return kvfeed.Config{
-		Writer:              buf,
-		Settings:            cfg.Settings,
-		DB:                  cfg.DB.KV(),
-		Codec:               cfg.Codec,
-		Clock:               cfg.DB.KV().Clock(),
-		Spans:               spans,
-		CheckpointSpans:     ca.spec.Checkpoint.Spans,
-		CheckpointTimestamp: ca.spec.Checkpoint.Timestamp,
-		Targets:             AllTargets(ca.spec.Feed),
-		Metrics:             &ca.metrics.KVFeedMetrics,
-		MM:                  memMon,
-		InitialHighWater:    initialHighWater,
-		EndTime:             config.EndTime,
-		WithDiff:            filters.WithDiff,
-		WithFiltering:       filters.WithFiltering,
-		NeedsInitialScan:    needsInitialScan,
-		SchemaChangeEvents:  schemaChange.EventClass,
-		SchemaChangePolicy:  schemaChange.Policy,
-		SchemaFeed:          sf,
-		Knobs:               ca.knobs.FeedKnobs,
-		MonitoringCfg:       monitoringCfg,
+		Writer:                   buf,
+		Settings:                 cfg.Settings,
+		DB:                       cfg.DB.KV(),
+		Codec:                    cfg.Codec,
+		Clock:                    cfg.DB.KV().Clock(),
+		Spans:                    spans,
+		CheckpointSpans:          ca.spec.Checkpoint.Spans,
+		CheckpointTimestamp:      ca.spec.Checkpoint.Timestamp,
+		Targets:                  AllTargets(ca.spec.Feed),
+		Metrics:                  &ca.metrics.KVFeedMetrics,
+		MM:                       memMon,
+		InitialHighWater:         initialHighWater,
+		EndTime:                  config.EndTime,
+		WithDiff:                 filters.WithDiff,
+		WithFiltering:            filters.WithFiltering,
+		ValueOnDelete:            filters.ValueOnDelete,
+		NeedsInitialScan:         needsInitialScan,
+		InitialScanParallelism:   initialScanParallelism,
+		InitialScanFollowerReads: initialScanConsistency == changefeedbase.OptInitialScanConsistencyFollower,
+		InitialScanAt:            initialScanAt,
+		ReplaySpans:              ca.spec.ReplaySpans,
+		SnapshotInterval:         snapshotInterval,
+		DDLOnly:                  config.Opts.DDLOnly(),
+		EmitBatchMarkers:         config.Opts.EmitBatchMarkers(),
+		SchemaChangeEvents:       schemaChange.EventClass,
+		SchemaChangePolicy:       schemaChange.Policy,
+		SchemaFeed:               sf,
+		Knobs:                    ca.knobs.FeedKnobs,
+		MonitoringCfg:            monitoringCfg,
 	}, nil
 }
 
@@ -518,6 +597,8 @@ func makeKVFeedMonitoringCfg(
 
 		OnBackfillCallback:      sliMetrics.getBackfillCallback(),
 		OnBackfillRangeCallback: sliMetrics.getBackfillRangeCallback(),
+
+		OnSchemaChangeBackfillCallback: sliMetrics.getSchemaChangeBackfillCallback(),
 	}, nil
 }
 
@@ -896,6 +977,11 @@ func (ca *changeAggregator) emitResolved(batch jobspb.ResolvedSpans) error {
 			RecentKvCount: ca.recentKVCount,
 		},
 	}
+	if ca.sequences != nil {
+		// The sink has been flushed, so every sequence number has been
+		// delivered.
+		progressUpdate.TopicSequences = ca.sequences.Sequences()
+	}
 	updateBytes, err := protoutil.Marshal(&progressUpdate)
 	if err != nil {
 		return err
@@ -958,6 +1044,17 @@ type changeFrontier struct {
 	freqEmitResolved time.Duration
 	// lastEmitResolved is the last time a resolved timestamp was emitted.
 	lastEmitResolved time.Time
+	// emitResolvedOnCatchup, if set, restricts resolved timestamp emits to
+	// when the frontier catches up to the current time after having been
+	// behind. caughtUp tracks whether the frontier has caught up.
+	emitResolvedOnCatchup bool
+	caughtUp              bool
+	// sinkStatus, if non-nil, is the status of the changefeed's sink published
+	// by the job's resumer, which is updated whenever the sinks are flushed.
+	sinkStatus *sinkStatus
+	// externalCheckpoint, if non-nil, writes the full frontier to external
+	// storage whenever the job progress is checkpointed.
+	externalCheckpoint *externalCheckpointWriter
 
 	// lastProtectedTimestampUpdate is the last time the protected timestamp
 	// record was updated to the frontier's highwater mark
@@ -991,6 +1088,32 @@ type changeFrontier struct {
 
 	usageWg       sync.WaitGroup
 	usageWgCancel context.CancelFunc
+
+	// checkpointRequests receives the checkpoints requested out of band (see
+	// requestCheckpoint). They are served by the processor's goroutine, between
+	// the rows it reads from the aggregators, so that they never race with the
+	// checkpoints written by the processor itself.
+	checkpointRequests chan checkpointRequest
+	// stopped is closed once the processor stops serving checkpoint requests.
+	stopped  chan struct{}
+	stopOnce sync.Once
+	// topicSequences, if non-nil, is the last sequence number emitted to each
+	// topic, as last reported by the aggregator. It's persisted in the job
+	// progress along with the highwater.
+	topicSequences map[string]int64
+}
+
+// checkpointRequest is a request for a changeFrontier to checkpoint its
+// frontier immediately. The result is sent on reply, which must be buffered.
+type checkpointRequest struct {
+	reply chan checkpointResult
+}
+
+// checkpointResult is the result of a checkpointRequest: the highwater which
+// was persisted, or the error which prevented the checkpoint.
+type checkpointResult struct {
+	highWater hlc.Timestamp
+	err       error
 }
 
 const (
@@ -1050,6 +1173,21 @@ func (cs *cachedState) SetCheckpoint(spans []roachpb.Span, timestamp hlc.Timesta
 	}
 }
 
+// SetTopicSequences sets the last sequence number emitted to each topic.
+func (cs *cachedState) SetTopicSequences(sequences map[string]int64) {
+	changefeedProgress := cs.progress.Details.(*jobspb.Progress_Changefeed).Changefeed
+	changefeedProgress.TopicSequences = sequences
+}
+
+// maybeClearReplay clears the spans to replay once the high-water has advanced
+// past the time they were re-scanned at, since they have been re-emitted by
+// then.
+func maybeClearReplay(progress *jobspb.ChangefeedProgress, highWater hlc.Timestamp) {
+	if progress != nil && progress.Replay != nil && progress.Replay.Timestamp.Less(highWater) {
+		progress.Replay = nil
+	}
+}
+
 func newJobState(
 	j *jobs.Job, st *cluster.Settings, metrics *Metrics, ts timeutil.TimeSource,
 ) *jobState {
@@ -1145,6 +1283,9 @@ func newChangeFrontierProcessor(
 		input:         input,
 		frontier:      sf,
 		usageWgCancel: func() {},
+
+		checkpointRequests: make(chan checkpointRequest),
+		stopped:            make(chan struct{}),
 	}
 
 	if cfKnobs, ok := flowCtx.TestingKnobs().Changefeed.(*TestingKnobs); ok {
@@ -1186,6 +1327,7 @@ func newChangeFrontierProcessor(
 	} else {
 		cf.freqEmitResolved = emitNoResolved
 	}
+	cf.emitResolvedOnCatchup = opts.IsResolvedOnCatchup()
 
 	encodingOpts, err := opts.GetEncodingOptions()
 	if err != nil {
@@ -1269,6 +1411,14 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 			return
 		}
 		cf.js.job = job
+		if uri := cf.spec.Feed.Opts[changefeedbase.OptExternalCheckpoint]; uri != "" {
+			es, err := cf.FlowCtx.Cfg.ExternalStorageFromURI(ctx, uri, cf.spec.User())
+			if err != nil {
+				cf.MoveToDraining(changefeedbase.MarkRetryableError(err))
+				return
+			}
+			cf.externalCheckpoint = startExternalCheckpointWriter(ctx, es, cf.spec.JobID)
+		}
 		if changefeedbase.FrontierCheckpointFrequency.Get(&cf.FlowCtx.Cfg.Settings.SV) == 0 {
 			log.Warning(ctx,
 				"Frontier checkpointing disabled; set changefeed.frontier_checkpoint_frequency to non-zero value to re-enable")
@@ -1299,6 +1449,11 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 			cf.js.lastRunStatusUpdate = timeutil.Now()
 		}
 
+		// Make this frontier reachable by builtins which need to interact with
+		// a running changefeed.
+		registerRunningFrontier(cf)
+		cf.sinkStatus = lookupSinkStatus(cf.spec.JobID)
+
 		// Start the usage metric reporting goroutine.
 		usageCtx, usageCancel := context.WithCancel(ctx)
 		cf.usageWgCancel = usageCancel
@@ -1329,9 +1484,17 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 	go func() {
 		<-ctx.Done()
 		cf.closeMetrics()
+		unregisterRunningFrontier(cf)
+		cf.stopServingCheckpointRequests()
 	}()
 }
 
+// stopServingCheckpointRequests unblocks the callers of requestCheckpoint once
+// the processor stops. It is idempotent.
+func (cf *changeFrontier) stopServingCheckpointRequests() {
+	cf.stopOnce.Do(func() { close(cf.stopped) })
+}
+
 func (cf *changeFrontier) runUsageMetricReporting(ctx context.Context) {
 	if cf.spec.JobID == 0 { // don't report for core (non-enterprise) changefeeds
 		return
@@ -1395,6 +1558,8 @@ func (cf *changeFrontier) close() {
 	// we can use a span after it's finished.
 	cf.usageWgCancel()
 	cf.usageWg.Wait()
+	unregisterRunningFrontier(cf)
+	cf.stopServingCheckpointRequests()
 
 	if cf.InternalClose() {
 		if cf.metrics != nil {
@@ -1404,6 +1569,9 @@ func (cf *changeFrontier) close() {
 			// Best effort: context is often cancel by now, so we expect to see an error
 			_ = cf.sink.Close()
 		}
+		if cf.externalCheckpoint != nil {
+			_ = cf.externalCheckpoint.close(cf.Ctx())
+		}
 		cf.memAcc.Close(cf.Ctx())
 		cf.MemMonitor.Stop(cf.Ctx())
 	}
@@ -1463,6 +1631,8 @@ func (cf *changeFrontier) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetad
 			break
 		}
 
+		cf.serveCheckpointRequests()
+
 		row, meta := cf.input.Next()
 		if meta != nil {
 			if meta.Err != nil {
@@ -1516,6 +1686,13 @@ func (cf *changeFrontier) noteAggregatorProgress(d rowenc.EncDatum) error {
 
 	cf.maybeMarkJobIdle(resolvedSpans.Stats.RecentKvCount)
 
+	if resolvedSpans.TopicSequences != nil {
+		// The aggregator flushed its sink before reporting these resolved
+		// spans, so the sequence numbers can be persisted by any checkpoint
+		// they trigger.
+		cf.topicSequences = resolvedSpans.TopicSequences
+	}
+
 	for _, resolved := range resolvedSpans.ResolvedSpans {
 		// Inserting a timestamp less than the one the changefeed flow started at
 		// could potentially regress the job progress. This is not expected, but it
@@ -1535,6 +1712,10 @@ func (cf *changeFrontier) noteAggregatorProgress(d rowenc.EncDatum) error {
 		}
 	}
 
+	// Aggregators flush their sinks before reporting progress.
+	if cf.sinkStatus != nil {
+		cf.sinkStatus.recordEmit(timeutil.Now())
+	}
 	return nil
 }
 
@@ -1641,6 +1822,73 @@ func (cf *changeFrontier) maybeCheckpointJob(
 	return false, nil
 }
 
+// requestCheckpoint asks the processor to checkpoint its frontier immediately,
+// and waits for it to do so. It returns the highwater that was persisted. It
+// may be called from any goroutine. The request is served the next time the
+// processor reads from the aggregators, which report their progress
+// regularly.
+func (cf *changeFrontier) requestCheckpoint(ctx context.Context) (hlc.Timestamp, error) {
+	req := checkpointRequest{reply: make(chan checkpointResult, 1)}
+	select {
+	case cf.checkpointRequests <- req:
+	case <-cf.stopped:
+		return hlc.Timestamp{}, errors.Newf("changefeed %d stopped before it could checkpoint", cf.spec.JobID)
+	case <-ctx.Done():
+		return hlc.Timestamp{}, ctx.Err()
+	}
+	select {
+	case res := <-req.reply:
+		return res.highWater, res.err
+	case <-cf.stopped:
+		return hlc.Timestamp{}, errors.Newf("changefeed %d stopped before it could checkpoint", cf.spec.JobID)
+	case <-ctx.Done():
+		return hlc.Timestamp{}, ctx.Err()
+	}
+}
+
+// serveCheckpointRequests serves the pending checkpoint requests, if any,
+// without blocking.
+func (cf *changeFrontier) serveCheckpointRequests() {
+	for {
+		select {
+		case req := <-cf.checkpointRequests:
+			highWater, err := cf.checkpointNow(cf.Ctx())
+			req.reply <- checkpointResult{highWater: highWater, err: err}
+		default:
+			return
+		}
+	}
+}
+
+// checkpointNow persists the current frontier, along with any spans leading
+// it, to the job record immediately, bypassing the throttling applied to
+// regular checkpoints. It returns the highwater that was persisted. It must
+// only be called from the processor's goroutine.
+func (cf *changeFrontier) checkpointNow(ctx context.Context) (hlc.Timestamp, error) {
+	if cf.js.job == nil {
+		return hlc.Timestamp{}, errors.AssertionFailedf("changefeed has no job to checkpoint")
+	}
+
+	// The frontier only moves forward, and it is only read and persisted by
+	// the processor's goroutine, so the persisted highwater can never regress.
+	frontier := cf.frontier.Frontier()
+	if cf.knobs.ShouldCheckpointToJobRecord != nil && !cf.knobs.ShouldCheckpointToJobRecord(frontier) {
+		return hlc.Timestamp{}, errors.Newf("checkpointing of changefeed %d is currently disabled", cf.spec.JobID)
+	}
+
+	var checkpoint jobspb.ChangefeedProgress_Checkpoint
+	maxBytes := changefeedbase.FrontierCheckpointMaxBytes.Get(&cf.FlowCtx.Cfg.Settings.SV)
+	checkpoint.Spans, checkpoint.Timestamp = cf.frontier.getCheckpointSpans(maxBytes)
+
+	checkpointStart := timeutil.Now()
+	if _, err := cf.checkpointJobProgress(frontier, checkpoint); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	cf.js.checkpointCompleted(ctx, timeutil.Since(checkpointStart))
+	log.Infof(ctx, "changefeed %d checkpointed highwater %s on request", cf.spec.JobID, frontier)
+	return frontier, nil
+}
+
 func (cf *changeFrontier) checkpointJobProgress(
 	frontier hlc.Timestamp, checkpoint jobspb.ChangefeedProgress_Checkpoint,
 ) (bool, error) {
@@ -1657,6 +1905,16 @@ func (cf *changeFrontier) checkpointJobProgress(
 	}
 	cf.metrics.FrontierUpdates.Inc(1)
 	if cf.js.job != nil {
+		if cf.externalCheckpoint != nil {
+			// Write the full frontier out of band, and keep the job record down to
+			// the highwater. Spans are only resolved once they are flushed to the
+			// sink, so it's fine for the external checkpoint to be ahead of the job
+			// record if the update below fails.
+			if err := cf.externalCheckpoint.submit(cf.frontier.Entries); err != nil {
+				return false, err
+			}
+			checkpoint = jobspb.ChangefeedProgress_Checkpoint{}
+		}
 		if err := cf.js.job.NoTxn().Update(cf.Ctx(), func(
 			txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
 		) error {
@@ -1672,6 +1930,10 @@ func (cf *changeFrontier) checkpointJobProgress(
 
 			changefeedProgress := progress.Details.(*jobspb.Progress_Changefeed).Changefeed
 			changefeedProgress.Checkpoint = &checkpoint
+			if cf.topicSequences != nil {
+				changefeedProgress.TopicSequences = cf.topicSequences
+			}
+			maybeClearReplay(changefeedProgress, frontier)
 
 			if err := cf.manageProtectedTimestamps(cf.Ctx(), txn, changefeedProgress); err != nil {
 				log.Warningf(cf.Ctx(), "error managing protected timestamp record: %v", err)
@@ -1703,6 +1965,10 @@ func (cf *changeFrontier) checkpointJobProgress(
 
 	cf.localState.SetHighwater(frontier)
 	cf.localState.SetCheckpoint(checkpoint.Spans, checkpoint.Timestamp)
+	if cf.topicSequences != nil {
+		cf.localState.SetTopicSequences(cf.topicSequences)
+	}
+	maybeClearReplay(cf.localState.progress.GetChangefeed(), frontier)
 
 	return true, nil
 }
@@ -1785,6 +2051,13 @@ func (cf *changeFrontier) maybeEmitResolved(newResolved hlc.Timestamp) error {
 	}
 	sinceEmitted := newResolved.GoTime().Sub(cf.lastEmitResolved)
 	shouldEmit := sinceEmitted >= cf.freqEmitResolved || cf.frontier.schemaChangeBoundaryReached()
+	if cf.emitResolvedOnCatchup {
+		// Only emit when the frontier transitions from being behind to being
+		// within the catchup threshold of the current time.
+		wasCaughtUp := cf.caughtUp
+		cf.caughtUp = timeutil.Since(newResolved.GoTime()) <= catchupThreshold(&cf.FlowCtx.Cfg.Settings.SV)
+		shouldEmit = (cf.caughtUp && !wasCaughtUp) || cf.frontier.schemaChangeBoundaryReached()
+	}
 	if !shouldEmit {
 		return nil
 	}
@@ -1843,7 +2116,19 @@ func slownessThreshold(sv *settings.Values) time.Duration {
 	if clusterThreshold > 0 {
 		return clusterThreshold
 	}
+	return defaultSlownessThreshold(sv)
+}
+
+// catchupThreshold returns how far behind the current time the frontier of a
+// resolved='on_catchup' changefeed may be while being considered caught up.
+func catchupThreshold(sv *settings.Values) time.Duration {
+	if threshold := changefeedbase.ResolvedOnCatchupThreshold.Get(sv); threshold > 0 {
+		return threshold
+	}
+	return defaultSlownessThreshold(sv)
+}
 
+func defaultSlownessThreshold(sv *settings.Values) time.Duration {
 	// These two cluster setting values represent the target
 	// responsiveness of schemafeed and rangefeed.
 	//
@@ -40,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
@@ -443,6 +444,24 @@ func createChangefeedJobRecord(
 		endTime = asOf.Timestamp
 	}
 
+	if opts.HasInitialScanAt() {
+		asOfClause := tree.AsOfClause{Expr: tree.NewStrVal(opts.GetInitialScanAt())}
+		asOf, err := asof.Eval(ctx, asOfClause, p.SemaCtx(), &p.ExtendedEvalContext().Context)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetInitialScanAt(asOf.Timestamp.AsOfSystemTime())
+	}
+
+	if opts.HasEmitAfter() {
+		asOfClause := tree.AsOfClause{Expr: tree.NewStrVal(opts.GetEmitAfter())}
+		asOf, err := asof.Eval(ctx, asOfClause, p.SemaCtx(), &p.ExtendedEvalContext().Context)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetEmitAfter(asOf.Timestamp.AsOfSystemTime())
+	}
+
 	{
 		initialScanType, err := opts.GetInitialScanType()
 		if err != nil {
@@ -466,6 +485,39 @@ func createChangefeedJobRecord(
 		return nil, err
 	}
 
+	// A changefeed on a view which is a simple projection of a single table is
+	// rewritten into a changefeed expression on that table.
+	if changefeedStmt.Select == nil {
+		for _, desc := range targetDescs {
+			view, ok := desc.(catalog.TableDescriptor)
+			if !ok || !view.IsView() {
+				continue
+			}
+			if len(changefeedStmt.Targets) != 1 {
+				return nil, errors.Errorf(
+					`CHANGEFEED on view %s cannot have other targets`, view.GetName())
+			}
+			target, sc, err := viewToChangefeedExpression(view)
+			if err != nil {
+				return nil, err
+			}
+			rewritten := *changefeedStmt.CreateChangefeed
+			rewritten.Targets = tree.ChangefeedTargets{target}
+			rewritten.Select = sc
+			annotated := *changefeedStmt
+			annotated.CreateChangefeed = &rewritten
+			changefeedStmt = &annotated
+
+			tableOnlyTargetList = tree.BackupTargetList{}
+			tableOnlyTargetList.Tables.TablePatterns = tree.TablePatterns{target.TableName}
+			targetDescs, err = getTableDescriptors(ctx, p, &tableOnlyTargetList, statementTime, initialHighWater)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
 	for _, t := range targetDescs {
 		if tbl, ok := t.(catalog.TableDescriptor); ok && tbl.ExternalRowData() != nil {
 			if tbl.ExternalRowData().TenantID.IsSet() {
@@ -494,6 +546,11 @@ func createChangefeedJobRecord(
 		SessionData:          &sd.SessionData,
 	}
 
+	var partitionColumn string
+	if format, ok := opts.GetPartitionFormat(); ok {
+		partitionColumn, _ = changefeedbase.ParsePartitionFormatColumn(format)
+	}
+
 	specs := AllTargets(details)
 	hasSelectPrivOnAllTables := true
 	hasChangefeedPrivOnAllTables := true
@@ -502,6 +559,11 @@ func createChangefeedJobRecord(
 			if err := changefeedvalidators.ValidateTable(specs, table, tolerances); err != nil {
 				return nil, err
 			}
+			if partitionColumn != "" && catalog.FindColumnByName(table, partitionColumn) == nil {
+				return nil, pgerror.Newf(pgcode.UndefinedColumn,
+					`option %s references column %s, which does not exist in table %s`,
+					changefeedbase.OptPartitionFormat, partitionColumn, table.GetName())
+			}
 			for _, warning := range changefeedvalidators.WarningsForTable(table, tolerances) {
 				p.BufferClientNotice(ctx, pgnotice.Newf("%s", warning))
 			}
@@ -781,6 +843,56 @@ func validateSettings(ctx context.Context, needsRangeFeed bool, execCfg *sql.Exe
 	return nil
 }
 
+// viewToChangefeedExpression returns the target and changefeed expression
+// equivalent to the given view. Only views that project and filter the rows
+// of a single table can be streamed; views which join, aggregate, or order
+// their rows are rejected.
+func viewToChangefeedExpression(
+	view catalog.TableDescriptor,
+) (tree.ChangefeedTarget, *tree.SelectClause, error) {
+	notStreamable := func(reason string) error {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			`CHANGEFEED cannot target view %s: %s`, view.GetName(), reason)
+	}
+
+	stmt, err := parser.ParseOne(view.GetViewQuery())
+	if err != nil {
+		return tree.ChangefeedTarget{}, nil, err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	for ok {
+		if sel.With != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Locking != nil {
+			return tree.ChangefeedTarget{}, nil, notStreamable(
+				"WITH, ORDER BY, LIMIT and locking clauses are not supported")
+		}
+		paren, isParen := sel.Select.(*tree.ParenSelect)
+		if !isParen {
+			break
+		}
+		sel = paren.Select
+	}
+	if !ok {
+		return tree.ChangefeedTarget{}, nil, notStreamable("view query is not a SELECT")
+	}
+	sc, ok := sel.Select.(*tree.SelectClause)
+	if !ok {
+		return tree.ChangefeedTarget{}, nil, notStreamable("set operations are not supported")
+	}
+	if sc.Distinct || sc.DistinctOn != nil || sc.GroupBy != nil || sc.Having != nil || sc.Window != nil {
+		return tree.ChangefeedTarget{}, nil, notStreamable(
+			"DISTINCT, GROUP BY, HAVING and window clauses are not supported")
+	}
+	if len(sc.From.Tables) != 1 {
+		return tree.ChangefeedTarget{}, nil, notStreamable("views on multiple tables are not supported")
+	}
+	target, err := tree.ChangefeedTargetFromTableExpr(sc.From.Tables[0])
+	if err != nil {
+		return tree.ChangefeedTarget{}, nil, notStreamable(
+			"only views which select from a single table are supported")
+	}
+	return target, sc, nil
+}
+
 func getTableDescriptors(
 	ctx context.Context,
 	p sql.PlanHookState,
@@ -965,9 +1077,29 @@ func validateSink(
 		}
 		opts.SetTopics(topics)
 	}
+	if opts.IsSet(changefeedbase.OptKeyTablePrefix) && !emitsToSingleTopic(canarySink) {
+		return errors.Newf(`%s is only usable with sinks which emit all tables to a single topic`,
+			changefeedbase.OptKeyTablePrefix)
+	}
 	return nil
 }
 
+// emitsToSingleTopic returns true if the sink emits the rows of all of the
+// watched tables to the same topic or endpoint, so that their keys cannot
+// be told apart by topic.
+func emitsToSingleTopic(s Sink) bool {
+	switch s.getConcreteType() {
+	case sinkTypeWebhook:
+		return true
+	case sinkTypeCloudstorage:
+		return false
+	}
+	if sink, ok := s.(SinkWithTopics); ok {
+		return len(sink.Topics()) == 1
+	}
+	return true
+}
+
 func requiresKeyInValue(s Sink) bool {
 	switch s.getConcreteType() {
 	case sinkTypeCloudstorage, sinkTypeWebhook:
@@ -1048,6 +1180,158 @@ func validateDetailsAndOptions(
 		}
 	}
 
+	if opts.HasInitialScanAt() {
+		scanType, err := opts.GetInitialScanType()
+		if err != nil {
+			return err
+		}
+		if scanType == changefeedbase.NoInitialScan {
+			return errors.Errorf(
+				`cannot specify %s without an initial scan`, changefeedbase.OptInitialScanAt)
+		}
+	}
+
+	if opts.IsSet(changefeedbase.OptInitialScanConsistency) {
+		if _, err := opts.GetInitialScanConsistency(); err != nil {
+			return err
+		}
+		scanType, err := opts.GetInitialScanType()
+		if err != nil {
+			return err
+		}
+		if scanType == changefeedbase.NoInitialScan {
+			return errors.Errorf(
+				`cannot specify %s without an initial scan`, changefeedbase.OptInitialScanConsistency)
+		}
+	}
+
+	if opts.IsSet(changefeedbase.OptExternalCheckpoint) && details.SinkURI == `` {
+		return errors.Errorf(`%s is not supported by sinkless changefeeds`,
+			changefeedbase.OptExternalCheckpoint)
+	}
+
+	if _, _, err := opts.GetMaxEmitRate(); err != nil {
+		return err
+	}
+	if _, err := opts.GetDelivery(); err != nil {
+		return err
+	}
+	if _, _, err := opts.GetMaxBuffer(); err != nil {
+		return err
+	}
+	if _, err := opts.GetRetryBudgets(); err != nil {
+		return err
+	}
+
+	encodingOpts, err := opts.GetEncodingOptions()
+	if err != nil {
+		return err
+	}
+	if encodingOpts.Checksum != `` && !emitsChecksumHeader(details.SinkURI) &&
+		(encodingOpts.Format != changefeedbase.OptFormatJSON ||
+			encodingOpts.Envelope != changefeedbase.OptEnvelopeWrapped) {
+		return errors.Errorf(`%s is only usable with a kafka sink, or with %s=%s and %s=%s`,
+			changefeedbase.OptEmitChecksum, changefeedbase.OptFormat, changefeedbase.OptFormatJSON,
+			changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
+
+	onFilterError, err := opts.GetOnFilterError()
+	if err != nil {
+		return err
+	}
+	if onFilterError == changefeedbase.OptOnFilterErrorSkip && details.Select == "" {
+		return errors.Errorf(`%s='%s' is only usable with CREATE CHANGEFEED ... AS SELECT ...`,
+			changefeedbase.OptOnFilterError, changefeedbase.OptOnFilterErrorSkip)
+	}
+
+	if opts.DDLOnly() {
+		if details.Select != "" {
+			return errors.Errorf(`%s is not supported with CREATE CHANGEFEED ... AS SELECT ...`,
+				changefeedbase.OptDDLOnly)
+		}
+		if opts.IsSet(changefeedbase.OptSnapshotInterval) {
+			return errors.Errorf(`cannot specify both %s and %s`,
+				changefeedbase.OptDDLOnly, changefeedbase.OptSnapshotInterval)
+		}
+		encodingOpts, err := opts.GetEncodingOptions()
+		if err != nil {
+			return err
+		}
+		if encodingOpts.Format != changefeedbase.OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`,
+				changefeedbase.OptDDLOnly, changefeedbase.OptFormat, changefeedbase.OptFormatJSON)
+		}
+		schemaChange, err := opts.GetSchemaChangeHandlingOptions()
+		if err != nil {
+			return err
+		}
+		if schemaChange.Policy == changefeedbase.OptSchemaChangePolicyIgnore {
+			return errors.Errorf(`%s is not usable with %s=%s`, changefeedbase.OptDDLOnly,
+				changefeedbase.OptSchemaChangePolicy, changefeedbase.OptSchemaChangePolicyIgnore)
+		}
+	}
+
+	if opts.EmitBatchMarkers() {
+		if opts.DDLOnly() {
+			return errors.Errorf(`cannot specify both %s and %s`,
+				changefeedbase.OptEmitBatchMarkers, changefeedbase.OptDDLOnly)
+		}
+		encodingOpts, err := opts.GetEncodingOptions()
+		if err != nil {
+			return err
+		}
+		if encodingOpts.Format != changefeedbase.OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`,
+				changefeedbase.OptEmitBatchMarkers, changefeedbase.OptFormat, changefeedbase.OptFormatJSON)
+		}
+	}
+
+	if opts.EmitSchemaPreamble() {
+		encodingOpts, err := opts.GetEncodingOptions()
+		if err != nil {
+			return err
+		}
+		if encodingOpts.Format != changefeedbase.OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`,
+				changefeedbase.OptEmitSchemaPreamble, changefeedbase.OptFormat, changefeedbase.OptFormatJSON)
+		}
+	}
+
+	if opts.EmitSchemaFingerprint() && !opts.EmitSchemaPreamble() && !opts.DDLOnly() {
+		return errors.Errorf(`%s is only usable with %s or %s`,
+			changefeedbase.OptEmitSchemaFingerprint, changefeedbase.OptEmitSchemaPreamble,
+			changefeedbase.OptDDLOnly)
+	}
+
+	if opts.EmitSequence() {
+		encodingOpts, err := opts.GetEncodingOptions()
+		if err != nil {
+			return err
+		}
+		if encodingOpts.Format != changefeedbase.OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`,
+				changefeedbase.OptEmitSequence, changefeedbase.OptFormat, changefeedbase.OptFormatJSON)
+		}
+		if encodingOpts.Envelope != changefeedbase.OptEnvelopeWrapped {
+			return errors.Errorf(`%s is only usable with %s=%s`,
+				changefeedbase.OptEmitSequence, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+		}
+		if encodingOpts.Tombstone == changefeedbase.OptTombstoneNull {
+			return errors.Errorf(`%s is not usable with %s=%s`,
+				changefeedbase.OptEmitSequence, changefeedbase.OptTombstone, changefeedbase.OptTombstoneNull)
+		}
+	}
+
+	if opts.IsSet(changefeedbase.OptTopicOverride) {
+		encodingOpts, err := opts.GetEncodingOptions()
+		if err != nil {
+			return err
+		}
+		if err := validateTopicOverride(encodingOpts, AllTargets(details)); err != nil {
+			return err
+		}
+	}
+
 	{
 		if details.Select != "" {
 			if len(details.TargetSpecifications) != 1 {
@@ -1059,6 +1343,36 @@ func validateDetailsAndOptions(
 	return nil
 }
 
+// validateTopicOverride checks that every table mapped by the topic_override
+// option is watched by the changefeed.
+func validateTopicOverride(
+	encodingOpts changefeedbase.EncodingOptions, targets changefeedbase.Targets,
+) error {
+	overrides, err := changefeedbase.ParseTopicOverride(encodingOpts.TopicOverride)
+	if err != nil {
+		return err
+	}
+	watched := make(map[string]struct{})
+	if err := targets.EachTarget(func(t changefeedbase.Target) error {
+		watched[string(t.StatementTimeName)] = struct{}{}
+		return nil
+	}); err != nil {
+		return err
+	}
+	tables := make([]string, 0, len(overrides))
+	for table := range overrides {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		if _, ok := watched[table]; !ok {
+			return errors.Errorf(`option %s references table %s, which is not watched by the changefeed`,
+				changefeedbase.OptTopicOverride, table)
+		}
+	}
+	return nil
+}
+
 // validateAndNormalizeChangefeedExpression validates and normalizes changefeed expressions.
 // This method modifies passed in select clause to reflect normalization step.
 // TODO(yevgeniy): Add virtual column support.
@@ -1213,22 +1527,34 @@ func (b *changefeedResumer) handleChangefeedError(
 		// note: we only want the job to pause here if a failure happens, not a
 		// user-initiated cancellation. if the job has been canceled, the ctx
 		// will handle it and the pause will return an error.
-		const errorFmt = "job failed (%v) but is being paused because of %s=%s"
-		errorMessage := fmt.Sprintf(errorFmt, changefeedErr,
-			changefeedbase.OptOnError, changefeedbase.OptOnErrorPause)
-		return b.job.NoTxn().PauseRequestedWithFunc(ctx, func(ctx context.Context, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+		// The pause goes through the registry so that OnPauseRequest runs and
+		// releases the protected timestamp record like a user-initiated pause.
+		errorMessage := pauseOnErrorMessage(changefeedErr)
+		registry := jobExec.ExecCfg().JobRegistry
+		return b.job.NoTxn().Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
 			// directly update running status to avoid the running/reverted job status check
 			md.Progress.RunningStatus = errorMessage
 			ju.UpdateProgress(md.Progress)
-			log.Warningf(ctx, errorFmt, changefeedErr, changefeedbase.OptOnError, changefeedbase.OptOnErrorPause)
-			return nil
-		}, errorMessage)
+			log.Warningf(ctx, "%s", errorMessage)
+			return registry.PauseRequestedWithHook(ctx, txn, md, ju, errorMessage)
+		})
 	default:
 		return errors.Wrapf(changefeedErr, "unrecognized option value: %s=%s for handling error",
 			changefeedbase.OptOnError, details.Opts[changefeedbase.OptOnError])
 	}
 }
 
+// pauseOnErrorMessage returns the message recorded as both the pause reason
+// and the running status of a changefeed paused because of on_error=pause, so
+// that SHOW JOBS explains why the changefeed paused.
+func pauseOnErrorMessage(changefeedErr error) string {
+	if errors.Is(changefeedErr, catalog.ErrDescriptorDropped) {
+		return fmt.Sprintf("paused due to schema change: %v", changefeedErr)
+	}
+	return fmt.Sprintf("job failed (%v) but is being paused because of %s=%s", changefeedErr,
+		changefeedbase.OptOnError, changefeedbase.OptOnErrorPause)
+}
+
 func (b *changefeedResumer) resumeWithRetries(
 	ctx context.Context,
 	jobExec sql.JobExecContext,
@@ -1256,6 +1582,11 @@ func (b *changefeedResumer) resumeWithRetries(
 	drainCh, cleanup := execCfg.JobRegistry.OnDrain()
 	defer cleanup()
 
+	// Publish the status of the changefeed's sink for
+	// crdb_internal.changefeed_sinks.
+	publishedStatus, unregisterSinkStatus := registerSinkStatus(jobID, details.SinkURI)
+	defer unregisterSinkStatus()
+
 	// We'd like to avoid failing a changefeed unnecessarily, so when an error
 	// bubbles up to this level, we'd like to "retry" the flow if possible. This
 	// could be because the sink is down or because a cockroach node has crashed
@@ -1270,6 +1601,16 @@ func (b *changefeedResumer) resumeWithRetries(
 	jobExec.ExtendedEvalContext().ChangefeedState = localState
 	knobs, _ := execCfg.DistSQLSrv.TestingKnobs.Changefeed.(*TestingKnobs)
 
+	// Retries of each class of errors are counted until the changefeed makes
+	// progress, and the changefeed gives up once the retry budget of a class
+	// is exhausted.
+	retryBudgets, err := changefeedbase.MakeStatementOptions(details.Opts).GetRetryBudgets()
+	if err != nil {
+		return err
+	}
+	classRetries := make(map[changefeedbase.ErrorClass]int)
+	var lastErrorHighWater hlc.Timestamp
+
 	for r := getRetry(ctx); r.Next(); {
 		flowErr := maybeUpgradePreProductionReadyExpression(ctx, jobID, details, jobExec)
 
@@ -1304,9 +1645,25 @@ func (b *changefeedResumer) resumeWithRetries(
 			return err
 		}
 
+		if hw := localState.progress.GetHighWater(); hw != nil && lastErrorHighWater.Less(*hw) {
+			lastErrorHighWater = *hw
+			classRetries = make(map[changefeedbase.ErrorClass]int)
+		}
+		class := changefeedbase.ClassifyError(flowErr)
+		classRetries[class]++
+		if budget := retryBudgets.For(class); budget > 0 && classRetries[class] > budget {
+			err := errors.Wrapf(flowErr, "giving up after %d retries of %s errors", budget, class)
+			log.Infof(ctx, "CHANGEFEED %d shutting down (cause: %v)", jobID, err)
+			if ctx.Err() == nil {
+				b.setJobRunningStatus(ctx, time.Time{}, "shutdown due to %s", err)
+			}
+			return err
+		}
+
 		// All other errors retry.
 		log.Warningf(ctx, `Changefeed job %d encountered transient error: %v (attempt %d)`,
 			jobID, flowErr, 1+r.CurrentAttempt())
+		publishedStatus.recordError(flowErr)
 		lastRunStatusUpdate = b.setJobRunningStatus(ctx, lastRunStatusUpdate, "transient error: %s", flowErr)
 
 		if metrics, ok := execCfg.JobRegistry.MetricsStruct().Changefeed.(*Metrics); ok {
@@ -1454,6 +1811,33 @@ func (b *changefeedResumer) OnFailOrCancel(
 	return nil
 }
 
+// OnPauseRequest implements jobs.PauseRequester. Unless the changefeed was
+// created with protect_data_from_gc_on_pause, the protected timestamp record
+// is released so that a paused changefeed does not hold up garbage
+// collection; the record is recreated when the changefeed resumes.
+func (b *changefeedResumer) OnPauseRequest(
+	ctx context.Context, jobExec interface{}, txn isql.Txn, progress *jobspb.Progress,
+) error {
+	details := b.job.Details().(jobspb.ChangefeedDetails)
+	if _, shouldProtect := details.Opts[changefeedbase.OptProtectDataFromGCOnPause]; shouldProtect {
+		return nil
+	}
+
+	cp := progress.GetChangefeed()
+	if cp == nil || cp.ProtectedTimestampRecord == uuid.Nil {
+		return nil
+	}
+	execCfg := jobExec.(sql.JobExecContext).ExecCfg()
+	pts := execCfg.ProtectedTimestampProvider.WithTxn(txn)
+	if err := pts.Release(ctx, cp.ProtectedTimestampRecord); err != nil &&
+		!errors.Is(err, protectedts.ErrNotExists) {
+		return err
+	}
+	log.Infof(ctx, "released protected timestamp %s on pause", cp.ProtectedTimestampRecord)
+	cp.ProtectedTimestampRecord = uuid.Nil
+	return nil
+}
+
 // CollectProfile is part of the jobs.Resumer interface.
 func (b *changefeedResumer) CollectProfile(_ context.Context, _ interface{}) error {
 	return nil
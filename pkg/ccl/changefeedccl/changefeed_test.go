@@ -14,6 +14,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"hash/crc32"
 	"math"
 	"math/rand"
 	"net/http"
@@ -81,6 +82,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/cidr"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	jsonb "github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
@@ -656,6 +658,1397 @@ func TestChangefeedDiff(t *testing.T) {
 	cdcTest(t, testFn)
 }
 
+func TestChangefeedValueOnDelete(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH value_on_delete`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}, "before": null}`,
+		})
+
+		// Updates do not carry the previous value.
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (0, 'updated')`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "updated"}, "before": null}`,
+		})
+
+		// Deletes carry the row's last value.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": null, "before": {"a": 0, "b": "updated"}}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH value_on_delete, envelope='bare'`,
+			`value_on_delete is only usable with envelope=wrapped`)
+	}
+
+	cdcTest(t, testFn)
+}
+
+func TestChangefeedShardCount(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo SELECT generate_series(1, 8)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH shard_count=2`)
+		defer closeFeed(t, foo)
+
+		// Each row is keyed by the shard its primary key hashes to.
+		var expected []string
+		for i := 1; i <= 8; i++ {
+			shard := shardKey([]byte(fmt.Sprintf(`[%d]`, i)), 2)
+			expected = append(expected, fmt.Sprintf(`foo: %s->{"after": {"a": %d}}`, shard, i))
+		}
+		assertPayloads(t, foo, expected)
+
+		// Updates to a row are emitted under the same shard.
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (1)`)
+		assertPayloads(t, foo, expected[:1])
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH shard_count=0`,
+			`option shard_count must be an integer greater than 0`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedMaxEmitRate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo SELECT generate_series(1, 40)`)
+
+		const rate = 10
+		start := timeutil.Now()
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH max_emit_rate='10'`)
+		defer closeFeed(t, foo)
+
+		var expected []string
+		for i := 1; i <= 40; i++ {
+			expected = append(expected, fmt.Sprintf(`foo: [%d]->{"after": {"a": %d}}`, i, i))
+		}
+		assertPayloads(t, foo, expected)
+
+		// The first burst of rows is emitted immediately, after which rows are
+		// emitted at no more than the configured rate.
+		minElapsed := time.Duration(len(expected)-rate) * time.Second / rate
+		require.GreaterOrEqual(t, timeutil.Since(start), minElapsed)
+
+		// The rate can be changed while the changefeed is paused.
+		enterpriseFeed, ok := foo.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+		sqlDB.Exec(t, `PAUSE JOB $1`, enterpriseFeed.JobID())
+		waitForJobStatus(sqlDB, t, enterpriseFeed.JobID(), `paused`)
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d SET max_emit_rate='1000'`, enterpriseFeed.JobID()))
+		sqlDB.Exec(t, `RESUME JOB $1`, enterpriseFeed.JobID())
+		waitForJobStatus(sqlDB, t, enterpriseFeed.JobID(), `running`)
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (41)`)
+		assertPayloads(t, foo, []string{`foo: [41]->{"after": {"a": 41}}`})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH max_emit_rate='0'`,
+			`option max_emit_rate must be an integer greater than 0`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedAtMostOnceDelivery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 0)`)
+
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		metrics := registry.MetricsStruct().Changefeed.(*Metrics)
+		sli, err := metrics.getSLIMetrics(defaultSLIScope)
+		require.NoError(t, err)
+
+		// Throttle the sink so that repeated updates to the same row overflow
+		// the small buffer.
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH delivery='at_most_once', max_buffer='64KiB', max_emit_rate='10'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{`foo: [1]->{"after": {"a": 1, "b": 0}}`})
+
+		const numUpdates = 1000
+		for i := 1; i <= numUpdates; i++ {
+			sqlDB.Exec(t, `UPDATE foo SET b = $1 WHERE a = 1`, i)
+		}
+
+		testutils.SucceedsSoon(t, func() error {
+			if sli.DroppedMessages.Value() == 0 {
+				return errors.New("expected dropped messages")
+			}
+			return nil
+		})
+
+		// The changefeed keeps making progress, and eventually emits the
+		// latest value of the row.
+		expected := fmt.Sprintf(`{"after": {"a": 1, "b": %d}}`, numUpdates)
+		for {
+			msgs, err := readNextMessages(ctx, foo, 1)
+			require.NoError(t, err)
+			if string(msgs[0].Value) == expected {
+				break
+			}
+		}
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH delivery='exactly_once'`,
+			`unknown delivery: exactly_once`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedResolvedOnCatchup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		const threshold = 2 * time.Second
+		sqlDB.Exec(t, `SET CLUSTER SETTING changefeed.resolved_on_catchup_threshold = $1`, threshold.String())
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+
+		var cursor string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&cursor)
+		const numRows = 50
+		for i := 1; i <= numRows; i++ {
+			sqlDB.Exec(t, `INSERT INTO foo VALUES ($1)`, i)
+		}
+		var afterBurst string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&afterBurst)
+
+		// Make sure that the changefeed starts out behind, so that it has to
+		// catch up through the burst of writes.
+		testutils.SucceedsSoon(t, func() error {
+			if behind := timeutil.Since(parseTimeToHLC(t, cursor).GoTime()); behind <= threshold {
+				return errors.Newf("cursor is only %s behind", behind)
+			}
+			return nil
+		})
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH resolved='on_catchup', cursor=$1`, cursor)
+		defer closeFeed(t, foo)
+
+		// No resolved timestamp is emitted while the changefeed catches up;
+		// the first one is emitted after all of the rows.
+		rows := 0
+		for {
+			m, err := foo.Next()
+			require.NoError(t, err)
+			if m.Resolved == nil {
+				rows++
+				continue
+			}
+			resolved := extractResolvedTimestamp(t, m)
+			require.Equal(t, numRows, rows)
+			require.True(t, parseTimeToHLC(t, afterBurst).Less(resolved),
+				"expected resolved %s to be after the burst at %s", resolved, afterBurst)
+			break
+		}
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH resolved='on_ketchup'`,
+			`problem parsing option resolved`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedSortWithinFlush(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b INT)`)
+		sqlDB.Exec(t, `ALTER TABLE foo SPLIT AT VALUES (10), (20), (30)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH sort_within_flush, mvcc_timestamp, resolved='100ms', no_initial_scan`)
+		defer closeFeed(t, foo)
+
+		// Write to each of the ranges in turn, so that events from different
+		// ranges interleave.
+		const numWrites = 100
+		for i := 0; i < numWrites; i++ {
+			sqlDB.Exec(t, `UPSERT INTO foo VALUES ($1, $2)`, (i%4)*10+i/4%10, i)
+		}
+
+		// There is a single aggregator, and rows are flushed before each
+		// resolved timestamp, so rows are sorted between resolved timestamps.
+		var prev hlc.Timestamp
+		for rows := 0; rows < numWrites; {
+			m, err := foo.Next()
+			require.NoError(t, err)
+			if m.Resolved != nil {
+				prev = hlc.Timestamp{}
+				continue
+			}
+			rows++
+			var value struct {
+				MVCCTimestamp string `json:"mvcc_timestamp"`
+			}
+			require.NoError(t, json.Unmarshal(m.Value, &value))
+			ts := parseTimeToHLC(t, value.MVCCTimestamp)
+			require.False(t, ts.Less(prev), "row at %s emitted after row at %s", ts, prev)
+			prev = ts
+		}
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH sort_within_flush, unordered`,
+			`cannot specify both`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("sinkless"))
+}
+
+func TestChangefeedSnapshotInterval(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH snapshot_interval='1s'`)
+		defer closeFeed(t, foo)
+
+		// The initial scan is not a snapshot.
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}, "snapshot": false}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}, "snapshot": false}`,
+		})
+
+		// Live changes are interleaved with snapshots.
+		sqlDB.Exec(t, `UPDATE foo SET b = 'c' WHERE a = 2`)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "c"}, "snapshot": false}`,
+		})
+
+		// Once the interval elapses, all of the current rows are emitted again.
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}, "snapshot": true}`,
+			`foo: [2]->{"after": {"a": 2, "b": "c"}, "snapshot": true}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH snapshot_interval='1s', initial_scan='only'`,
+			`cannot specify both initial_scan='only' and snapshot_interval`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedMarkInitialScan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH mark_initial_scan`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}, "bootstrap": true}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}, "bootstrap": true}`,
+		})
+
+		// Live changes are not marked.
+		sqlDB.Exec(t, `UPDATE foo SET b = 'c' WHERE a = 2`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'd')`)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "c"}, "bootstrap": false}`,
+			`foo: [3]->{"after": {"a": 3, "b": "d"}, "bootstrap": false}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH mark_initial_scan, format=avro`,
+			`mark_initial_scan is only usable with format=json`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedEmitTTLExpiration(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY) WITH (ttl_expire_after = '10 days')`)
+		sqlDB.Exec(t, `INSERT INTO foo (a, crdb_internal_expiration) VALUES (1, '2030-01-01 00:00:00+00')`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, expire_at TIMESTAMPTZ) `+
+			`WITH (ttl_expiration_expression = 'expire_at')`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1, '2031-06-01 12:00:00+00'), (2, NULL)`)
+		sqlDB.Exec(t, `CREATE TABLE baz (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO baz VALUES (1)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo, bar, baz WITH emit_ttl_expiration`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "crdb_internal_expiration": "2030-01-01T00:00:00Z"}, "ttl_expiration": "2030-01-01T00:00:00Z"}`,
+			`bar: [1]->{"after": {"a": 1, "expire_at": "2031-06-01T12:00:00Z"}, "ttl_expiration": "2031-06-01T12:00:00Z"}`,
+			`bar: [2]->{"after": {"a": 2, "expire_at": null}, "ttl_expiration": null}`,
+			`baz: [1]->{"after": {"a": 1}, "ttl_expiration": null}`,
+		})
+
+		// The expiration follows the row's TTL column, and deleted rows don't
+		// have one.
+		sqlDB.Exec(t, `UPDATE bar SET expire_at = '2032-01-01 00:00:00+00' WHERE a = 2`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`bar: [2]->{"after": {"a": 2, "expire_at": "2032-01-01T00:00:00Z"}, "ttl_expiration": "2032-01-01T00:00:00Z"}`,
+			`foo: [1]->{"after": null, "ttl_expiration": null}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_ttl_expiration, format=avro`,
+			`emit_ttl_expiration is only usable with format=json`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedKeyTablePrefix(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1)`)
+
+		// All tables are delivered to the same webhook endpoint, so the keys
+		// are told apart by their table prefix.
+		foobar := feed(t, f, `CREATE CHANGEFEED FOR foo, bar WITH key_table_prefix`)
+		defer closeFeed(t, foobar)
+
+		assertPayloads(t, foobar, []string{
+			`foo: ["foo", 1]->{"after": {"a": 1}}`,
+			`bar: ["bar", 1]->{"after": {"a": 1}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("webhook"))
+}
+
+func TestChangefeedKafkaKeySerializer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT, b STRING, c INT, PRIMARY KEY (a, b))`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'one', 10)`)
+
+		// Keys are the bare primary key values, while values remain JSON.
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH kafka_key_serializer='string'`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: 1,one->{"after": {"a": 1, "b": "one", "c": 10}}`,
+		})
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: 1,one->{"after": null}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH kafka_key_serializer='avro'`,
+			`WITH option confluent_schema_registry is required for kafka_key_serializer=avro`)
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH kafka_key_serializer='xml'`,
+			`unknown kafka_key_serializer: xml`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedDecimalFormat(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a DECIMAL PRIMARY KEY, b DECIMAL)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1.5, 12345678901234567890.123456789012345678901234567890)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH decimal_format='string'`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: ["1.5"]->{"after": {"a": "1.5", "b": "12345678901234567890.123456789012345678901234567890"}}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH decimal_format='string', format=csv, initial_scan='only'`,
+			`decimal_format=string is only usable with format=json`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedEnumFormat(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TYPE status AS ENUM ('open', 'closed')`)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b status)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'open'), (2, 'closed')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH enum_format='label'`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "open"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "closed"}}`,
+		})
+
+		// Values added to the type after the changefeed started are rendered
+		// using their labels as well.
+		sqlDB.Exec(t, `ALTER TYPE status ADD VALUE 'inactive'`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'inactive')`)
+		assertPayloads(t, foo, []string{
+			`foo: [3]->{"after": {"a": 3, "b": "inactive"}}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH enum_format='physical', format=csv, initial_scan='only'`,
+			`enum_format=physical is only usable with format=json`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedComplexFormat(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING[], c JSONB)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, ARRAY['x', 'y z'], '{"k": [1, 2]}')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH complex_format='string'`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "{x,\"y z\"}", "c": "{\"k\": [1, 2]}"}}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH complex_format='string', format=csv, initial_scan='only'`,
+			`complex_format=string is only usable with format=json`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedEmitChecksum(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_checksum='crc32'`)
+		defer closeFeed(t, foo)
+
+		// The kafka sink emits the checksum of the value in a header.
+		nextChecksum := func() string {
+			msgs, err := readNextMessages(ctx, foo, 1)
+			require.NoError(t, err)
+			msg := msgs[0].RawMessage.(*sarama.ProducerMessage)
+			require.Len(t, msg.Headers, 1)
+			require.Equal(t, `crc32`, string(msg.Headers[0].Key))
+			checksum := string(msg.Headers[0].Value)
+			require.Equal(t, fmt.Sprintf(`%08x`, crc32.ChecksumIEEE(msgs[0].Value)), checksum)
+			require.NotContains(t, string(msgs[0].Value), `crc32`)
+			return checksum
+		}
+		checksum := nextChecksum()
+
+		sqlDB.Exec(t, `UPDATE foo SET b = 'b' WHERE a = 1`)
+		require.NotEqual(t, checksum, nextChecksum())
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_checksum='md5'`,
+			`unknown emit_checksum: md5`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedEmitChecksumInEnvelope(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_checksum='crc32'`)
+		defer closeFeed(t, foo)
+
+		// Other sinks emit the checksum of the `after` field in the envelope.
+		nextChecksum := func() string {
+			msgs, err := readNextMessages(ctx, foo, 1)
+			require.NoError(t, err)
+			var value struct {
+				After json.RawMessage `json:"after"`
+				CRC32 string          `json:"crc32"`
+			}
+			require.NoError(t, json.Unmarshal(msgs[0].Value, &value))
+			require.Equal(t, fmt.Sprintf(`%08x`, crc32.ChecksumIEEE(value.After)), value.CRC32)
+			return value.CRC32
+		}
+		checksum := nextChecksum()
+
+		sqlDB.Exec(t, `UPDATE foo SET b = 'b' WHERE a = 1`)
+		require.NotEqual(t, checksum, nextChecksum())
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_checksum='crc32', envelope='key_only'`,
+			`emit_checksum is only usable with a kafka sink, or with format=json and envelope=wrapped`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("sinkless"))
+}
+
+func TestChangefeedSpatialFormat(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b GEOMETRY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'POINT(1 2)')`)
+
+		t.Run("json geojson", func(t *testing.T) {
+			foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH spatial_format='geojson'`)
+			defer closeFeed(t, foo)
+			assertPayloads(t, foo, []string{
+				`foo: [1]->{"after": {"a": 1, "b": {"coordinates": [1, 2], "type": "Point"}}}`,
+			})
+		})
+
+		t.Run("json ewkb", func(t *testing.T) {
+			foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH spatial_format='ewkb'`)
+			defer closeFeed(t, foo)
+			assertPayloads(t, foo, []string{
+				`foo: [1]->{"after": {"a": 1, "b": "0101000000000000000000f03f0000000000000040"}}`,
+			})
+		})
+
+		t.Run("avro geojson", func(t *testing.T) {
+			foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH spatial_format='geojson', format=avro`)
+			defer closeFeed(t, foo)
+			assertPayloads(t, foo, []string{
+				`foo: {"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"{\"type\":\"Point\",\"coordinates\":[1,2]}"}}}}`,
+			})
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH spatial_format='geojson', format=csv, initial_scan='only'`,
+			`spatial_format is only usable with format=json or format=avro`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedFieldRename(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'one', 10)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH field_rename='a:id,b:name', diff`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"c": 10, "id": 1, "name": "one"}, "before": null}`,
+		})
+		sqlDB.Exec(t, `UPDATE foo SET b = 'uno' WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"c": 10, "id": 1, "name": "uno"}, "before": {"c": 10, "id": 1, "name": "one"}}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH field_rename='d:id'`,
+			`required column d not present on table foo`)
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH field_rename='a'`,
+			`problem parsing option field_rename`)
+	}
+
+	cdcTest(t, testFn)
+}
+
+func TestChangefeedDeleteDelay(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+
+		// Hold deletes, and avoid flushes, for long enough that the re-insert
+		// below always supersedes the delete.
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH delete_delay='1h', min_checkpoint_frequency='1h'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+		})
+
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'c')`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'd')`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "c"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "d"}}`,
+		})
+
+		// Deletes which aren't superseded are emitted once the delay expires
+		// or the changefeed flushes.
+		bar := feed(t, f, `CREATE CHANGEFEED FOR foo WITH delete_delay='10ms', no_initial_scan`)
+		defer closeFeed(t, bar)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		assertPayloads(t, bar, []string{
+			`foo: [2]->{"after": null}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH delete_delay='1s', format=parquet`,
+			`cannot specify both format=parquet and delete_delay`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("sinkless"))
+}
+
+func TestChangefeedEmitBatchMarkers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_batch_markers`)
+		defer closeFeed(t, foo)
+
+		// Rows from the initial scan aren't bracketed by markers.
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}}`,
+		})
+
+		var tsStr string
+		sqlDB.QueryRow(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b'), (3, 'c') `+
+			`RETURNING cluster_logical_timestamp()`).Scan(&tsStr)
+		ts := parseTimeToHLC(t, tsStr)
+		marker := func(m string) string {
+			return fmt.Sprintf(`{"batch": "%s", "mvcc_timestamp": "%s"}`, m, ts.AsOfSystemTime())
+		}
+
+		msgs, err := readNextMessages(context.Background(), foo, 5)
+		require.NoError(t, err)
+		require.Equal(t, marker(`begin`), string(msgs[0].Value))
+		require.Equal(t, marker(`end`), string(msgs[4].Value))
+		var rows []string
+		for _, m := range msgs[1:4] {
+			rows = append(rows, fmt.Sprintf(`%s: %s->%s`, m.Topic, m.Key, m.Value))
+		}
+		require.ElementsMatch(t, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "c"}}`,
+		}, rows)
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_batch_markers, format=csv, initial_scan='only'`,
+			`emit_batch_markers is only usable with format=json`)
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_batch_markers, ddl_only`,
+			`cannot specify both emit_batch_markers and ddl_only`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("sinkless"))
+}
+
+func TestChangefeedEmitSchemaPreamble(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_schema_preamble, schema_change_policy='nobackfill'`)
+		defer closeFeed(t, foo)
+
+		// The first row emitted to the topic is preceded by a preamble, with
+		// the same key, describing its columns.
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"schema": {"columns": [{"name": "a", "type": "INT8"}, {"name": "b", "type": "STRING"}], ` +
+				`"key": [{"name": "a", "type": "INT8"}], "table": "foo"}}`,
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}}`,
+		})
+
+		// Later rows of the same schema aren't.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+		})
+
+		// The preamble recurs after a schema change.
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN c INT`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'b', 3)`)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"schema": {"columns": [{"name": "a", "type": "INT8"}, {"name": "b", "type": "STRING"}, {"name": "c", "type": "INT8"}], ` +
+				`"key": [{"name": "a", "type": "INT8"}], "table": "foo"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b", "c": 3}}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_schema_preamble, format=csv, initial_scan='only'`,
+			`emit_schema_preamble is only usable with format=json`)
+	}
+
+	// Use the kafka sink, which keeps the key of each message apart from its
+	// value.
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedDeleteTopicSuffix(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial'), (1, 'a')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH delete_topic_suffix='_deletes'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}}`,
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+		})
+
+		// Deletes go to their own topic, while inserts and updates don't.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 0`)
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (1, 'b')`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'c')`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		assertPayloads(t, foo, []string{
+			`foo_deletes: [0]->{"after": null}`,
+			`foo: [1]->{"after": {"a": 1, "b": "b"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "c"}}`,
+			`foo_deletes: [2]->{"after": null}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH delete_topic_suffix=''`,
+			`option delete_topic_suffix must not be empty`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedNullTombstone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial'), (1, 'a')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH tombstone='null'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}}`,
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+		})
+
+		// Deletes keep their key but have a null value, while the other events
+		// keep the wrapped envelope.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 0`)
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (1, 'b')`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->`,
+			`foo: [1]->{"after": {"a": 1, "b": "b"}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedEmitSchemaFingerprint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	fingerprint := func(columns string) string {
+		j, err := jsonb.ParseJSON(columns)
+		require.NoError(t, err)
+		return schemaFingerprint(j)
+	}
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b VARCHAR(10))`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_schema_preamble, emit_schema_fingerprint, `+
+			`schema_change_policy='nobackfill'`)
+		defer closeFeed(t, foo)
+
+		const beforeCols = `[{"name": "a", "type": "INT8"}, {"name": "b", "type": "VARCHAR(10)"}]`
+		before := fingerprint(beforeCols)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"schema": {"columns": ` + beforeCols + `, "fingerprint": "` + before + `", ` +
+				`"key": [{"name": "a", "type": "INT8"}], "table": "foo"}}`,
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}}`,
+		})
+
+		// A data change doesn't emit a new fingerprint.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+		})
+
+		// Changing the type of a column changes the fingerprint.
+		sqlDB.Exec(t, `ALTER TABLE foo ALTER COLUMN b TYPE STRING`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'b')`)
+		const afterCols = `[{"name": "a", "type": "INT8"}, {"name": "b", "type": "STRING"}]`
+		after := fingerprint(afterCols)
+		require.NotEqual(t, before, after)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"schema": {"columns": ` + afterCols + `, "fingerprint": "` + after + `", ` +
+				`"key": [{"name": "a", "type": "INT8"}], "table": "foo"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_schema_fingerprint`,
+			`emit_schema_fingerprint is only usable with emit_schema_preamble or ddl_only`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedCloudStorageIcebergLayout(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, region STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'east'), (2, 'west'), (3, 'east'), (4, NULL)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH format=parquet, resolved='10ms', `+
+			`cloudstorage_layout='iceberg', cloudstorage_partition_column='region'`)
+		defer closeFeed(t, foo)
+		tableDir := path.Join(foo.(*cloudFeed).dir, `foo`)
+		metadataDir := path.Join(tableDir, icebergMetadataDir)
+
+		// Wait for the initial scan to be committed by a snapshot.
+		var metadata icebergTableMetadata
+		testutils.SucceedsSoon(t, func() error {
+			files, err := os.ReadDir(metadataDir)
+			if err != nil {
+				return err
+			}
+			var latest string
+			for _, f := range files {
+				if strings.HasSuffix(f.Name(), icebergMetadataSuffix) && f.Name() > latest {
+					latest = f.Name()
+				}
+			}
+			if latest == `` {
+				return errors.New(`waiting for a snapshot`)
+			}
+			content, err := os.ReadFile(path.Join(metadataDir, latest))
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(content, &metadata)
+		})
+
+		require.Equal(t, `foo`, metadata.Location)
+		require.Equal(t, []icebergPartitionField{
+			{Name: `region`, SourceCol: `region`, Transform: `identity`},
+		}, metadata.PartitionSpec)
+		require.Len(t, metadata.Snapshots, 1)
+		snapshot := metadata.Snapshots[0]
+		require.Equal(t, metadata.CurrentSnapshotID, snapshot.SnapshotID)
+
+		// Every data file listed by the snapshot's manifests exists, and is in
+		// the directory of its partition.
+		partitions := make(map[string]struct{})
+		for _, m := range snapshot.Manifests {
+			content, err := os.ReadFile(path.Join(tableDir, m))
+			require.NoError(t, err)
+			var manifest icebergManifest
+			require.NoError(t, json.Unmarshal(content, &manifest))
+			for _, e := range manifest.Entries {
+				require.Equal(t, `PARQUET`, e.DataFile.FileFormat)
+				_, err := os.Stat(path.Join(tableDir, e.DataFile.FilePath))
+				require.NoError(t, err)
+				partitions[path.Dir(e.DataFile.FilePath)] = struct{}{}
+			}
+		}
+		var partitionDirs []string
+		for p := range partitions {
+			partitionDirs = append(partitionDirs, p)
+		}
+		require.ElementsMatch(t, []string{
+			`data/region=east`,
+			`data/region=west`,
+			`data/region=__HIVE_DEFAULT_PARTITION__`,
+		}, partitionDirs)
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH format=json, cloudstorage_layout='iceberg'`,
+			`cloudstorage_layout=iceberg is only usable with format=parquet`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("cloudstorage"))
+}
+
+func TestChangefeedResolvedIncludeLag(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH resolved='10ms', resolved_include_lag`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{`foo: [1]->{"after": {"a": 1}}`})
+
+		m, err := foo.Next()
+		require.NoError(t, err)
+		var resolved struct {
+			Resolved string `json:"resolved"`
+			LagMs    *int64 `json:"lag_ms"`
+		}
+		require.NoError(t, json.Unmarshal(m.Resolved, &resolved))
+		// The lag is computed when the message is encoded, so it can't exceed
+		// the lag observed when it is received.
+		observed := timeutil.Since(parseTimeToHLC(t, resolved.Resolved).GoTime())
+		require.NotNil(t, resolved.LagMs)
+		require.GreaterOrEqual(t, *resolved.LagMs, int64(0))
+		require.LessOrEqual(t, *resolved.LagMs, observed.Milliseconds())
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH resolved_include_lag`,
+			`resolved_include_lag requires the resolved option`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedDDLOnly(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH ddl_only`)
+		defer closeFeed(t, foo)
+
+		// Rows are never emitted; each schema change emits one record.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2)`)
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN b STRING`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'three')`)
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN c INT DEFAULT 1`)
+		sqlDB.Exec(t, `ALTER TABLE foo DROP COLUMN b`)
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: ["foo"]->{"after": [{"name": "a", "type": "INT8"}, {"name": "b", "type": "STRING"}], ` +
+				`"before": [{"name": "a", "type": "INT8"}], ` +
+				`"changes": [{"column": "b", "op": "add_column", "type": "STRING"}], "table": "foo"}`,
+			`foo: ["foo"]->{"after": [{"name": "a", "type": "INT8"}, {"name": "b", "type": "STRING"}, {"name": "c", "type": "INT8"}], ` +
+				`"before": [{"name": "a", "type": "INT8"}, {"name": "b", "type": "STRING"}], ` +
+				`"changes": [{"column": "c", "op": "add_column", "type": "INT8"}], "table": "foo"}`,
+			`foo: ["foo"]->{"after": [{"name": "a", "type": "INT8"}, {"name": "c", "type": "INT8"}], ` +
+				`"before": [{"name": "a", "type": "INT8"}, {"name": "b", "type": "STRING"}, {"name": "c", "type": "INT8"}], ` +
+				`"changes": [{"column": "b", "op": "drop_column", "type": "STRING"}], "table": "foo"}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH ddl_only, schema_change_policy='ignore'`,
+			`ddl_only is not usable with schema_change_policy=ignore`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedIncludeSource(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH include_source`)
+		defer closeFeed(t, foo)
+
+		msgs, err := readNextMessages(ctx, foo, 1)
+		require.NoError(t, err)
+		var row struct {
+			After  map[string]int `json:"after"`
+			Source struct {
+				NodeID   int    `json:"node_id"`
+				Region   string `json:"region"`
+				Locality string `json:"locality"`
+			} `json:"source"`
+		}
+		require.NoError(t, json.Unmarshal(msgs[0].Value, &row))
+		require.Equal(t, map[string]int{"a": 1}, row.After)
+		require.Equal(t, int(s.Server.SQLInstanceID()), row.Source.NodeID)
+		require.Equal(t, testServerRegion, row.Source.Region)
+		require.Equal(t, `region=`+testServerRegion, row.Source.Locality)
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH include_source, envelope=key_only`,
+			`include_source is only usable with envelope=wrapped or envelope=bare`)
+	}
+
+	withTestServerRegion := func(args *base.TestServerArgs) {
+		args.Locality.Tiers = append(args.Locality.Tiers, roachpb.Tier{
+			Key:   "region",
+			Value: testServerRegion,
+		})
+	}
+	cdcTest(t, testFn, feedTestForceSink("kafka"), feedTestNoTenants, withArgsFn(withTestServerRegion))
+}
+
+func TestChangefeedOnFilterErrorSkip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 1), (2, 0), (3, 2)`)
+
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		metrics := registry.MetricsStruct().Changefeed.(*Metrics)
+		sli, err := metrics.getSLIMetrics(defaultSLIScope)
+		require.NoError(t, err)
+		skippedBefore := sli.FilterErrorMessages.Value()
+
+		// The filter fails to evaluate for rows with b = 0, which are skipped.
+		foo := feed(t, f, `CREATE CHANGEFEED WITH on_filter_error='skip' AS SELECT * FROM foo WHERE 10 / b > 1`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"a": 1, "b": 1}`,
+			`foo: [3]->{"a": 3, "b": 2}`,
+		})
+
+		// Rows after a skipped row are still evaluated.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (4, 0)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (5, 5)`)
+		assertPayloads(t, foo, []string{
+			`foo: [5]->{"a": 5, "b": 5}`,
+		})
+		require.EqualValues(t, 2, sli.FilterErrorMessages.Value()-skippedBefore)
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH on_filter_error='skip'`,
+			`on_filter_error='skip' is only usable with CREATE CHANGEFEED ... AS SELECT ...`)
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED WITH on_filter_error='ignore' AS SELECT * FROM foo`,
+			`unknown on_filter_error: ignore`)
+	}
+
+	cdcTest(t, testFn)
+}
+
+func TestChangefeedOnlyInserts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH only_inserts`)
+		defer closeFeed(t, foo)
+
+		// Rows from the initial scan have no before image, so they are emitted.
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}}`,
+		})
+
+		// Neither the update nor the delete of 1 should be emitted; only the
+		// two inserts should be.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'b' WHERE a = 1`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'c')`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "c"}}`,
+		})
+
+		// Re-inserting a deleted key is an insert.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'new a')`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "new a"}}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH only_inserts, diff`,
+			`only_inserts is not usable with diff`)
+	}
+
+	cdcTest(t, testFn)
+}
+
+func TestChangefeedEmitOpField(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_op_field`)
+		defer closeFeed(t, foo)
+
+		// Rows from the initial scan have no before image, so they are inserts.
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}, "op": "INSERT"}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'b' WHERE a = 1`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}, "op": "INSERT"}`,
+			`foo: [1]->{"after": {"a": 1, "b": "b"}, "op": "UPDATE"}`,
+			`foo: [1]->{"after": null, "op": "DELETE"}`,
+		})
+
+		// The op field is emitted alongside the before image.
+		fooDiff := feed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_op_field, diff, no_initial_scan`)
+		defer closeFeed(t, fooDiff)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'c')`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'd' WHERE a = 2`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		assertPayloads(t, fooDiff, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "c"}, "before": null, "op": "INSERT"}`,
+			`foo: [2]->{"after": {"a": 2, "b": "d"}, "before": {"a": 2, "b": "c"}, "op": "UPDATE"}`,
+			`foo: [2]->{"after": null, "before": {"a": 2, "b": "d"}, "op": "DELETE"}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_op_field, envelope='row'`,
+			`emit_op_field is only usable with envelope=wrapped or envelope=bare`)
+	}
+
+	cdcTest(t, testFn)
+}
+
+func TestChangefeedFileSizeRollover(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+
+		// Checkpoint flushes are effectively disabled, so rows can only become
+		// visible because the sink rolled over to a new file once it exceeded
+		// file_size.
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo
+WITH file_size='512B', min_checkpoint_frequency='1h', no_initial_scan`)
+		defer closeFeed(t, foo)
+
+		sqlDB.Exec(t, `INSERT INTO foo SELECT i, repeat('x', 100) FROM generate_series(1, 100) AS i`)
+		_, err := readNextMessages(ctx, foo, 50)
+		require.NoError(t, err)
+		require.Greater(t, len(foo.(*cloudFeed).seenFiles), 1)
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH file_size='0B'`,
+			`option file_size must be a size greater than 0`)
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH file_size='abc'`,
+			`problem parsing option file_size`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("cloudstorage"))
+}
+
+func TestChangefeedInitialScanParallelism(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo SELECT * FROM generate_series(1, 5)`)
+		sqlDB.Exec(t, `ALTER TABLE foo SPLIT AT SELECT * FROM generate_series(2, 5)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH initial_scan_parallelism='4'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1}}`,
+			`foo: [2]->{"after": {"a": 2}}`,
+			`foo: [3]->{"after": {"a": 3}}`,
+			`foo: [4]->{"after": {"a": 4}}`,
+			`foo: [5]->{"after": {"a": 5}}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH initial_scan_parallelism='0'`,
+			`option initial_scan_parallelism must be an integer greater than 0`)
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH initial_scan_parallelism='abc'`,
+			`problem parsing option initial_scan_parallelism`)
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
+func TestChangefeedInitialScanConsistency(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		// Record the routing policies of the scan requests.
+		var mu syncutil.Mutex
+		var policies []kvpb.RoutingPolicy
+		knobs := s.TestingKnobs.DistSQL.(*execinfra.TestingKnobs).Changefeed.(*TestingKnobs)
+		knobs.FeedKnobs.BeforeScanRequest = func(b *kv.Batch) error {
+			mu.Lock()
+			defer mu.Unlock()
+			policies = append(policies, b.Header.RoutingPolicy)
+			return nil
+		}
+		scanPolicies := func() []kvpb.RoutingPolicy {
+			mu.Lock()
+			defer mu.Unlock()
+			p := policies
+			policies = nil
+			return p
+		}
+
+		for _, tc := range []struct {
+			consistency string
+			expected    kvpb.RoutingPolicy
+		}{
+			{consistency: ``, expected: kvpb.RoutingPolicy_LEASEHOLDER},
+			{consistency: `, initial_scan_consistency='leaseholder'`, expected: kvpb.RoutingPolicy_LEASEHOLDER},
+			{consistency: `, initial_scan_consistency='follower'`, expected: kvpb.RoutingPolicy_NEAREST},
+		} {
+			foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH initial_scan_only`+tc.consistency)
+			assertPayloads(t, foo, []string{
+				`foo: [1]->{"after": {"a": 1}}`,
+			})
+			closeFeed(t, foo)
+
+			p := scanPolicies()
+			require.NotEmpty(t, p, tc.consistency)
+			for _, policy := range p {
+				require.Equal(t, tc.expected, policy, tc.consistency)
+			}
+		}
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH initial_scan_consistency='bar'`,
+			`unknown initial_scan_consistency: bar`)
+		expectErrCreatingFeed(t, f,
+			`CREATE CHANGEFEED FOR foo WITH initial_scan_consistency='follower', no_initial_scan`,
+			`cannot specify initial_scan_consistency without an initial scan`)
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
+func TestChangefeedMaxMessageBytes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, repeat('x', 4000))`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH max_message_bytes='1KiB'`)
+		defer closeFeed(t, foo)
+
+		// The oversized row is split into ordered chunks sharing its key, which
+		// reassemble into the original value.
+		var value []byte
+		for total := 1; ; {
+			msgs, err := readNextMessages(ctx, foo, 1)
+			require.NoError(t, err)
+			m := msgs[0]
+			require.Equal(t, `[1]`, string(m.Key))
+			require.LessOrEqual(t, len(m.Value), 1<<10)
+
+			var chunk chunkEnvelope
+			require.NoError(t, json.Unmarshal(m.Value, &chunk))
+			require.Equal(t, len(value) > 0, chunk.Chunk.Index > 0)
+			if chunk.Chunk.Index == 0 {
+				total = chunk.Chunk.Total
+				require.Greater(t, total, 1)
+			}
+			require.Equal(t, total, chunk.Chunk.Total)
+			value = append(value, chunk.Chunk.Data...)
+			if chunk.Chunk.Index == total-1 {
+				break
+			}
+		}
+		var row struct {
+			After struct {
+				A int    `json:"a"`
+				B string `json:"b"`
+			} `json:"after"`
+		}
+		require.NoError(t, json.Unmarshal(value, &row))
+		require.Equal(t, 1, row.After.A)
+		require.Equal(t, strings.Repeat("x", 4000), row.After.B)
+
+		// Rows within the limit are emitted as is.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'small')`)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "small"}}`,
+		})
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH max_message_bytes='1B'`,
+			`max_message_bytes must be at least 1024 bytes`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
 func TestChangefeedTenants(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -1276,6 +2669,135 @@ func TestChangefeedInitialScan(t *testing.T) {
 	cdcTest(t, testFn)
 }
 
+func TestChangefeedInitialScanAt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		expectErrCreatingFeed(t, f,
+			`CREATE CHANGEFEED FOR foo WITH no_initial_scan, initial_scan_at='2000-01-01'`,
+			`cannot specify initial_scan_at without an initial scan`)
+
+		scanAt := timeutil.Now().Add(5 * time.Second)
+		foo := feed(t, f, fmt.Sprintf(`CREATE CHANGEFEED FOR foo WITH initial_scan_at='%s'`,
+			scanAt.UTC().Format(`2006-01-02 15:04:05.999999`)))
+		defer closeFeed(t, foo)
+
+		// Live changes are streamed while the initial scan is deferred.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2)`)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"after": {"a": 2}}`,
+		})
+		require.True(t, timeutil.Now().Before(scanAt))
+
+		// The initial scan runs as of the changefeed's creation once the
+		// configured time has been reached.
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1}}`,
+		})
+		require.False(t, timeutil.Now().Before(scanAt))
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
+func TestChangefeedEmitAfter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		emitAfter := timeutil.Now().Add(5 * time.Second)
+		foo := feed(t, f, fmt.Sprintf(`CREATE CHANGEFEED FOR foo WITH emit_after='%s'`,
+			emitAfter.UTC().Format(`2006-01-02 15:04:05.999999`)))
+		defer closeFeed(t, foo)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2)`)
+
+		// Nothing is emitted until the configured time, after which both the
+		// initial scan and the changes made since are emitted.
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1}}`,
+			`foo: [2]->{"after": {"a": 2}}`,
+		})
+		require.False(t, timeutil.Now().Before(emitAfter))
+	}
+
+	cdcTest(t, testFn)
+}
+
+func TestChangefeedEmitSequence(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1), (2)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1)`)
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_sequence, envelope=bare`,
+			`emit_sequence is only usable with envelope=wrapped`)
+
+		feed := feed(t, f, `CREATE CHANGEFEED FOR foo, bar WITH emit_sequence, resolved`)
+		defer closeFeed(t, feed)
+
+		// readSequences reads the given number of rows, and returns the sequence
+		// numbers of each topic, in order. Rows for different keys may be
+		// delivered out of order by some sinks.
+		readSequences := func(numRows int) map[string][]int64 {
+			sequences := make(map[string][]int64)
+			for numRows > 0 {
+				m, err := feed.Next()
+				require.NoError(t, err)
+				if m.Key == nil {
+					continue
+				}
+				var value struct {
+					Sequence int64 `json:"sequence"`
+				}
+				require.NoError(t, json.Unmarshal(m.Value, &value))
+				sequences[m.Topic] = append(sequences[m.Topic], value.Sequence)
+				numRows--
+			}
+			for _, seqs := range sequences {
+				sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+			}
+			return sequences
+		}
+		require.Equal(t, map[string][]int64{
+			`foo`: {1, 2},
+			`bar`: {1},
+		}, readSequences(3))
+
+		// Wait for the sequence numbers of the initial scan to be checkpointed,
+		// which happens before resolved timestamps are emitted.
+		expectResolvedTimestamp(t, feed)
+
+		// The numbering of each topic continues where it left off once the
+		// changefeed is resumed.
+		feedJob := feed.(cdctest.EnterpriseTestFeed)
+		sqlDB.Exec(t, `PAUSE JOB $1`, feedJob.JobID())
+		waitForJobStatus(sqlDB, t, feedJob.JobID(), jobs.StatusPaused)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3)`)
+		sqlDB.Exec(t, `UPSERT INTO bar VALUES (1), (2)`)
+		sqlDB.Exec(t, `RESUME JOB $1`, feedJob.JobID())
+		require.Equal(t, map[string][]int64{
+			`foo`: {3},
+			`bar`: {2, 3},
+		}, readSequences(3))
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
 // TestChangefeedLaggingRangesMetrics tests the behavior of the
 // changefeed.lagging_ranges metric.
 func TestChangefeedLaggingRangesMetrics(t *testing.T) {
@@ -1515,6 +3037,45 @@ func TestChangefeedBackfillObservability(t *testing.T) {
 	cdcTest(t, testFn, feedTestNoTenants, feedTestEnterpriseSinks)
 }
 
+func TestChangefeedSchemaChangeBackfillMetrics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		sli, err := registry.MetricsStruct().Changefeed.(*Metrics).getSLIMetrics(defaultSLIScope)
+		require.NoError(t, err)
+
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1}}`,
+		})
+
+		// The initial scan isn't a schema change backfill.
+		require.Zero(t, sli.SchemaChangeBackfills.Value())
+		require.Zero(t, sli.SchemaChangeBackfillRunning.Value())
+
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN b INT DEFAULT 2`)
+		testutils.SucceedsSoon(t, func() error {
+			if count := sli.SchemaChangeBackfills.Value(); count == 0 {
+				return errors.New("waiting for a schema change backfill")
+			}
+			if running := sli.SchemaChangeBackfillRunning.Value(); running != 0 {
+				return errors.Newf("expected no running backfills, found %d", running)
+			}
+			return nil
+		})
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
 func TestChangefeedUserDefinedTypes(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -3055,6 +4616,45 @@ func TestChangefeedEachColumnFamily(t *testing.T) {
 	cdcTest(t, testFn)
 }
 
+func TestChangefeedEmitChangedFamilyOnly(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c STRING, FAMILY most (a,b), FAMILY only_c (c))`)
+		sqlDB.Exec(t, `INSERT INTO foo values (0, 'dog', 'cat')`)
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH emit_changed_family_only`,
+			`emit_changed_family_only requires the split_column_families option`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH split_column_families, emit_changed_family_only`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo.most: [0]->{"after": {"a": 0, "b": "dog"}}`,
+			`foo.only_c: [0]->{"after": {"c": "cat"}}`,
+		})
+
+		// The update rewrites both families, but only the values of only_c
+		// change.
+		sqlDB.Exec(t, `UPDATE foo SET b = 'dog', c = 'lion' WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo.only_c: [0]->{"after": {"c": "lion"}}`,
+		})
+
+		// Changed families, and deletes, are still emitted.
+		sqlDB.Exec(t, `UPDATE foo SET b = 'puppy', c = 'lion' WHERE a = 0`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo.most: [0]->{"after": {"a": 0, "b": "puppy"}}`,
+			`foo.most: [0]->{"after": null}`,
+			`foo.only_c: [0]->{"after": null}`,
+		})
+	}
+
+	cdcTest(t, testFn)
+}
+
 func TestChangefeedSingleColumnFamily(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -3691,12 +5291,46 @@ func TestChangefeedBareJSON(t *testing.T) {
 		defer closeFeed(t, foo)
 		assertPayloads(t, foo, []string{`foo: [0]->{"a": 0, "b": "dog"}`})
 	}
-	cdcTest(t, testFn, feedTestForceSink("kafka"))
-	cdcTest(t, testFn, feedTestForceSink("enterprise"))
-	cdcTest(t, testFn, feedTestForceSink("pubsub"))
-	cdcTest(t, testFn, feedTestForceSink("sinkless"))
-	cdcTest(t, testFn, feedTestForceSink("webhook"))
-	cdcTest(t, testFn, feedTestForceSink("cloudstorage"))
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+	cdcTest(t, testFn, feedTestForceSink("enterprise"))
+	cdcTest(t, testFn, feedTestForceSink("pubsub"))
+	cdcTest(t, testFn, feedTestForceSink("sinkless"))
+	cdcTest(t, testFn, feedTestForceSink("webhook"))
+	cdcTest(t, testFn, feedTestForceSink("cloudstorage"))
+}
+
+func TestChangefeedOnView(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'dog', 10)`)
+		sqlDB.Exec(t, `CREATE VIEW vw AS SELECT a, b FROM foo WHERE c > 5`)
+
+		// A changefeed on a projection view streams the projected columns of
+		// the underlying table.
+		vw := feed(t, f, `CREATE CHANGEFEED FOR vw`)
+		defer closeFeed(t, vw)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'cat', 1), (2, 'bird', 20)`)
+		assertPayloads(t, vw, []string{
+			`foo: [0]->{"a": 0, "b": "dog"}`,
+			`foo: [2]->{"a": 2, "b": "bird"}`,
+		})
+
+		sqlDB.Exec(t, `CREATE VIEW joined AS SELECT foo.a, foo.b FROM foo JOIN bar ON foo.a = bar.a`)
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR joined`,
+			`CHANGEFEED cannot target view joined: only views which select from a single table are supported`)
+		sqlDB.Exec(t, `CREATE VIEW counts AS SELECT b, count(*) FROM foo GROUP BY b`)
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR counts`,
+			`CHANGEFEED cannot target view counts: DISTINCT, GROUP BY, HAVING and window clauses are not supported`)
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR vw, foo`,
+			`CHANGEFEED on view vw cannot have other targets`)
+	}
+	cdcTest(t, testFn)
 }
 
 func TestChangefeedExternalConnectionSchemaRegistry(t *testing.T) {
@@ -4801,6 +6435,54 @@ func TestChangefeedRetryableError(t *testing.T) {
 	cdcTest(t, testFn, feedTestEnterpriseSinks)
 }
 
+func TestChangefeedRetryBudgetPerErrorClass(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		knobs := s.TestingKnobs.
+			DistSQL.(*execinfra.TestingKnobs).
+			Changefeed.(*TestingKnobs)
+		var failEncode, encodeAttempts int64
+		knobs.BeforeEncodeRow = func(_ context.Context) error {
+			if atomic.LoadInt64(&failEncode) == 0 {
+				return nil
+			}
+			atomic.AddInt64(&encodeAttempts, 1)
+			return errors.New("synthetic encode error")
+		}
+
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH retry_encode_max=0`,
+			`option retry_encode_max must be an integer greater than 0`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH retry_timeout_max=100, retry_encode_max=3`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1}}`,
+		})
+
+		// Encode errors are only retried 3 times before the changefeed fails,
+		// even though timeouts would be retried 100 times.
+		atomic.StoreInt64(&failEncode, 1)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2)`)
+		for {
+			_, err := foo.Next()
+			if err == nil {
+				continue
+			}
+			require.Regexp(t, `giving up after 3 retries of encode errors: synthetic encode error`, err)
+			break
+		}
+		require.GreaterOrEqual(t, atomic.LoadInt64(&encodeAttempts), int64(4))
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
 func TestChangefeedJobUpdateFailsIfNotClaimed(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -5191,9 +6873,9 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `CHANGEFEED cannot target sequences: seq`,
 		`EXPERIMENTAL CHANGEFEED FOR seq`,
 	)
-	sqlDB.Exec(t, `CREATE VIEW vw AS SELECT a, b FROM foo`)
+	sqlDB.Exec(t, `CREATE VIEW vw AS SELECT count(*) FROM foo GROUP BY b`)
 	sqlDB.ExpectErrWithTimeout(
-		t, `CHANGEFEED cannot target views: vw`,
+		t, `CHANGEFEED cannot target view vw: DISTINCT, GROUP BY, HAVING and window clauses are not supported`,
 		`EXPERIMENTAL CHANGEFEED FOR vw`,
 	)
 
@@ -5848,6 +7530,62 @@ func TestChangefeedPauseUnpause(t *testing.T) {
 	cdcTest(t, testFn, feedTestEnterpriseSinks)
 }
 
+func TestChangefeedExternalCheckpoint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH external_checkpoint='nodelocal://1/checkpoints'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+		})
+
+		jobFeed := foo.(cdctest.EnterpriseTestFeed)
+		jobRegistry := s.Server.JobRegistry().(*jobs.Registry)
+		waitForHighwater(t, jobFeed, jobRegistry)
+		require.NoError(t, jobFeed.Pause())
+
+		// Clear the highwater from the job record, so that the only record of the
+		// initial scan having completed is the external checkpoint.
+		require.NoError(t, func() error {
+			job, err := jobRegistry.LoadJob(context.Background(), jobFeed.JobID())
+			if err != nil {
+				return err
+			}
+			return job.NoTxn().Update(context.Background(), func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+				md.Progress.Progress = nil
+				ju.UpdateProgress(md.Progress)
+				return nil
+			})
+		}())
+
+		// Resume; we expect the feed to pick up from the external checkpoint
+		// rather than re-running the initial scan.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'c')`)
+		require.NoError(t, jobFeed.Resume())
+		assertPayloads(t, foo, []string{
+			`foo: [3]->{"after": {"a": 3, "b": "c"}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("cloudstorage"))
+
+	sinklessTestFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		expectErrCreatingFeed(t, f,
+			`CREATE CHANGEFEED FOR foo WITH external_checkpoint='nodelocal://1/checkpoints'`,
+			`external_checkpoint is not supported by sinkless changefeeds`)
+	}
+	cdcTest(t, sinklessTestFn, feedTestForceSink("sinkless"))
+}
+
 func TestChangefeedPauseUnpauseCursorAndInitialScan(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -7384,6 +9122,26 @@ func TestChangefeedOnErrorOption(t *testing.T) {
 			}))
 		})
 
+		t.Run(`pause on schema change`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE baz (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `CREATE TABLE qux (a INT PRIMARY KEY)`)
+
+			bazFeed := feed(t, f, `CREATE CHANGEFEED FOR baz, qux WITH on_error='pause'`)
+			defer closeFeed(t, bazFeed)
+			feedJob := bazFeed.(cdctest.EnterpriseTestFeed)
+
+			// Dropping a watched table pauses the changefeed.
+			sqlDB.Exec(t, `DROP TABLE qux`)
+			require.NoError(t, feedJob.WaitForStatus(func(s jobs.Status) bool { return s == jobs.StatusPaused }))
+
+			// Verify `SHOW JOBS` explains why the changefeed paused.
+			var runningStatus string
+			sqlDB.QueryRow(t,
+				`SELECT running_status FROM [SHOW JOBS] WHERE job_id = $1`, feedJob.JobID(),
+			).Scan(&runningStatus)
+			require.Regexp(t, `^paused due to schema change: .*dropped`, runningStatus)
+		})
+
 		t.Run(`fail on error`, func(t *testing.T) {
 			sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, b STRING)`)
 
@@ -8317,6 +10075,56 @@ func TestChangefeedFlushesSinkToReleaseMemory(t *testing.T) {
 	require.Greater(t, sink.numFlushes(), 0)
 }
 
+func TestChangefeedUnackedMetrics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	s, stopServer := makeServer(t)
+	defer stopServer()
+
+	sqlDB := sqlutils.MakeSQLRunner(s.DB)
+	knobs := s.TestingKnobs.
+		DistSQL.(*execinfra.TestingKnobs).
+		Changefeed.(*TestingKnobs)
+
+	// Never advance the frontier, so that the sink is never flushed on its
+	// own.
+	knobs.FilterSpanWithMutation = func(_ *jobspb.ResolvedSpan) (bool, error) {
+		return true, nil
+	}
+
+	// Arrange for a sink which holds on to its allocations (i.e. never
+	// acknowledges messages) until it is flushed.
+	sink := &memoryHoggingSink{}
+	knobs.WrapSink = func(_ Sink, _ jobspb.JobID) Sink {
+		return sink
+	}
+
+	registry := s.Server.JobRegistry().(*jobs.Registry)
+	metrics := registry.MetricsStruct().Changefeed.(*Metrics)
+	sli, err := metrics.getSLIMetrics(defaultSLIScope)
+	require.NoError(t, err)
+
+	sqlDB.Exec(t, `CREATE TABLE foo(key INT PRIMARY KEY DEFAULT unique_rowid(), val INT)`)
+	sqlDB.Exec(t, `INSERT INTO foo (val) SELECT * FROM generate_series(1, 10)`)
+
+	allEmitted := sink.expectRows(10)
+	sqlDB.Exec(t, `CREATE CHANGEFEED FOR foo INTO 'http://host/does/not/matter'`)
+	<-allEmitted
+
+	require.GreaterOrEqual(t, sli.UnackedMessages.Value(), int64(10))
+	require.Greater(t, sli.UnackedBytes.Value(), int64(0))
+
+	// Acknowledge all messages; the gauges should drop back down.
+	require.NoError(t, sink.Flush(context.Background()))
+	testutils.SucceedsSoon(t, func() error {
+		if msgs, bytes := sli.UnackedMessages.Value(), sli.UnackedBytes.Value(); msgs != 0 || bytes != 0 {
+			return errors.Newf("expected no unacked messages, found %d messages (%d bytes)", msgs, bytes)
+		}
+		return nil
+	})
+}
+
 // Test verifies that KV feed does not leak event memory allocation
 // when it reaches end_time or scan boundary.
 func TestKVFeedDoesNotLeakMemoryWhenSkippingEvents(t *testing.T) {
@@ -8807,6 +10615,28 @@ func TestRedactedSchemaRegistry(t *testing.T) {
 	cdcTest(t, testFn, feedTestForceSink("kafka"))
 }
 
+func TestRedactedExternalCheckpoint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+
+		cf := feed(t, f, `CREATE CHANGEFEED FOR foo WITH `+
+			`external_checkpoint='nodelocal://1/checkpoints?AWS_SECRET_ACCESS_KEY=secret-key'`)
+		defer closeFeed(t, cf)
+
+		var description string
+		sqlDB.QueryRow(t, "SELECT description from [SHOW CHANGEFEED JOBS]").Scan(&description)
+
+		assert.Contains(t, description, `AWS_SECRET_ACCESS_KEY=redacted`)
+		assert.NotContains(t, description, `secret-key`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("cloudstorage"))
+}
+
 func TestChangefeedMetricsScopeNotice(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -9527,7 +11357,7 @@ func TestChangefeedProtectedTimestampUpdate(t *testing.T) {
 
 		sqlDB.Exec(t, `CREATE TABLE foo (id INT)`)
 
-		createStmt := `CREATE CHANGEFEED FOR foo WITH resolved='10ms', no_initial_scan`
+		createStmt := `CREATE CHANGEFEED FOR foo WITH resolved='10ms', no_initial_scan, protect_data_from_gc_on_pause`
 		testFeed := feed(t, f, createStmt)
 		defer closeFeed(t, testFeed)
 
@@ -9580,3 +11410,231 @@ func TestChangefeedProtectedTimestampUpdate(t *testing.T) {
 
 	cdcTest(t, testFn, feedTestForceSink("kafka"))
 }
+
+// TestChangefeedCheckpointNow verifies that
+// crdb_internal.changefeed_checkpoint_now persists the frontier to the job
+// record immediately, rather than on the periodic checkpoint schedule.
+func TestChangefeedCheckpointNow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH min_checkpoint_frequency='10ms'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{`foo: [1]->{"after": {"a": 1}}`})
+
+		jobFeed := foo.(cdctest.EnterpriseTestFeed)
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		waitForHighwater(t, jobFeed, registry)
+
+		// Make regular highwater checkpoints effectively never happen, so that
+		// any advance of the persisted highwater is due to the builtin.
+		changefeedbase.MinHighWaterMarkCheckpointAdvance.Override(
+			ctx, &s.Server.ClusterSettings().SV, time.Hour)
+
+		before := *loadProgress(t, jobFeed, registry).GetHighWater()
+		testutils.SucceedsSoon(t, func() error {
+			var raw string
+			sqlDB.QueryRow(t, `SELECT crdb_internal.changefeed_checkpoint_now($1)::STRING`,
+				jobFeed.JobID()).Scan(&raw)
+			checkpointed, err := hlc.ParseHLC(raw)
+			require.NoError(t, err)
+			if !before.Less(checkpointed) {
+				return errors.Newf("checkpointed highwater %s has not advanced past %s", checkpointed, before)
+			}
+			// The checkpoint must be visible in the job record as soon as the
+			// builtin returns.
+			persisted := loadProgress(t, jobFeed, registry).GetHighWater()
+			require.NotNil(t, persisted)
+			require.True(t, checkpointed.LessEq(*persisted),
+				"persisted highwater %s behind checkpointed %s", persisted, checkpointed)
+			return nil
+		})
+
+		// Unknown jobs are rejected.
+		sqlDB.ExpectErr(t, `changefeed job 1 is not running on this node`,
+			`SELECT crdb_internal.changefeed_checkpoint_now(1)`)
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
+// TestChangefeedCheckpointNowConcurrent verifies that checkpoints requested by
+// crdb_internal.changefeed_checkpoint_now from several sessions, while the
+// changefeed is emitting rows and checkpointing on its own, are served by the
+// changefeed's frontier without racing with it. It is meant to be run with the
+// race detector.
+func TestChangefeedCheckpointNowConcurrent(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH min_checkpoint_frequency='1ms', resolved='10ms'`)
+		defer closeFeed(t, foo)
+		jobFeed := foo.(cdctest.EnterpriseTestFeed)
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		waitForHighwater(t, jobFeed, registry)
+
+		const numRequesters, numRequests, numRows = 4, 10, 50
+		g := ctxgroup.WithContext(context.Background())
+		g.GoCtx(func(ctx context.Context) error {
+			for i := 0; i < numRows; i++ {
+				if _, err := s.DB.ExecContext(ctx, `INSERT INTO foo VALUES ($1)`, i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		for r := 0; r < numRequesters; r++ {
+			g.GoCtx(func(ctx context.Context) error {
+				var last hlc.Timestamp
+				for i := 0; i < numRequests; i++ {
+					var raw gosql.NullString
+					if err := s.DB.QueryRowContext(ctx,
+						`SELECT crdb_internal.changefeed_checkpoint_now($1)::STRING`, jobFeed.JobID(),
+					).Scan(&raw); err != nil {
+						return err
+					}
+					checkpointed, err := hlc.ParseHLC(raw.String)
+					if err != nil {
+						return err
+					}
+					// The checkpointed highwater never regresses.
+					if checkpointed.Less(last) {
+						return errors.Newf("checkpointed highwater %s regressed from %s", checkpointed, last)
+					}
+					last = checkpointed
+				}
+				return nil
+			})
+		}
+		require.NoError(t, g.Wait())
+
+		rows := make([]string, numRows)
+		for i := range rows {
+			rows[i] = fmt.Sprintf(`foo: [%d]->{"after": {"a": %d}}`, i, i)
+		}
+		assertPayloads(t, foo, rows)
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
+// TestChangefeedReplaySpan verifies that crdb_internal.changefeed_replay_span
+// re-emits the rows of the replayed span, and only those, once the changefeed
+// is resumed.
+func TestChangefeedReplaySpan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1), (2), (3), (4)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1}}`,
+			`foo: [2]->{"after": {"a": 2}}`,
+			`foo: [3]->{"after": {"a": 3}}`,
+			`foo: [4]->{"after": {"a": 4}}`,
+		})
+
+		jobFeed := foo.(cdctest.EnterpriseTestFeed)
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		waitForHighwater(t, jobFeed, registry)
+
+		const replayQuery = `SELECT crdb_internal.changefeed_replay_span($1,
+crdb_internal.encode_key('foo'::REGCLASS::INT, 1, (2,)),
+crdb_internal.encode_key('foo'::REGCLASS::INT, 1, (4,)))`
+
+		// The changefeed must be paused.
+		sqlDB.ExpectErr(t, `must be paused to replay a span`, replayQuery, jobFeed.JobID())
+
+		require.NoError(t, jobFeed.Pause())
+
+		// Spans which are not watched by the changefeed are rejected.
+		sqlDB.ExpectErr(t, `is not watched by changefeed`,
+			`SELECT crdb_internal.changefeed_replay_span($1, '\x00'::BYTES, '\x01'::BYTES)`,
+			jobFeed.JobID())
+
+		loadJob := func() *jobs.Job {
+			job, err := registry.LoadJob(context.Background(), jobFeed.JobID())
+			require.NoError(t, err)
+			return job
+		}
+		before := loadJob()
+		sqlDB.Exec(t, replayQuery, jobFeed.JobID())
+
+		// The replay is recorded in the progress, as of the high-water, and the
+		// changefeed's details are left alone.
+		after := loadJob()
+		require.Equal(t, before.Details(), after.Details())
+		replay := after.Progress().GetChangefeed().Replay
+		require.NotNil(t, replay)
+		require.Equal(t, *after.Progress().GetHighWater(), replay.Timestamp)
+
+		// Rows in [2, 4) are re-emitted on resume, and the other rows are not.
+		require.NoError(t, jobFeed.Resume())
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (5)`)
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: [2]->{"after": {"a": 2}}`,
+			`foo: [3]->{"after": {"a": 3}}`,
+			`foo: [5]->{"after": {"a": 5}}`,
+		})
+
+		// The replay is cleared once the high-water advances past it.
+		testutils.SucceedsSoon(t, func() error {
+			if loadJob().Progress().GetChangefeed().Replay != nil {
+				return errors.New("waiting for the replay to be cleared")
+			}
+			return nil
+		})
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
+// TestChangefeedSinksBuiltin verifies that crdb_internal.changefeed_sinks
+// lists the sink of every changefeed running on the node, along with the last
+// time its sinks were flushed.
+func TestChangefeedSinksBuiltin(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH resolved='10ms'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{`foo: [1]->{"after": {"a": 1}}`})
+		kafkaJobID := foo.(cdctest.EnterpriseTestFeed).JobID()
+
+		var nullJobID jobspb.JobID
+		sqlDB.QueryRow(t, `CREATE CHANGEFEED FOR foo INTO 'null://' WITH resolved='10ms'`).Scan(&nullJobID)
+		defer sqlDB.Exec(t, `CANCEL JOB $1`, nullJobID)
+
+		sqlDB.CheckQueryResultsRetry(t, `
+SELECT job_id, sink_type, last_error IS NULL
+  FROM crdb_internal.changefeed_sinks()
+ WHERE last_emit_ts > now() - INTERVAL '1 minute'
+ ORDER BY sink_type`,
+			[][]string{
+				{fmt.Sprint(kafkaJobID), `kafka`, `true`},
+				{fmt.Sprint(nullJobID), `null`, `true`},
+			})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"), feedTestNoExternalConnection)
+}
@@ -10,9 +10,11 @@ package changefeedbase
 
 import (
 	"context"
+	"net"
 
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -101,6 +103,61 @@ func MarkRetryableError(cause error) error {
 	return errors.Mark(cause, &retryableError{})
 }
 
+type encodeError struct{}
+
+func (e *encodeError) Error() string {
+	return "changefeed encode error"
+}
+
+// MarkEncodeError decorates underlying error to indicate that it was
+// encountered while encoding a row.
+func MarkEncodeError(cause error) error {
+	if cause == nil {
+		return nil
+	}
+	return errors.Mark(cause, &encodeError{})
+}
+
+// ErrorClass categorizes the retryable errors encountered by a changefeed, so
+// that each class may be retried a different number of times.
+type ErrorClass int
+
+const (
+	// ErrorClassOther applies to all errors not otherwise categorized.
+	ErrorClassOther ErrorClass = iota
+	// ErrorClassTimeout applies to errors caused by an operation, such as a
+	// request to the sink, timing out.
+	ErrorClassTimeout
+	// ErrorClassEncode applies to errors encountered while encoding a row.
+	ErrorClassEncode
+)
+
+// String implements the fmt.Stringer interface.
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassTimeout:
+		return "timeout"
+	case ErrorClassEncode:
+		return "encode"
+	default:
+		return "other"
+	}
+}
+
+// ClassifyError returns the class of the given error.
+func ClassifyError(err error) ErrorClass {
+	if errors.Is(err, &encodeError{}) {
+		return ErrorClassEncode
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.HasType(err, (*timeutil.TimeoutError)(nil)) {
+		return ErrorClassTimeout
+	}
+	if netErr := (net.Error)(nil); errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorClassTimeout
+	}
+	return ErrorClassOther
+}
+
 type drainHelper interface {
 	IsDraining() bool
 }
@@ -74,3 +74,24 @@ func TestAsTerminalError(t *testing.T) {
 		require.Regexp(t, cause.Error(), termErr)
 	})
 }
+
+func TestClassifyError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		err   error
+		class changefeedbase.ErrorClass
+	}{
+		{err: errors.New("boom"), class: changefeedbase.ErrorClassOther},
+		{err: errors.Wrap(context.DeadlineExceeded, "flushing"), class: changefeedbase.ErrorClassTimeout},
+		{err: changefeedbase.MarkEncodeError(errors.New("boom")), class: changefeedbase.ErrorClassEncode},
+		// Encode errors take precedence, since they are caught while encoding.
+		{
+			err:   changefeedbase.MarkEncodeError(errors.Wrap(context.DeadlineExceeded, "registering schema")),
+			class: changefeedbase.ErrorClassEncode,
+		},
+	} {
+		require.Equal(t, tc.class, changefeedbase.ClassifyError(tc.err), "%v", tc.err)
+	}
+}
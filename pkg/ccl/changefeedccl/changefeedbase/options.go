@@ -10,14 +10,19 @@ package changefeedbase
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -46,6 +51,17 @@ type FormatType string
 // OnErrorType configures the job behavior when an error occurs.
 type OnErrorType string
 
+// OnFilterErrorType configures the changefeed behavior when the filter or
+// projection of a CDC query fails to evaluate for a row.
+type OnFilterErrorType string
+
+// DeliveryType configures the delivery guarantee of the changefeed.
+type DeliveryType string
+
+// InitialScanConsistency configures which replicas serve the changefeed's
+// initial scan.
+type InitialScanConsistency string
+
 // SchemaChangeEventClass defines a set of schema change event types which
 // trigger the action defined by the SchemaChangeEventPolicy.
 type SchemaChangeEventClass string
@@ -58,6 +74,40 @@ type SchemaChangePolicy string
 // include virtual columns in an event
 type VirtualColumnVisibility string
 
+// AvroSubjectStrategy defines how the schema registry subjects under which
+// Avro schemas are registered are named.
+type AvroSubjectStrategy string
+
+// DecimalFormat configures how DECIMAL values are rendered by the JSON
+// encoder.
+type DecimalFormat string
+
+// EnumFormat configures how values of user-defined enum types are rendered by
+// the JSON encoder.
+type EnumFormat string
+
+// ComplexFormat configures how ARRAY and JSONB values are rendered by the
+// JSON encoder.
+type ComplexFormat string
+
+// ChecksumAlgorithm configures the checksum emitted with each message.
+type ChecksumAlgorithm string
+
+// CloudStorageLayout configures how the cloudstorage sink lays out the files
+// it writes.
+type CloudStorageLayout string
+
+// KafkaKeySerializer configures how the kafka sink's message keys are
+// serialized, independently of the format of their values.
+type KafkaKeySerializer string
+
+// Tombstone configures the value of the messages emitted for deletes.
+type Tombstone string
+
+// SpatialFormat configures how GEOGRAPHY and GEOMETRY values are rendered by
+// the encoder.
+type SpatialFormat string
+
 // InitialScanType configures whether the changefeed will perform an
 // initial scan, and the type of initial scan that it will perform
 type InitialScanType int
@@ -76,6 +126,8 @@ const (
 // Constants for the options.
 const (
 	OptAvroSchemaPrefix                   = `avro_schema_prefix`
+	OptAvroSubjectStrategy                = `avro_subject_strategy`
+	OptAvroUnionNullFirst                 = `avro_union_null_first`
 	OptConfluentSchemaRegistry            = `confluent_schema_registry`
 	OptCursor                             = `cursor`
 	OptCustomKeyColumn                    = `key_column`
@@ -97,6 +149,7 @@ const (
 	OptExpirePTSAfter                     = `gc_protect_expires_after`
 	OptWebhookAuthHeader                  = `webhook_auth_header`
 	OptWebhookClientTimeout               = `webhook_client_timeout`
+	OptWebhookCompression                 = `webhook_compression`
 	OptOnError                            = `on_error`
 	OptMetricsScope                       = `metrics_label`
 	OptUnordered                          = `unordered`
@@ -106,10 +159,119 @@ const (
 	OptLaggingRangesPollingInterval       = `lagging_ranges_polling_interval`
 	OptIgnoreDisableChangefeedReplication = `ignore_disable_changefeed_replication`
 	OptEncodeJSONValueNullAsObject        = `encode_json_value_null_as_object`
+	OptOnlyInserts                        = `only_inserts`
+	OptFileSize                           = `file_size`
+	OptInitialScanParallelism             = `initial_scan_parallelism`
+	OptMaxMessageBytes                    = `max_message_bytes`
+	OptEmitOpField                        = `emit_op_field`
+	OptInitialScanAt                      = `initial_scan_at`
+	OptValueOnDelete                      = `value_on_delete`
+	OptShardCount                         = `shard_count`
+	OptSQLTableName                       = `sql_table_name`
+	OptMaxEmitRate                        = `max_emit_rate`
+	OptIncludeSource                      = `include_source`
+	OptDelivery                           = `delivery`
+	OptMaxBuffer                          = `max_buffer`
+	OptSortWithinFlush                    = `sort_within_flush`
+	OptSnapshotInterval                   = `snapshot_interval`
+	OptKeyTablePrefix                     = `key_table_prefix`
+	OptKafkaKeySerializer                 = `kafka_key_serializer`
+	OptDDLOnly                            = `ddl_only`
+	OptDecimalFormat                      = `decimal_format`
+	OptResolvedIncludeLag                 = `resolved_include_lag`
+	OptEnumFormat                         = `enum_format`
+	OptCloudStorageLayout                 = `cloudstorage_layout`
+	OptCloudStoragePartitionColumn        = `cloudstorage_partition_column`
+	OptEmitBatchMarkers                   = `emit_batch_markers`
+	OptFieldRename                        = `field_rename`
+	OptDeleteDelay                        = `delete_delay`
+	OptMarkInitialScan                    = `mark_initial_scan`
+	OptTopicOverride                      = `topic_override`
+	OptAvroCombinedKeyValue               = `avro_combined_key_value`
+	OptInitialScanConsistency             = `initial_scan_consistency`
+	OptSpatialFormat                      = `spatial_format`
+	OptOnFilterError                      = `on_filter_error`
+	OptComplexFormat                      = `complex_format`
+	OptEmitChecksum                       = `emit_checksum`
+	OptPartitionFormat                    = `partition_format`
+	OptExternalCheckpoint                 = `external_checkpoint`
+	OptEmitChangedFamilyOnly              = `emit_changed_family_only`
+	OptRetryTimeoutMax                    = `retry_timeout_max`
+	OptRetryEncodeMax                     = `retry_encode_max`
+	OptEmitSchemaPreamble                 = `emit_schema_preamble`
+	OptDeleteTopicSuffix                  = `delete_topic_suffix`
+	OptTombstone                          = `tombstone`
+	OptEmitAfter                          = `emit_after`
+	OptEmitSequence                       = `emit_sequence`
+	OptEmitTTLExpiration                  = `emit_ttl_expiration`
+	OptEmitSchemaFingerprint              = `emit_schema_fingerprint`
 
 	OptVirtualColumnsOmitted VirtualColumnVisibility = `omitted`
 	OptVirtualColumnsNull    VirtualColumnVisibility = `null`
 
+	// OptAvroSubjectStrategyTopic names subjects after the topic, suffixed
+	// with -key or -value (Confluent's TopicNameStrategy). This is the default.
+	OptAvroSubjectStrategyTopic AvroSubjectStrategy = `topic`
+	// OptAvroSubjectStrategyRecord names subjects after the fully-qualified
+	// name of the Avro record (Confluent's RecordNameStrategy).
+	OptAvroSubjectStrategyRecord AvroSubjectStrategy = `record`
+	// OptAvroSubjectStrategyTopicRecord names subjects after both the topic
+	// and the fully-qualified name of the Avro record
+	// (Confluent's TopicRecordNameStrategy).
+	OptAvroSubjectStrategyTopicRecord AvroSubjectStrategy = `topic_record`
+
+	// OptDecimalFormatNumber renders decimals as JSON numbers. This is the
+	// default.
+	OptDecimalFormatNumber DecimalFormat = `number`
+	// OptDecimalFormatString renders decimals as JSON strings, which preserve
+	// their full precision for consumers which parse numbers as doubles.
+	OptDecimalFormatString DecimalFormat = `string`
+
+	// OptEnumFormatLabel renders enum values as their string labels, resolved
+	// against the enum type's definition as of the row's timestamp. This is the
+	// default.
+	OptEnumFormatLabel EnumFormat = `label`
+	// OptEnumFormatPhysical renders enum values as the hex encoding of their
+	// physical representation, which is stable across label renames.
+	OptEnumFormatPhysical EnumFormat = `physical`
+
+	// OptComplexFormatNested renders arrays as JSON arrays and JSONB values as
+	// nested JSON. This is the default.
+	OptComplexFormatNested ComplexFormat = `nested`
+	// OptComplexFormatString renders arrays and JSONB values as JSON strings
+	// holding their text representation, for consumers which can't handle
+	// nested values.
+	OptComplexFormatString ComplexFormat = `string`
+
+	// OptChecksumCRC32 emits the IEEE CRC32 of each message's value.
+	OptChecksumCRC32 ChecksumAlgorithm = `crc32`
+
+	// OptCloudStorageLayoutDefault writes data files into date-based
+	// directories, alongside RESOLVED files. This is the default.
+	OptCloudStorageLayoutDefault CloudStorageLayout = `default`
+	// OptCloudStorageLayoutIceberg writes each topic as an Apache
+	// Iceberg-style table: data files go into the topic's data directory, and
+	// are committed by manifest and metadata files written into the topic's
+	// metadata directory at resolved timestamps.
+	OptCloudStorageLayoutIceberg CloudStorageLayout = `iceberg`
+
+	// OptKafkaKeySerializerString serializes keys as the text of the primary
+	// key's values, separated by commas.
+	OptKafkaKeySerializerString KafkaKeySerializer = `string`
+	// OptKafkaKeySerializerJSON serializes keys as a JSON array of the
+	// primary key's values, as with format=json.
+	OptKafkaKeySerializerJSON KafkaKeySerializer = `json`
+	// OptKafkaKeySerializerAvro serializes keys as Avro records registered
+	// with the confluent schema registry, as with format=avro.
+	OptKafkaKeySerializerAvro KafkaKeySerializer = `avro`
+
+	// OptTombstoneWrapped emits deletes with the value of the changefeed's
+	// envelope, e.g. `{"after": null}`. This is the default.
+	OptTombstoneWrapped Tombstone = `wrapped`
+	// OptTombstoneNull emits deletes with a null value, which kafka's log
+	// compaction treats as a tombstone for the message's key.
+	OptTombstoneNull Tombstone = `null`
+
 	// OptSchemaChangeEventClassColumnChange corresponds to all schema change
 	// events which add or remove any column.
 	OptSchemaChangeEventClassColumnChange SchemaChangeEventClass = `column_changes`
@@ -144,6 +306,10 @@ const (
 	// OptEmitAllResolvedTimestamps is a sentinel value to indicate that all
 	// resolved timestamp events should be emitted.
 	OptEmitAllResolvedTimestamps = ``
+	// OptEmitResolvedTimestampsOnCatchup is a sentinel value to indicate that
+	// resolved timestamps should only be emitted when the changefeed catches
+	// up to the current time.
+	OptEmitResolvedTimestampsOnCatchup = `on_catchup`
 
 	OptInitialScanOnly = `initial_scan_only`
 
@@ -157,10 +323,43 @@ const (
 	OptFormatAvro    FormatType = `avro`
 	OptFormatCSV     FormatType = `csv`
 	OptFormatParquet FormatType = `parquet`
+	OptFormatSQL     FormatType = `sql`
 
 	OptOnErrorFail  OnErrorType = `fail`
 	OptOnErrorPause OnErrorType = `pause`
 
+	// OptOnFilterErrorFail fails the changefeed when its CDC query fails to
+	// evaluate for a row. This is the default.
+	OptOnFilterErrorFail OnFilterErrorType = `fail`
+	// OptOnFilterErrorSkip skips rows for which the changefeed's CDC query
+	// fails to evaluate.
+	OptOnFilterErrorSkip OnFilterErrorType = `skip`
+
+	// OptDeliveryAtLeastOnce guarantees that every change is emitted at least
+	// once. When the changefeed's buffer fills up, the changefeed applies
+	// backpressure. This is the default.
+	OptDeliveryAtLeastOnce DeliveryType = `at_least_once`
+	// OptDeliveryAtMostOnce allows the changefeed to drop buffered changes to
+	// a key that have been superseded by a later buffered change to the same
+	// key, instead of applying backpressure, when its buffer fills up.
+	OptDeliveryAtMostOnce DeliveryType = `at_most_once`
+
+	// OptInitialScanConsistencyLeaseholder has the initial scan served by the
+	// leaseholders of the scanned ranges. This is the default.
+	OptInitialScanConsistencyLeaseholder InitialScanConsistency = `leaseholder`
+	// OptInitialScanConsistencyFollower routes the initial scan to the nearest
+	// replica of each range, which serves it as a follower read if the scan's
+	// timestamp is below the range's closed timestamp. This reduces the load
+	// the scan puts on leaseholders.
+	OptInitialScanConsistencyFollower InitialScanConsistency = `follower`
+
+	// OptSpatialFormatEWKB renders spatial values as their EWKB encoding: raw
+	// bytes with format=avro and a hex string with format=json.
+	OptSpatialFormatEWKB SpatialFormat = `ewkb`
+	// OptSpatialFormatGeoJSON renders spatial values as GeoJSON: a JSON object
+	// with format=json and a JSON string with format=avro.
+	OptSpatialFormatGeoJSON SpatialFormat = `geojson`
+
 	DeprecatedOptFormatAvro                   = `experimental_avro`
 	DeprecatedSinkSchemeCloudStorageAzure     = `experimental-azure`
 	DeprecatedSinkSchemeCloudStorageGCS       = `experimental-gs`
@@ -176,6 +375,7 @@ const (
 
 	// OptKafkaSinkConfig is a JSON configuration for kafka sink (kafkaSinkConfig).
 	OptKafkaSinkConfig   = `kafka_sink_config`
+	OptKafkaTopicConfig  = `kafka_topic_config`
 	OptPubsubSinkConfig  = `pubsub_sink_config`
 	OptWebhookSinkConfig = `webhook_sink_config`
 
@@ -183,8 +383,10 @@ const (
 	// Note that this option is only allowed for alter changefeed statements.
 	OptSink = `sink`
 
-	// Deprecated options.
-	DeprecatedOptProtectDataFromGCOnPause = `protect_data_from_gc_on_pause`
+	// OptProtectDataFromGCOnPause retains the changefeed's protected timestamp
+	// record while the job is paused. Without it, the record is released when
+	// the job is paused and recreated once the job resumes.
+	OptProtectDataFromGCOnPause = `protect_data_from_gc_on_pause`
 
 	SinkParamCACert                 = `ca_cert`
 	SinkParamClientCert             = `client_cert`
@@ -208,6 +410,10 @@ const (
 	SinkSchemeWebhookHTTP           = `webhook-http`
 	SinkSchemeWebhookHTTPS          = `webhook-https`
 	SinkSchemePulsar                = `pulsar`
+	SinkSchemeSyslog                = `syslog`
+	SinkSchemeUnix                  = `unix`
+	SinkSchemeClickHouse            = `clickhouse`
+	SinkSchemeAMQP                  = `amqp`
 	SinkSchemeExternalConnection    = `external`
 	SinkParamSASLEnabled            = `sasl_enabled`
 	SinkParamSASLHandshake          = `sasl_handshake`
@@ -223,6 +429,10 @@ const (
 	SinkParamSASLAwsRegion          = `sasl_aws_region`
 	SinkParamSASLAwsIAMSessionName  = `sasl_aws_iam_session_name`
 	SinkParamTableNameAttribute     = `with_table_name_attribute`
+	SinkParamClickHouseTable        = `table_name`
+	SinkParamClickHouseSignColumn   = `sign_column`
+	SinkParamAMQPExchange           = `exchange`
+	SinkParamAMQPResolvedRoutingKey = `resolved_routing_key`
 
 	SinkSchemeConfluentKafka    = `confluent-cloud`
 	SinkParamConfluentAPIKey    = `api_key`
@@ -281,6 +491,12 @@ const (
 	OptionTypeEnum
 
 	OptionTypeJSON
+
+	// OptionTypeBytes is a byte size such as '16MiB'.
+	OptionTypeBytes
+
+	// OptionTypeInt is a positive integer.
+	OptionTypeInt
 )
 
 // OptionPermittedValues is used in validations and is meant to be self-documenting.
@@ -333,17 +549,21 @@ var durationOption = OptionPermittedValues{Type: OptionTypeDuration}
 var timestampOption = OptionPermittedValues{Type: OptionTypeTimestamp}
 var flagOption = OptionPermittedValues{Type: OptionTypeFlag}
 var jsonOption = OptionPermittedValues{Type: OptionTypeJSON}
+var bytesOption = OptionPermittedValues{Type: OptionTypeBytes}
+var intOption = OptionPermittedValues{Type: OptionTypeInt}
 
 // ChangefeedOptionExpectValues is used to parse changefeed options using
 // PlanHookState.TypeAsStringOpts().
 var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptAvroSchemaPrefix:                   stringOption,
+	OptAvroSubjectStrategy:                enum("topic", "record", "topic_record"),
+	OptAvroUnionNullFirst:                 enum("true", "false"),
 	OptConfluentSchemaRegistry:            stringOption,
 	OptCursor:                             timestampOption,
 	OptCustomKeyColumn:                    stringOption,
 	OptEndTime:                            timestampOption,
 	OptEnvelope:                           enum("row", "key_only", "wrapped", "deprecated_row", "bare"),
-	OptFormat:                             enum("json", "avro", "csv", "experimental_avro", "parquet"),
+	OptFormat:                             enum("json", "avro", "csv", "experimental_avro", "parquet", "sql"),
 	OptFullTableName:                      flagOption,
 	OptKeyInValue:                         flagOption,
 	OptTopicInValue:                       flagOption,
@@ -359,13 +579,15 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptInitialScan:                        enum("yes", "no", "only").orEmptyMeans("yes"),
 	OptNoInitialScan:                      flagOption,
 	OptInitialScanOnly:                    flagOption,
-	DeprecatedOptProtectDataFromGCOnPause: flagOption,
+	OptProtectDataFromGCOnPause:           flagOption,
 	OptExpirePTSAfter:                     durationOption.thatCanBeZero(),
 	OptKafkaSinkConfig:                    jsonOption,
+	OptKafkaTopicConfig:                   stringOption,
 	OptPubsubSinkConfig:                   jsonOption,
 	OptWebhookSinkConfig:                  jsonOption,
 	OptWebhookAuthHeader:                  stringOption,
 	OptWebhookClientTimeout:               durationOption,
+	OptWebhookCompression:                 enum("gzip"),
 	OptOnError:                            enum("pause", "fail"),
 	OptMetricsScope:                       stringOption,
 	OptUnordered:                          flagOption,
@@ -375,6 +597,52 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptLaggingRangesPollingInterval:       durationOption,
 	OptIgnoreDisableChangefeedReplication: flagOption,
 	OptEncodeJSONValueNullAsObject:        flagOption,
+	OptOnlyInserts:                        flagOption,
+	OptFileSize:                           bytesOption,
+	OptInitialScanParallelism:             intOption,
+	OptMaxMessageBytes:                    bytesOption,
+	OptEmitOpField:                        flagOption,
+	OptInitialScanAt:                      timestampOption,
+	OptValueOnDelete:                      flagOption,
+	OptShardCount:                         intOption,
+	OptSQLTableName:                       stringOption,
+	OptMaxEmitRate:                        intOption,
+	OptIncludeSource:                      flagOption,
+	OptDelivery:                           enum("at_least_once", "at_most_once"),
+	OptMaxBuffer:                          bytesOption,
+	OptSortWithinFlush:                    flagOption,
+	OptSnapshotInterval:                   durationOption,
+	OptKeyTablePrefix:                     flagOption,
+	OptKafkaKeySerializer:                 enum("string", "json", "avro"),
+	OptDDLOnly:                            flagOption,
+	OptDecimalFormat:                      enum("number", "string"),
+	OptResolvedIncludeLag:                 flagOption,
+	OptEnumFormat:                         enum("label", "physical"),
+	OptCloudStorageLayout:                 enum("default", "iceberg"),
+	OptCloudStoragePartitionColumn:        stringOption,
+	OptEmitBatchMarkers:                   flagOption,
+	OptFieldRename:                        stringOption,
+	OptDeleteDelay:                        durationOption,
+	OptMarkInitialScan:                    flagOption,
+	OptTopicOverride:                      stringOption,
+	OptAvroCombinedKeyValue:               flagOption,
+	OptInitialScanConsistency:             enum("leaseholder", "follower"),
+	OptSpatialFormat:                      enum("ewkb", "geojson"),
+	OptOnFilterError:                      enum("fail", "skip"),
+	OptComplexFormat:                      enum("nested", "string"),
+	OptEmitChecksum:                       enum("crc32"),
+	OptPartitionFormat:                    stringOption,
+	OptExternalCheckpoint:                 stringOption,
+	OptEmitChangedFamilyOnly:              flagOption,
+	OptRetryTimeoutMax:                    intOption,
+	OptRetryEncodeMax:                     intOption,
+	OptEmitSchemaPreamble:                 flagOption,
+	OptDeleteTopicSuffix:                  stringOption,
+	OptTombstone:                          enum("wrapped", "null"),
+	OptEmitAfter:                          timestampOption,
+	OptEmitSequence:                       flagOption,
+	OptEmitTTLExpiration:                  flagOption,
+	OptEmitSchemaFingerprint:              flagOption,
 }
 
 // CommonOptions is options common to all sinks
@@ -389,23 +657,49 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 	OptMinCheckpointFrequency, OptMetricsScope, OptVirtualColumns, Topics, OptExpirePTSAfter,
 	OptExecutionLocality, OptLaggingRangesThreshold, OptLaggingRangesPollingInterval,
 	OptIgnoreDisableChangefeedReplication, OptEncodeJSONValueNullAsObject,
+	OptOnlyInserts, OptInitialScanParallelism, OptMaxMessageBytes,
+	OptProtectDataFromGCOnPause, OptEmitOpField, OptInitialScanAt, OptValueOnDelete,
+	OptShardCount, OptSQLTableName, OptMaxEmitRate, OptIncludeSource,
+	OptDelivery, OptMaxBuffer, OptSortWithinFlush, OptSnapshotInterval,
+	OptKeyTablePrefix, OptDDLOnly, OptDecimalFormat, OptResolvedIncludeLag,
+	OptEnumFormat, OptEmitBatchMarkers, OptFieldRename, OptDeleteDelay, OptMarkInitialScan,
+	OptInitialScanConsistency, OptSpatialFormat, OptOnFilterError, OptComplexFormat,
+	OptEmitChecksum, OptExternalCheckpoint, OptEmitChangedFamilyOnly,
+	OptRetryTimeoutMax, OptRetryEncodeMax, OptEmitSchemaPreamble, OptEmitAfter,
+	OptEmitSequence, OptEmitTTLExpiration, OptEmitSchemaFingerprint,
 )
 
 // SQLValidOptions is options exclusive to SQL sink
 var SQLValidOptions map[string]struct{} = nil
 
 // KafkaValidOptions is options exclusive to Kafka sink
-var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig)
+var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptAvroSubjectStrategy, OptAvroUnionNullFirst, OptConfluentSchemaRegistry, OptKafkaSinkConfig,
+	OptKafkaKeySerializer, OptAvroCombinedKeyValue, OptKafkaTopicConfig, OptDeleteTopicSuffix,
+	OptTombstone)
 
 // CloudStorageValidOptions is options exclusive to cloud storage sink
-var CloudStorageValidOptions = makeStringSet(OptCompression)
+var CloudStorageValidOptions = makeStringSet(OptCompression, OptFileSize,
+	OptCloudStorageLayout, OptCloudStoragePartitionColumn, OptTopicOverride, OptPartitionFormat)
 
 // WebhookValidOptions is options exclusive to webhook sink
-var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig)
+var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig,
+	OptWebhookCompression)
 
 // PubsubValidOptions is options exclusive to pubsub sink
 var PubsubValidOptions = makeStringSet(OptPubsubSinkConfig)
 
+// SyslogValidOptions is options exclusive to syslog sink
+var SyslogValidOptions map[string]struct{} = nil
+
+// UnixValidOptions is options exclusive to unix socket sink
+var UnixValidOptions map[string]struct{} = nil
+
+// ClickHouseValidOptions is options exclusive to clickhouse sink
+var ClickHouseValidOptions map[string]struct{} = nil
+
+// AMQPValidOptions is options exclusive to amqp sink
+var AMQPValidOptions map[string]struct{} = nil
+
 // ExternalConnectionValidOptions is options exclusive to the external
 // connection sink.
 //
@@ -416,10 +710,11 @@ var ExternalConnectionValidOptions = unionStringSets(SQLValidOptions, KafkaValid
 
 // CaseInsensitiveOpts options which supports case Insensitive value
 var CaseInsensitiveOpts = makeStringSet(OptFormat, OptEnvelope, OptCompression, OptSchemaChangeEvents,
-	OptSchemaChangePolicy, OptOnError, OptInitialScan)
+	OptSchemaChangePolicy, OptOnError, OptInitialScan, OptWebhookCompression, OptOnFilterError,
+	OptEmitChecksum)
 
 // RetiredOptions are the options which are no longer active.
-var RetiredOptions = makeStringSet(DeprecatedOptProtectDataFromGCOnPause)
+var RetiredOptions = makeStringSet()
 
 // redactionFunc is a function applied to a string option which returns its redacted value.
 type redactionFunc func(string) (string, error)
@@ -441,11 +736,18 @@ func RedactUserFromURI(uri string) (string, error) {
 	return u.String(), nil
 }
 
+// redactExternalStorageURI removes the credentials from an external storage
+// URI, as is done for the sink URI.
+func redactExternalStorageURI(uri string) (string, error) {
+	return cloud.SanitizeExternalStorageURI(uri, nil /* extraParams */)
+}
+
 // RedactedOptions are options whose values should be replaced with "redacted" in job descriptions and errors.
 var RedactedOptions = map[string]redactionFunc{
 	OptWebhookAuthHeader:       redactSimple,
 	SinkParamClientKey:         redactSimple,
 	OptConfluentSchemaRegistry: RedactUserFromURI,
+	OptExternalCheckpoint:      redactExternalStorageURI,
 }
 
 // NoLongerExperimental aliases options prefixed with experimental that no longer need to be
@@ -465,11 +767,18 @@ type OptionsSet map[string]struct{}
 // InitialScanOnlyUnsupportedOptions is options that are not supported with the
 // initial scan only option
 var InitialScanOnlyUnsupportedOptions OptionsSet = makeStringSet(OptEndTime, OptResolvedTimestamps, OptDiff,
-	OptMVCCTimestamps, OptUpdatedTimestamps)
+	OptMVCCTimestamps, OptUpdatedTimestamps, OptSnapshotInterval, OptDDLOnly)
 
 // ParquetFormatUnsupportedOptions is options that are not supported with the
 // parquet format.
-var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue)
+var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue, OptEmitOpField,
+	OptValueOnDelete, OptShardCount, OptIncludeSource, OptSortWithinFlush, OptDeleteDelay)
+
+// SQLFormatUnsupportedOptions are options which add metadata that can't be
+// expressed by the DML statements emitted with format=sql.
+var SQLFormatUnsupportedOptions OptionsSet = makeStringSet(OptResolvedTimestamps,
+	OptKeyInValue, OptTopicInValue, OptUpdatedTimestamps, OptMVCCTimestamps,
+	OptEmitOpField, OptValueOnDelete, OptMaxMessageBytes, OptIncludeSource)
 
 // AlterChangefeedUnsupportedOptions are changefeed options that we do not allow
 // users to alter.
@@ -478,7 +787,7 @@ var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue)
 // allowed to alter either of these options. We need to support the alteration
 // of these fields.
 var AlterChangefeedUnsupportedOptions OptionsSet = makeStringSet(OptCursor, OptInitialScan,
-	OptNoInitialScan, OptInitialScanOnly, OptEndTime)
+	OptNoInitialScan, OptInitialScanOnly, OptEndTime, OptInitialScanAt)
 
 // AlterChangefeedOptionExpectValues is used to parse alter changefeed options
 // using PlanHookState.TypeAsStringOpts().
@@ -529,10 +838,14 @@ func makeDirectedInvertedIndex(pairs []dependentOption) map[string][]dependentOp
 
 var incompatibleOptionsMap = makeInvertedIndex([]incompatibleOptions{
 	{opt1: OptUnordered, opt2: OptResolvedTimestamps, reason: `resolved timestamps cannot be guaranteed to be correct in unordered mode`},
+	{opt1: OptOnlyInserts, opt2: OptDiff, reason: `the before image of an insert is always null`},
+	{opt1: OptUnordered, opt2: OptSortWithinFlush, reason: `rows cannot be sorted by timestamp in unordered mode`},
 })
 
 var dependentOptionsMap = makeDirectedInvertedIndex([]dependentOption{
 	{opt1: OptCustomKeyColumn, opt2: OptUnordered, reason: `using a value other than the primary key as the message key means end-to-end ordering cannot be preserved`},
+	{opt1: OptResolvedIncludeLag, opt2: OptResolvedTimestamps, reason: `the lag is only included in resolved messages`},
+	{opt1: OptEmitChangedFamilyOnly, opt2: OptSplitColumnFamilies, reason: `column families are otherwise emitted together`},
 })
 
 // MakeStatementOptions wraps and canonicalizes the options we get
@@ -627,6 +940,44 @@ func (s StatementOptions) GetEndTime() string {
 	return s.m[OptEndTime]
 }
 
+// HasInitialScanAt returns true if the initial scan should be deferred until
+// a user-provided time.
+func (s StatementOptions) HasInitialScanAt() bool {
+	_, ok := s.m[OptInitialScanAt]
+	return ok
+}
+
+// GetInitialScanAt returns the time at which the initial scan should run.
+// Once the changefeed has been planned, this is a decimal HLC timestamp.
+func (s StatementOptions) GetInitialScanAt() string {
+	return s.m[OptInitialScanAt]
+}
+
+// SetInitialScanAt replaces the user-provided initial scan time with its
+// evaluated form so that it can be interpreted without an eval context.
+func (s StatementOptions) SetInitialScanAt(v string) {
+	s.m[OptInitialScanAt] = v
+}
+
+// HasEmitAfter returns true if emission should be held until a user-provided
+// time.
+func (s StatementOptions) HasEmitAfter() bool {
+	_, ok := s.m[OptEmitAfter]
+	return ok
+}
+
+// GetEmitAfter returns the time until which emission is held. Once the
+// changefeed has been planned, this is a decimal HLC timestamp.
+func (s StatementOptions) GetEmitAfter() string {
+	return s.m[OptEmitAfter]
+}
+
+// SetEmitAfter replaces the user-provided emission time with its evaluated
+// form so that it can be interpreted without an eval context.
+func (s StatementOptions) SetEmitAfter(v string) {
+	s.m[OptEmitAfter] = v
+}
+
 func (s StatementOptions) getEnumValue(k string) (string, error) {
 	enumOptions := ChangefeedOptionExpectValues[k]
 	rawVal, present := s.m[k]
@@ -666,6 +1017,40 @@ func (s StatementOptions) getDurationValue(k string) (*time.Duration, error) {
 	}
 }
 
+// getBytesValue validates that the option `k` was supplied with a valid,
+// positive byte size.
+func (s StatementOptions) getBytesValue(k string) (int64, bool, error) {
+	v, ok := s.m[k]
+	if !ok {
+		return 0, false, nil
+	}
+	b, err := humanizeutil.ParseBytes(v)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "problem parsing option %s", k)
+	}
+	if b <= 0 {
+		return 0, false, errors.Errorf("option %s must be a size greater than 0", k)
+	}
+	return b, true, nil
+}
+
+// getIntValue validates that the option `k` was supplied with a valid,
+// positive integer.
+func (s StatementOptions) getIntValue(k string) (int, bool, error) {
+	v, ok := s.m[k]
+	if !ok {
+		return 0, false, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "problem parsing option %s", k)
+	}
+	if i <= 0 {
+		return 0, false, errors.Errorf("option %s must be an integer greater than 0", k)
+	}
+	return i, true, nil
+}
+
 func (s StatementOptions) getJSONValue(k string) SinkSpecificJSONConfig {
 	return SinkSpecificJSONConfig(s.m[k])
 }
@@ -767,9 +1152,171 @@ func (s StatementOptions) GetCanHandle() CanHandle {
 	if s.IsSet(OptCustomKeyColumn) {
 		h.RequiredColumns = append(h.RequiredColumns, s.m[OptCustomKeyColumn])
 	}
+	// A malformed field_rename is rejected by GetEncodingOptions.
+	if renames, err := ParseFieldRename(s.m[OptFieldRename]); err == nil {
+		sources := make([]string, 0, len(renames))
+		for source := range renames {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+		h.RequiredColumns = append(h.RequiredColumns, sources...)
+	}
 	return h
 }
 
+// ParseFieldRename parses the value of the field_rename option, a comma
+// separated list of source:output column name pairs, into a map from source
+// to output column names.
+func ParseFieldRename(v string) (map[string]string, error) {
+	if v == `` {
+		return nil, nil
+	}
+	renames := make(map[string]string)
+	outputs := make(map[string]struct{})
+	for _, pair := range strings.Split(v, `,`) {
+		source, output, ok := strings.Cut(strings.TrimSpace(pair), `:`)
+		source, output = strings.TrimSpace(source), strings.TrimSpace(output)
+		if !ok || source == `` || output == `` {
+			return nil, errors.Errorf(
+				`problem parsing option %s: expected source:output, found %q`, OptFieldRename, pair)
+		}
+		if _, ok := renames[source]; ok {
+			return nil, errors.Errorf(`option %s renames column %s more than once`, OptFieldRename, source)
+		}
+		if _, ok := outputs[output]; ok {
+			return nil, errors.Errorf(`option %s renames more than one column to %s`, OptFieldRename, output)
+		}
+		renames[source] = output
+		outputs[output] = struct{}{}
+	}
+	return renames, nil
+}
+
+// ParseTopicOverride parses the value of the topic_override option, a comma
+// separated list of table:topic pairs, into a map from the statement time name
+// of each table to the topic which its rows are emitted to. Several tables may
+// be emitted to the same topic.
+func ParseTopicOverride(v string) (map[string]string, error) {
+	if v == `` {
+		return nil, nil
+	}
+	overrides := make(map[string]string)
+	for _, pair := range strings.Split(v, `,`) {
+		// Table names may be qualified, so the topic follows the last colon.
+		pair = strings.TrimSpace(pair)
+		i := strings.LastIndexByte(pair, ':')
+		if i < 0 {
+			return nil, errors.Errorf(
+				`problem parsing option %s: expected table:topic, found %q`, OptTopicOverride, pair)
+		}
+		table, topic := strings.TrimSpace(pair[:i]), strings.TrimSpace(pair[i+1:])
+		if table == `` || topic == `` {
+			return nil, errors.Errorf(
+				`problem parsing option %s: expected table:topic, found %q`, OptTopicOverride, pair)
+		}
+		if !isLegalTopicName(topic) {
+			return nil, errors.Errorf(`option %s maps table %s to illegal topic name %q: `+
+				`topic names may only contain letters, digits and underscores`, OptTopicOverride, table, topic)
+		}
+		if _, ok := overrides[table]; ok {
+			return nil, errors.Errorf(`option %s maps table %s more than once`, OptTopicOverride, table)
+		}
+		overrides[table] = topic
+	}
+	return overrides, nil
+}
+
+// KafkaTopicConfig holds the kafka producer settings which the
+// kafka_topic_config option overrides for a single topic.
+type KafkaTopicConfig struct {
+	// Acks, if set, is the number of acknowledgements required for each
+	// produce request to the topic, with the values accepted by the
+	// RequiredAcks field of kafka_sink_config.
+	Acks string `json:"acks,omitempty"`
+	// Retries, if set, is the number of times producing a message to the
+	// topic is retried before it fails.
+	Retries *int `json:"retries,omitempty"`
+}
+
+// ParseKafkaTopicConfig parses the value of the kafka_topic_config option, a
+// comma separated list of topic:config pairs in which each config is a JSON
+// object, e.g. `foo:{"acks":"all"},bar:{"acks":"1","retries":10}`, into a map
+// from each topic to its config.
+func ParseKafkaTopicConfig(v string) (map[string]KafkaTopicConfig, error) {
+	if v == `` {
+		return nil, nil
+	}
+	configs := make(map[string]KafkaTopicConfig)
+	for rest := v; ; {
+		// Kafka topic names may not contain colons, so the config follows the
+		// first one.
+		rest = strings.TrimSpace(rest)
+		i := strings.IndexByte(rest, ':')
+		if i <= 0 {
+			return nil, errors.Errorf(
+				`problem parsing option %s: expected topic:{...}, found %q`, OptKafkaTopicConfig, rest)
+		}
+		topic := strings.TrimSpace(rest[:i])
+		dec := json.NewDecoder(strings.NewReader(rest[i+1:]))
+		dec.DisallowUnknownFields()
+		var cfg KafkaTopicConfig
+		if err := dec.Decode(&cfg); err != nil {
+			return nil, errors.Wrapf(err, `problem parsing option %s for topic %s`, OptKafkaTopicConfig, topic)
+		}
+		switch strings.ToUpper(cfg.Acks) {
+		case ``, `0`, `NONE`, `1`, `ONE`, `-1`, `ALL`:
+		default:
+			return nil, errors.Errorf(`option %s sets invalid acks value %q for topic %s: `+
+				`must be "NONE"/"0", "ONE"/"1", or "ALL"/"-1"`, OptKafkaTopicConfig, cfg.Acks, topic)
+		}
+		if cfg.Retries != nil && *cfg.Retries < 0 {
+			return nil, errors.Errorf(`option %s sets negative retries for topic %s`, OptKafkaTopicConfig, topic)
+		}
+		if _, ok := configs[topic]; ok {
+			return nil, errors.Errorf(`option %s configures topic %s more than once`, OptKafkaTopicConfig, topic)
+		}
+		configs[topic] = cfg
+
+		rest = strings.TrimSpace(rest[i+1:][dec.InputOffset():])
+		if rest == `` {
+			return configs, nil
+		}
+		if rest[0] != ',' {
+			return nil, errors.Errorf(
+				`problem parsing option %s: expected a comma after the config of topic %s, found %q`,
+				OptKafkaTopicConfig, topic, rest)
+		}
+		rest = rest[1:]
+	}
+}
+
+// PartitionFormatColumnPrefix prefixes the column named by a partition_format
+// which partitions data files by the value of that column, rather than by
+// their earliest event time, e.g. partition_format='column=region'.
+const PartitionFormatColumnPrefix = `column=`
+
+// ParsePartitionFormatColumn returns the column by whose value the given
+// partition_format partitions data files, or false if it partitions them by
+// time.
+func ParsePartitionFormatColumn(v string) (string, bool) {
+	if !strings.HasPrefix(v, PartitionFormatColumnPrefix) {
+		return ``, false
+	}
+	return strings.TrimPrefix(v, PartitionFormatColumnPrefix), true
+}
+
+// isLegalTopicName returns true if the name can be used as a topic by every
+// sink, including as a directory or file name component by the cloudstorage
+// sink.
+func isLegalTopicName(name string) bool {
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return name != ``
+}
+
 // EncodingOptions describe how events are encoded when
 // sent to the sink.
 type EncodingOptions struct {
@@ -783,11 +1330,93 @@ type EncodingOptions struct {
 	Diff                        bool
 	EncodeJSONValueNullAsObject bool
 	AvroSchemaPrefix            string
+	AvroSubjectStrategy         AvroSubjectStrategy
 	SchemaRegistryURI           string
 	Compression                 string
 	CustomKeyColumn             string
+	// MaxMessageBytes, if positive, is the maximum size of an encoded value.
+	// Values larger than this are split into multiple chunk messages which
+	// share the row's key; see OptMaxMessageBytes.
+	MaxMessageBytes int64
+	// EmitOpField adds an `op` field to each row's value that classifies
+	// the event as an INSERT, UPDATE or DELETE.
+	EmitOpField bool
+	// AvroUnionNullLast places null last in the unions which make avro
+	// fields nullable; see OptAvroUnionNullFirst.
+	AvroUnionNullLast bool
+	// AvroCombinedKeyValue encodes each row's value as a single avro record
+	// with nested `key` and `value` records.
+	AvroCombinedKeyValue bool
+	// ValueOnDelete populates the `before` field of delete events with the
+	// deleted row's last value, without doing so for other events.
+	ValueOnDelete bool
+	// ShardCount, if positive, replaces each message's key with the key of
+	// one of ShardCount shards, chosen by hashing the row's primary key.
+	ShardCount int
+	// SQLTableName, if set, is the table targeted by the statements emitted
+	// with format=sql instead of the watched table's name.
+	SQLTableName string
+	// IncludeSource adds a `source` field to each row's value identifying
+	// the node, and its locality, which emitted the row.
+	IncludeSource bool
+	// SnapshotField adds a `snapshot` field to each row's value which is
+	// true for rows emitted by a periodic snapshot; see OptSnapshotInterval.
+	SnapshotField bool
+	// KeyTablePrefix prepends the name of the row's table to each key, so
+	// that keys from different tables can be told apart when all tables are
+	// emitted to a single topic.
+	KeyTablePrefix bool
+	// KafkaKeySerializer, if set, is how keys are serialized, regardless of
+	// Format; see OptKafkaKeySerializer.
+	KafkaKeySerializer KafkaKeySerializer
+	// Tombstone is the value of the messages emitted for deletes; see
+	// OptTombstone.
+	Tombstone Tombstone
+	// DecimalFormat is how the JSON encoder renders DECIMAL values.
+	DecimalFormat DecimalFormat
+	// ResolvedIncludeLag adds a `lag_ms` field to resolved messages: the
+	// number of milliseconds between the resolved timestamp and the time the
+	// message was encoded.
+	ResolvedIncludeLag bool
+	// EnumFormat is how the JSON encoder renders enum values.
+	EnumFormat EnumFormat
+	// ComplexFormat is how the JSON encoder renders ARRAY and JSONB values.
+	ComplexFormat ComplexFormat
+	// Checksum, if set, is the checksum emitted with each row: in a header
+	// with the kafka sink, and in a `crc32` field of the envelope otherwise.
+	Checksum ChecksumAlgorithm
+	// SpatialFormat, if set, is how GEOGRAPHY and GEOMETRY values are
+	// rendered. If unset, the encoder's default is used: GeoJSON with
+	// format=json and EWKB with format=avro.
+	SpatialFormat SpatialFormat
+	// CloudStorageLayout is how the cloudstorage sink lays out the files it
+	// writes.
+	CloudStorageLayout CloudStorageLayout
+	// CloudStoragePartitionColumn, if set, is the column by whose value the
+	// data files of an iceberg cloudstorage layout are partitioned.
+	CloudStoragePartitionColumn string
+	// FieldRename, if set, renames columns in encoded values; see
+	// ParseFieldRename for its format.
+	FieldRename string
+	// TopicOverride, if set, maps tables to the topics their rows are
+	// emitted to; see ParseTopicOverride for its format.
+	TopicOverride string
+	// DeleteTopicSuffix, if set, is appended to the topic names of deletes,
+	// which are emitted to separate topics.
+	DeleteTopicSuffix string
+	// MarkInitialScan adds a `bootstrap` field to each row's value which is
+	// true for rows emitted by the initial scan.
+	MarkInitialScan bool
+	// TTLExpiration adds a `ttl_expiration` field to each row's value holding
+	// the time at which the row expires under its table's row-level TTL.
+	TTLExpiration bool
 }
 
+// MinMaxMessageBytes is the smallest permitted value of the
+// max_message_bytes option. Each chunk message carries some overhead in
+// addition to the chunk data, so very small limits are not useful.
+const MinMaxMessageBytes = 1 << 10
+
 // GetEncodingOptions populates and validates an EncodingOptions.
 func (s StatementOptions) GetEncodingOptions() (EncodingOptions, error) {
 	o := EncodingOptions{}
@@ -822,17 +1451,137 @@ func (s StatementOptions) GetEncodingOptions() (EncodingOptions, error) {
 		o.Envelope = EnvelopeType(envelope)
 	}
 
+	subjectStrategy, err := s.getEnumValue(OptAvroSubjectStrategy)
+	if err != nil {
+		return o, err
+	}
+	if subjectStrategy == `` {
+		o.AvroSubjectStrategy = OptAvroSubjectStrategyTopic
+	} else {
+		o.AvroSubjectStrategy = AvroSubjectStrategy(subjectStrategy)
+	}
+
+	decimalFormat, err := s.getEnumValue(OptDecimalFormat)
+	if err != nil {
+		return o, err
+	}
+	if decimalFormat == `` {
+		o.DecimalFormat = OptDecimalFormatNumber
+	} else {
+		o.DecimalFormat = DecimalFormat(decimalFormat)
+	}
+
+	enumFormat, err := s.getEnumValue(OptEnumFormat)
+	if err != nil {
+		return o, err
+	}
+	if enumFormat == `` {
+		o.EnumFormat = OptEnumFormatLabel
+	} else {
+		o.EnumFormat = EnumFormat(enumFormat)
+	}
+
+	checksum, err := s.getEnumValue(OptEmitChecksum)
+	if err != nil {
+		return o, err
+	}
+	o.Checksum = ChecksumAlgorithm(checksum)
+
+	complexFormat, err := s.getEnumValue(OptComplexFormat)
+	if err != nil {
+		return o, err
+	}
+	if complexFormat == `` {
+		o.ComplexFormat = OptComplexFormatNested
+	} else {
+		o.ComplexFormat = ComplexFormat(complexFormat)
+	}
+
+	spatialFormat, err := s.getEnumValue(OptSpatialFormat)
+	if err != nil {
+		return o, err
+	}
+	o.SpatialFormat = SpatialFormat(spatialFormat)
+
+	layout, err := s.getEnumValue(OptCloudStorageLayout)
+	if err != nil {
+		return o, err
+	}
+	if layout == `` {
+		o.CloudStorageLayout = OptCloudStorageLayoutDefault
+	} else {
+		o.CloudStorageLayout = CloudStorageLayout(layout)
+	}
+	o.CloudStoragePartitionColumn = s.m[OptCloudStoragePartitionColumn]
+
+	keySerializer, err := s.getEnumValue(OptKafkaKeySerializer)
+	if err != nil {
+		return o, err
+	}
+	o.KafkaKeySerializer = KafkaKeySerializer(keySerializer)
+
+	tombstone, err := s.getEnumValue(OptTombstone)
+	if err != nil {
+		return o, err
+	}
+	if tombstone == `` {
+		o.Tombstone = OptTombstoneWrapped
+	} else {
+		o.Tombstone = Tombstone(tombstone)
+	}
+
 	_, o.KeyInValue = s.m[OptKeyInValue]
 	_, o.TopicInValue = s.m[OptTopicInValue]
 	_, o.UpdatedTimestamps = s.m[OptUpdatedTimestamps]
 	_, o.MVCCTimestamps = s.m[OptMVCCTimestamps]
 	_, o.Diff = s.m[OptDiff]
 	_, o.EncodeJSONValueNullAsObject = s.m[OptEncodeJSONValueNullAsObject]
+	_, o.EmitOpField = s.m[OptEmitOpField]
+	_, o.ValueOnDelete = s.m[OptValueOnDelete]
+	_, o.IncludeSource = s.m[OptIncludeSource]
+	_, o.SnapshotField = s.m[OptSnapshotInterval]
+	_, o.MarkInitialScan = s.m[OptMarkInitialScan]
+	_, o.TTLExpiration = s.m[OptEmitTTLExpiration]
+	_, o.KeyTablePrefix = s.m[OptKeyTablePrefix]
+	_, o.ResolvedIncludeLag = s.m[OptResolvedIncludeLag]
 
 	o.SchemaRegistryURI = s.m[OptConfluentSchemaRegistry]
 	o.AvroSchemaPrefix = s.m[OptAvroSchemaPrefix]
+	unionNullFirst, err := s.getEnumValue(OptAvroUnionNullFirst)
+	if err != nil {
+		return o, err
+	}
+	o.AvroUnionNullLast = unionNullFirst == `false`
+	_, o.AvroCombinedKeyValue = s.m[OptAvroCombinedKeyValue]
 	o.Compression = s.m[OptCompression]
 	o.CustomKeyColumn = s.m[OptCustomKeyColumn]
+	o.SQLTableName = s.m[OptSQLTableName]
+	o.FieldRename = s.m[OptFieldRename]
+	if _, err := ParseFieldRename(o.FieldRename); err != nil {
+		return o, err
+	}
+	o.TopicOverride = s.m[OptTopicOverride]
+	if _, err := ParseTopicOverride(o.TopicOverride); err != nil {
+		return o, err
+	}
+	if suffix, ok := s.m[OptDeleteTopicSuffix]; ok {
+		if suffix == `` {
+			return o, errors.Errorf(`option %s must not be empty`, OptDeleteTopicSuffix)
+		}
+		o.DeleteTopicSuffix = suffix
+	}
+
+	maxMessageBytes, _, err := s.getBytesValue(OptMaxMessageBytes)
+	if err != nil {
+		return o, err
+	}
+	o.MaxMessageBytes = maxMessageBytes
+
+	shardCount, _, err := s.getIntValue(OptShardCount)
+	if err != nil {
+		return o, err
+	}
+	o.ShardCount = shardCount
 
 	s.cache.EncodingOptions = o
 	return o, o.Validate()
@@ -848,6 +1597,122 @@ func (e EncodingOptions) Validate() error {
 	if e.Format != OptFormatJSON && e.EncodeJSONValueNullAsObject {
 		return errors.Errorf(`%s is only usable with %s=%s`, OptEncodeJSONValueNullAsObject, OptFormat, OptFormatJSON)
 	}
+	if e.AvroUnionNullLast && e.Format != OptFormatAvro {
+		return errors.Errorf(`%s is only usable with %s=%s`, OptAvroUnionNullFirst, OptFormat, OptFormatAvro)
+	}
+	if e.EmitOpField {
+		if e.Format != OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`, OptEmitOpField, OptFormat, OptFormatJSON)
+		}
+		if e.Envelope != OptEnvelopeWrapped && e.Envelope != OptEnvelopeBare {
+			return errors.Errorf(`%s is only usable with %s=%s or %s=%s`,
+				OptEmitOpField, OptEnvelope, OptEnvelopeWrapped, OptEnvelope, OptEnvelopeBare)
+		}
+	}
+	if e.ValueOnDelete {
+		if e.Format != OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`, OptValueOnDelete, OptFormat, OptFormatJSON)
+		}
+		if e.Envelope != OptEnvelopeWrapped {
+			return errors.Errorf(`%s is only usable with %s=%s`, OptValueOnDelete, OptEnvelope, OptEnvelopeWrapped)
+		}
+	}
+	if e.IncludeSource {
+		if e.Format != OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`, OptIncludeSource, OptFormat, OptFormatJSON)
+		}
+		if e.Envelope != OptEnvelopeWrapped && e.Envelope != OptEnvelopeBare {
+			return errors.Errorf(`%s is only usable with %s=%s or %s=%s`,
+				OptIncludeSource, OptEnvelope, OptEnvelopeWrapped, OptEnvelope, OptEnvelopeBare)
+		}
+	}
+	if e.SnapshotField {
+		if e.Format != OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`, OptSnapshotInterval, OptFormat, OptFormatJSON)
+		}
+		if e.Envelope != OptEnvelopeWrapped && e.Envelope != OptEnvelopeBare {
+			return errors.Errorf(`%s is only usable with %s=%s or %s=%s`,
+				OptSnapshotInterval, OptEnvelope, OptEnvelopeWrapped, OptEnvelope, OptEnvelopeBare)
+		}
+	}
+	if e.MarkInitialScan {
+		if e.Format != OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`, OptMarkInitialScan, OptFormat, OptFormatJSON)
+		}
+		if e.Envelope != OptEnvelopeWrapped && e.Envelope != OptEnvelopeBare {
+			return errors.Errorf(`%s is only usable with %s=%s or %s=%s`,
+				OptMarkInitialScan, OptEnvelope, OptEnvelopeWrapped, OptEnvelope, OptEnvelopeBare)
+		}
+	}
+	if e.TTLExpiration {
+		if e.Format != OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`, OptEmitTTLExpiration, OptFormat, OptFormatJSON)
+		}
+		if e.Envelope != OptEnvelopeWrapped && e.Envelope != OptEnvelopeBare {
+			return errors.Errorf(`%s is only usable with %s=%s or %s=%s`,
+				OptEmitTTLExpiration, OptEnvelope, OptEnvelopeWrapped, OptEnvelope, OptEnvelopeBare)
+		}
+	}
+	if e.AvroCombinedKeyValue {
+		if e.Format != OptFormatAvro {
+			return errors.Errorf(`%s is only usable with %s=%s`, OptAvroCombinedKeyValue, OptFormat, OptFormatAvro)
+		}
+		if e.Envelope != OptEnvelopeWrapped && e.Envelope != OptEnvelopeBare {
+			return errors.Errorf(`%s is only usable with %s=%s or %s=%s`,
+				OptAvroCombinedKeyValue, OptEnvelope, OptEnvelopeWrapped, OptEnvelope, OptEnvelopeBare)
+		}
+	}
+	if e.KeyTablePrefix && e.Format != OptFormatJSON {
+		return errors.Errorf(`%s is only usable with %s=%s`, OptKeyTablePrefix, OptFormat, OptFormatJSON)
+	}
+	if e.ResolvedIncludeLag && e.Format != OptFormatJSON {
+		return errors.Errorf(`%s is only usable with %s=%s`, OptResolvedIncludeLag, OptFormat, OptFormatJSON)
+	}
+	if e.DecimalFormat == OptDecimalFormatString && e.Format != OptFormatJSON {
+		return errors.Errorf(`%s=%s is only usable with %s=%s`,
+			OptDecimalFormat, OptDecimalFormatString, OptFormat, OptFormatJSON)
+	}
+	if e.CloudStorageLayout == OptCloudStorageLayoutIceberg && e.Format != OptFormatParquet {
+		return errors.Errorf(`%s=%s is only usable with %s=%s`,
+			OptCloudStorageLayout, OptCloudStorageLayoutIceberg, OptFormat, OptFormatParquet)
+	}
+	if e.CloudStoragePartitionColumn != `` && e.CloudStorageLayout != OptCloudStorageLayoutIceberg {
+		return errors.Errorf(`%s is only usable with %s=%s`,
+			OptCloudStoragePartitionColumn, OptCloudStorageLayout, OptCloudStorageLayoutIceberg)
+	}
+	if e.FieldRename != `` && e.Format != OptFormatJSON {
+		return errors.Errorf(`%s is only usable with %s=%s`, OptFieldRename, OptFormat, OptFormatJSON)
+	}
+	if e.EnumFormat == OptEnumFormatPhysical && e.Format != OptFormatJSON {
+		return errors.Errorf(`%s=%s is only usable with %s=%s`,
+			OptEnumFormat, OptEnumFormatPhysical, OptFormat, OptFormatJSON)
+	}
+	if e.ComplexFormat == OptComplexFormatString && e.Format != OptFormatJSON {
+		return errors.Errorf(`%s=%s is only usable with %s=%s`,
+			OptComplexFormat, OptComplexFormatString, OptFormat, OptFormatJSON)
+	}
+	if e.SpatialFormat != `` && e.Format != OptFormatJSON && e.Format != OptFormatAvro {
+		return errors.Errorf(`%s is only usable with %s=%s or %s=%s`,
+			OptSpatialFormat, OptFormat, OptFormatJSON, OptFormat, OptFormatAvro)
+	}
+	if e.KafkaKeySerializer == OptKafkaKeySerializerAvro && e.SchemaRegistryURI == `` {
+		return errors.Errorf(`WITH option %s is required for %s=%s`,
+			OptConfluentSchemaRegistry, OptKafkaKeySerializer, OptKafkaKeySerializerAvro)
+	}
+	if e.SQLTableName != `` && e.Format != OptFormatSQL {
+		return errors.Errorf(`%s is only usable with %s=%s`, OptSQLTableName, OptFormat, OptFormatSQL)
+	}
+	if e.ShardCount > 0 && e.Format != OptFormatJSON {
+		return errors.Errorf(`%s is only usable with %s=%s`, OptShardCount, OptFormat, OptFormatJSON)
+	}
+	if e.MaxMessageBytes > 0 {
+		if e.Format != OptFormatJSON {
+			return errors.Errorf(`%s is only usable with %s=%s`, OptMaxMessageBytes, OptFormat, OptFormatJSON)
+		}
+		if e.MaxMessageBytes < MinMaxMessageBytes {
+			return errors.Errorf(`%s must be at least %d bytes`, OptMaxMessageBytes, MinMaxMessageBytes)
+		}
+	}
 	if e.Envelope != OptEnvelopeWrapped && e.Format != OptFormatJSON && e.Format != OptFormatParquet {
 		requiresWrap := []struct {
 			k string
@@ -883,7 +1748,11 @@ func (s StatementOptions) GetSchemaChangeHandlingOptions() (SchemaChangeHandling
 	if err != nil {
 		return o, err
 	}
-	if ec == `` {
+	if ec == `` && s.DDLOnly() {
+		// Changefeeds which only emit schema changes are interested in all
+		// column changes, including those which don't require a backfill.
+		o.EventClass = OptSchemaChangeEventClassColumnChange
+	} else if ec == `` {
 		o.EventClass = OptSchemaChangeEventClassDefault
 	} else {
 		o.EventClass = SchemaChangeEventClass(ec)
@@ -908,15 +1777,25 @@ func (s StatementOptions) GetSchemaChangeHandlingOptions() (SchemaChangeHandling
 type Filters struct {
 	WithDiff      bool
 	WithFiltering bool
+	// ValueOnDelete is set when the previous value of deleted rows, and only
+	// of deleted rows, must be fetched.
+	ValueOnDelete bool
 }
 
 // GetFilters returns a populated Filters.
 func (s StatementOptions) GetFilters() Filters {
 	_, withDiff := s.m[OptDiff]
+	// Telling inserts apart from updates and deletes requires the previous
+	// value of each row, even though it is not emitted.
+	_, emitOpField := s.m[OptEmitOpField]
+	// So does telling whether a column family changed.
+	withDiff = withDiff || s.OnlyInserts() || emitOpField || s.EmitChangedFamilyOnly()
+	_, valueOnDelete := s.m[OptValueOnDelete]
 	_, withIgnoreDisableChangefeedReplication := s.m[OptIgnoreDisableChangefeedReplication]
 	return Filters{
 		WithDiff:      withDiff,
 		WithFiltering: !withIgnoreDisableChangefeedReplication,
+		ValueOnDelete: valueOnDelete && !withDiff,
 	}
 }
 
@@ -924,16 +1803,22 @@ func (s StatementOptions) GetFilters() Filters {
 // are specific to the webhook sink.
 // ClientTimeout is nil if not set as the default
 // is different from 0.
+// Compression, if set, is the algorithm used to compress request bodies.
 type WebhookSinkOptions struct {
 	JSONConfig    SinkSpecificJSONConfig
 	AuthHeader    string
 	ClientTimeout *time.Duration
+	Compression   string
 }
 
 // GetWebhookSinkOptions includes arbitrary json to be interpreted
 // by the webhook sink.
 func (s StatementOptions) GetWebhookSinkOptions() (WebhookSinkOptions, error) {
-	o := WebhookSinkOptions{JSONConfig: s.getJSONValue(OptWebhookSinkConfig), AuthHeader: s.m[OptWebhookAuthHeader]}
+	o := WebhookSinkOptions{
+		JSONConfig:  s.getJSONValue(OptWebhookSinkConfig),
+		AuthHeader:  s.m[OptWebhookAuthHeader],
+		Compression: s.m[OptWebhookCompression],
+	}
 	timeout, err := s.getDurationValue(OptWebhookClientTimeout)
 	if err != nil {
 		return o, err
@@ -942,12 +1827,104 @@ func (s StatementOptions) GetWebhookSinkOptions() (WebhookSinkOptions, error) {
 	return o, nil
 }
 
+// GetFileSize returns the size at which the cloud storage sink should roll
+// over to a new file, or false if none has been provided.
+func (s StatementOptions) GetFileSize() (int64, bool, error) {
+	return s.getBytesValue(OptFileSize)
+}
+
+// GetPartitionFormat returns how the cloud storage sink should partition its
+// data files, or false if none has been provided.
+func (s StatementOptions) GetPartitionFormat() (string, bool) {
+	v, ok := s.m[OptPartitionFormat]
+	return v, ok
+}
+
+// GetInitialScanParallelism returns the number of concurrent scan requests
+// the changefeed should use for its initial scan, or false if none has been
+// provided.
+func (s StatementOptions) GetInitialScanParallelism() (int, bool, error) {
+	return s.getIntValue(OptInitialScanParallelism)
+}
+
+// GetMaxEmitRate returns the maximum number of rows per second the changefeed
+// should emit to the sink, if one was specified.
+func (s StatementOptions) GetMaxEmitRate() (int, bool, error) {
+	return s.getIntValue(OptMaxEmitRate)
+}
+
+// RetryBudgets are the number of times a changefeed retries each class of
+// errors before giving up and handling the error according to on_error. Zero
+// means that errors of the class are retried indefinitely.
+type RetryBudgets struct {
+	Timeout int
+	Encode  int
+}
+
+// For returns the retry budget of the given class of errors.
+func (b RetryBudgets) For(class ErrorClass) int {
+	switch class {
+	case ErrorClassTimeout:
+		return b.Timeout
+	case ErrorClassEncode:
+		return b.Encode
+	default:
+		return 0
+	}
+}
+
+// GetRetryBudgets returns the retry budgets of each class of errors.
+func (s StatementOptions) GetRetryBudgets() (RetryBudgets, error) {
+	var b RetryBudgets
+	var err error
+	if b.Timeout, _, err = s.getIntValue(OptRetryTimeoutMax); err != nil {
+		return RetryBudgets{}, err
+	}
+	if b.Encode, _, err = s.getIntValue(OptRetryEncodeMax); err != nil {
+		return RetryBudgets{}, err
+	}
+	return b, nil
+}
+
+// GetMaxBuffer returns the maximum number of bytes each aggregator may
+// buffer before the changefeed either applies backpressure or drops
+// superseded changes, if one was specified.
+func (s StatementOptions) GetMaxBuffer() (int64, bool, error) {
+	return s.getBytesValue(OptMaxBuffer)
+}
+
+// GetSnapshotInterval returns the interval at which the changefeed re-scans
+// all of its targets, or 0 if periodic snapshots are disabled.
+func (s StatementOptions) GetSnapshotInterval() (time.Duration, error) {
+	d, err := s.getDurationValue(OptSnapshotInterval)
+	if err != nil || d == nil {
+		return 0, err
+	}
+	return *d, nil
+}
+
+// GetDeleteDelay returns how long deletes are held back so that a re-insert of
+// the same key supersedes them, or 0 if deletes are emitted right away.
+func (s StatementOptions) GetDeleteDelay() (time.Duration, error) {
+	d, err := s.getDurationValue(OptDeleteDelay)
+	if err != nil || d == nil {
+		return 0, err
+	}
+	return *d, nil
+}
+
 // GetKafkaConfigJSON returns arbitrary json to be interpreted
 // by the kafka sink.
 func (s StatementOptions) GetKafkaConfigJSON() SinkSpecificJSONConfig {
 	return s.getJSONValue(OptKafkaSinkConfig)
 }
 
+// GetKafkaTopicConfig returns the kafka producer settings to override for
+// each topic, keyed by topic name.
+func (s StatementOptions) GetKafkaTopicConfig() (map[string]KafkaTopicConfig, error) {
+	return ParseKafkaTopicConfig(s.m[OptKafkaTopicConfig])
+}
+
 // GetPubsubConfigJSON returns arbitrary json to be interpreted
 // by the pubsub sink.
 func (s StatementOptions) GetPubsubConfigJSON() SinkSpecificJSONConfig {
@@ -959,13 +1936,20 @@ func (s StatementOptions) GetPubsubConfigJSON() SinkSpecificJSONConfig {
 // Returns an error for negative or invalid duration value.
 func (s StatementOptions) GetResolvedTimestampInterval() (*time.Duration, bool, error) {
 	str, ok := s.m[OptResolvedTimestamps]
-	if ok && str == OptEmitAllResolvedTimestamps {
+	if ok && (str == OptEmitAllResolvedTimestamps || str == OptEmitResolvedTimestampsOnCatchup) {
 		return nil, true, nil
 	}
 	d, err := s.getDurationValue(OptResolvedTimestamps)
 	return d, d != nil, err
 }
 
+// IsResolvedOnCatchup returns true if resolved timestamps should only be
+// emitted when the changefeed catches up to the current time, rather than
+// at the interval returned by GetResolvedTimestampInterval.
+func (s StatementOptions) IsResolvedOnCatchup() bool {
+	return s.m[OptResolvedTimestamps] == OptEmitResolvedTimestampsOnCatchup
+}
+
 // GetMetricScope returns a namespace for metrics affected by this changefeed, or
 // false if none has been provided.
 func (s StatementOptions) GetMetricScope() (string, bool) {
@@ -1004,6 +1988,59 @@ func (s StatementOptions) IncludeVirtual() bool {
 	return s.m[OptVirtualColumns] == string(OptVirtualColumnsNull)
 }
 
+// OnlyInserts returns true if updates and deletes should be suppressed, so
+// that only newly inserted rows are emitted.
+func (s StatementOptions) OnlyInserts() bool {
+	_, ok := s.m[OptOnlyInserts]
+	return ok
+}
+
+// EmitChangedFamilyOnly returns true if updates which leave the values of a
+// column family unchanged should be suppressed for that family.
+func (s StatementOptions) EmitChangedFamilyOnly() bool {
+	_, ok := s.m[OptEmitChangedFamilyOnly]
+	return ok
+}
+
+// DDLOnly returns true if row data should be suppressed, so that only a
+// record describing each schema change to the targets is emitted.
+func (s StatementOptions) DDLOnly() bool {
+	_, ok := s.m[OptDDLOnly]
+	return ok
+}
+
+// EmitBatchMarkers returns true if the rows written by each batch, such as a
+// single statement, should be bracketed by begin and end marker messages.
+func (s StatementOptions) EmitBatchMarkers() bool {
+	_, ok := s.m[OptEmitBatchMarkers]
+	return ok
+}
+
+// EmitSchemaPreamble returns true if the first row emitted to each topic, and
+// the first one after each schema change, should be preceded by a message
+// describing the columns of the rows.
+func (s StatementOptions) EmitSchemaPreamble() bool {
+	_, ok := s.m[OptEmitSchemaPreamble]
+	return ok
+}
+
+// EmitSchemaFingerprint returns true if the messages emitted at schema-change
+// boundaries, i.e. schema preambles and ddl_only schema change records, should
+// include a fingerprint of the table's ordered column names and types.
+func (s StatementOptions) EmitSchemaFingerprint() bool {
+	_, ok := s.m[OptEmitSchemaFingerprint]
+	return ok
+}
+
+// EmitSequence returns true if each row should carry a sequence number which
+// increases by one with every row emitted to its topic. The numbering resumes
+// from the last checkpoint when the changefeed restarts, so the numbers of rows
+// emitted again after a restart are reused; see sequenceSink.
+func (s StatementOptions) EmitSequence() bool {
+	_, ok := s.m[OptEmitSequence]
+	return ok
+}
+
 // KeyOnly returns true if we are using the 'key_only' envelope.
 func (s StatementOptions) KeyOnly() bool {
 	return s.m[OptEnvelope] == string(OptEnvelopeKeyOnly)
@@ -1087,6 +2124,35 @@ func (s StatementOptions) GetOnError() (OnErrorType, error) {
 	return OnErrorType(v), nil
 }
 
+// GetOnFilterError validates and returns the desired behavior when the
+// changefeed's CDC query fails to evaluate for a row.
+func (s StatementOptions) GetOnFilterError() (OnFilterErrorType, error) {
+	v, err := s.getEnumValue(OptOnFilterError)
+	if err != nil || v == `` {
+		return OptOnFilterErrorFail, err
+	}
+	return OnFilterErrorType(v), nil
+}
+
+// GetDelivery validates and returns the delivery guarantee of the changefeed.
+func (s StatementOptions) GetDelivery() (DeliveryType, error) {
+	v, err := s.getEnumValue(OptDelivery)
+	if err != nil || v == `` {
+		return OptDeliveryAtLeastOnce, err
+	}
+	return DeliveryType(v), nil
+}
+
+// GetInitialScanConsistency validates and returns which replicas should serve
+// the changefeed's initial scan.
+func (s StatementOptions) GetInitialScanConsistency() (InitialScanConsistency, error) {
+	v, err := s.getEnumValue(OptInitialScanConsistency)
+	if err != nil || v == `` {
+		return OptInitialScanConsistencyLeaseholder, err
+	}
+	return InitialScanConsistency(v), nil
+}
+
 func describeEnum(strs ...string) string {
 	switch len(strs) {
 	case 1:
@@ -1151,6 +2217,11 @@ func (s StatementOptions) ValidateForCreateChangefeed(isPredicateChangefeed bool
 			return err
 		}
 	}
+	if s.m[OptFormat] == string(OptFormatSQL) {
+		if err := validateUnsupportedOptions(SQLFormatUnsupportedOptions, fmt.Sprintf("format=%s", OptFormatSQL)); err != nil {
+			return err
+		}
+	}
 	for o := range s.m {
 		for _, pair := range incompatibleOptionsMap[o] {
 			if s.IsSet(pair.opt1) && s.IsSet(pair.opt2) {
@@ -1177,6 +2248,9 @@ func (s StatementOptions) validateAgainst(m map[string]OptionPermittedValues) er
 		case OptionTypeString, OptionTypeTimestamp, OptionTypeJSON:
 			// Consumer (usually a sink) must parse and validate these
 		case OptionTypeDuration:
+			if k == OptResolvedTimestamps && s.IsResolvedOnCatchup() {
+				continue
+			}
 			if _, err := s.getDurationValue(k); err != nil {
 				return err
 			}
@@ -1184,6 +2258,14 @@ func (s StatementOptions) validateAgainst(m map[string]OptionPermittedValues) er
 			if _, err := s.getEnumValue(k); err != nil {
 				return err
 			}
+		case OptionTypeBytes:
+			if _, _, err := s.getBytesValue(k); err != nil {
+				return err
+			}
+		case OptionTypeInt:
+			if _, _, err := s.getIntValue(k); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -40,6 +40,8 @@ func TestOptionsValidations(t *testing.T) {
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"key_column": "b"}, false, "requires the unordered option"},
+		{map[string]string{"resolved": "on_catchup"}, false, ""},
+		{map[string]string{"resolved": "on_ketchup"}, false, "problem parsing option resolved"},
 	}
 
 	for _, test := range tests {
@@ -69,6 +71,47 @@ func TestEncodingOptionsValidations(t *testing.T) {
 		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeBare, UpdatedTimestamps: true}, "is only usable with envelope=wrapped"},
 		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeBare, MVCCTimestamps: true}, "is only usable with envelope=wrapped"},
 		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeBare, Diff: true}, "is only usable with envelope=wrapped"},
+		{EncodingOptions{Format: OptFormatAvro, MaxMessageBytes: 1 << 20}, "max_message_bytes is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatJSON, MaxMessageBytes: 1}, "max_message_bytes must be at least 1024 bytes"},
+		{EncodingOptions{Format: OptFormatJSON, MaxMessageBytes: 1 << 20}, ""},
+		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeWrapped, EmitOpField: true}, "emit_op_field is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeRow, EmitOpField: true}, "emit_op_field is only usable with envelope=wrapped or envelope=bare"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeWrapped, EmitOpField: true}, ""},
+		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeWrapped, ValueOnDelete: true}, "value_on_delete is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeBare, ValueOnDelete: true}, "value_on_delete is only usable with envelope=wrapped"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeWrapped, ValueOnDelete: true}, ""},
+		{EncodingOptions{Format: OptFormatAvro, ShardCount: 4}, "shard_count is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatJSON, ShardCount: 4}, ""},
+		{EncodingOptions{Format: OptFormatCSV, Envelope: OptEnvelopeWrapped, IncludeSource: true}, "include_source is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeKeyOnly, IncludeSource: true}, "include_source is only usable with envelope=wrapped or envelope=bare"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeWrapped, IncludeSource: true}, ""},
+		{EncodingOptions{Format: OptFormatAvro, KeyTablePrefix: true}, "key_table_prefix is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatJSON, KeyTablePrefix: true}, ""},
+		{EncodingOptions{Format: OptFormatAvro, ResolvedIncludeLag: true}, "resolved_include_lag is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatCSV, DecimalFormat: OptDecimalFormatString},
+			"decimal_format=string is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatCSV, DecimalFormat: OptDecimalFormatNumber}, ""},
+		{EncodingOptions{Format: OptFormatCSV, EnumFormat: OptEnumFormatPhysical},
+			"enum_format=physical is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatCSV, EnumFormat: OptEnumFormatLabel}, ""},
+		{EncodingOptions{Format: OptFormatCSV, ComplexFormat: OptComplexFormatString},
+			"complex_format=string is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatCSV, ComplexFormat: OptComplexFormatNested}, ""},
+		{EncodingOptions{Format: OptFormatAvro, FieldRename: "a:id"}, "field_rename is only usable with format=json"},
+		{EncodingOptions{Format: OptFormatJSON, FieldRename: "a:id"}, ""},
+		{EncodingOptions{Format: OptFormatJSON, CloudStorageLayout: OptCloudStorageLayoutIceberg},
+			"cloudstorage_layout=iceberg is only usable with format=parquet"},
+		{EncodingOptions{Format: OptFormatParquet, CloudStoragePartitionColumn: "a"},
+			"cloudstorage_partition_column is only usable with cloudstorage_layout=iceberg"},
+		{EncodingOptions{Format: OptFormatParquet, CloudStorageLayout: OptCloudStorageLayoutIceberg,
+			CloudStoragePartitionColumn: "a"}, ""},
+		{EncodingOptions{Format: OptFormatJSON, KafkaKeySerializer: OptKafkaKeySerializerAvro},
+			"WITH option confluent_schema_registry is required for kafka_key_serializer=avro"},
+		{EncodingOptions{Format: OptFormatJSON, KafkaKeySerializer: OptKafkaKeySerializerString}, ""},
+		{EncodingOptions{Format: OptFormatJSON, SQLTableName: "t"}, "sql_table_name is only usable with format=sql"},
+		{EncodingOptions{Format: OptFormatSQL, SQLTableName: "t"}, ""},
+		{EncodingOptions{Format: OptFormatJSON, AvroUnionNullLast: true}, "avro_union_null_first is only usable with format=avro"},
+		{EncodingOptions{Format: OptFormatAvro, AvroUnionNullLast: true}, ""},
 	}
 
 	for _, c := range cases {
@@ -82,3 +125,85 @@ func TestEncodingOptionsValidations(t *testing.T) {
 	}
 
 }
+
+func TestParseFieldRename(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	renames, err := ParseFieldRename(`a:id, b:name`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "id", "b": "name"}, renames)
+
+	renames, err = ParseFieldRename(``)
+	require.NoError(t, err)
+	require.Empty(t, renames)
+
+	for input, expectErr := range map[string]string{
+		`a`:           "problem parsing option field_rename",
+		`a:`:          "problem parsing option field_rename",
+		`a:id,a:name`: "renames column a more than once",
+		`a:id,b:id`:   "renames more than one column to id",
+	} {
+		_, err := ParseFieldRename(input)
+		require.Error(t, err, input)
+		require.Contains(t, err.Error(), expectErr)
+	}
+}
+
+func TestParseTopicOverride(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	overrides, err := ParseTopicOverride(`foo:events, d.public.bar:events`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"foo": "events", "d.public.bar": "events"}, overrides)
+
+	overrides, err = ParseTopicOverride(``)
+	require.NoError(t, err)
+	require.Empty(t, overrides)
+
+	for input, expectErr := range map[string]string{
+		`foo`:                 "problem parsing option topic_override",
+		`foo:`:                "problem parsing option topic_override",
+		`:events`:             "problem parsing option topic_override",
+		`foo:a/b`:             `maps table foo to illegal topic name "a/b"`,
+		`foo:a.b`:             `maps table foo to illegal topic name "a.b"`,
+		`foo:events,foo:bars`: "maps table foo more than once",
+	} {
+		_, err := ParseTopicOverride(input)
+		require.Error(t, err, input)
+		require.Contains(t, err.Error(), expectErr)
+	}
+}
+
+func TestParseKafkaTopicConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	retries := 10
+	configs, err := ParseKafkaTopicConfig(`foo:{"acks":"all"}, bar:{"acks":"1","retries":10}`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]KafkaTopicConfig{
+		"foo": {Acks: "all"},
+		"bar": {Acks: "1", Retries: &retries},
+	}, configs)
+
+	configs, err = ParseKafkaTopicConfig(``)
+	require.NoError(t, err)
+	require.Empty(t, configs)
+
+	for input, expectErr := range map[string]string{
+		`foo`:                                 "problem parsing option kafka_topic_config",
+		`:{"acks":"all"}`:                     "problem parsing option kafka_topic_config",
+		`foo:{"acks":"all"`:                   "problem parsing option kafka_topic_config for topic foo",
+		`foo:{"linger":"1s"}`:                 `unknown field "linger"`,
+		`foo:{"acks":"some"}`:                 `sets invalid acks value "some" for topic foo`,
+		`foo:{"retries":-1}`:                  "sets negative retries for topic foo",
+		`foo:{"acks":"all"} bar:{}`:           "expected a comma after the config of topic foo",
+		`foo:{"acks":"all"},foo:{"acks":"1"}`: "configures topic foo more than once",
+	} {
+		_, err := ParseKafkaTopicConfig(input)
+		require.Error(t, err, input)
+		require.Contains(t, err.Error(), expectErr)
+	}
+}
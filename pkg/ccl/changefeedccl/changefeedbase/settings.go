@@ -59,6 +59,17 @@ var SlowSpanLogThreshold = settings.RegisterDurationSetting(
 	settings.NonNegativeDuration,
 )
 
+// ResolvedOnCatchupThreshold controls how far behind the current wall-clock
+// time the frontier of a resolved='on_catchup' changefeed may be while still
+// being considered caught up.
+var ResolvedOnCatchupThreshold = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"changefeed.resolved_on_catchup_threshold",
+	"a changefeed with resolved='on_catchup' emits a resolved timestamp once its resolved timestamp is within this far of the current wall-clock time; if 0, a default value is calculated based on other cluster settings",
+	0,
+	settings.NonNegativeDuration,
+)
+
 // IdleTimeout controls how long the changefeed will wait for a new KV being
 // emitted before marking itself as idle.
 var IdleTimeout = settings.RegisterDurationSetting(
@@ -121,6 +132,17 @@ var ScanRequestLimit = settings.RegisterIntSetting(
 	0,
 	settings.WithPublic)
 
+// MaxInitialScanParallelism is the upper bound on the number of concurrent
+// scan requests a changefeed may request via the initial_scan_parallelism
+// option.
+var MaxInitialScanParallelism = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"changefeed.backfill.max_initial_scan_parallelism",
+	"maximum number of concurrent scan requests that may be requested using initial_scan_parallelism",
+	100,
+	settings.PositiveInt,
+)
+
 // ScanRequestSize is the target size of the scan request response.
 //
 // TODO(cdc,yevgeniy,irfansharif): 16 MiB is too large for "elastic" work such
@@ -10,6 +10,8 @@ package changefeedccl
 
 import (
 	"context"
+	"fmt"
+	"hash/crc32"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
@@ -49,6 +51,12 @@ func getEncoder(
 	p externalConnectionProvider,
 	sliMetrics *sliMetrics,
 ) (Encoder, error) {
+	if opts.Tombstone == changefeedbase.OptTombstoneNull {
+		return newNullTombstoneEncoder(ctx, opts, targets, encodeForQuery, p, sliMetrics)
+	}
+	if opts.KafkaKeySerializer != `` && string(opts.KafkaKeySerializer) != string(opts.Format) {
+		return newKeySerializerEncoder(ctx, opts, targets, encodeForQuery, p, sliMetrics)
+	}
 	switch opts.Format {
 	case changefeedbase.OptFormatJSON:
 		return makeJSONEncoder(ctx, jsonEncoderOptions{EncodingOptions: opts, encodeForQuery: encodeForQuery})
@@ -56,6 +64,8 @@ func getEncoder(
 		return newConfluentAvroEncoder(opts, targets, p, sliMetrics)
 	case changefeedbase.OptFormatCSV:
 		return newCSVEncoder(opts), nil
+	case changefeedbase.OptFormatSQL:
+		return newSQLEncoder(opts)
 	case changefeedbase.OptFormatParquet:
 		//We will return no encoder for parquet format because there is a separate
 		//sink implemented for parquet format for cloud storage, which does the job
@@ -66,3 +76,9 @@ func getEncoder(
 		return nil, errors.AssertionFailedf(`unknown format: %s`, opts.Format)
 	}
 }
+
+// messageChecksum returns the checksum of a message payload emitted with
+// emit_checksum='crc32': its IEEE CRC32, as 8 hex digits.
+func messageChecksum(payload []byte) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE(payload))
+}
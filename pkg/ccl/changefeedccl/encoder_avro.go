@@ -39,9 +39,15 @@ type confluentAvroEncoder struct {
 	targets                   changefeedbase.Targets
 	envelopeType              changefeedbase.EnvelopeType
 	customKeyColumn           string
+	subjectStrategy           changefeedbase.AvroSubjectStrategy
+	schemaOpts                avroSchemaOpts
+	// combinedKeyValue encodes values as records which nest the key and the
+	// envelope; see avroKeyValueRecord.
+	combinedKeyValue bool
 
-	keyCache   *cache.UnorderedCache // [tableIDAndVersion]confluentRegisteredKeySchema
-	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]confluentRegisteredEnvelopeSchema
+	keyCache      *cache.UnorderedCache // [tableIDAndVersion]confluentRegisteredKeySchema
+	valueCache    *cache.UnorderedCache // [tableIDAndVersionPair]confluentRegisteredEnvelopeSchema
+	keyValueCache *cache.UnorderedCache // [tableIDAndVersionPair]confluentRegisteredKeyValueSchema
 
 	// resolvedCache doesn't need to be bounded like the other caches because the number of topics
 	// is fixed per changefeed.
@@ -65,6 +71,11 @@ type confluentRegisteredEnvelopeSchema struct {
 	registryID int32
 }
 
+type confluentRegisteredKeyValueSchema struct {
+	schema     *avroKeyValueRecord
+	registryID int32
+}
+
 var _ Encoder = &confluentAvroEncoder{}
 
 var encoderCacheConfig = cache.Config{
@@ -86,12 +97,16 @@ func newConfluentAvroEncoder(
 		targets:                 targets,
 		virtualColumnVisibility: opts.VirtualColumns,
 		envelopeType:            opts.Envelope,
+		subjectStrategy:         opts.AvroSubjectStrategy,
+		schemaOpts:              avroSchemaOpts{unionNullLast: opts.AvroUnionNullLast},
+		combinedKeyValue:        opts.AvroCombinedKeyValue,
 	}
 
 	e.updatedField = opts.UpdatedTimestamps
 	e.beforeField = opts.Diff
 	e.customKeyColumn = opts.CustomKeyColumn
 	e.mvccTimestampField = opts.MVCCTimestamps
+	e.schemaOpts.spatialAsGeoJSON = opts.SpatialFormat == changefeedbase.OptSpatialFormatGeoJSON
 
 	// TODO: Implement this.
 	if opts.KeyInValue {
@@ -117,6 +132,7 @@ func newConfluentAvroEncoder(
 	e.schemaRegistry = reg
 	e.keyCache = cache.NewUnorderedCache(encoderCacheConfig)
 	e.valueCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.keyValueCache = cache.NewUnorderedCache(encoderCacheConfig)
 	e.resolvedCache = make(map[string]confluentRegisteredEnvelopeSchema)
 	return e, nil
 }
@@ -153,30 +169,16 @@ func (e *confluentAvroEncoder) EncodeKey(ctx context.Context, row cdcevent.Row)
 			return nil, err
 		}
 	} else {
-		var err error
 		tableName, err := e.rawTableName(row.Metadata)
 		if err != nil {
 			return nil, err
 		}
-		if e.customKeyColumn == "" {
-			registered.schema, err = primaryIndexToAvroSchema(row, tableName, e.schemaPrefix)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			it, err := row.DatumNamed(e.customKeyColumn)
-			if err != nil {
-				return nil, err
-			}
-			registered.schema, err = newSchemaForRow(it, SQLNameToAvroName(tableName), e.schemaPrefix)
-			if err != nil {
-				return nil, err
-			}
+		registered.schema, err = e.keySchema(row, tableName)
+		if err != nil {
+			return nil, err
 		}
 
-		// NB: This uses the kafka name escaper because it has to match the name
-		// of the kafka topic.
-		subject := SQLNameToKafkaName(tableName) + confluentSubjectSuffixKey
+		subject := e.subject(tableName, &registered.schema.avroRecord, confluentSubjectSuffixKey)
 		registered.registryID, err = e.register(ctx, &registered.schema.avroRecord, subject)
 		if err != nil {
 			return nil, err
@@ -190,14 +192,32 @@ func (e *confluentAvroEncoder) EncodeKey(ctx context.Context, row cdcevent.Row)
 		0, 0, 0, 0, // Placeholder for the ID.
 	}
 	binary.BigEndian.PutUint32(header[1:5], uint32(registered.registryID))
+	it, err := e.keyColumns(row)
+	if err != nil {
+		return nil, err
+	}
+	return registered.schema.BinaryFromRow(header, it)
+}
+
+// keySchema returns the schema of the key of rows of the given table, named
+// after sqlName.
+func (e *confluentAvroEncoder) keySchema(row cdcevent.Row, sqlName string) (*avroDataRecord, error) {
+	if e.customKeyColumn == "" {
+		return primaryIndexToAvroSchema(row, sqlName, e.schemaPrefix, e.schemaOpts)
+	}
+	it, err := row.DatumNamed(e.customKeyColumn)
+	if err != nil {
+		return nil, err
+	}
+	return newSchemaForRow(it, SQLNameToAvroName(sqlName), e.schemaPrefix, e.schemaOpts)
+}
+
+// keyColumns returns an iterator over the columns of the row's key.
+func (e *confluentAvroEncoder) keyColumns(row cdcevent.Row) (cdcevent.Iterator, error) {
 	if e.customKeyColumn != "" {
-		it, err := row.DatumNamed(e.customKeyColumn)
-		if err != nil {
-			return nil, err
-		}
-		return registered.schema.BinaryFromRow(header, it)
+		return row.DatumNamed(e.customKeyColumn)
 	}
-	return registered.schema.BinaryFromRow(header, row.ForEachKeyColumn())
+	return row.ForEachKeyColumn(), nil
 }
 
 // EncodeValue implements the Encoder interface.
@@ -218,80 +238,90 @@ func (e *confluentAvroEncoder) EncodeValue(
 		tableID: updatedRow.TableID, version: updatedRow.Version, familyID: updatedRow.FamilyID,
 	}
 
+	if e.combinedKeyValue {
+		return e.encodeKeyValue(ctx, cacheKey, evCtx, updatedRow, prevRow)
+	}
+
 	var registered confluentRegisteredEnvelopeSchema
 	v, ok := e.valueCache.Get(cacheKey)
 	if ok {
 		registered = v.(confluentRegisteredEnvelopeSchema)
-		if prevRow.IsInitialized() && registered.schema.before != nil {
-			if err := registered.schema.before.refreshTypeMetadata(prevRow); err != nil {
-				return nil, err
-			}
-		}
-		if registered.schema.after != nil {
-			if err := registered.schema.after.refreshTypeMetadata(updatedRow); err != nil {
-				return nil, err
-			}
-		}
-		if registered.schema.record != nil {
-			if err := registered.schema.record.refreshTypeMetadata(updatedRow); err != nil {
-				return nil, err
-			}
+		if err := registered.schema.refreshTypeMetadata(prevRow, updatedRow); err != nil {
+			return nil, err
 		}
 	} else {
-		var beforeDataSchema, afterDataSchema, recordDataSchema *avroDataRecord
-		if e.beforeField && prevRow.IsInitialized() {
-			var err error
-			beforeDataSchema, err = tableToAvroSchema(prevRow, `before`, e.schemaPrefix)
-			if err != nil {
-				return nil, err
-			}
+		name, err := e.rawTableName(updatedRow.Metadata)
+		if err != nil {
+			return nil, err
 		}
-
-		currentSchema, err := tableToAvroSchema(updatedRow, avroSchemaNoSuffix, e.schemaPrefix)
+		registered.schema, err = e.envelopeSchema(name, updatedRow, prevRow)
 		if err != nil {
 			return nil, err
 		}
 
-		var opts avroEnvelopeOpts
-
-		// In the wrapped envelope, row data goes in the "after" field. In the raw envelope,
-		// it goes in the "record" field. In the "key_only" envelope it's omitted.
-		// This means metadata can safely go at the top level as there are never arbitrary column names
-		// for it to conflict with.
-		if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
-			opts = avroEnvelopeOpts{afterField: true, beforeField: e.beforeField, updatedField: e.updatedField, mvccTimestampField: e.mvccTimestampField}
-			afterDataSchema = currentSchema
-		} else {
-			opts = avroEnvelopeOpts{recordField: true, updatedField: e.updatedField, mvccTimestampField: e.mvccTimestampField}
-			recordDataSchema = currentSchema
+		subject := e.subject(name, &registered.schema.avroRecord, confluentSubjectSuffixValue)
+		registered.registryID, err = e.register(ctx, &registered.schema.avroRecord, subject)
+		if err != nil {
+			return nil, err
 		}
+		e.valueCache.Add(cacheKey, registered)
+	}
+
+	// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
+	header := []byte{
+		changefeedbase.ConfluentAvroWireFormatMagic,
+		0, 0, 0, 0, // Placeholder for the ID.
+	}
+	binary.BigEndian.PutUint32(header[1:5], uint32(registered.registryID))
+	meta := envelopeMetadata(registered.schema.opts, evCtx)
+	return registered.schema.BinaryFromRow(header, meta, prevRow, updatedRow, updatedRow)
+}
 
+// encodeKeyValue encodes the row's key and value as a single record, which is
+// registered under the subject of the topic's values.
+func (e *confluentAvroEncoder) encodeKeyValue(
+	ctx context.Context,
+	cacheKey tableIDAndVersionPair,
+	evCtx eventContext,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+) ([]byte, error) {
+	var registered confluentRegisteredKeyValueSchema
+	v, ok := e.keyValueCache.Get(cacheKey)
+	if ok {
+		registered = v.(confluentRegisteredKeyValueSchema)
+		if err := registered.schema.key.refreshTypeMetadata(updatedRow); err != nil {
+			return nil, err
+		}
+		if err := registered.schema.value.refreshTypeMetadata(prevRow, updatedRow); err != nil {
+			return nil, err
+		}
+	} else {
 		name, err := e.rawTableName(updatedRow.Metadata)
 		if err != nil {
 			return nil, err
 		}
-		registered.schema, err = envelopeToAvroSchema(name, opts, beforeDataSchema, afterDataSchema, recordDataSchema, e.schemaPrefix)
-
+		// The key is renamed, since it would otherwise have the same name as
+		// the row data record, which avro doesn't allow within one schema.
+		keySchema, err := e.keySchema(updatedRow, name+`_key`)
+		if err != nil {
+			return nil, err
+		}
+		valueSchema, err := e.envelopeSchema(name, updatedRow, prevRow)
+		if err != nil {
+			return nil, err
+		}
+		registered.schema, err = keyValueToAvroSchema(name, keySchema, valueSchema, e.schemaPrefix)
 		if err != nil {
 			return nil, err
 		}
 
-		// NB: This uses the kafka name escaper because it has to match the name
-		// of the kafka topic.
-		subject := SQLNameToKafkaName(name) + confluentSubjectSuffixValue
+		subject := e.subject(name, &registered.schema.avroRecord, confluentSubjectSuffixValue)
 		registered.registryID, err = e.register(ctx, &registered.schema.avroRecord, subject)
 		if err != nil {
 			return nil, err
 		}
-		e.valueCache.Add(cacheKey, registered)
-	}
-
-	meta := avroMetadata{}
-	if registered.schema.opts.updatedField {
-		meta[`updated`] = evCtx.updated
-	}
-	if registered.schema.opts.mvccTimestampField {
-		meta[`mvcc_timestamp`] = evCtx.mvcc
+		e.keyValueCache.Add(cacheKey, registered)
 	}
 
 	// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
@@ -300,7 +330,61 @@ func (e *confluentAvroEncoder) EncodeValue(
 		0, 0, 0, 0, // Placeholder for the ID.
 	}
 	binary.BigEndian.PutUint32(header[1:5], uint32(registered.registryID))
-	return registered.schema.BinaryFromRow(header, meta, prevRow, updatedRow, updatedRow)
+	key, err := e.keyColumns(updatedRow)
+	if err != nil {
+		return nil, err
+	}
+	meta := envelopeMetadata(registered.schema.value.opts, evCtx)
+	return registered.schema.BinaryFromRow(header, key, meta, prevRow, updatedRow, updatedRow)
+}
+
+// envelopeSchema returns the schema of the envelope of changes to the given
+// table, named after name.
+func (e *confluentAvroEncoder) envelopeSchema(
+	name string, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) (*avroEnvelopeRecord, error) {
+	var beforeDataSchema, afterDataSchema, recordDataSchema *avroDataRecord
+	if e.beforeField && prevRow.IsInitialized() {
+		var err error
+		beforeDataSchema, err = tableToAvroSchema(prevRow, `before`, e.schemaPrefix, e.schemaOpts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	currentSchema, err := tableToAvroSchema(updatedRow, avroSchemaNoSuffix, e.schemaPrefix, e.schemaOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts avroEnvelopeOpts
+
+	// In the wrapped envelope, row data goes in the "after" field. In the raw envelope,
+	// it goes in the "record" field. In the "key_only" envelope it's omitted.
+	// This means metadata can safely go at the top level as there are never arbitrary column names
+	// for it to conflict with.
+	if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
+		opts = avroEnvelopeOpts{afterField: true, beforeField: e.beforeField, updatedField: e.updatedField, mvccTimestampField: e.mvccTimestampField}
+		afterDataSchema = currentSchema
+	} else {
+		opts = avroEnvelopeOpts{recordField: true, updatedField: e.updatedField, mvccTimestampField: e.mvccTimestampField}
+		recordDataSchema = currentSchema
+	}
+
+	return envelopeToAvroSchema(name, opts, beforeDataSchema, afterDataSchema, recordDataSchema, e.schemaPrefix)
+}
+
+// envelopeMetadata returns the metadata of the event which is included in
+// envelopes with the given options.
+func envelopeMetadata(opts avroEnvelopeOpts, evCtx eventContext) avroMetadata {
+	meta := avroMetadata{}
+	if opts.updatedField {
+		meta[`updated`] = evCtx.updated
+	}
+	if opts.mvccTimestampField {
+		meta[`mvcc_timestamp`] = evCtx.mvcc
+	}
+	return meta
 }
 
 // EncodeResolvedTimestamp implements the Encoder interface.
@@ -316,9 +400,7 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 			return nil, err
 		}
 
-		// NB: This uses the kafka name escaper because it has to match the name
-		// of the kafka topic.
-		subject := SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		subject := e.subject(topic, &registered.schema.avroRecord, confluentSubjectSuffixValue)
 		registered.registryID, err = e.register(ctx, &registered.schema.avroRecord, subject)
 		if err != nil {
 			return nil, err
@@ -342,6 +424,26 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 	return registered.schema.BinaryFromRow(header, meta, nilRow, nilRow, nilRow)
 }
 
+// subject returns the schema registry subject under which schema, the key or
+// value schema (as indicated by suffix) of records in the given topic, is
+// registered, following the configured subject naming strategy.
+func (e *confluentAvroEncoder) subject(topic string, schema *avroRecord, suffix string) string {
+	recordName := schema.Name
+	if schema.Namespace != `` {
+		recordName = schema.Namespace + `.` + schema.Name
+	}
+	// NB: This uses the kafka name escaper because it has to match the name
+	// of the kafka topic.
+	switch e.subjectStrategy {
+	case changefeedbase.OptAvroSubjectStrategyRecord:
+		return recordName
+	case changefeedbase.OptAvroSubjectStrategyTopicRecord:
+		return SQLNameToKafkaName(topic) + `-` + recordName
+	default:
+		return SQLNameToKafkaName(topic) + suffix
+	}
+}
+
 func (e *confluentAvroEncoder) register(
 	ctx context.Context, schema *avroRecord, subject string,
 ) (int32, error) {
@@ -11,13 +11,19 @@ package changefeedccl
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	gojson "encoding/json"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
@@ -29,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -54,8 +61,27 @@ func init() {
 // to its value. Updated timestamps in rows and resolved timestamp payloads are
 // stored in a sub-object under the `__crdb__` key in the top-level JSON object.
 type jsonEncoder struct {
-	updatedField, mvccTimestampField, beforeField, keyInValue, topicInValue bool
-	envelopeType                                                            changefeedbase.EnvelopeType
+	updatedField, mvccTimestampField, beforeField, keyInValue, topicInValue, opField bool
+	// valueOnDelete adds the `before` field, populated only for deletes.
+	valueOnDelete bool
+	// sourceField adds the `source` field, identifying the node which
+	// emitted the row.
+	sourceField bool
+	// snapshotField adds the `snapshot` field, which is true for rows emitted
+	// by a periodic snapshot.
+	snapshotField bool
+	// bootstrapField adds the `bootstrap` field, which is true for rows
+	// emitted by the initial scan.
+	bootstrapField bool
+	// checksumField adds the `crc32` field to the wrapped envelope: the
+	// checksum of the encoded `after` field.
+	checksumField bool
+	// ttlExpirationField adds the `ttl_expiration` field, the time at which
+	// the row expires under its table's row-level TTL.
+	ttlExpirationField bool
+	// resolvedLag adds the `lag_ms` field to resolved messages.
+	resolvedLag  bool
+	envelopeType changefeedbase.EnvelopeType
 
 	buf             bytes.Buffer
 	versionEncoder  func(ed *cdcevent.EventDescriptor, isPrev bool) *versionEncoder
@@ -94,6 +120,10 @@ type jsonEncoderOptions struct {
 }
 
 func makeJSONEncoder(ctx context.Context, opts jsonEncoderOptions) (*jsonEncoder, error) {
+	fieldRename, err := changefeedbase.ParseFieldRename(opts.FieldRename)
+	if err != nil {
+		return nil, err
+	}
 	versionCache := cache.NewUnorderedCache(cdcevent.DefaultCacheConfig)
 	e := &jsonEncoder{
 		envelopeType:       opts.Envelope,
@@ -102,9 +132,18 @@ func makeJSONEncoder(ctx context.Context, opts jsonEncoderOptions) (*jsonEncoder
 		customKeyColumn:    opts.CustomKeyColumn,
 		// In the bare envelope we don't output diff directly, it's incorporated into the
 		// projection as desired.
-		beforeField:  opts.Diff && opts.Envelope != changefeedbase.OptEnvelopeBare,
-		keyInValue:   opts.KeyInValue,
-		topicInValue: opts.TopicInValue,
+		beforeField:    opts.Diff && opts.Envelope != changefeedbase.OptEnvelopeBare,
+		keyInValue:     opts.KeyInValue,
+		topicInValue:   opts.TopicInValue,
+		opField:        opts.EmitOpField,
+		sourceField:    opts.IncludeSource,
+		resolvedLag:    opts.ResolvedIncludeLag,
+		snapshotField:  opts.SnapshotField,
+		bootstrapField: opts.MarkInitialScan,
+		checksumField:  opts.Checksum == changefeedbase.OptChecksumCRC32,
+		valueOnDelete: opts.ValueOnDelete && !opts.Diff &&
+			opts.Envelope == changefeedbase.OptEnvelopeWrapped,
+		ttlExpirationField: opts.TTLExpiration,
 		versionEncoder: func(ed *cdcevent.EventDescriptor, isPrev bool) *versionEncoder {
 			key := jsonEncoderVersionKey{
 				CacheKey: cdcevent.CacheKey{
@@ -118,7 +157,16 @@ func makeJSONEncoder(ctx context.Context, opts jsonEncoderOptions) (*jsonEncoder
 				splitPrevRowVersion: isPrev && opts.encodeForQuery && opts.Envelope != changefeedbase.OptEnvelopeBare,
 			}
 			return getCachedOrCreate(key, versionCache, func() interface{} {
-				return &versionEncoder{encodeJSONValueNullAsObject: opts.EncodeJSONValueNullAsObject}
+				return &versionEncoder{
+					encodeJSONValueNullAsObject: opts.EncodeJSONValueNullAsObject,
+					keyTablePrefix:              opts.KeyTablePrefix,
+					decimalAsString:             opts.DecimalFormat == changefeedbase.OptDecimalFormatString,
+					enumAsPhysical:              opts.EnumFormat == changefeedbase.OptEnumFormatPhysical,
+					complexAsString:             opts.ComplexFormat == changefeedbase.OptComplexFormatString,
+					spatialAsEWKB:               opts.SpatialFormat == changefeedbase.OptSpatialFormatEWKB,
+					fieldRename:                 fieldRename,
+					ttlExpirationColumn:         ttlExpirationColumn(ed.TableDescriptor()),
+				}
 			}).(*versionEncoder)
 		},
 	}
@@ -132,6 +180,10 @@ func makeJSONEncoder(ctx context.Context, opts jsonEncoderOptions) (*jsonEncoder
 			return nil, errors.Errorf(`%s is only usable with %s=%s`,
 				changefeedbase.OptTopicInValue, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 		}
+		if e.opField {
+			return nil, errors.Errorf(`%s is only usable with %s=%s`,
+				changefeedbase.OptEmitOpField, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+		}
 	}
 
 	if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
@@ -150,7 +202,34 @@ func makeJSONEncoder(ctx context.Context, opts jsonEncoderOptions) (*jsonEncoder
 // versionEncoder memoizes version specific encoding state.
 type versionEncoder struct {
 	encodeJSONValueNullAsObject bool
-	valueBuilder                *json.FixedKeysObjectBuilder
+	// keyTablePrefix prepends the table name to encoded keys.
+	keyTablePrefix bool
+	// decimalAsString renders DECIMAL values as strings rather than numbers.
+	decimalAsString bool
+	// enumAsPhysical renders enum values as their hex-encoded physical
+	// representation rather than their labels.
+	enumAsPhysical bool
+	// complexAsString renders ARRAY and JSONB values as strings holding their
+	// text representation rather than as nested JSON.
+	complexAsString bool
+	// spatialAsEWKB renders GEOGRAPHY and GEOMETRY values as their hex-encoded
+	// EWKB rather than as GeoJSON.
+	spatialAsEWKB bool
+	// fieldRename maps the names of renamed columns to their names in
+	// encoded values.
+	fieldRename map[string]string
+	// ttlExpirationColumn is the column holding the expiration time of rows
+	// under the table's row-level TTL, if any.
+	ttlExpirationColumn string
+	valueBuilder        *json.FixedKeysObjectBuilder
+}
+
+// fieldName returns the name of the column in encoded values.
+func (e *versionEncoder) fieldName(col cdcevent.ResultColumn) string {
+	if name, ok := e.fieldRename[col.Name]; ok {
+		return name
+	}
+	return col.Name
 }
 
 // EncodeKey implements the Encoder interface.
@@ -164,7 +243,7 @@ func (e *jsonEncoder) EncodeKey(ctx context.Context, row cdcevent.Row) (enc []by
 			return nil, err
 		}
 	}
-	j, err := e.versionEncoder(row.EventDescriptor, false).encodeKeyRaw(ctx, keys)
+	j, err := e.versionEncoder(row.EventDescriptor, false).encodeKeyRaw(ctx, row, keys)
 	if err != nil {
 		return nil, err
 	}
@@ -173,10 +252,14 @@ func (e *jsonEncoder) EncodeKey(ctx context.Context, row cdcevent.Row) (enc []by
 	return e.buf.Bytes(), nil
 }
 
+// encodeKeyRaw encodes the key columns of row returned by it as a JSON array.
 func (e *versionEncoder) encodeKeyRaw(
-	ctx context.Context, it cdcevent.Iterator,
+	ctx context.Context, row cdcevent.Row, it cdcevent.Iterator,
 ) (json.JSON, error) {
 	kb := json.NewArrayBuilder(1)
+	if e.keyTablePrefix {
+		kb.Add(json.FromString(row.TableName))
+	}
 	if err := it.Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
 		j, err := e.datumToJSON(ctx, d)
 		if err != nil {
@@ -194,7 +277,7 @@ func (e *versionEncoder) encodeKeyRaw(
 func (e *versionEncoder) encodeKeyInValue(
 	ctx context.Context, updated cdcevent.Row, b *json.FixedKeysObjectBuilder,
 ) error {
-	keyEntries, err := e.encodeKeyRaw(ctx, updated.ForEachKeyColumn())
+	keyEntries, err := e.encodeKeyRaw(ctx, updated, updated.ForEachKeyColumn())
 	if err != nil {
 		return err
 	}
@@ -216,7 +299,7 @@ func (e *versionEncoder) rowAsGoNative(
 	if e.valueBuilder == nil {
 		keys := make([]string, 0, len(row.ResultColumns()))
 		_ = row.ForEachColumn().Col(func(col cdcevent.ResultColumn) error {
-			keys = append(keys, col.Name)
+			keys = append(keys, e.fieldName(col))
 			return nil
 		})
 		if meta != nil {
@@ -234,7 +317,7 @@ func (e *versionEncoder) rowAsGoNative(
 		if err != nil {
 			return err
 		}
-		return e.valueBuilder.Set(col.Name, j)
+		return e.valueBuilder.Set(e.fieldName(col), j)
 	}); err != nil {
 		return nil, err
 	}
@@ -251,6 +334,30 @@ func (e *versionEncoder) rowAsGoNative(
 var jsonNullObjectCollisionLogLim = log.Every(10 * time.Second)
 
 func (e *versionEncoder) datumToJSON(ctx context.Context, d tree.Datum) (json.JSON, error) {
+	if dd, ok := d.(*tree.DDecimal); ok && e.decimalAsString {
+		// JSON numbers are often parsed as doubles, which can't represent every
+		// decimal exactly.
+		return json.FromString(dd.Decimal.String()), nil
+	}
+	if de, ok := d.(*tree.DEnum); ok && e.enumAsPhysical {
+		return json.FromString(hex.EncodeToString(de.PhysicalRep)), nil
+	}
+	if e.complexAsString {
+		switch t := d.(type) {
+		case *tree.DArray:
+			return json.FromString(tree.AsStringWithFlags(t, tree.FmtPgwireText)), nil
+		case *tree.DJSON:
+			return json.FromString(t.JSON.String()), nil
+		}
+	}
+	if e.spatialAsEWKB {
+		switch t := d.(type) {
+		case *tree.DGeography:
+			return json.FromString(hex.EncodeToString(t.EWKB())), nil
+		case *tree.DGeometry:
+			return json.FromString(hex.EncodeToString(t.EWKB())), nil
+		}
+	}
 	j, err := tree.AsJSON(d, sessiondatapb.DataConversionConfig{}, time.UTC)
 	if err != nil {
 		return nil, err
@@ -308,6 +415,21 @@ func (e *jsonEncoder) initRawEnvelope(ctx context.Context) error {
 	if e.topicInValue {
 		metaKeys = append(metaKeys, "topic")
 	}
+	if e.opField {
+		metaKeys = append(metaKeys, "op")
+	}
+	if e.sourceField {
+		metaKeys = append(metaKeys, "source")
+	}
+	if e.snapshotField {
+		metaKeys = append(metaKeys, "snapshot")
+	}
+	if e.bootstrapField {
+		metaKeys = append(metaKeys, "bootstrap")
+	}
+	if e.ttlExpirationField {
+		metaKeys = append(metaKeys, "ttl_expiration")
+	}
 
 	// Setup builder for crdb meta if needed.
 	var metaBuilder *json.FixedKeysObjectBuilder
@@ -320,7 +442,7 @@ func (e *jsonEncoder) initRawEnvelope(ctx context.Context) error {
 	}
 
 	const emitDeletedRowAsNull = false
-	e.envelopeEncoder = func(evCtx eventContext, updated, _ cdcevent.Row) (_ json.JSON, err error) {
+	e.envelopeEncoder = func(evCtx eventContext, updated, prev cdcevent.Row) (_ json.JSON, err error) {
 		ve := e.versionEncoder(updated.EventDescriptor, false)
 		if len(metaKeys) == 0 {
 			return ve.rowAsGoNative(ctx, updated, emitDeletedRowAsNull, nil)
@@ -350,6 +472,40 @@ func (e *jsonEncoder) initRawEnvelope(ctx context.Context) error {
 			}
 		}
 
+		if e.opField {
+			if err := metaBuilder.Set("op", json.FromString(rowOp(updated, prev))); err != nil {
+				return nil, err
+			}
+		}
+
+		if e.sourceField {
+			if err := metaBuilder.Set("source", evCtx.sourceOrNull()); err != nil {
+				return nil, err
+			}
+		}
+
+		if e.snapshotField {
+			if err := metaBuilder.Set("snapshot", json.FromBool(evCtx.snapshot)); err != nil {
+				return nil, err
+			}
+		}
+
+		if e.bootstrapField {
+			if err := metaBuilder.Set("bootstrap", json.FromBool(evCtx.initialScan)); err != nil {
+				return nil, err
+			}
+		}
+
+		if e.ttlExpirationField {
+			expiration, err := ve.ttlExpiration(updated)
+			if err != nil {
+				return nil, err
+			}
+			if err := metaBuilder.Set("ttl_expiration", expiration); err != nil {
+				return nil, err
+			}
+		}
+
 		meta, err := metaBuilder.Build()
 		if err != nil {
 			return nil, err
@@ -361,7 +517,7 @@ func (e *jsonEncoder) initRawEnvelope(ctx context.Context) error {
 
 func (e *jsonEncoder) initWrappedEnvelope(ctx context.Context) error {
 	keys := []string{"after"}
-	if e.beforeField {
+	if e.beforeField || e.valueOnDelete {
 		keys = append(keys, "before")
 	}
 	if e.keyInValue {
@@ -376,6 +532,24 @@ func (e *jsonEncoder) initWrappedEnvelope(ctx context.Context) error {
 	if e.mvccTimestampField {
 		keys = append(keys, "mvcc_timestamp")
 	}
+	if e.opField {
+		keys = append(keys, "op")
+	}
+	if e.sourceField {
+		keys = append(keys, "source")
+	}
+	if e.snapshotField {
+		keys = append(keys, "snapshot")
+	}
+	if e.bootstrapField {
+		keys = append(keys, "bootstrap")
+	}
+	if e.ttlExpirationField {
+		keys = append(keys, "ttl_expiration")
+	}
+	if e.checksumField {
+		keys = append(keys, "crc32")
+	}
 	b, err := json.NewFixedKeysObjectBuilder(keys)
 	if err != nil {
 		return err
@@ -392,9 +566,9 @@ func (e *jsonEncoder) initWrappedEnvelope(ctx context.Context) error {
 			return nil, err
 		}
 
-		if e.beforeField {
+		if e.beforeField || e.valueOnDelete {
 			var before json.JSON
-			if prev.IsInitialized() && !prev.IsDeleted() {
+			if prev.IsInitialized() && !prev.IsDeleted() && (e.beforeField || updated.IsDeleted()) {
 				before, err = e.versionEncoder(prev.EventDescriptor, true).rowAsGoNative(ctx, prev, emitDeletedRowAsNull, nil)
 				if err != nil {
 					return nil, err
@@ -432,11 +606,129 @@ func (e *jsonEncoder) initWrappedEnvelope(ctx context.Context) error {
 			}
 		}
 
+		if e.opField {
+			if err := b.Set("op", json.FromString(rowOp(updated, prev))); err != nil {
+				return nil, err
+			}
+		}
+
+		if e.sourceField {
+			if err := b.Set("source", evCtx.sourceOrNull()); err != nil {
+				return nil, err
+			}
+		}
+
+		if e.snapshotField {
+			if err := b.Set("snapshot", json.FromBool(evCtx.snapshot)); err != nil {
+				return nil, err
+			}
+		}
+
+		if e.bootstrapField {
+			if err := b.Set("bootstrap", json.FromBool(evCtx.initialScan)); err != nil {
+				return nil, err
+			}
+		}
+
+		if e.ttlExpirationField {
+			expiration, err := ve.ttlExpiration(updated)
+			if err != nil {
+				return nil, err
+			}
+			if err := b.Set("ttl_expiration", expiration); err != nil {
+				return nil, err
+			}
+		}
+
+		if e.checksumField {
+			// The checksum covers the text of the `after` field as it appears in
+			// the encoded value.
+			if err := b.Set("crc32", json.FromString(messageChecksum([]byte(after.String())))); err != nil {
+				return nil, err
+			}
+		}
+
 		return b.Build()
 	}
 	return nil
 }
 
+// makeSourceJSON returns the `source` object added to row values with
+// include_source, which identifies the node that emitted the row.
+func makeSourceJSON(nodeID base.SQLInstanceID, locality roachpb.Locality) json.JSON {
+	b := json.NewObjectBuilder(3)
+	b.Add("node_id", json.FromInt(int(nodeID)))
+	if region, ok := locality.Find("region"); ok {
+		b.Add("region", json.FromString(region))
+	} else {
+		b.Add("region", json.NullJSONValue)
+	}
+	b.Add("locality", json.FromString(locality.String()))
+	return b.Build()
+}
+
+// ttlExpirationColumn returns the column holding the expiration time of the
+// rows of the table under its row-level TTL: crdb_internal_expiration for
+// tables with ttl_expire_after, or the column named by ttl_expiration_expression.
+// It returns an empty string if the table has no row-level TTL, or if its
+// expiration expression isn't a column.
+func ttlExpirationColumn(desc catalog.TableDescriptor) string {
+	if desc == nil || !desc.HasRowLevelTTL() {
+		return ""
+	}
+	expr, err := parser.ParseExpr(string(desc.GetRowLevelTTL().GetTTLExpr()))
+	if err != nil {
+		return ""
+	}
+	name, ok := expr.(*tree.UnresolvedName)
+	if !ok || name.NumParts != 1 {
+		return ""
+	}
+	return name.Parts[0]
+}
+
+// ttlExpiration returns the `ttl_expiration` field of the given row: the value
+// of its TTL expiration column, or JSON null if its table has no row-level
+// TTL, the row is deleted, or the column isn't part of the row, as is the case
+// when it belongs to another column family.
+func (e *versionEncoder) ttlExpiration(row cdcevent.Row) (json.JSON, error) {
+	if e.ttlExpirationColumn == "" || row.IsDeleted() {
+		return json.NullJSONValue, nil
+	}
+	if !slices.ContainsFunc(row.ResultColumns(), func(col cdcevent.ResultColumn) bool {
+		return col.Name == e.ttlExpirationColumn
+	}) {
+		return json.NullJSONValue, nil
+	}
+	it, err := row.DatumNamed(e.ttlExpirationColumn)
+	if err != nil {
+		return nil, err
+	}
+	var expiration json.JSON
+	if err := it.Datum(func(d tree.Datum, _ cdcevent.ResultColumn) (err error) {
+		expiration, err = tree.AsJSON(d, sessiondatapb.DataConversionConfig{}, time.UTC)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return expiration, nil
+}
+
+// rowOp classifies a row event as an INSERT, UPDATE or DELETE based on the
+// state of the row before and after the event. The before image is only
+// available when the feed fetches previous values; emit_op_field ensures that
+// it does.
+func rowOp(updated, prev cdcevent.Row) string {
+	switch {
+	case updated.IsDeleted():
+		return "DELETE"
+	case isInsert(updated, prev):
+		return "INSERT"
+	default:
+		return "UPDATE"
+	}
+}
+
 // EncodeValue implements the Encoder interface.
 func (e *jsonEncoder) EncodeValue(
 	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
@@ -465,6 +757,9 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 	meta := map[string]interface{}{
 		`resolved`: eval.TimestampToDecimalDatum(resolved).Decimal.String(),
 	}
+	if e.resolvedLag {
+		meta[`lag_ms`] = timeutil.Since(resolved.GoTime()).Milliseconds()
+	}
 	var jsonEntries interface{}
 	if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
 		jsonEntries = meta
@@ -0,0 +1,108 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// keySerializerEncoder encodes values, and resolved timestamps, with the
+// encoder for the changefeed's format, but keys with a separate encoder
+// chosen by the kafka_key_serializer option.
+type keySerializerEncoder struct {
+	Encoder
+	keyEncoder interface {
+		EncodeKey(context.Context, cdcevent.Row) ([]byte, error)
+	}
+}
+
+var _ Encoder = &keySerializerEncoder{}
+
+func newKeySerializerEncoder(
+	ctx context.Context,
+	opts changefeedbase.EncodingOptions,
+	targets changefeedbase.Targets,
+	encodeForQuery bool,
+	p externalConnectionProvider,
+	sliMetrics *sliMetrics,
+) (*keySerializerEncoder, error) {
+	valueOpts := opts
+	valueOpts.KafkaKeySerializer = ``
+	valueEncoder, err := getEncoder(ctx, valueOpts, targets, encodeForQuery, p, sliMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the options which affect keys are passed on to the key encoder,
+	// so that value-only options don't trip its validations.
+	keyOpts := changefeedbase.EncodingOptions{
+		Envelope:            changefeedbase.OptEnvelopeKeyOnly,
+		VirtualColumns:      opts.VirtualColumns,
+		CustomKeyColumn:     opts.CustomKeyColumn,
+		SchemaRegistryURI:   opts.SchemaRegistryURI,
+		AvroSchemaPrefix:    opts.AvroSchemaPrefix,
+		AvroSubjectStrategy: opts.AvroSubjectStrategy,
+		AvroUnionNullLast:   opts.AvroUnionNullLast,
+		KeyTablePrefix:      opts.KeyTablePrefix,
+	}
+	e := &keySerializerEncoder{Encoder: valueEncoder}
+	switch opts.KafkaKeySerializer {
+	case changefeedbase.OptKafkaKeySerializerString:
+		e.keyEncoder = newStringKeyEncoder()
+	case changefeedbase.OptKafkaKeySerializerJSON:
+		keyOpts.Format = changefeedbase.OptFormatJSON
+		if e.keyEncoder, err = makeJSONEncoder(ctx, jsonEncoderOptions{EncodingOptions: keyOpts}); err != nil {
+			return nil, err
+		}
+	case changefeedbase.OptKafkaKeySerializerAvro:
+		keyOpts.Format = changefeedbase.OptFormatAvro
+		if e.keyEncoder, err = newConfluentAvroEncoder(keyOpts, targets, p, sliMetrics); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.AssertionFailedf(`unknown %s: %s`,
+			changefeedbase.OptKafkaKeySerializer, opts.KafkaKeySerializer)
+	}
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *keySerializerEncoder) EncodeKey(ctx context.Context, row cdcevent.Row) ([]byte, error) {
+	return e.keyEncoder.EncodeKey(ctx, row)
+}
+
+// stringKeyEncoder encodes keys as the text of the primary key's values,
+// separated by commas. Unlike the other encoders, it only encodes keys.
+type stringKeyEncoder struct {
+	formatter *tree.FmtCtx
+}
+
+func newStringKeyEncoder() *stringKeyEncoder {
+	return &stringKeyEncoder{formatter: tree.NewFmtCtx(tree.FmtExport)}
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *stringKeyEncoder) EncodeKey(_ context.Context, row cdcevent.Row) ([]byte, error) {
+	e.formatter.Reset()
+	sep := ``
+	if err := row.ForEachKeyColumn().Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		e.formatter.WriteString(sep)
+		sep = `,`
+		e.formatter.FormatNode(d)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return e.formatter.Bytes(), nil
+}
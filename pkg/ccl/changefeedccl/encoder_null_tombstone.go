@@ -0,0 +1,53 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+)
+
+// nullTombstoneEncoder encodes rows with the encoder for the changefeed's
+// format and envelope, except for the values of deletes, which it leaves
+// null. Kafka's log compaction treats such messages as tombstones, and
+// eventually removes every message with the same key.
+type nullTombstoneEncoder struct {
+	Encoder
+}
+
+var _ Encoder = &nullTombstoneEncoder{}
+
+func newNullTombstoneEncoder(
+	ctx context.Context,
+	opts changefeedbase.EncodingOptions,
+	targets changefeedbase.Targets,
+	encodeForQuery bool,
+	p externalConnectionProvider,
+	sliMetrics *sliMetrics,
+) (*nullTombstoneEncoder, error) {
+	wrappedOpts := opts
+	wrappedOpts.Tombstone = changefeedbase.OptTombstoneWrapped
+	e, err := getEncoder(ctx, wrappedOpts, targets, encodeForQuery, p, sliMetrics)
+	if err != nil {
+		return nil, err
+	}
+	return &nullTombstoneEncoder{Encoder: e}, nil
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *nullTombstoneEncoder) EncodeValue(
+	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) ([]byte, error) {
+	if updatedRow.IsDeleted() {
+		return nil, nil
+	}
+	return e.Encoder.EncodeValue(ctx, evCtx, updatedRow, prevRow)
+}
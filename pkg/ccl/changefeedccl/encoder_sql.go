@@ -0,0 +1,172 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// sqlEncoder encodes changefeed entries as SQL DML statements which apply
+// each change to a table with the same primary key as the watched table.
+// Inserts and updates are encoded as an INSERT ... ON CONFLICT DO UPDATE on
+// the primary key, and deletes as a DELETE by primary key. Values are
+// formatted as parsable literals, so that they round-trip exactly. Keys are
+// the primary key values as a SQL tuple.
+type sqlEncoder struct {
+	// tableName, if set, overrides the name of the table targeted by the
+	// statements.
+	tableName *tree.TableName
+	buf       *tree.FmtCtx
+}
+
+var _ Encoder = &sqlEncoder{}
+
+func newSQLEncoder(opts changefeedbase.EncodingOptions) (*sqlEncoder, error) {
+	e := &sqlEncoder{buf: tree.NewFmtCtx(tree.FmtParsable)}
+	if opts.SQLTableName != `` {
+		tn, err := parser.ParseQualifiedTableName(opts.SQLTableName)
+		if err != nil {
+			return nil, errors.Wrapf(err, `invalid %s`, changefeedbase.OptSQLTableName)
+		}
+		e.tableName = tn
+	}
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *sqlEncoder) EncodeKey(_ context.Context, row cdcevent.Row) ([]byte, error) {
+	e.buf.Reset()
+	e.buf.WriteByte('(')
+	if err := e.writeDatums(row.ForEachKeyColumn()); err != nil {
+		return nil, err
+	}
+	e.buf.WriteByte(')')
+	return e.buf.Bytes(), nil
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *sqlEncoder) EncodeValue(
+	_ context.Context, _ eventContext, updatedRow cdcevent.Row, _ cdcevent.Row,
+) ([]byte, error) {
+	e.buf.Reset()
+	if updatedRow.IsDeleted() {
+		e.buf.WriteString(`DELETE FROM `)
+		e.writeTableName(updatedRow)
+		e.buf.WriteString(` WHERE `)
+		sep := ``
+		if err := updatedRow.ForEachKeyColumn().Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+			e.buf.WriteString(sep)
+			sep = ` AND `
+			e.buf.FormatName(col.Name)
+			e.buf.WriteString(` = `)
+			e.buf.FormatNode(d)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		e.buf.WriteByte(';')
+		return e.buf.Bytes(), nil
+	}
+
+	isKey := make(map[string]struct{})
+	var keyNames, valueNames []string
+	if err := updatedRow.ForEachKeyColumn().Col(func(col cdcevent.ResultColumn) error {
+		isKey[col.Name] = struct{}{}
+		keyNames = append(keyNames, col.Name)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	e.buf.WriteString(`INSERT INTO `)
+	e.writeTableName(updatedRow)
+	e.buf.WriteString(` (`)
+	sep := ``
+	if err := updatedRow.ForEachColumn().Col(func(col cdcevent.ResultColumn) error {
+		if col.Computed {
+			return nil
+		}
+		if _, ok := isKey[col.Name]; !ok {
+			valueNames = append(valueNames, col.Name)
+		}
+		e.buf.WriteString(sep)
+		sep = `, `
+		e.buf.FormatName(col.Name)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	e.buf.WriteString(`) VALUES (`)
+	sep = ``
+	if err := updatedRow.ForEachColumn().Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		if col.Computed {
+			return nil
+		}
+		e.buf.WriteString(sep)
+		sep = `, `
+		e.buf.FormatNode(d)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	e.buf.WriteString(`) ON CONFLICT (`)
+	for i, name := range keyNames {
+		if i > 0 {
+			e.buf.WriteString(`, `)
+		}
+		e.buf.FormatName(name)
+	}
+	if len(valueNames) == 0 {
+		e.buf.WriteString(`) DO NOTHING;`)
+		return e.buf.Bytes(), nil
+	}
+	e.buf.WriteString(`) DO UPDATE SET `)
+	for i, name := range valueNames {
+		if i > 0 {
+			e.buf.WriteString(`, `)
+		}
+		e.buf.FormatName(name)
+		e.buf.WriteString(` = excluded.`)
+		e.buf.FormatName(name)
+	}
+	e.buf.WriteByte(';')
+	return e.buf.Bytes(), nil
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *sqlEncoder) EncodeResolvedTimestamp(
+	_ context.Context, _ string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	return nil, errors.New("EncodeResolvedTimestamp is not supported with the SQL encoder")
+}
+
+func (e *sqlEncoder) writeTableName(row cdcevent.Row) {
+	if e.tableName != nil {
+		e.buf.FormatNode(e.tableName)
+		return
+	}
+	e.buf.FormatName(row.TableName)
+}
+
+func (e *sqlEncoder) writeDatums(it cdcevent.Iterator) error {
+	sep := ``
+	return it.Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		e.buf.WriteString(sep)
+		sep = `, `
+		e.buf.FormatNode(d)
+		return nil
+	})
+}
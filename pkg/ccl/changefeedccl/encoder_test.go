@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/randgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -728,6 +729,125 @@ func TestAvroSchemaNaming(t *testing.T) {
 	cdcTest(t, testFn, feedTestForceSink("kafka"), feedTestUseRootUserConnection)
 }
 
+// TestAvroKeySchemaRegistration verifies that message keys are encoded with a
+// schema derived from the primary key columns, registered under the topic's
+// key subject, separately from the value schema.
+func TestAvroKeySchemaRegistration(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT, b STRING, c INT, PRIMARY KEY (a, b))`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'x', 2)`)
+
+		fooFeed := feed(t, f, fmt.Sprintf(`CREATE CHANGEFEED FOR foo WITH format=%s`,
+			changefeedbase.OptFormatAvro))
+		defer closeFeed(t, fooFeed)
+
+		// The key decodes against the registered key schema.
+		assertPayloads(t, fooFeed, []string{
+			`foo: {"a":{"long":1},"b":{"string":"x"}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"x"},"c":{"long":2}}}}`,
+		})
+
+		reg := fooFeed.(*kafkaFeed).registry
+		assertRegisteredSubjects(t, reg, []string{
+			`foo-key`,
+			`foo-value`,
+		})
+
+		// The key schema only contains the primary key columns.
+		keySchema := reg.SchemaForSubject(`foo-key`)
+		require.Contains(t, keySchema, `"name":"a"`)
+		require.Contains(t, keySchema, `"name":"b"`)
+		require.NotContains(t, keySchema, `"name":"c"`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestAvroSubjectStrategy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+
+		for _, tc := range []struct {
+			strategy string
+			subjects []string
+		}{
+			{strategy: `topic`, subjects: []string{`foo-key`, `foo-value`}},
+			{strategy: `record`, subjects: []string{`foo`, `foo_envelope`}},
+			{strategy: `topic_record`, subjects: []string{`foo-foo`, `foo-foo_envelope`}},
+		} {
+			t.Run(tc.strategy, func(t *testing.T) {
+				fooFeed := feed(t, f, fmt.Sprintf(`CREATE CHANGEFEED FOR foo `+
+					`WITH format=%s, avro_subject_strategy=%s`, changefeedbase.OptFormatAvro, tc.strategy))
+				defer closeFeed(t, fooFeed)
+
+				assertPayloads(t, fooFeed, []string{
+					`foo: {"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"a"}}}}`,
+				})
+				assertRegisteredSubjects(t, fooFeed.(*kafkaFeed).registry, tc.subjects)
+			})
+		}
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestAvroCombinedKeyValue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+
+		fooFeed := feed(t, f, fmt.Sprintf(`CREATE CHANGEFEED FOR foo `+
+			`WITH format=%s, avro_combined_key_value`, changefeedbase.OptFormatAvro))
+		defer closeFeed(t, fooFeed)
+
+		assertPayloads(t, fooFeed, []string{
+			`foo: {"a":{"long":1}}->{"key":{"a":{"long":1}},"value":{"after":{"foo":{"a":{"long":1},"b":{"string":"a"}}}}}`,
+		})
+
+		// The key and the value can both be extracted from the combined record.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		msgs, err := readNextMessages(context.Background(), fooFeed, 1)
+		require.NoError(t, err)
+		combined, err := json.ParseJSON(string(msgs[0].Value))
+		require.NoError(t, err)
+		key, err := combined.FetchValKey(`key`)
+		require.NoError(t, err)
+		messageKey, err := json.ParseJSON(string(msgs[0].Key))
+		require.NoError(t, err)
+		require.Equal(t, messageKey.String(), key.String())
+		value, err := combined.FetchValKey(`value`)
+		require.NoError(t, err)
+		require.Equal(t, `{"after": null}`, value.String())
+
+		// The combined record is registered under the value subject.
+		reg := fooFeed.(*kafkaFeed).registry
+		assertRegisteredSubjects(t, reg, []string{`foo-key`, `foo-value`})
+		valueSchema := reg.SchemaForSubject(`foo-value`)
+		require.Contains(t, valueSchema, `"name":"foo_key_value"`)
+		require.Contains(t, valueSchema, `"name":"foo_key"`)
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH avro_combined_key_value`,
+			`avro_combined_key_value is only usable with format=avro`)
+		expectErrCreatingFeed(t, f, fmt.Sprintf(`CREATE CHANGEFEED FOR foo `+
+			`WITH format=%s, avro_combined_key_value, envelope=key_only`, changefeedbase.OptFormatAvro),
+			`avro_combined_key_value is only usable with envelope=wrapped or envelope=bare`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
 func TestAvroSchemaNamespace(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -1131,6 +1251,56 @@ func TestParquetEncoder(t *testing.T) {
 	cdcTest(t, testFn, feedTestForceSink("cloudstorage"))
 }
 
+func TestSQLEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT, b STRING, c FLOAT, d INT AS (a + 1) STORED, PRIMARY KEY (a, b))`)
+		sqlDB.Exec(t, `CREATE TABLE "my replica" (a INT, b STRING, c FLOAT, d INT AS (a + 1) STORED, PRIMARY KEY (a, b))`)
+		sqlDB.Exec(t, `INSERT INTO foo (a, b, c) VALUES (1, 'it''s', 0.5), (2, e'new\nline', NULL)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH format=sql, sql_table_name='"my replica"'`)
+		defer closeFeed(t, foo)
+
+		// apply parses and executes the statements in the next n messages
+		// against the replica.
+		apply := func(n int) {
+			t.Helper()
+			for i := 0; i < n; i++ {
+				m, err := foo.Next()
+				require.NoError(t, err)
+				stmts, err := parser.Parse(string(m.Value))
+				require.NoError(t, err)
+				require.Len(t, stmts, 1)
+				sqlDB.Exec(t, string(m.Value))
+			}
+		}
+		expectReplica := func() {
+			t.Helper()
+			sqlDB.CheckQueryResults(t, `SELECT * FROM "my replica" ORDER BY a`,
+				sqlDB.QueryStr(t, `SELECT * FROM foo ORDER BY a`))
+		}
+
+		apply(2)
+		expectReplica()
+
+		sqlDB.Exec(t, `UPDATE foo SET c = 1.5 WHERE a = 1`)
+		apply(1)
+		expectReplica()
+
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		apply(1)
+		expectReplica()
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH format=sql, resolved`,
+			`cannot specify both format=sql and resolved`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
 func TestJsonRountrip(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
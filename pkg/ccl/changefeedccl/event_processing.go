@@ -9,10 +9,15 @@
 package changefeedccl
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	gojson "encoding/json"
 	"hash"
 	"hash/crc32"
 	"runtime"
+	"strconv"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
@@ -21,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -29,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logcrash"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
@@ -41,6 +48,21 @@ type eventContext struct {
 	updated, mvcc hlc.Timestamp
 	// topic is set to the string to be included if TopicInValue is true
 	topic string
+	// source is set to the object to be included if IncludeSource is true.
+	source json.JSON
+	// snapshot is true if the row was emitted by a periodic snapshot.
+	snapshot bool
+	// initialScan is true if the row was emitted by the initial scan.
+	initialScan bool
+}
+
+// sourceOrNull returns the source of the event, or JSON null if it is not
+// known, as is the case for events which are not emitted by an aggregator.
+func (c eventContext) sourceOrNull() json.JSON {
+	if c.source == nil {
+		return json.NullJSONValue
+	}
+	return c.source
 }
 
 type eventConsumer interface {
@@ -69,6 +91,46 @@ type kvEventToRowConsumer struct {
 	metrics *sliMetrics
 	sv      *settings.Values
 
+	// source identifies this node in emitted rows if IncludeSource is set.
+	source json.JSON
+
+	// batch tracks the batch of KV events being consumed if the changefeed
+	// emits batch markers. The begin marker is emitted along with the first row
+	// of the batch, since rows may be filtered out, and the end marker goes to
+	// the same topic with the same key as the begin marker.
+	batch struct {
+		ts hlc.Timestamp
+		// pending is set once the begin marker is consumed and until the
+		// first row of the batch is emitted.
+		pending bool
+		// open is set once the begin marker is emitted and until the end
+		// marker is emitted.
+		open  bool
+		topic TopicDescriptor
+		key   []byte
+	}
+
+	// schemaPreambles, if non-nil, holds the descriptor version of the rows
+	// described by the last schema preamble emitted to each topic. A preamble
+	// is emitted, with the key of the row which follows it, whenever a row of a
+	// different version is emitted to the topic.
+	schemaPreambles map[TopicIdentifier]descpb.DescriptorVersion
+
+	// deleteDelay, if positive, is how long deletes are held back before
+	// being emitted. A non-delete row with the same key arriving in the
+	// meantime supersedes the held delete, which is then dropped. Held deletes
+	// are emitted no later than the next Flush, so that they are never held
+	// past a resolved timestamp.
+	deleteDelay time.Duration
+	// heldDeletes are the held deletes in the order they were held, and
+	// heldDeletesByKey indexes those which haven't been superseded yet.
+	heldDeletes      []*heldDelete
+	heldDeletesByKey map[heldDeleteKey]*heldDelete
+
+	// skipFilterErrors is set if rows for which the CDC query fails to
+	// evaluate are skipped rather than failing the changefeed.
+	skipFilterErrors bool
+
 	// This pacer is used to incorporate event consumption to elastic CPU
 	// control. This helps ensure that event encoding/decoding does not throttle
 	// foreground SQL traffic.
@@ -97,6 +159,11 @@ func newEventConsumer(
 	if err != nil {
 		return nil, nil, err
 	}
+	if emitsChecksumHeader(feed.SinkURI) {
+		// The sink emits the checksums in message headers, so the envelope
+		// doesn't need to carry them.
+		encodingOpts.Checksum = ``
+	}
 
 	pacerRequestUnit := changefeedbase.EventConsumerPacerRequestSize.Get(&cfg.Settings.SV)
 	enablePacer := changefeedbase.PerEventElasticCPUControlEnabled.Get(&cfg.Settings.SV)
@@ -111,7 +178,7 @@ func newEventConsumer(
 
 		var topicNamer *TopicNamer
 		if encodingOpts.TopicInValue {
-			topicNamer, err = MakeTopicNamer(feed.Targets)
+			topicNamer, err = MakeTopicNamer(feed.Targets, WithDeleteSuffix(encodingOpts.DeleteTopicSuffix))
 			if err != nil {
 				return nil, err
 			}
@@ -157,8 +224,18 @@ func newEventConsumer(
 	// does not work for parquet format.
 	//
 	// TODO (jayshrivastava) enable parallel consumers for sinkless changefeeds.
+	//
+	// Batch markers must be emitted in order with the rows of their batch,
+	// which parallel consumers don't preserve. Held deletes must be emitted
+	// when the consumer is flushed, which parallel consumers don't do for
+	// their workers.
 	isSinkless := spec.JobID == 0
-	if numWorkers <= 1 || isSinkless || encodingOpts.Format == changefeedbase.OptFormatParquet {
+	deleteDelay, err := feed.Opts.GetDeleteDelay()
+	if err != nil {
+		return nil, nil, err
+	}
+	if numWorkers <= 1 || isSinkless || encodingOpts.Format == changefeedbase.OptFormatParquet ||
+		feed.Opts.EmitBatchMarkers() || feed.Opts.EmitSchemaPreamble() || deleteDelay > 0 {
 		c, err := makeConsumer(sink, spanFrontier)
 		if err != nil {
 			return nil, nil, err
@@ -241,6 +318,26 @@ func newKVEventToRowConsumer(
 		return nil, err
 	}
 
+	deleteDelay, err := details.Opts.GetDeleteDelay()
+	if err != nil {
+		return nil, err
+	}
+
+	onFilterError, err := details.Opts.GetOnFilterError()
+	if err != nil {
+		return nil, err
+	}
+
+	var schemaPreambles map[TopicIdentifier]descpb.DescriptorVersion
+	if details.Opts.EmitSchemaPreamble() {
+		schemaPreambles = make(map[TopicIdentifier]descpb.DescriptorVersion)
+	}
+
+	var source json.JSON
+	if encodingOpts.IncludeSource {
+		source = makeSourceJSON(cfg.NodeInfo.NodeID.SQLInstanceID(), cfg.Locality)
+	}
+
 	return &kvEventToRowConsumer{
 		frontier:             frontier,
 		encoder:              encoder,
@@ -256,6 +353,11 @@ func newKVEventToRowConsumer(
 		metrics:              metrics,
 		pacer:                pacer,
 		sv:                   cfg.SV(),
+		source:               source,
+		deleteDelay:          deleteDelay,
+		heldDeletesByKey:     make(map[heldDeleteKey]*heldDelete),
+		skipFilterErrors:     onFilterError == changefeedbase.OptOnFilterErrorSkip,
+		schemaPreambles:      schemaPreambles,
 	}, nil
 }
 
@@ -326,6 +428,14 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		return err
 	}
 
+	if ev.BatchMarker() != kvevent.NotBatchMarker {
+		return c.handleBatchMarker(ctx, ev)
+	}
+
+	if ev.SchemaChange() != nil {
+		return c.emitSchemaChange(ctx, ev)
+	}
+
 	schemaTimestamp := ev.KV().Value.Timestamp
 	prevSchemaTimestamp := schemaTimestamp
 	keyOnly := c.details.Opts.KeyOnly()
@@ -347,7 +457,8 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 
 	// Get prev value, if necessary.
 	prevRow, err := func() (cdcevent.Row, error) {
-		if !c.details.Opts.GetFilters().WithDiff {
+		filters := c.details.Opts.GetFilters()
+		if !filters.WithDiff && !(filters.ValueOnDelete && updatedRow.IsDeleted()) {
 			return cdcevent.Row{}, nil
 		}
 		return c.decoder.DecodeKV(ctx, ev.PrevKeyValue(), cdcevent.PrevRow, prevSchemaTimestamp, keyOnly)
@@ -361,9 +472,30 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		return err
 	}
 
+	if c.details.Opts.OnlyInserts() && !isInsert(updatedRow, prevRow) {
+		c.metrics.FilteredMessages.Inc(1)
+		a := ev.DetachAlloc()
+		a.Release(ctx)
+		return nil
+	}
+
+	if c.details.Opts.EmitChangedFamilyOnly() && isUnchangedFamily(ev) {
+		c.metrics.FilteredMessages.Inc(1)
+		a := ev.DetachAlloc()
+		a.Release(ctx)
+		return nil
+	}
+
 	if c.evaluator != nil {
 		updatedRow, err = c.evaluator.Eval(ctx, updatedRow, prevRow)
 		if err != nil {
+			if c.skipFilterErrors && errors.Is(err, cdceval.ErrRowEvaluation) {
+				log.VEventf(ctx, 2, "skipping row which failed to evaluate: %v", err)
+				c.metrics.FilterErrorMessages.Inc(1)
+				a := ev.DetachAlloc()
+				a.Release(ctx)
+				return nil
+			}
 			return err
 		}
 
@@ -376,7 +508,166 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		}
 	}
 
-	return c.encodeAndEmit(ctx, updatedRow, prevRow, schemaTimestamp, ev.DetachAlloc())
+	return c.encodeAndEmit(
+		ctx, updatedRow, prevRow, schemaTimestamp, ev.IsSnapshot(), ev.IsInitialScan(), ev.DetachAlloc())
+}
+
+// emitSchemaChange emits the record describing the schema change event of a
+// changefeed created with ddl_only. Schema changes which don't change the
+// table's visible columns aren't emitted.
+func (c *kvEventToRowConsumer) emitSchemaChange(ctx context.Context, ev kvevent.Event) error {
+	sc := ev.SchemaChange()
+	alloc := ev.DetachAlloc()
+	schemaTS := ev.Timestamp()
+	record := encodeSchemaChangeRecord(sc.Before, sc.After, schemaTS, c.details.Opts.EmitSchemaFingerprint())
+	if record == nil {
+		alloc.Release(ctx)
+		return nil
+	}
+
+	families := sc.After.GetFamilies()
+	topic, err := c.topicForEvent(cdcevent.Metadata{
+		TableID:          sc.After.GetID(),
+		TableName:        sc.After.GetName(),
+		Version:          sc.After.GetVersion(),
+		FamilyID:         families[0].ID,
+		FamilyName:       families[0].Name,
+		HasOtherFamilies: len(families) > 1,
+		SchemaTS:         schemaTS,
+	})
+	if err != nil {
+		return err
+	}
+
+	kb := json.NewArrayBuilder(1)
+	kb.Add(json.FromString(sc.After.GetName()))
+	var keyCopy, valueCopy []byte
+	c.scratch, keyCopy = c.scratch.Copy([]byte(kb.Build().String()), 0 /* extraCap */)
+	c.scratch, valueCopy = c.scratch.Copy([]byte(record.String()), 0 /* extraCap */)
+	alloc.AdjustBytesToTarget(ctx, int64(len(keyCopy)+len(valueCopy)))
+	return c.sink.EmitRow(ctx, topic, keyCopy, valueCopy, schemaTS, schemaTS, alloc)
+}
+
+// handleBatchMarker handles the batch marker event of a changefeed created with
+// emit_batch_markers. Markers are only emitted for batches with at least one
+// emitted row.
+func (c *kvEventToRowConsumer) handleBatchMarker(ctx context.Context, ev kvevent.Event) error {
+	alloc := ev.DetachAlloc()
+	alloc.Release(ctx)
+
+	switch ev.BatchMarker() {
+	case kvevent.BatchBegin:
+		c.batch.ts = ev.KV().Value.Timestamp
+		c.batch.pending = true
+		c.batch.open = false
+		return nil
+	case kvevent.BatchEnd:
+		c.batch.pending = false
+		if !c.batch.open {
+			return nil
+		}
+		c.batch.open = false
+		return c.emitBatchMarker(ctx, c.batch.topic, c.batch.key, `end`)
+	default:
+		return errors.AssertionFailedf("unexpected batch marker %d", ev.BatchMarker())
+	}
+}
+
+// emitBatchMarker emits a marker message, such as
+// {"batch": "begin", "mvcc_timestamp": "..."}, for the current batch.
+func (c *kvEventToRowConsumer) emitBatchMarker(
+	ctx context.Context, topic TopicDescriptor, key []byte, marker string,
+) error {
+	b := json.NewObjectBuilder(2)
+	b.Add("batch", json.FromString(marker))
+	b.Add("mvcc_timestamp", json.FromString(c.batch.ts.AsOfSystemTime()))
+	var valueCopy []byte
+	c.scratch, valueCopy = c.scratch.Copy([]byte(b.Build().String()), 0 /* extraCap */)
+	return c.sink.EmitRow(ctx, topic, key, valueCopy, c.batch.ts, c.batch.ts, kvevent.Alloc{})
+}
+
+// maybeEmitSchemaPreamble emits the schema preamble describing the columns of
+// the row to the topic, unless the last one emitted to it described the same
+// version of the table.
+func (c *kvEventToRowConsumer) maybeEmitSchemaPreamble(
+	ctx context.Context, topic TopicDescriptor, key []byte, row cdcevent.Row, schemaTS hlc.Timestamp,
+) error {
+	id := topic.GetTopicIdentifier()
+	if v, ok := c.schemaPreambles[id]; ok && v == topic.GetVersion() {
+		return nil
+	}
+	preamble, err := encodeSchemaPreamble(row, c.details.Opts.EmitSchemaFingerprint())
+	if err != nil {
+		return err
+	}
+	var valueCopy []byte
+	c.scratch, valueCopy = c.scratch.Copy([]byte(preamble.String()), 0 /* extraCap */)
+	if err := c.sink.EmitRow(ctx, topic, key, valueCopy, schemaTS, row.MvccTimestamp, kvevent.Alloc{}); err != nil {
+		return err
+	}
+	c.schemaPreambles[id] = topic.GetVersion()
+	return nil
+}
+
+// encodeSchemaPreamble returns the schema preamble describing the key and
+// value columns of the row, which is of the form:
+//
+//	{"schema": {"columns": [...], "key": [{"name": "a", "type": "INT8"}], "table": "foo"}}
+//
+// The family is included if the table has more than one column family, and
+// the schema fingerprint of the value columns if fingerprint is set.
+func encodeSchemaPreamble(row cdcevent.Row, fingerprint bool) (json.JSON, error) {
+	columns := func(it cdcevent.Iterator) (json.JSON, error) {
+		b := json.NewArrayBuilder(0)
+		if err := it.Col(func(col cdcevent.ResultColumn) error {
+			cb := json.NewObjectBuilder(2)
+			cb.Add("name", json.FromString(col.Name))
+			cb.Add("type", json.FromString(col.Typ.SQLString()))
+			b.Add(cb.Build())
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return b.Build(), nil
+	}
+	keyCols, err := columns(row.ForEachKeyColumn())
+	if err != nil {
+		return nil, err
+	}
+	valueCols, err := columns(row.ForEachColumn())
+	if err != nil {
+		return nil, err
+	}
+
+	schema := json.NewObjectBuilder(5)
+	schema.Add("table", json.FromString(row.TableName))
+	if row.HasOtherFamilies {
+		schema.Add("family", json.FromString(row.FamilyName))
+	}
+	schema.Add("key", keyCols)
+	schema.Add("columns", valueCols)
+	if fingerprint {
+		schema.Add("fingerprint", json.FromString(schemaFingerprint(valueCols)))
+	}
+	b := json.NewObjectBuilder(1)
+	b.Add("schema", schema.Build())
+	return b.Build(), nil
+}
+
+// isInsert returns true if the event is the insertion of a new row: the row
+// exists and its before image is null. Rows produced by a backfill have no
+// before image and are therefore treated as inserts.
+func isInsert(updatedRow, prevRow cdcevent.Row) bool {
+	return !updatedRow.IsDeleted() && (!prevRow.IsInitialized() || prevRow.IsDeleted())
+}
+
+// isUnchangedFamily returns whether the event rewrites a column family of a
+// row with the same value it had before, as happens when other families of the
+// row are updated by an UPSERT. Deletions are never considered unchanged.
+func isUnchangedFamily(ev kvevent.Event) bool {
+	updated, prev := ev.KV().Value, ev.PrevKeyValue().Value
+	return updated.IsPresent() && prev.IsPresent() &&
+		bytes.Equal(updated.TagAndDataBytes(), prev.TagAndDataBytes())
 }
 
 func (c *kvEventToRowConsumer) encodeAndEmit(
@@ -384,6 +675,7 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	updatedRow cdcevent.Row,
 	prevRow cdcevent.Row,
 	schemaTS hlc.Timestamp,
+	snapshot, initialScan bool,
 	alloc kvevent.Alloc,
 ) error {
 	topic, err := c.topicForEvent(updatedRow.Metadata)
@@ -403,8 +695,15 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	}
 
 	evCtx := eventContext{
-		updated: schemaTS,
-		mvcc:    updatedRow.MvccTimestamp,
+		updated:     schemaTS,
+		mvcc:        updatedRow.MvccTimestamp,
+		source:      c.source,
+		snapshot:    snapshot,
+		initialScan: initialScan,
+	}
+
+	if c.encodingOpts.DeleteTopicSuffix != "" && updatedRow.IsDeleted() {
+		topic = deletesTopic{TopicDescriptor: topic}
 	}
 
 	if c.topicNamer != nil {
@@ -428,24 +727,76 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 		)
 	}
 	var keyCopy, valueCopy []byte
+	if c.knobs.BeforeEncodeRow != nil {
+		if err := c.knobs.BeforeEncodeRow(ctx); err != nil {
+			return changefeedbase.MarkEncodeError(err)
+		}
+	}
 	encodedKey, err := c.encoder.EncodeKey(ctx, updatedRow)
 	if err != nil {
-		return err
+		return changefeedbase.MarkEncodeError(err)
+	}
+	if c.encodingOpts.ShardCount > 0 {
+		encodedKey = shardKey(encodedKey, c.encodingOpts.ShardCount)
 	}
 	c.scratch, keyCopy = c.scratch.Copy(encodedKey, 0 /* extraCap */)
+	if c.schemaPreambles != nil {
+		if err := c.maybeEmitSchemaPreamble(ctx, topic, keyCopy, updatedRow, schemaTS); err != nil {
+			return err
+		}
+	}
+	if c.batch.pending {
+		c.batch.pending = false
+		c.batch.open = true
+		c.batch.topic, c.batch.key = topic, keyCopy
+		if err := c.emitBatchMarker(ctx, topic, keyCopy, `begin`); err != nil {
+			return err
+		}
+	}
 	// TODO(yevgeniy): Some refactoring is needed in the encoder: namely, prevRow
 	// might not be available at all when working with changefeed expressions.
 	encodedValue, err := c.encoder.EncodeValue(ctx, evCtx, updatedRow, prevRow)
 	if err != nil {
-		return err
+		return changefeedbase.MarkEncodeError(err)
+	}
+	// A nil value, e.g. a null tombstone, is emitted as such.
+	if encodedValue != nil {
+		c.scratch, valueCopy = c.scratch.Copy(encodedValue, 0 /* extraCap */)
 	}
-	c.scratch, valueCopy = c.scratch.Copy(encodedValue, 0 /* extraCap */)
 
 	// Since we're done processing/converting this event, and will not use much more
 	// than len(key)+len(bytes) worth of resources, adjust allocation to match.
 	alloc.AdjustBytesToTarget(ctx, int64(len(keyCopy)+len(valueCopy)))
 
-	if err := c.sink.EmitRow(
+	if c.deleteDelay > 0 {
+		now := timeutil.Now()
+		if err := c.emitExpiredDeletes(ctx, now); err != nil {
+			return err
+		}
+		k := makeHeldDeleteKey(topic, keyCopy)
+		if held, ok := c.heldDeletesByKey[k]; ok {
+			delete(c.heldDeletesByKey, k)
+			if !updatedRow.IsDeleted() {
+				// The row was re-inserted: drop the delete.
+				held.superseded = true
+				held.alloc.Release(ctx)
+				c.metrics.FilteredMessages.Inc(1)
+			} else if err := c.emitHeldDelete(ctx, held); err != nil {
+				return err
+			}
+		}
+		if updatedRow.IsDeleted() {
+			held := &heldDelete{
+				heldAt: now, topic: topic, key: keyCopy, value: valueCopy,
+				updated: schemaTS, mvcc: updatedRow.MvccTimestamp, alloc: alloc,
+			}
+			c.heldDeletes = append(c.heldDeletes, held)
+			c.heldDeletesByKey[k] = held
+			return nil
+		}
+	}
+
+	if err := c.emitEncodedRow(
 		ctx, topic, keyCopy, valueCopy, schemaTS, updatedRow.MvccTimestamp, alloc,
 	); err != nil {
 		return err
@@ -456,8 +807,167 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	return nil
 }
 
+// emitEncodedRow emits an encoded row to the sink, splitting its value into
+// chunks if it exceeds max_message_bytes.
+func (c *kvEventToRowConsumer) emitEncodedRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	if max := c.encodingOpts.MaxMessageBytes; max > 0 && int64(len(value)) > max {
+		return c.emitChunked(ctx, topic, key, value, updated, mvcc, alloc, max)
+	}
+	return c.sink.EmitRow(ctx, topic, key, value, updated, mvcc, alloc)
+}
+
+// heldDeleteKey identifies the row of a held delete.
+type heldDeleteKey struct {
+	topic TopicIdentifier
+	key   string
+}
+
+// makeHeldDeleteKey returns the heldDeleteKey of the row with the given key.
+// Deletes routed to a topic of their own are keyed by the topic of the other
+// events of the row, so that a re-insert supersedes them.
+func makeHeldDeleteKey(topic TopicDescriptor, key []byte) heldDeleteKey {
+	id := topic.GetTopicIdentifier()
+	id.Deletes = false
+	return heldDeleteKey{topic: id, key: string(key)}
+}
+
+// heldDelete is an encoded delete held back by a changefeed created with
+// delete_delay.
+type heldDelete struct {
+	heldAt     time.Time
+	topic      TopicDescriptor
+	key, value []byte
+	updated    hlc.Timestamp
+	mvcc       hlc.Timestamp
+	alloc      kvevent.Alloc
+	superseded bool
+	emitted    bool
+}
+
+// emitExpiredDeletes emits the held deletes which have been held for at least
+// deleteDelay as of now.
+func (c *kvEventToRowConsumer) emitExpiredDeletes(ctx context.Context, now time.Time) error {
+	for len(c.heldDeletes) > 0 && now.Sub(c.heldDeletes[0].heldAt) >= c.deleteDelay {
+		held := c.heldDeletes[0]
+		c.heldDeletes[0] = nil
+		c.heldDeletes = c.heldDeletes[1:]
+		if err := c.emitHeldDelete(ctx, held); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emitHeldDelete emits a held delete, unless it has been superseded or
+// already emitted.
+func (c *kvEventToRowConsumer) emitHeldDelete(ctx context.Context, held *heldDelete) error {
+	if held.superseded || held.emitted {
+		return nil
+	}
+	held.emitted = true
+	k := makeHeldDeleteKey(held.topic, held.key)
+	if c.heldDeletesByKey[k] == held {
+		delete(c.heldDeletesByKey, k)
+	}
+	return c.emitEncodedRow(ctx, held.topic, held.key, held.value, held.updated, held.mvcc, held.alloc)
+}
+
+// shardKey returns the JSON key, e.g. `[3]`, of the shard out of shardCount
+// that the encoded primary key hashes to. The checksum of the encoded key does
+// not depend on the process, so a row maps to the same shard across restarts.
+func shardKey(encodedKey []byte, shardCount int) []byte {
+	shard := crc32.ChecksumIEEE(encodedKey) % uint32(shardCount)
+	return append(strconv.AppendUint([]byte{'['}, uint64(shard), 10), ']')
+}
+
+// chunkEnvelopeOverhead is an upper bound on the number of bytes, in addition
+// to the base64 encoded data, in a chunkEnvelope.
+const chunkEnvelopeOverhead = 128
+
+// chunkEnvelope is the value of a message carrying a chunk of a value which
+// exceeded the max_message_bytes option.
+//
+// All chunks of a value are emitted, in order, with the key of the original
+// row. Consumers reassemble the original value by concatenating the data of
+// chunks 0 through total-1 for a key; a message for that key which is not a
+// chunk, or a chunk with index 0, starts a new value.
+type chunkEnvelope struct {
+	Chunk struct {
+		Index int    `json:"index"`
+		Total int    `json:"total"`
+		Data  []byte `json:"data"`
+	} `json:"__crdb_chunk__"`
+}
+
+// chunkValue splits value into chunk messages, each of which is at most
+// maxBytes in size.
+func chunkValue(value []byte, maxBytes int64) ([][]byte, error) {
+	chunkSize := base64.StdEncoding.DecodedLen(int(maxBytes) - chunkEnvelopeOverhead)
+	if chunkSize <= 0 {
+		return nil, errors.AssertionFailedf("%s=%d is too small", changefeedbase.OptMaxMessageBytes, maxBytes)
+	}
+	total := (len(value) + chunkSize - 1) / chunkSize
+	chunks := make([][]byte, 0, total)
+	for i := 0; i < total; i++ {
+		var env chunkEnvelope
+		env.Chunk.Index = i
+		env.Chunk.Total = total
+		env.Chunk.Data = value[i*chunkSize : min((i+1)*chunkSize, len(value))]
+		chunk, err := gojson.Marshal(env)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// emitChunked emits value as a sequence of chunk messages. The allocation is
+// attached to the last chunk, so that it is released once the entire value
+// has been acknowledged.
+func (c *kvEventToRowConsumer) emitChunked(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+	maxBytes int64,
+) error {
+	chunks, err := chunkValue(value, maxBytes)
+	if err != nil {
+		return err
+	}
+	for i, chunk := range chunks {
+		var chunkAlloc kvevent.Alloc
+		if i == len(chunks)-1 {
+			chunkAlloc = alloc
+		}
+		if err := c.sink.EmitRow(ctx, topic, key, chunk, updated, mvcc, chunkAlloc); err != nil {
+			if i < len(chunks)-1 {
+				alloc.Release(ctx)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 // Close closes this consumer.
 func (c *kvEventToRowConsumer) Close() error {
+	// Deletes still held were never flushed, so they are emitted again once the
+	// changefeed restarts from its last checkpoint.
+	for _, held := range c.heldDeletes {
+		if !held.superseded && !held.emitted {
+			held.alloc.Release(context.Background())
+		}
+	}
+	c.heldDeletes = nil
 	c.pacer.Close()
 	if c.evaluator != nil {
 		c.evaluator.Close()
@@ -486,8 +996,15 @@ func (c *kvEventToRowConsumer) encodeForParquet(
 	return nil
 }
 
-// Flush is a noop for the kvEventToRowConsumer because it does not buffer any events.
+// Flush emits the deletes held by the kvEventToRowConsumer, if any. It
+// doesn't otherwise buffer events.
 func (c *kvEventToRowConsumer) Flush(ctx context.Context) error {
+	for _, held := range c.heldDeletes {
+		if err := c.emitHeldDelete(ctx, held); err != nil {
+			return err
+		}
+	}
+	c.heldDeletes = nil
 	return nil
 }
 
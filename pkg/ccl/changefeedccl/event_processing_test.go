@@ -9,6 +9,7 @@
 package changefeedccl
 
 import (
+	"fmt"
 	"math/rand"
 	"testing"
 
@@ -206,6 +207,23 @@ func TestShardingByKey(t *testing.T) {
 	}
 }
 
+func TestShardKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const shardCount = 4
+	shards := map[string]string{}
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf(`[%d]`, i))
+		shard := string(shardKey(key, shardCount))
+		require.Equal(t, shard, string(shardKey(key, shardCount)))
+		shards[shard] = string(key)
+	}
+	require.Len(t, shards, shardCount)
+	for i := 0; i < shardCount; i++ {
+		require.Contains(t, shards, fmt.Sprintf(`[%d]`, i))
+	}
+}
+
 func BenchmarkShardingByKey(b *testing.B) {
 	rng, _ := randutil.NewTestRand()
 	p := parallelEventConsumer{numWorkers: 32, hasher: makeHasher()}
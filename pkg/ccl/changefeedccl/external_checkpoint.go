@@ -0,0 +1,223 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// With external_checkpoint='<uri>', the changeFrontier writes the full span
+// frontier of the changefeed to a file in the given external storage location
+// every time it checkpoints the changefeed's progress. The spans leading the
+// highwater are then left out of the job record, which only holds the
+// highwater, however many of them there are. When the changefeed resumes, the
+// frontier is restored from the file, so that the aggregators don't scan or
+// re-emit the spans which were resolved past the highwater.
+//
+// The frontier only contains spans which have been flushed to the sink, so
+// the file may safely be ahead of the job record. It may also be behind it: the
+// file is written in the background, so that slow external storage doesn't
+// hold up the changeFrontier, and the spans which are missing from the file are
+// only re-emitted from the highwater when the changefeed resumes.
+
+// externalCheckpointWriteTimeout bounds each write of the external checkpoint.
+const externalCheckpointWriteTimeout = time.Minute
+
+// externalCheckpointFilename returns the name of the file holding the frontier
+// of the given changefeed.
+func externalCheckpointFilename(jobID jobspb.JobID) string {
+	return fmt.Sprintf(`changefeed-%d.frontier`, jobID)
+}
+
+// externalCheckpointWriter writes the frontier of a changefeed to its external
+// checkpoint in the background. Only the latest frontier is written: the ones
+// submitted while a write is in flight replace each other.
+type externalCheckpointWriter struct {
+	es    cloud.ExternalStorage
+	jobID jobspb.JobID
+
+	// pending is signaled when a frontier is submitted.
+	pending chan struct{}
+	mu      struct {
+		syncutil.Mutex
+		// frontier is the encoded frontier to write next, if any.
+		frontier []byte
+	}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startExternalCheckpointWriter starts writing the frontiers submitted to the
+// returned writer to the external checkpoint of the given changefeed, until
+// the writer is closed or ctx is canceled.
+func startExternalCheckpointWriter(
+	ctx context.Context, es cloud.ExternalStorage, jobID jobspb.JobID,
+) *externalCheckpointWriter {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &externalCheckpointWriter{
+		es:      es,
+		jobID:   jobID,
+		pending: make(chan struct{}, 1),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		for {
+			select {
+			case <-w.pending:
+				w.writePending(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return w
+}
+
+// submit encodes every span of the frontier, along with its resolved
+// timestamp, and schedules it to be written to the external checkpoint.
+func (w *externalCheckpointWriter) submit(forEachSpan spanIter) error {
+	var frontier jobspb.ResolvedSpans
+	forEachSpan(func(s roachpb.Span, ts hlc.Timestamp) span.OpResult {
+		frontier.ResolvedSpans = append(frontier.ResolvedSpans, jobspb.ResolvedSpan{Span: s, Timestamp: ts})
+		return span.ContinueMatch
+	})
+	buf, err := protoutil.Marshal(&frontier)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.mu.frontier = buf
+	w.mu.Unlock()
+	select {
+	case w.pending <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// writePending writes the last frontier submitted, if it hasn't been written
+// yet. Failures are logged rather than returned: the job record still holds
+// the highwater, so a missing or stale file only causes spans to be re-emitted.
+func (w *externalCheckpointWriter) writePending(ctx context.Context) {
+	w.mu.Lock()
+	buf := w.mu.frontier
+	w.mu.frontier = nil
+	w.mu.Unlock()
+	if buf == nil {
+		return
+	}
+	if err := timeutil.RunWithTimeout(ctx, "write external checkpoint", externalCheckpointWriteTimeout,
+		func(ctx context.Context) error {
+			return cloud.WriteFile(ctx, w.es, externalCheckpointFilename(w.jobID), bytes.NewReader(buf))
+		},
+	); err != nil {
+		log.Warningf(ctx, "writing external checkpoint of changefeed %d: %v", w.jobID, err)
+	}
+}
+
+// close stops the writer, writes the last frontier submitted, if it hasn't
+// been written yet, and closes the external storage.
+func (w *externalCheckpointWriter) close(ctx context.Context) error {
+	w.cancel()
+	<-w.done
+	w.writePending(context.WithoutCancel(ctx))
+	return w.es.Close()
+}
+
+// readExternalCheckpoint returns the spans of the frontier written to the
+// external checkpoint of the given changefeed, if any.
+func readExternalCheckpoint(
+	ctx context.Context, es cloud.ExternalStorage, jobID jobspb.JobID,
+) ([]jobspb.ResolvedSpan, error) {
+	r, _, err := es.ReadFile(ctx, externalCheckpointFilename(jobID), cloud.ReadOptions{NoFileSize: true})
+	if err != nil {
+		if errors.Is(err, cloud.ErrFileDoesNotExist) {
+			// The changefeed hasn't checkpointed yet.
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "reading external checkpoint")
+	}
+	defer r.Close(ctx)
+	buf, err := ioctx.ReadAll(ctx, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading external checkpoint")
+	}
+	var frontier jobspb.ResolvedSpans
+	if err := protoutil.Unmarshal(buf, &frontier); err != nil {
+		return nil, errors.Wrap(err, "decoding external checkpoint")
+	}
+	return frontier.ResolvedSpans, nil
+}
+
+// restoreExternalCheckpoint advances the given highwater of the tracked spans
+// with the frontier read from the external checkpoint at uri. It returns the
+// resulting highwater, along with a checkpoint of the spans leading it, which
+// is nil if there are none.
+func restoreExternalCheckpoint(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	uri string,
+	jobID jobspb.JobID,
+	trackedSpans []roachpb.Span,
+	highWater hlc.Timestamp,
+) (hlc.Timestamp, *jobspb.ChangefeedProgress_Checkpoint, error) {
+	es, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, uri, user)
+	if err != nil {
+		return hlc.Timestamp{}, nil, err
+	}
+	defer es.Close()
+
+	resolved, err := readExternalCheckpoint(ctx, es, jobID)
+	if err != nil || len(resolved) == 0 {
+		return highWater, nil, err
+	}
+
+	sf, err := span.MakeFrontierAt(highWater, trackedSpans...)
+	if err != nil {
+		return hlc.Timestamp{}, nil, err
+	}
+	defer sf.Release()
+	// The tracked spans may have changed since the checkpoint was written, in
+	// which case the spans which are no longer tracked are ignored, and the
+	// ones which weren't tracked yet remain at the highwater.
+	for _, rs := range resolved {
+		if _, err := sf.Forward(rs.Span, rs.Timestamp); err != nil {
+			return hlc.Timestamp{}, nil, err
+		}
+	}
+
+	var checkpoint *jobspb.ChangefeedProgress_Checkpoint
+	spans, ts := getCheckpointSpans(sf.Frontier(), sf.Entries, math.MaxInt64)
+	if len(spans) > 0 {
+		checkpoint = &jobspb.ChangefeedProgress_Checkpoint{Spans: spans, Timestamp: ts}
+	}
+	return sf.Frontier(), checkpoint, nil
+}
@@ -1025,6 +1025,26 @@ func makeFeedFactoryWithOptions(
 		userDB, cleanup := getInitialDBForEnterpriseFactory(t, s, db, options)
 		f.(*pulsarFeedFactory).enterpriseFeedFactory.configureUserDB(userDB)
 		return f, func() { cleanup() }
+	case "syslog":
+		f := makeSyslogFeedFactory(srvOrCluster, db)
+		userDB, cleanup := getInitialDBForEnterpriseFactory(t, s, db, options)
+		f.(*syslogFeedFactory).enterpriseFeedFactory.configureUserDB(userDB)
+		return f, func() { cleanup() }
+	case "unix":
+		f := makeUnixFeedFactory(srvOrCluster, db)
+		userDB, cleanup := getInitialDBForEnterpriseFactory(t, s, db, options)
+		f.(*unixFeedFactory).enterpriseFeedFactory.configureUserDB(userDB)
+		return f, func() { cleanup() }
+	case "clickhouse":
+		f := makeClickHouseFeedFactory(srvOrCluster, db)
+		userDB, cleanup := getInitialDBForEnterpriseFactory(t, s, db, options)
+		f.(*clickhouseFeedFactory).enterpriseFeedFactory.configureUserDB(userDB)
+		return f, func() { cleanup() }
+	case "amqp":
+		f := makeAMQPFeedFactory(srvOrCluster, db)
+		userDB, cleanup := getInitialDBForEnterpriseFactory(t, s, db, options)
+		f.(*amqpFeedFactory).enterpriseFeedFactory.configureUserDB(userDB)
+		return f, func() { cleanup() }
 	case "sinkless":
 		pgURLForUserSinkless := func(u string, pass ...string) (url.URL, func()) {
 			t.Logf("pgURL %s %s", sinkType, u)
@@ -1189,7 +1209,7 @@ func maybeUseExternalConnection(
 	// percentExternal is the chance of randomly running a test using an `external://` uri.
 	// Set to 1 to always do this.
 	const percentExternal = 0.5
-	if sinkType == `sinkless` || sinkType == `enterprise` || sinkType == `pulsar` || strings.Contains(flakyWhenExternalConnection, sinkType) ||
+	if sinkType == `sinkless` || sinkType == `enterprise` || sinkType == `pulsar` || sinkType == `syslog` || sinkType == `unix` || sinkType == `clickhouse` || sinkType == `amqp` || strings.Contains(flakyWhenExternalConnection, sinkType) ||
 		options.forceNoExternalConnectionURI || rand.Float32() > percentExternal {
 		return factory
 	}
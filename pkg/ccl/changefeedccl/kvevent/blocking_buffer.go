@@ -32,6 +32,10 @@ type blockingBuffer struct {
 	qp       allocPool     // Pool for memory allocations.
 	signalCh chan struct{} // Signal when new events are available.
 
+	// onDrop, if set, enables dropping of superseded events when the buffer
+	// runs out of memory, and is invoked with the number of dropped events.
+	onDrop func(entries int64)
+
 	req struct {
 		syncutil.Mutex
 		memRequest
@@ -55,7 +59,35 @@ type blockingBuffer struct {
 func NewMemBuffer(
 	acc mon.BoundAccount, sv *settings.Values, metrics *PerBufferMetricsWithCompat,
 ) Buffer {
-	return newMemBuffer(acc, sv, metrics, nil)
+	return newMemBuffer(acc, sv, metrics, nil, nil)
+}
+
+// NewMemBufferWithAllocCallback is like NewMemBuffer, but additionally
+// invokes onAllocChange with the change in the number of bytes and entries
+// allocated from this buffer which have not yet been released. Allocations
+// are released once the events have been acknowledged by the sink.
+func NewMemBufferWithAllocCallback(
+	acc mon.BoundAccount,
+	sv *settings.Values,
+	metrics *PerBufferMetricsWithCompat,
+	onAllocChange func(bytes, entries int64),
+) Buffer {
+	return newMemBuffer(acc, sv, metrics, nil, onAllocChange, nil)
+}
+
+// NewDroppingMemBuffer is like NewMemBufferWithAllocCallback, but instead of
+// blocking when it runs out of space, it first drops queued KV events whose
+// key has been written again by a later queued KV event. onDrop is invoked
+// with the number of events dropped. The buffer only blocks if dropping
+// superseded events does not release enough memory.
+func NewDroppingMemBuffer(
+	acc mon.BoundAccount,
+	sv *settings.Values,
+	metrics *PerBufferMetricsWithCompat,
+	onAllocChange func(bytes, entries int64),
+	onDrop func(entries int64),
+) Buffer {
+	return newMemBuffer(acc, sv, metrics, nil, onAllocChange, onDrop)
 }
 
 // TestingNewMemBuffer allows test to construct buffer which will invoked
@@ -66,7 +98,7 @@ func TestingNewMemBuffer(
 	metrics *PerBufferMetricsWithCompat,
 	onWaitStart quotapool.OnWaitStartFunc,
 ) Buffer {
-	return newMemBuffer(acc, sv, metrics, onWaitStart)
+	return newMemBuffer(acc, sv, metrics, onWaitStart, nil, nil)
 }
 
 func newMemBuffer(
@@ -74,6 +106,8 @@ func newMemBuffer(
 	sv *settings.Values,
 	metrics *PerBufferMetricsWithCompat,
 	onWaitStart quotapool.OnWaitStartFunc,
+	onAllocChange func(bytes, entries int64),
+	onDrop func(entries int64),
 ) Buffer {
 	const slowAcquisitionThreshold = 5 * time.Second
 
@@ -81,11 +115,12 @@ func newMemBuffer(
 		signalCh: make(chan struct{}, 1),
 		metrics:  metrics,
 		sv:       sv,
+		onDrop:   onDrop,
 	}
 	b.mu.queue = &bufferEventChunkQueue{}
 
 	// Quota pool notifies out of quota events through notifyOutOfQuota
-	quota := &memQuota{acc: acc, notifyOutOfQuota: b.notifyOutOfQuota}
+	quota := &memQuota{acc: acc, notifyOutOfQuota: b.notifyOutOfQuota, onAllocChange: onAllocChange}
 
 	opts := []quotapool.Option{
 		quotapool.OnSlowAcquisition(slowAcquisitionThreshold, logSlowAcquisition(slowAcquisitionThreshold, metrics.BufferType)),
@@ -239,6 +274,13 @@ func (b *blockingBuffer) AcquireMemory(ctx context.Context, n int64) (alloc Allo
 		return alloc, errors.Newf("event size %d exceeds per changefeed limit %d", alloc, l)
 	}
 	alloc.init(n, &b.qp)
+	if b.onDrop != nil {
+		// Prefer dropping superseded events over blocking.
+		if b.tryAcquire(ctx, n) {
+			return alloc, nil
+		}
+		b.dropSuperseded(ctx)
+	}
 	if err := func() error {
 		b.req.Lock()
 		defer b.req.Unlock()
@@ -255,6 +297,38 @@ func (b *blockingBuffer) AcquireMemory(ctx context.Context, n int64) (alloc Allo
 	return alloc, nil
 }
 
+// tryAcquire attempts to acquire the specified number of bytes without
+// blocking. Returns true if the bytes were acquired.
+func (b *blockingBuffer) tryAcquire(ctx context.Context, n int64) bool {
+	r := memRequestNoWait(n)
+	if err := b.qp.Acquire(ctx, &r); err != nil {
+		return false
+	}
+	b.metrics.BufferEntriesMemAcquired.Inc(n)
+	b.metrics.AllocatedMem.Inc(n)
+	return true
+}
+
+// dropSuperseded drops queued events which have been superseded by a later
+// queued event for the same key, releasing their allocations.
+func (b *blockingBuffer) dropSuperseded(ctx context.Context) {
+	dropped := func() []Event {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.mu.closed {
+			return nil
+		}
+		return b.mu.queue.dropSuperseded()
+	}()
+	if len(dropped) == 0 {
+		return
+	}
+	for i := range dropped {
+		dropped[i].alloc.Release(ctx)
+	}
+	b.onDrop(int64(len(dropped)))
+}
+
 // Add implements Writer interface.
 func (b *blockingBuffer) Add(ctx context.Context, e Event) error {
 	if log.V(2) {
@@ -331,6 +405,7 @@ func (b *blockingBuffer) CloseWithReason(ctx context.Context, reason error) erro
 		quota.closed = true
 		quota.acc.Close(ctx)
 		b.metrics.AllocatedMem.Dec(quota.allocated)
+		quota.updateAllocated(-quota.allocated, -quota.allocatedEntries)
 		return false
 	})
 
@@ -363,6 +438,9 @@ type memQuota struct {
 	// allocated is the number of bytes currently allocated.
 	allocated int64
 
+	// allocatedEntries is the number of entries currently allocated.
+	allocatedEntries int64
+
 	// Errors indicating a failure to allocate are relatively expensive.
 	// We don't want to see them often. If we see one, avoid allocating
 	// again until the allocated budget drops to below half that level.
@@ -373,11 +451,25 @@ type memQuota struct {
 	// times for a single request that's blocked.
 	notifyOutOfQuota func(canFlush bool)
 
+	// onAllocChange, if set, is invoked with the change in allocated bytes
+	// and entries.
+	onAllocChange func(bytes, entries int64)
+
 	acc mon.BoundAccount
 }
 
 var _ quotapool.Resource = (*memQuota)(nil)
 
+// updateAllocated adjusts the allocated bytes and entries by the specified
+// deltas.
+func (q *memQuota) updateAllocated(bytes, entries int64) {
+	q.allocated += bytes
+	q.allocatedEntries += entries
+	if q.onAllocChange != nil {
+		q.onAllocChange(bytes, entries)
+	}
+}
+
 type memRequest int64
 
 // Acquire implements quotapool.Request interface.
@@ -436,7 +528,7 @@ func (r *memRequest) acquireQuota(
 		return false, 0
 	}
 
-	quota.allocated += int64(*r)
+	quota.updateAllocated(int64(*r), 1)
 	quota.canAllocateBelow = 0
 	return true, 0
 }
@@ -446,6 +538,31 @@ func (r *memRequest) ShouldWait() bool {
 	return true
 }
 
+// memRequestNoWait is a memory request which fails rather than waits when
+// the memory is not immediately available.
+type memRequestNoWait int64
+
+// Acquire implements quotapool.Request interface.
+func (r *memRequestNoWait) Acquire(
+	ctx context.Context, resource quotapool.Resource,
+) (fulfilled bool, tryAgainAfter time.Duration) {
+	quota := resource.(*memQuota)
+	if quota.canAllocateBelow > 0 && quota.allocated > quota.canAllocateBelow {
+		return false, 0
+	}
+	if err := quota.acc.Grow(ctx, int64(*r)); err != nil {
+		return false, 0
+	}
+	quota.updateAllocated(int64(*r), 1)
+	quota.canAllocateBelow = 0
+	return true, 0
+}
+
+// ShouldWait implements quotapool.Request interface.
+func (r *memRequestNoWait) ShouldWait() bool {
+	return false
+}
+
 type allocPool struct {
 	*quotapool.AbstractPool
 	metrics *PerBufferMetricsWithCompat
@@ -463,7 +580,7 @@ func (ap allocPool) Release(ctx context.Context, bytes, entries int64) {
 			return false
 		}
 		quota.acc.Shrink(ctx, bytes)
-		quota.allocated -= bytes
+		quota.updateAllocated(-bytes, -entries)
 		ap.metrics.AllocatedMem.Dec(bytes)
 		ap.metrics.BufferEntriesMemReleased.Inc(bytes)
 		ap.metrics.BufferEntriesReleased.Inc(entries)
@@ -239,3 +239,56 @@ func TestBlockingBufferNotifiesConsumerWhenOutOfMemory(t *testing.T) {
 		},
 	))
 }
+
+func TestDroppingBufferDropsSupersededEvents(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	metrics := kvevent.MakeMetrics(time.Minute).AggregatorBufferMetricsWithCompat
+	ba, release := getBoundAccountWithBudget(4096)
+	defer release()
+
+	var dropped int64
+	st := cluster.MakeTestingClusterSettings()
+	buf := kvevent.NewDroppingMemBuffer(ba, &st.SV, &metrics, nil, func(entries int64) {
+		dropped += entries
+	})
+	defer func() {
+		require.NoError(t, buf.CloseWithReason(context.Background(), nil))
+	}()
+
+	// Repeatedly write the same key without consuming any events. A regular
+	// buffer would block once it runs out of memory; this one should instead
+	// drop the superseded events.
+	const numEvents = 100
+	rnd, _ := randutil.NewTestRand()
+	key := makeRangeFeedEvent(rnd, 0, 0).Val.Key
+	var lastValue []byte
+	require.NoError(t, timeutil.RunWithTimeout(
+		context.Background(), "produce", 10*time.Second,
+		func(ctx context.Context) error {
+			for i := 0; i < numEvents; i++ {
+				ev := makeRangeFeedEvent(rnd, 256, 0)
+				ev.Val.Key = key
+				lastValue = ev.Val.Value.RawBytes
+				if err := buf.Add(ctx, kvevent.MakeKVEvent(ev)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	))
+	require.Greater(t, dropped, int64(0))
+
+	// Only the events which were not dropped remain, and the latest value is
+	// the last one to be consumed.
+	ctx := context.Background()
+	var e kvevent.Event
+	for i := int64(0); i < numEvents-dropped; i++ {
+		var err error
+		e, err = buf.Get(ctx)
+		require.NoError(t, err)
+		require.Equal(t, kvevent.TypeKV, e.Type())
+	}
+	require.Equal(t, lastValue, e.KV().Value.RawBytes)
+}
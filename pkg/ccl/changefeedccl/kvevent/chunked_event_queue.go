@@ -65,6 +65,30 @@ func (l *bufferEventChunkQueue) dequeue() (e Event, ok bool) {
 
 }
 
+// dropSuperseded removes from the queue every KV event whose key is also
+// written by a later KV event in the queue, and returns the removed events.
+// The relative order of the remaining events is preserved.
+func (l *bufferEventChunkQueue) dropSuperseded() (dropped []Event) {
+	var events []Event
+	latest := make(map[string]int)
+	for e, ok := l.dequeue(); ok; e, ok = l.dequeue() {
+		if e.Type() == TypeKV {
+			latest[string(e.KV().Key)] = len(events)
+		}
+		events = append(events, e)
+	}
+	l.purge()
+
+	for i, e := range events {
+		if e.Type() == TypeKV && latest[string(e.KV().Key)] != i {
+			dropped = append(dropped, e)
+			continue
+		}
+		l.enqueue(e)
+	}
+	return dropped
+}
+
 func (l *bufferEventChunkQueue) purge() {
 	for l.head != nil {
 		chunkToFree := l.head
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -106,6 +107,10 @@ type Event struct {
 	ev                 *kvpb.RangeFeedEvent
 	et                 Type
 	backfillTimestamp  hlc.Timestamp
+	snapshot           bool
+	initialScan        bool
+	schemaChange       *SchemaChange
+	batchMarker        BatchMarker
 	bufferAddTimestamp time.Time
 	alloc              Alloc
 }
@@ -200,6 +205,48 @@ func (e *Event) BackfillTimestamp() hlc.Timestamp {
 	return e.backfillTimestamp
 }
 
+// IsSnapshot returns true if this KV event was emitted by a periodic snapshot
+// of the watched spans, rather than by the rangefeed or a backfill.
+func (e *Event) IsSnapshot() bool {
+	return e.snapshot
+}
+
+// IsInitialScan returns true if this KV event was emitted by the initial scan
+// of the watched spans.
+func (e *Event) IsInitialScan() bool {
+	return e.initialScan
+}
+
+// SchemaChange describes a change to the schema of a watched table.
+type SchemaChange struct {
+	Before, After catalog.TableDescriptor
+}
+
+// SchemaChange returns the schema change described by this KV event, or nil
+// if this event is not a schema change event.
+func (e *Event) SchemaChange() *SchemaChange {
+	return e.schemaChange
+}
+
+// BatchMarker identifies the KV events which mark the beginning or the end of
+// a batch of KV events; see NewBatchMarkerEvent.
+type BatchMarker int
+
+const (
+	// NotBatchMarker is the BatchMarker of all other KV events.
+	NotBatchMarker BatchMarker = iota
+	// BatchBegin marks the beginning of a batch.
+	BatchBegin
+	// BatchEnd marks the end of a batch.
+	BatchEnd
+)
+
+// BatchMarker returns which batch boundary this KV event marks, or
+// NotBatchMarker if it isn't a batch marker event.
+func (e *Event) BatchMarker() BatchMarker {
+	return e.batchMarker
+}
+
 // BufferAddTimestamp is the time this event came into  the buffer.
 func (e *Event) BufferAddTimestamp() time.Time {
 	return e.bufferAddTimestamp
@@ -333,3 +380,56 @@ func NewBackfillKVEvent(
 		backfillTimestamp: backfillTS,
 	}
 }
+
+// NewSnapshotKVEvent returns new KV event constructed during a periodic
+// snapshot of the watched spans taken at snapshotTS.
+func NewSnapshotKVEvent(key []byte, ts hlc.Timestamp, val []byte, snapshotTS hlc.Timestamp) Event {
+	e := NewBackfillKVEvent(key, ts, val, false /* withDiff */, snapshotTS)
+	e.snapshot = true
+	return e
+}
+
+// NewInitialScanKVEvent returns new KV event constructed during the initial
+// scan of the watched spans taken at scanTS.
+func NewInitialScanKVEvent(key []byte, ts hlc.Timestamp, val []byte, scanTS hlc.Timestamp) Event {
+	e := NewBackfillKVEvent(key, ts, val, false /* withDiff */, scanTS)
+	e.initialScan = true
+	return e
+}
+
+// NewSchemaChangeEvent returns new KV event describing a change to the schema
+// of a watched table, for changefeeds which only emit schema changes. The
+// event has no value; its key is within the table's watched spans, and its
+// timestamp is the time of the change.
+func NewSchemaChangeEvent(key roachpb.Key, before, after catalog.TableDescriptor) Event {
+	rfe := &kvpb.RangeFeedEvent{
+		Val: &kvpb.RangeFeedValue{
+			Key:   key,
+			Value: roachpb.Value{Timestamp: after.GetModificationTime()},
+		},
+	}
+	return Event{
+		ev:           rfe,
+		et:           TypeKV,
+		schemaChange: &SchemaChange{Before: before, After: after},
+	}
+}
+
+// NewBatchMarkerEvent returns a new KV event marking the beginning or the end
+// of a batch of KV events, for changefeeds which emit batch boundaries. A
+// batch is a run of KV events of a single table at the same MVCC timestamp.
+// The event has no value; its key and timestamp are those of the first KV
+// event of the batch.
+func NewBatchMarkerEvent(key roachpb.Key, ts hlc.Timestamp, marker BatchMarker) Event {
+	rfe := &kvpb.RangeFeedEvent{
+		Val: &kvpb.RangeFeedValue{
+			Key:   key,
+			Value: roachpb.Value{Timestamp: ts},
+		},
+	}
+	return Event{
+		ev:          rfe,
+		et:          TypeKV,
+		batchMarker: marker,
+	}
+}
@@ -15,6 +15,7 @@ package kvfeed
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
@@ -50,6 +51,11 @@ type MonitoringConfig struct {
 
 	OnBackfillCallback      func() func()
 	OnBackfillRangeCallback func(int64) (func(), func())
+
+	// OnSchemaChangeBackfillCallback is called at the beginning of each
+	// backfill triggered by a schema change, i.e. any backfill but the initial
+	// scan, and returns a function to be called once the backfill is done.
+	OnSchemaChangeBackfillCallback func() func()
 }
 
 // Config configures a kvfeed.
@@ -91,6 +97,44 @@ type Config struct {
 	// enables filtering out any transactional writes with that flag set to true.
 	WithFiltering bool
 
+	// InitialScanParallelism, if positive, is the number of concurrent scan
+	// requests issued during the initial scan, bounded by the
+	// changefeed.backfill.max_initial_scan_parallelism setting.
+	InitialScanParallelism int
+
+	// InitialScanFollowerReads, if set, has the initial scan served by the
+	// nearest replicas rather than by the leaseholders.
+	InitialScanFollowerReads bool
+
+	// InitialScanAt, if set, defers the initial scan until the clock reaches
+	// this time. Changes are streamed from InitialHighWater in the meantime,
+	// but no resolved timestamps are emitted until the scan completes.
+	InitialScanAt hlc.Timestamp
+
+	// ReplaySpans, if set, are re-scanned as of InitialHighWater before the
+	// rangefeed starts, when the feed doesn't need an initial scan. Only the
+	// parts of the spans which overlap Spans are scanned.
+	ReplaySpans []roachpb.Span
+
+	// ValueOnDelete, if set without WithDiff, fetches the previous value of
+	// deleted keys so that delete events carry the row's last value.
+	ValueOnDelete bool
+
+	// SnapshotInterval, if positive, is the interval at which all of the
+	// spans are re-scanned while the rangefeed runs. Rows emitted by these
+	// scans are marked as snapshot rows.
+	SnapshotInterval time.Duration
+
+	// DDLOnly, if set, suppresses row data: no spans are scanned and no
+	// rangefeed is established. The spans are resolved as the schema feed
+	// advances, and an event is emitted for each schema change to the watched
+	// tables.
+	DDLOnly bool
+
+	// EmitBatchMarkers, if set, brackets each batch of KV events from the
+	// rangefeed with batch marker events; see kvevent.NewBatchMarkerEvent.
+	EmitBatchMarkers bool
+
 	// Knobs are kvfeed testing knobs.
 	Knobs TestingKnobs
 }
@@ -108,7 +152,13 @@ func Run(ctx context.Context, cfg Config) error {
 		}
 	}
 	var pff physicalFeedFactory
-	{
+	if cfg.DDLOnly {
+		pff = &schemaFeedResolver{
+			schemaFeed: cfg.SchemaFeed,
+			clock:      cfg.Clock,
+			interval:   changefeedbase.TableDescriptorPollInterval.Get(&cfg.Settings.SV),
+		}
+	} else {
 		sender := cfg.DB.NonTransactionalSender()
 		distSender := sender.(*kv.CrossRangeTxnWrapperSender).Wrapped().(*kvcoord.DistSender)
 		pff = rangefeedFactory(distSender.RangeFeedSpans)
@@ -128,6 +178,18 @@ func Run(ctx context.Context, cfg Config) error {
 		cfg.SchemaFeed,
 		sc, pff, bf, cfg.Targets, cfg.Knobs)
 	f.onBackfillCallback = cfg.MonitoringCfg.OnBackfillCallback
+	f.onSchemaChangeBackfillCallback = cfg.MonitoringCfg.OnSchemaChangeBackfillCallback
+	f.initialScanParallelism = cfg.InitialScanParallelism
+	f.initialScanFollowerReads = cfg.InitialScanFollowerReads
+	f.initialScanAt = cfg.InitialScanAt
+	f.replaySpans = cfg.ReplaySpans
+	f.clock = cfg.Clock
+	f.snapshotInterval = cfg.SnapshotInterval
+	f.ddlOnly = cfg.DDLOnly
+	f.emitBatchMarkers = cfg.EmitBatchMarkers
+	if cfg.ValueOnDelete && !cfg.WithDiff {
+		f.db = cfg.DB
+	}
 	f.rangeObserver = startLaggingRangesObserver(g, cfg.MonitoringCfg.LaggingRangesCallback,
 		cfg.MonitoringCfg.LaggingRangesPollingInterval, cfg.MonitoringCfg.LaggingRangesThreshold)
 
@@ -252,11 +314,54 @@ type kvFeed struct {
 	writer              kvevent.Writer
 	codec               keys.SQLCodec
 
+	// initialScanParallelism, if positive, overrides the number of concurrent
+	// scan requests used during the initial scan.
+	initialScanParallelism int
+
+	// initialScanFollowerReads, if set, routes the initial scan's requests to
+	// the nearest replicas.
+	initialScanFollowerReads bool
+
+	// initialScanAt, if set, is the time until which the initial scan is
+	// deferred. While the deferred scan is pending, deferredScan runs it
+	// alongside the rangefeeds and scanPending is true. scanDone is closed
+	// once it completes.
+	initialScanAt hlc.Timestamp
+	clock         *hlc.Clock
+	deferredScan  func(ctx context.Context) error
+	scanPending   atomic.Bool
+	scanDone      chan struct{}
+
+	// replaySpans are re-scanned as of the initial high-water in place of the
+	// initial scan.
+	replaySpans []roachpb.Span
+
+	// snapshotInterval, if positive, is the interval between periodic
+	// snapshots of the spans. nextSnapshot is the time at which the next one
+	// is due, and snapshotPending is true while one is running.
+	snapshotInterval time.Duration
+	nextSnapshot     time.Time
+	snapshotPending  atomic.Bool
+
+	// ddlOnly, if set, suppresses row data in favor of schema change events.
+	ddlOnly bool
+
+	// emitBatchMarkers, if set, brackets batches of KV events from the
+	// rangefeed with batch marker events.
+	emitBatchMarkers bool
+
+	// db, if set, is used to fetch the previous value of deleted keys.
+	db *kv.DB
+
 	onBackfillCallback func() func()
 	rangeObserver      kvcoord.RangeObserver
 	schemaChangeEvents changefeedbase.SchemaChangeEventClass
 	schemaChangePolicy changefeedbase.SchemaChangePolicy
 
+	// onSchemaChangeBackfillCallback is like onBackfillCallback, but only
+	// called for backfills triggered by schema changes.
+	onSchemaChangeBackfillCallback func() func()
+
 	targets changefeedbase.Targets
 
 	// These dependencies are made available for test injection.
@@ -310,7 +415,19 @@ func newKVFeed(
 
 var errChangefeedCompleted = errors.New("changefeed completed")
 
-func (f *kvFeed) run(ctx context.Context) (err error) {
+func (f *kvFeed) run(ctx context.Context) error {
+	// A deferred initial scan runs in the background until it completes,
+	// alongside the rangefeeds which runFeeds restarts at each table event.
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		return f.runFeeds(ctx, g)
+	})
+	return g.Wait()
+}
+
+// runFeeds runs the scans and rangefeeds of the kvfeed until an error occurs
+// or the changefeed completes. A deferred initial scan is started in g.
+func (f *kvFeed) runFeeds(ctx context.Context, g ctxgroup.Group) (err error) {
 	emitResolved := func(ts hlc.Timestamp, boundary jobspb.ResolvedSpan_BoundaryType) error {
 		for _, sp := range f.spans {
 			if err := f.writer.Add(ctx, kvevent.NewBackfillResolvedEvent(sp, ts, boundary)); err != nil {
@@ -337,6 +454,9 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 		if err != nil {
 			return err
 		}
+		if initialScan && f.scanPending.Load() {
+			g.GoCtx(f.deferredScan)
+		}
 		// We have scanned scannedSpans up to and including scannedTS.  Advance frontier
 		// for those spans.  Note, since rangefeed start time is *exclusive* (that it, rangefeed
 		// starts from timestamp.Next()), we advanced frontier to the scannedTS.
@@ -353,8 +473,14 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 			return errChangefeedCompleted
 		}
 
-		if err = f.runUntilTableEvent(ctx, rangeFeedResumeFrontier); err != nil {
+		err = f.runUntilTableEvent(ctx, rangeFeedResumeFrontier)
+		if err != nil {
 			if tErr := (*errEndTimeReached)(nil); errors.As(err, &tErr) {
+				// The spans are resolved past the initial high-water as the
+				// changefeed exits, so a deferred initial scan must finish first.
+				if err := f.waitForDeferredScan(ctx); err != nil {
+					return err
+				}
 				if err := emitResolved(rangeFeedResumeFrontier.Frontier(), jobspb.ResolvedSpan_EXIT); err != nil {
 					return err
 				}
@@ -375,6 +501,11 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 		if err != nil {
 			return err
 		}
+		if f.ddlOnly {
+			if err := f.emitSchemaChanges(ctx, events); err != nil {
+				return err
+			}
+		}
 
 		// Detect whether the event corresponds to a primary index change. Also
 		// detect whether the change corresponds to any change in the set of visible
@@ -394,10 +525,19 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 		} else if f.schemaChangePolicy == changefeedbase.OptSchemaChangePolicyStop {
 			boundaryType = jobspb.ResolvedSpan_EXIT
 		}
+		if boundaryType == jobspb.ResolvedSpan_RESTART || boundaryType == jobspb.ResolvedSpan_EXIT {
+			// The changefeed stops at the boundary, which resolves the spans
+			// past the initial high-water, so a deferred initial scan must
+			// finish first.
+			if err := f.waitForDeferredScan(ctx); err != nil {
+				return err
+			}
+		}
 		// Resolve all of the spans as a boundary if the policy indicates that
-		// we should do so.
-		if f.schemaChangePolicy != changefeedbase.OptSchemaChangePolicyNoBackfill ||
-			boundaryType == jobspb.ResolvedSpan_RESTART {
+		// we should do so. While a deferred initial scan is pending, the
+		// rangefeed resolves the spans once it completes instead.
+		if (f.schemaChangePolicy != changefeedbase.OptSchemaChangePolicyNoBackfill ||
+			boundaryType == jobspb.ResolvedSpan_RESTART) && !f.scanPending.Load() {
 			if err := emitResolved(highWater, boundaryType); err != nil {
 				return err
 			}
@@ -434,6 +574,7 @@ func filterCheckpointSpans(spans []roachpb.Span, completed []roachpb.Span) []roa
 
 // scanIfShould performs a scan of KV pairs in watched span if
 // - this is the initial scan, or
+// - this is the first scan of a feed with spans to replay, or
 // - table schema is changed (a column is added/dropped) and a re-scan is needed.
 // It returns spans it has scanned, the timestamp at which the scan happened, and error if any.
 //
@@ -457,10 +598,23 @@ func (f *kvFeed) scanIfShould(
 	// time with an initial backfill but if you use a cursor then you will get the
 	// updates after that timestamp.
 	isInitialScan := initialScan && f.withInitialBackfill
+	isReplay := initialScan && !isInitialScan && len(f.replaySpans) > 0
 	var spansToScan []roachpb.Span
 	if isInitialScan {
 		scanTime = highWater
 		spansToScan = f.spans
+	} else if isReplay {
+		// The replayed spans are scanned as of the high-water, as they would be
+		// by an initial scan, and schema changes right after it are handled
+		// once the rangefeed runs into them.
+		scanTime = highWater
+		for _, sp := range f.spans {
+			for _, replay := range f.replaySpans {
+				if sp.Overlaps(replay) {
+					spansToScan = append(spansToScan, sp.Intersect(replay))
+				}
+			}
+		}
 	} else if len(events) > 0 {
 		// Only backfill for the tables which have events which may not be all
 		// of the targets.
@@ -502,26 +656,68 @@ func (f *kvFeed) scanIfShould(
 	// spans which we no longer need to scan.
 	spansToBackfill := filterCheckpointSpans(spansToScan, f.checkpoint)
 
-	if (!isInitialScan && f.schemaChangePolicy == changefeedbase.OptSchemaChangePolicyNoBackfill) ||
-		len(spansToBackfill) == 0 {
+	isSchemaChangeBackfill := !isInitialScan && !isReplay
+	if (isSchemaChangeBackfill && f.schemaChangePolicy == changefeedbase.OptSchemaChangePolicyNoBackfill) ||
+		len(spansToBackfill) == 0 || f.ddlOnly {
 		return spansToScan, scanTime, nil
 	}
 
-	if f.onBackfillCallback != nil {
-		defer f.onBackfillCallback()()
-	}
-
 	boundaryType := jobspb.ResolvedSpan_NONE
 	if initialScanOnly {
 		boundaryType = jobspb.ResolvedSpan_EXIT
 	}
-	if err := f.scanner.Scan(ctx, f.writer, scanConfig{
+	scanCfg := scanConfig{
 		Spans:     spansToBackfill,
 		Timestamp: scanTime,
-		WithDiff:  !isInitialScan && f.withDiff,
+		WithDiff:  isSchemaChangeBackfill && f.withDiff,
 		Knobs:     f.knobs,
 		Boundary:  boundaryType,
-	}); err != nil {
+	}
+	if isInitialScan {
+		scanCfg.Parallelism = f.initialScanParallelism
+		scanCfg.InitialScan = true
+		scanCfg.FollowerReads = f.initialScanFollowerReads
+	}
+	writer := f.writer
+	if !isInitialScan && f.scanPending.Load() {
+		// The spans mustn't be resolved past the initial high-water until the
+		// deferred initial scan completes.
+		writer = &resolvedWithholdingWriter{Writer: f.writer, withhold: f.scanPending.Load}
+	}
+	scan := func(ctx context.Context) error {
+		if f.onBackfillCallback != nil {
+			defer f.onBackfillCallback()()
+		}
+		if isSchemaChangeBackfill && f.onSchemaChangeBackfillCallback != nil {
+			defer f.onSchemaChangeBackfillCallback()()
+		}
+		return f.scanner.Scan(ctx, writer, scanCfg)
+	}
+
+	if isInitialScan && f.initialScanAt.IsSet() {
+		if !initialScanOnly {
+			// Defer the scan so that it runs alongside the rangefeed. The spans
+			// are reported as scanned so that the rangefeed starts from scanTime.
+			f.scanPending.Store(true)
+			f.scanDone = make(chan struct{})
+			f.deferredScan = func(ctx context.Context) error {
+				if err := f.waitForInitialScanAt(ctx); err != nil {
+					return err
+				}
+				if err := scan(ctx); err != nil {
+					return err
+				}
+				f.scanPending.Store(false)
+				close(f.scanDone)
+				return nil
+			}
+			return spansToScan, scanTime, nil
+		}
+		if err := f.waitForInitialScanAt(ctx); err != nil {
+			return nil, hlc.Timestamp{}, err
+		}
+	}
+	if err := scan(ctx); err != nil {
 		return nil, hlc.Timestamp{}, err
 	}
 
@@ -530,6 +726,190 @@ func (f *kvFeed) scanIfShould(
 	return spansToScan, scanTime, nil
 }
 
+// emitSchemaChanges writes a schema change event for each of the table events
+// to the writer. Every kvfeed of the changefeed sees the same table events, so
+// only the one watching the start of a table's primary index emits its events.
+func (f *kvFeed) emitSchemaChanges(ctx context.Context, events []schemafeed.TableEvent) error {
+	for _, ev := range events {
+		key := f.codec.IndexPrefix(uint32(ev.Before.GetID()), uint32(ev.Before.GetPrimaryIndexID()))
+		watched := false
+		for _, sp := range f.spans {
+			if sp.ContainsKey(key) {
+				watched = true
+				break
+			}
+		}
+		if !watched {
+			continue
+		}
+		if err := f.writer.Add(ctx, kvevent.NewSchemaChangeEvent(key, ev.Before, ev.After)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchPrevValue returns the value of the key immediately before ts.
+func (f *kvFeed) fetchPrevValue(
+	ctx context.Context, key roachpb.Key, ts hlc.Timestamp,
+) (roachpb.Value, error) {
+	txn := f.db.NewTxn(ctx, "changefeed value on delete")
+	if err := txn.SetFixedTimestamp(ctx, ts.Prev()); err != nil {
+		return roachpb.Value{}, err
+	}
+	res, err := txn.Get(ctx, key)
+	if err != nil || res.Value == nil {
+		return roachpb.Value{}, err
+	}
+	return *res.Value, nil
+}
+
+// waitForInitialScanAt blocks until the clock reaches initialScanAt.
+func (f *kvFeed) waitForInitialScanAt(ctx context.Context) error {
+	wait := f.initialScanAt.GoTime().Sub(f.clock.PhysicalTime())
+	if wait <= 0 {
+		return nil
+	}
+	log.Infof(ctx, "deferring initial scan until %s", f.initialScanAt.GoTime())
+	var timer timeutil.Timer
+	defer timer.Stop()
+	timer.Reset(wait)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		timer.Read = true
+		return nil
+	}
+}
+
+// waitForDeferredScan blocks until the deferred initial scan, if any, has
+// completed.
+func (f *kvFeed) waitForDeferredScan(ctx context.Context) error {
+	if !f.scanPending.Load() {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-f.scanDone:
+		return nil
+	}
+}
+
+// runPeriodicSnapshots re-scans all of the spans every snapshotInterval,
+// alongside the rangefeed. Resolved events are withheld while a snapshot is
+// running, so that the frontier never advances past the snapshot's rows.
+func (f *kvFeed) runPeriodicSnapshots(ctx context.Context) error {
+	// A snapshot interrupted by a table event is retried once the rangefeed
+	// restarts.
+	f.snapshotPending.Store(false)
+	if f.nextSnapshot.IsZero() {
+		f.nextSnapshot = f.clock.PhysicalTime().Add(f.snapshotInterval)
+	}
+
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for {
+		timer.Reset(f.nextSnapshot.Sub(f.clock.PhysicalTime()))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			timer.Read = true
+		}
+
+		f.snapshotPending.Store(true)
+		scanTime := f.clock.Now()
+		if f.endTime.IsSet() && f.endTime.LessEq(scanTime) {
+			// Rows past the end time are never emitted.
+			f.snapshotPending.Store(false)
+			return nil
+		}
+		log.Infof(ctx, "starting periodic snapshot at %s", scanTime)
+		if err := f.scanner.Scan(ctx, f.writer, scanConfig{
+			Spans:     f.spans,
+			Timestamp: scanTime,
+			Knobs:     f.knobs,
+			Snapshot:  true,
+		}); err != nil {
+			return err
+		}
+		f.snapshotPending.Store(false)
+		f.nextSnapshot = f.clock.PhysicalTime().Add(f.snapshotInterval)
+	}
+}
+
+// batchMarkingWriter is a kvevent.Writer which brackets each batch of KV
+// events with batch marker events. A batch is a run of consecutive KV events of
+// the same table at the same MVCC timestamp, such as the rows written by a
+// single statement; it ends at the first event which isn't part of it.
+type batchMarkingWriter struct {
+	kvevent.Writer
+	codec keys.SQLCodec
+
+	// open is set while a batch is in progress. key is the key of the first
+	// KV event of that batch.
+	open    bool
+	tableID uint32
+	ts      hlc.Timestamp
+	key     roachpb.Key
+}
+
+// Add implements the kvevent.Writer interface.
+func (w *batchMarkingWriter) Add(ctx context.Context, e kvevent.Event) error {
+	if e.Type() != kvevent.TypeKV || e.BatchMarker() != kvevent.NotBatchMarker ||
+		e.SchemaChange() != nil {
+		if err := w.endBatch(ctx); err != nil {
+			return err
+		}
+		return w.Writer.Add(ctx, e)
+	}
+
+	kv := e.KV()
+	_, tableID, err := w.codec.DecodeTablePrefix(kv.Key)
+	if err != nil {
+		return err
+	}
+	if !w.open || tableID != w.tableID || kv.Value.Timestamp != w.ts {
+		if err := w.endBatch(ctx); err != nil {
+			return err
+		}
+		w.open, w.tableID, w.ts, w.key = true, tableID, kv.Value.Timestamp, kv.Key
+		if err := w.Writer.Add(ctx, kvevent.NewBatchMarkerEvent(w.key, w.ts, kvevent.BatchBegin)); err != nil {
+			return err
+		}
+	}
+	return w.Writer.Add(ctx, e)
+}
+
+// endBatch writes the end marker of the batch in progress, if any.
+func (w *batchMarkingWriter) endBatch(ctx context.Context) error {
+	if !w.open {
+		return nil
+	}
+	w.open = false
+	return w.Writer.Add(ctx, kvevent.NewBatchMarkerEvent(w.key, w.ts, kvevent.BatchEnd))
+}
+
+// resolvedWithholdingWriter is a kvevent.Writer which drops resolved events
+// while withhold returns true. It keeps the changefeed frontier from
+// advancing while a deferred initial scan or a periodic snapshot is pending.
+type resolvedWithholdingWriter struct {
+	kvevent.Writer
+	withhold func() bool
+}
+
+// Add implements the kvevent.Writer interface.
+func (w *resolvedWithholdingWriter) Add(ctx context.Context, e kvevent.Event) error {
+	if e.Type() == kvevent.TypeResolved && w.withhold() {
+		a := e.DetachAlloc()
+		a.Release(ctx)
+		return nil
+	}
+	return w.Writer.Add(ctx, e)
+}
+
 func (f *kvFeed) runUntilTableEvent(ctx context.Context, resumeFrontier span.Frontier) (err error) {
 	startFrom := resumeFrontier.Frontier()
 
@@ -568,16 +948,34 @@ func (f *kvFeed) runUntilTableEvent(ctx context.Context, resumeFrontier span.Fro
 		Knobs:         f.knobs,
 		RangeObserver: f.rangeObserver,
 	}
+	if f.db != nil {
+		physicalCfg.FetchPrevValue = f.fetchPrevValue
+	}
 
 	// The following two synchronous calls works as follows:
 	// - `f.physicalFeed.Run` establish a rangefeed on the watched spans at the
 	// high watermark ts (i.e. frontier.smallestTS), which we know we have scanned,
 	// and it will detect and send any changed data (from DML operations) to `membuf`.
+	// For ddl_only changefeeds, it only resolves the spans (see schemaFeedResolver).
 	// - `copyFromSourceToDestUntilTableEvent` consumes `membuf` into `f.writer`
 	// until a table event (i.e. a column is added/dropped) has occurred, which
 	// signals another possible scan.
+	dest := f.writer
+	if f.scanPending.Load() || f.snapshotInterval > 0 {
+		dest = &resolvedWithholdingWriter{Writer: f.writer, withhold: func() bool {
+			return f.scanPending.Load() || f.snapshotPending.Load()
+		}}
+	}
+	var batchWriter *batchMarkingWriter
+	if f.emitBatchMarkers {
+		batchWriter = &batchMarkingWriter{Writer: dest, codec: f.codec}
+		dest = batchWriter
+	}
+	if f.snapshotInterval > 0 {
+		g.GoCtx(f.runPeriodicSnapshots)
+	}
 	g.GoCtx(func(ctx context.Context) error {
-		return copyFromSourceToDestUntilTableEvent(ctx, f.writer, memBuf, resumeFrontier, f.tableFeed, f.endTime, f.knobs)
+		return copyFromSourceToDestUntilTableEvent(ctx, dest, memBuf, resumeFrontier, f.tableFeed, f.endTime, f.knobs)
 	})
 	g.GoCtx(func(ctx context.Context) error {
 		return f.physicalFeed.Run(ctx, memBuf, physicalCfg)
@@ -594,6 +992,9 @@ func (f *kvFeed) runUntilTableEvent(ctx context.Context, resumeFrontier span.Fro
 		// TODO(ajwerner): iterate the spans and add a Resolved timestamp.
 		// We'll need to do this to ensure that a resolved timestamp propagates
 		// when we're trying to exit.
+		if batchWriter != nil {
+			return batchWriter.endBatch(ctx)
+		}
 		return nil
 	} else if tErr := (*errEndTimeReached)(nil); errors.As(err, &tErr) {
 		return err
@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -106,13 +107,18 @@ func TestKVFeed(t *testing.T) {
 		endTime            hlc.Timestamp
 		spans              []roachpb.Span
 		checkpoint         []roachpb.Span
+		replaySpans        []roachpb.Span
+		initialScanAt      hlc.Timestamp
 		events             []kvpb.RangeFeedEvent
 
 		descs []catalog.TableDescriptor
 
-		expScans  []hlc.Timestamp
-		expEvents int
-		expErrRE  string
+		expScans []hlc.Timestamp
+		// expScanSpans, if set, are the spans expected to be scanned instead
+		// of the spans which aren't checkpointed.
+		expScanSpans []roachpb.Span
+		expEvents    int
+		expErrRE     string
 	}
 	st := cluster.MakeTestingClusterSettings()
 	runTest := func(t *testing.T, tc testCase) {
@@ -150,6 +156,9 @@ func TestKVFeed(t *testing.T) {
 			tf, sf, rangefeedFactory(ref.run), bufferFactory,
 			changefeedbase.Targets{},
 			TestingKnobs{})
+		f.replaySpans = tc.replaySpans
+		f.initialScanAt = tc.initialScanAt
+		f.clock = hlc.NewClockForTesting(nil)
 		ctx, cancel := context.WithCancel(context.Background())
 		g := ctxgroup.WithContext(ctx)
 		g.GoCtx(func(ctx context.Context) error {
@@ -159,6 +168,9 @@ func TestKVFeed(t *testing.T) {
 		// Assert that each scanConfig pushed to the channel `scans` by `f.run()`
 		// is what we expected (as specified in the test case).
 		spansToScan := filterCheckpointSpans(tc.spans, tc.checkpoint)
+		if tc.expScanSpans != nil {
+			spansToScan = tc.expScanSpans
+		}
 		testG := ctxgroup.WithContext(ctx)
 		testG.GoCtx(func(ctx context.Context) error {
 			for expScans := tc.expScans; len(expScans) > 0; expScans = expScans[1:] {
@@ -270,6 +282,29 @@ func TestKVFeed(t *testing.T) {
 			},
 			expEvents: 2,
 		},
+		{
+			name:               "no initial scan - replay",
+			schemaChangeEvents: changefeedbase.OptSchemaChangeEventClassDefault,
+			schemaChangePolicy: changefeedbase.OptSchemaChangePolicyNoBackfill,
+			initialHighWater:   ts(2),
+			spans: []roachpb.Span{
+				tableSpan(codec, 42),
+			},
+			replaySpans: []roachpb.Span{
+				makeSpan(codec, 42, "a", "q"),
+				tableSpan(codec, 43),
+			},
+			events: []kvpb.RangeFeedEvent{
+				kvEvent(codec, 42, "a", "b", ts(3)),
+			},
+			expScans: []hlc.Timestamp{
+				ts(2),
+			},
+			expScanSpans: []roachpb.Span{
+				makeSpan(codec, 42, "a", "q"),
+			},
+			expEvents: 1,
+		},
 		{
 			name:               "one table event - backfill",
 			schemaChangeEvents: changefeedbase.OptSchemaChangeEventClassDefault,
@@ -296,6 +331,34 @@ func TestKVFeed(t *testing.T) {
 			},
 			expEvents: 5,
 		},
+		{
+			// The deferred initial scan never comes due, and doesn't hold up the
+			// backfill for the table event. No resolved events are emitted while
+			// it is pending.
+			name:               "one table event - deferred initial scan",
+			schemaChangeEvents: changefeedbase.OptSchemaChangeEventClassDefault,
+			schemaChangePolicy: changefeedbase.OptSchemaChangePolicyBackfill,
+			needsInitialScan:   true,
+			initialHighWater:   ts(2),
+			initialScanAt:      hlc.MaxTimestamp,
+			spans: []roachpb.Span{
+				tableSpan(codec, 42),
+			},
+			events: []kvpb.RangeFeedEvent{
+				kvEvent(codec, 42, "a", "b", ts(3)),
+				checkpointEvent(tableSpan(codec, 42), ts(4)),
+				kvEvent(codec, 42, "a", "b", ts(5)),
+				checkpointEvent(tableSpan(codec, 42), ts(5)),
+			},
+			expScans: []hlc.Timestamp{
+				ts(3),
+			},
+			descs: []catalog.TableDescriptor{
+				makeTableDesc(42, 1, ts(1), 2, 1),
+				addColumnDropBackfillMutation(makeTableDesc(42, 2, ts(3), 1, 1)),
+			},
+			expEvents: 2,
+		},
 		{
 			name:               "one table event - skip",
 			schemaChangeEvents: changefeedbase.OptSchemaChangeEventClassDefault,
@@ -645,3 +708,62 @@ func TestCopyFromSourceToDestUntilTableEvent(t *testing.T) {
 		})
 	}
 }
+
+// errWriterFull is returned by cappedKVEventWriter once it is full.
+var errWriterFull = errors.New("writer full")
+
+// cappedKVEventWriter is a testKVEventWriter which refuses events once it
+// holds limit of them.
+type cappedKVEventWriter struct {
+	testKVEventWriter
+	limit int
+}
+
+func (w *cappedKVEventWriter) Add(ctx context.Context, event kvevent.Event) error {
+	if len(w.events) == w.limit {
+		return errWriterFull
+	}
+	return w.testKVEventWriter.Add(ctx, event)
+}
+
+func TestSchemaFeedResolver(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts := func(ts int) hlc.Timestamp { return hlc.Timestamp{WallTime: int64(ts)} }
+	spans := []roachpb.Span{
+		{Key: []byte("a"), EndKey: []byte("b")},
+		{Key: []byte("b"), EndKey: []byte("c")},
+	}
+	var stps []kvcoord.SpanTimePair
+	for _, sp := range spans {
+		stps = append(stps, kvcoord.SpanTimePair{Span: sp, StartAfter: ts(1)})
+	}
+	schemaFeed := &testSchemaFeed{tableEvents: []schemafeed.TableEvent{
+		{After: &testTableDesc{modTime: ts(8)}},
+	}}
+	clock := hlc.NewClockForTesting(timeutil.NewManualTime(timeutil.Unix(0, 10)))
+
+	// The resolver emits no KVs, only resolved events for every span at the
+	// current time.
+	resolved := &cappedKVEventWriter{limit: len(spans)}
+	r := &schemaFeedResolver{schemaFeed: schemaFeed, clock: clock}
+	err := r.Run(ctx, resolved, rangeFeedConfig{Spans: stps})
+	require.ErrorIs(t, err, errWriterFull)
+	for i, e := range resolved.events {
+		require.Equal(t, kvevent.TypeResolved, e.Type())
+		require.Equal(t, spans[i], e.Resolved().Span)
+		require.Equal(t, ts(10), e.Resolved().Timestamp)
+	}
+
+	// Those resolved events carry the frontier up to the table event.
+	frontier, err := span.MakeFrontierAt(ts(1), spans...)
+	require.NoError(t, err)
+	dest := &testKVEventWriter{}
+	src := &testKVEventReader{events: resolved.events}
+	err = copyFromSourceToDestUntilTableEvent(ctx, dest, src, frontier, schemaFeed, hlc.Timestamp{}, TestingKnobs{})
+	require.Equal(t, &errTableEventReached{schemaFeed.tableEvents[0]}, err)
+	require.Empty(t, dest.events)
+	require.Equal(t, ts(8).Prev(), frontier.Frontier())
+}
@@ -10,13 +10,17 @@ package kvfeed
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/schemafeed"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -35,6 +39,9 @@ type rangeFeedConfig struct {
 	WithFiltering bool
 	RangeObserver kvcoord.RangeObserver
 	Knobs         TestingKnobs
+	// FetchPrevValue, if set, is used to populate the previous value of
+	// deletes which the rangefeed did not provide one for.
+	FetchPrevValue func(ctx context.Context, key roachpb.Key, ts hlc.Timestamp) (roachpb.Value, error)
 }
 
 // rangefeedFactory is a function that creates and runs a rangefeed.
@@ -105,6 +112,48 @@ func (p rangefeedFactory) Run(ctx context.Context, sink kvevent.Writer, cfg rang
 	return g.Wait()
 }
 
+// schemaFeedResolver is a physicalFeedFactory for ddl_only changefeeds, which
+// need no row data. Rather than establishing a rangefeed, it periodically
+// resolves all of the spans at the current time once the schema feed has
+// caught up to it, so that table events are discovered and the frontier
+// advances without reading any KVs.
+type schemaFeedResolver struct {
+	schemaFeed schemafeed.SchemaFeed
+	clock      *hlc.Clock
+	interval   time.Duration
+}
+
+var _ physicalFeedFactory = (*schemaFeedResolver)(nil)
+
+// Run implements the physicalFeedFactory interface.
+func (r *schemaFeedResolver) Run(ctx context.Context, sink kvevent.Writer, cfg rangeFeedConfig) error {
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for {
+		// Peek blocks until the schema feed has caught up to now, so every
+		// table event at or before the resolved timestamp is already known.
+		now := r.clock.Now()
+		if _, err := r.schemaFeed.Peek(ctx, now); err != nil {
+			return err
+		}
+		for _, sp := range cfg.Spans {
+			if err := sink.Add(
+				ctx, kvevent.NewBackfillResolvedEvent(sp.Span, now, jobspb.ResolvedSpan_NONE),
+			); err != nil {
+				return err
+			}
+		}
+
+		timer.Reset(r.interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			timer.Read = true
+		}
+	}
+}
+
 // addEventsToBuffer consumes rangefeed events from `p.eventCh`, transforms
 // them to kvevent.Event's, and pushes them into `p.memBuf`.
 func (p *rangefeed) addEventsToBuffer(ctx context.Context) error {
@@ -118,6 +167,13 @@ func (p *rangefeed) addEventsToBuffer(ctx context.Context) error {
 						return err
 					}
 				}
+				if p.cfg.FetchPrevValue != nil && !t.Value.IsPresent() && !t.PrevValue.IsPresent() {
+					prev, err := p.cfg.FetchPrevValue(ctx, t.Key, t.Value.Timestamp)
+					if err != nil {
+						return err
+					}
+					t.PrevValue = prev
+				}
 				if err := p.memBuf.Add(
 					ctx, kvevent.MakeKVEvent(e.RangeFeedEvent),
 				); err != nil {
@@ -42,6 +42,22 @@ type scanConfig struct {
 	WithDiff  bool
 	Knobs     TestingKnobs
 	Boundary  jobspb.ResolvedSpan_BoundaryType
+	// Parallelism, if positive, is the requested number of concurrent scan
+	// requests. It overrides the changefeed.backfill.concurrent_scan_requests
+	// setting, but is bounded by the
+	// changefeed.backfill.max_initial_scan_parallelism setting.
+	Parallelism int
+	// Snapshot, if set, marks the scanned rows as part of a periodic snapshot.
+	// The rangefeed already covers the scanned spans, so no resolved events
+	// are emitted for them.
+	Snapshot bool
+	// InitialScan, if set, marks the scanned rows as part of the changefeed's
+	// initial scan.
+	InitialScan bool
+	// FollowerReads, if set, routes scan requests to the nearest replica
+	// rather than to the leaseholder, so that they can be served as follower
+	// reads.
+	FollowerReads bool
 }
 
 type kvScanner interface {
@@ -81,7 +97,7 @@ func (p *scanRequestScanner) Scan(ctx context.Context, sink kvevent.Writer, cfg
 		defer backfillClear()
 	}
 
-	maxConcurrentScans := maxConcurrentScanRequests(numNodesHint, &p.settings.SV)
+	maxConcurrentScans := maxConcurrentScanRequests(numNodesHint, cfg.Parallelism, &p.settings.SV)
 	exportLim := limit.MakeConcurrentRequestLimiter("changefeedScanRequestLimiter", maxConcurrentScans)
 
 	lastScanLimitUserSetting := changefeedbase.ScanRequestLimit.Get(&p.settings.SV)
@@ -95,7 +111,7 @@ func (p *scanRequestScanner) Scan(ctx context.Context, sink kvevent.Writer, cfg
 		// If the user defined scan request limit has changed, recalculate it
 		if currentUserScanLimit := changefeedbase.ScanRequestLimit.Get(&p.settings.SV); currentUserScanLimit != lastScanLimitUserSetting {
 			lastScanLimitUserSetting = currentUserScanLimit
-			exportLim.SetLimit(maxConcurrentScanRequests(numNodesHint, &p.settings.SV))
+			exportLim.SetLimit(maxConcurrentScanRequests(numNodesHint, cfg.Parallelism, &p.settings.SV))
 		}
 
 		limAlloc, err := exportLim.Begin(ctx)
@@ -112,7 +128,8 @@ func (p *scanRequestScanner) Scan(ctx context.Context, sink kvevent.Writer, cfg
 			}
 			defer spanAlloc.Release(ctx)
 
-			err = p.exportSpan(ctx, span, cfg.Timestamp, cfg.Boundary, cfg.WithDiff, sink, cfg.Knobs)
+			err = p.exportSpan(ctx, span, cfg.Timestamp, cfg.Boundary,
+				cfg.WithDiff, cfg.Snapshot, cfg.InitialScan, cfg.FollowerReads, sink, cfg.Knobs)
 			finished := atomic.AddInt64(&atomicFinished, 1)
 			if backfillDec != nil {
 				backfillDec()
@@ -172,7 +189,7 @@ func (p *scanRequestScanner) exportSpan(
 	span roachpb.Span,
 	ts hlc.Timestamp,
 	boundaryType jobspb.ResolvedSpan_BoundaryType,
-	withDiff bool,
+	withDiff, snapshot, initialScan, followerReads bool,
 	sink kvevent.Writer,
 	knobs TestingKnobs,
 ) error {
@@ -193,6 +210,11 @@ func (p *scanRequestScanner) exportSpan(
 		r.ScanFormat = kvpb.BATCH_RESPONSE
 		b.Header.TargetBytes = targetBytesPerScan
 		b.Header.ConnectionClass = rpc.RangefeedClass
+		if followerReads {
+			// Replicas which can't serve the scan as a follower read redirect
+			// it to the leaseholder.
+			b.Header.RoutingPolicy = kvpb.RoutingPolicy_NEAREST
+		}
 		b.AdmissionHeader = kvpb.AdmissionHeader{
 			// TODO(irfansharif): Make this configurable if we want system table
 			// scanners or support "high priority" changefeeds to run at higher
@@ -221,13 +243,13 @@ func (p *scanRequestScanner) exportSpan(
 		}
 		afterScan := timeutil.Now()
 		res := b.RawResponse().Responses[0].GetScan()
-		if err := slurpScanResponse(ctx, sink, res, ts, withDiff, *remaining); err != nil {
+		if err := slurpScanResponse(ctx, sink, res, ts, withDiff, snapshot, initialScan, *remaining); err != nil {
 			return err
 		}
 		afterBuffer := timeutil.Now()
 		scanDuration += afterScan.Sub(start)
 		bufferDuration += afterBuffer.Sub(afterScan)
-		if res.ResumeSpan != nil {
+		if res.ResumeSpan != nil && !snapshot {
 			consumed := roachpb.Span{Key: remaining.Key, EndKey: res.ResumeSpan.Key}
 			if err := sink.Add(
 				ctx, kvevent.NewBackfillResolvedEvent(consumed, ts, boundaryType),
@@ -238,10 +260,12 @@ func (p *scanRequestScanner) exportSpan(
 		remaining = res.ResumeSpan
 	}
 	// p.metrics.PollRequestNanosHist.RecordValue(scanDuration.Nanoseconds())
-	if err := sink.Add(
-		ctx, kvevent.NewBackfillResolvedEvent(span, ts, boundaryType),
-	); err != nil {
-		return err
+	if !snapshot {
+		if err := sink.Add(
+			ctx, kvevent.NewBackfillResolvedEvent(span, ts, boundaryType),
+		); err != nil {
+			return err
+		}
 	}
 	if log.V(2) {
 		log.Infof(ctx, `finished Scan of %s at %s took %s`,
@@ -302,7 +326,7 @@ func slurpScanResponse(
 	sink kvevent.Writer,
 	res *kvpb.ScanResponse,
 	backfillTS hlc.Timestamp,
-	withDiff bool,
+	withDiff, snapshot, initialScan bool,
 	span roachpb.Span,
 ) error {
 	var keyBytes, valBytes []byte
@@ -317,7 +341,13 @@ func slurpScanResponse(
 			if log.V(3) {
 				log.Infof(ctx, "scanResponse: %s@%s", keys.PrettyPrint(nil, keyBytes), ts)
 			}
-			if err = sink.Add(ctx, kvevent.NewBackfillKVEvent(keyBytes, ts, valBytes, withDiff, backfillTS)); err != nil {
+			ev := kvevent.NewBackfillKVEvent(keyBytes, ts, valBytes, withDiff, backfillTS)
+			if snapshot {
+				ev = kvevent.NewSnapshotKVEvent(keyBytes, ts, valBytes, backfillTS)
+			} else if initialScan {
+				ev = kvevent.NewInitialScanKVEvent(keyBytes, ts, valBytes, backfillTS)
+			}
+			if err = sink.Add(ctx, ev); err != nil {
 				return errors.Wrapf(err, `buffering changes for %s`, span)
 			}
 		}
@@ -326,7 +356,15 @@ func slurpScanResponse(
 }
 
 // maxConcurrentScanRequests returns the number of concurrent scan requests.
-func maxConcurrentScanRequests(numNodesHint int, sv *settings.Values) int {
+// If parallelism is positive, it is used, capped at
+// MaxInitialScanParallelism.
+func maxConcurrentScanRequests(numNodesHint int, parallelism int, sv *settings.Values) int {
+	if parallelism > 0 {
+		if ceiling := int(changefeedbase.MaxInitialScanParallelism.Get(sv)); parallelism > ceiling {
+			return ceiling
+		}
+		return parallelism
+	}
 	// If the user specified ScanRequestLimit -- use that value.
 	if max := changefeedbase.ScanRequestLimit.Get(sv); max > 0 {
 		return int(max)
@@ -10,7 +10,9 @@ package kvfeed
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
@@ -103,3 +105,64 @@ INSERT INTO t VALUES (1), (2), (3);
 	require.Equal(t, span, sink.resolved[2].Span)
 	require.Equal(t, exportTime, sink.resolved[2].Timestamp)
 }
+
+func TestScanParallelism(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, db, kvdb := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `
+CREATE TABLE t (a INT PRIMARY KEY);
+INSERT INTO t SELECT * FROM generate_series(1, 20);
+ALTER TABLE t SPLIT AT SELECT * FROM generate_series(2, 20);
+`)
+
+	codec := s.Codec()
+	descr := desctestutils.TestingGetPublicTableDescriptor(kvdb, codec, "defaultdb", "t")
+	span := tableSpan(codec, uint32(descr.GetID()))
+
+	// maxInFlight returns the maximum number of scan requests observed in
+	// flight at once when scanning with the given parallelism.
+	maxInFlight := func(parallelism int) int64 {
+		var inFlight, maxSeen int64
+		cfg := scanConfig{
+			Spans:       []roachpb.Span{span},
+			Timestamp:   kvdb.Clock().Now(),
+			Parallelism: parallelism,
+			Knobs: TestingKnobs{
+				BeforeScanRequest: func(b *kv.Batch) error {
+					n := atomic.AddInt64(&inFlight, 1)
+					defer atomic.AddInt64(&inFlight, -1)
+					for {
+						cur := atomic.LoadInt64(&maxSeen)
+						if n <= cur || atomic.CompareAndSwapInt64(&maxSeen, cur, n) {
+							break
+						}
+					}
+					// Hold the request long enough for others to be issued
+					// concurrently.
+					time.Sleep(20 * time.Millisecond)
+					return nil
+				},
+			},
+		}
+		scanner := &scanRequestScanner{
+			settings: s.ClusterSettings(),
+			db:       kvdb,
+		}
+		require.NoError(t, scanner.Scan(ctx, &recordResolvedWriter{}, cfg))
+		return atomic.LoadInt64(&maxSeen)
+	}
+
+	require.Equal(t, int64(1), maxInFlight(1))
+	require.Greater(t, maxInFlight(8), int64(1))
+
+	// The requested parallelism is bounded by the cluster setting.
+	sqlDB.Exec(t, `SET CLUSTER SETTING changefeed.backfill.max_initial_scan_parallelism = 1`)
+	require.Equal(t, int64(1), maxInFlight(8))
+}
@@ -56,6 +56,8 @@ type AggMetrics struct {
 	EmittedMessages             *aggmetric.AggCounter
 	EmittedBatchSizes           *aggmetric.AggHistogram
 	FilteredMessages            *aggmetric.AggCounter
+	FilterErrorMessages         *aggmetric.AggCounter
+	DroppedMessages             *aggmetric.AggCounter
 	MessageSize                 *aggmetric.AggHistogram
 	EmittedBytes                *aggmetric.AggCounter
 	FlushedBytes                *aggmetric.AggCounter
@@ -71,6 +73,8 @@ type AggMetrics struct {
 	CommitLatency               *aggmetric.AggHistogram
 	BackfillCount               *aggmetric.AggGauge
 	BackfillPendingRanges       *aggmetric.AggGauge
+	SchemaChangeBackfills       *aggmetric.AggCounter
+	SchemaChangeBackfillRunning *aggmetric.AggGauge
 	ErrorRetries                *aggmetric.AggCounter
 	AdmitLatency                *aggmetric.AggHistogram
 	RunningCount                *aggmetric.AggGauge
@@ -83,6 +87,8 @@ type AggMetrics struct {
 	LaggingRanges               *aggmetric.AggGauge
 	TotalRanges                 *aggmetric.AggGauge
 	CloudstorageBufferedBytes   *aggmetric.AggGauge
+	UnackedBytes                *aggmetric.AggGauge
+	UnackedMessages             *aggmetric.AggGauge
 	KafkaThrottlingNanos        *aggmetric.AggHistogram
 
 	// There is always at least 1 sliMetrics created for defaultSLI scope.
@@ -134,6 +140,8 @@ type sliMetrics struct {
 	EmittedResolvedMessages     *aggmetric.Counter
 	EmittedBatchSizes           *aggmetric.Histogram
 	FilteredMessages            *aggmetric.Counter
+	FilterErrorMessages         *aggmetric.Counter
+	DroppedMessages             *aggmetric.Counter
 	MessageSize                 *aggmetric.Histogram
 	EmittedBytes                *aggmetric.Counter
 	FlushedBytes                *aggmetric.Counter
@@ -151,6 +159,8 @@ type sliMetrics struct {
 	AdmitLatency                *aggmetric.Histogram
 	BackfillCount               *aggmetric.Gauge
 	BackfillPendingRanges       *aggmetric.Gauge
+	SchemaChangeBackfills       *aggmetric.Counter
+	SchemaChangeBackfillRunning *aggmetric.Gauge
 	RunningCount                *aggmetric.Gauge
 	BatchReductionCount         *aggmetric.Gauge
 	InternalRetryMessageCount   *aggmetric.Gauge
@@ -161,6 +171,8 @@ type sliMetrics struct {
 	LaggingRanges               *aggmetric.Gauge
 	TotalRanges                 *aggmetric.Gauge
 	CloudstorageBufferedBytes   *aggmetric.Gauge
+	UnackedBytes                *aggmetric.Gauge
+	UnackedMessages             *aggmetric.Gauge
 	KafkaThrottlingNanos        *aggmetric.Histogram
 
 	mu struct {
@@ -244,6 +256,28 @@ func (m *sliMetrics) makeCloudstorageFileAllocCallback() func(delta int64) {
 	}
 }
 
+// makeUnackedAllocCallback returns a callback which is to be invoked with the
+// change in the number of bytes and messages buffered by the aggregator that
+// have not yet been acknowledged by the sink.
+func (m *sliMetrics) makeUnackedAllocCallback() func(bytes, messages int64) {
+	return func(bytes, messages int64) {
+		if m != nil {
+			m.UnackedBytes.Inc(bytes)
+			m.UnackedMessages.Inc(messages)
+		}
+	}
+}
+
+// makeDroppedMessagesCallback returns a callback which is to be invoked with
+// the number of buffered messages dropped by the aggregator.
+func (m *sliMetrics) makeDroppedMessagesCallback() func(messages int64) {
+	return func(messages int64) {
+		if m != nil {
+			m.DroppedMessages.Inc(messages)
+		}
+	}
+}
+
 func (m *sliMetrics) recordInternalRetry(numMessages int64, reducedBatchSize bool) {
 	if m == nil {
 		return
@@ -312,6 +346,19 @@ func (m *sliMetrics) getBackfillCallback() func() func() {
 	}
 }
 
+// getSchemaChangeBackfillCallback returns a callback which is to be called at
+// the beginning of a backfill triggered by a schema change, and which returns
+// a callback to be called once that backfill is done.
+func (m *sliMetrics) getSchemaChangeBackfillCallback() func() func() {
+	return func() func() {
+		m.SchemaChangeBackfills.Inc(1)
+		m.SchemaChangeBackfillRunning.Inc(1)
+		return func() {
+			m.SchemaChangeBackfillRunning.Dec(1)
+		}
+	}
+}
+
 // getBackfillRangeCallback returns a backfillRangeCallback that is to be called
 // at the beginning of a backfill with the number of ranges that will be scanned
 // and returns a two callbacks to decrement the value until all ranges have
@@ -792,6 +839,20 @@ func newAggregateMetrics(histogramWindow time.Duration, lookup *cidr.Lookup) *Ag
 		Measurement: "Messages",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaChangefeedFilterErrorMessages := metric.Metadata{
+		Name: "changefeed.filter_error_messages",
+		Help: "Messages skipped by feeds with on_filter_error='skip' because their " +
+			"filter or projection failed to evaluate",
+		Measurement: "Messages",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaChangefeedDroppedMessages := metric.Metadata{
+		Name: "changefeed.dropped_messages",
+		Help: "Messages dropped by feeds with at_most_once delivery because they " +
+			"were superseded by a later message for the same key while the buffer was full",
+		Measurement: "Messages",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaChangefeedEmittedBytes := metric.Metadata{
 		Name:        "changefeed.emitted_bytes",
 		Help:        "Bytes emitted by all feeds",
@@ -853,6 +914,18 @@ func newAggregateMetrics(histogramWindow time.Duration, lookup *cidr.Lookup) *Ag
 		Measurement: "Count",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaChangefeedSchemaChangeBackfills := metric.Metadata{
+		Name:        "changefeed.schema_change_backfills",
+		Help:        "Total backfills triggered by schema changes to watched tables",
+		Measurement: "Backfills",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaChangefeedSchemaChangeBackfillRunning := metric.Metadata{
+		Name:        "changefeed.schema_change_backfill_running",
+		Help:        "Number of changefeeds currently executing a backfill triggered by a schema change",
+		Measurement: "Count",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaChangefeedBackfillPendingRanges := metric.Metadata{
 		Name:        "changefeed.backfill_pending_ranges",
 		Help:        "Number of ranges in an ongoing backfill that are yet to be fully emitted",
@@ -957,6 +1030,18 @@ func newAggregateMetrics(histogramWindow time.Duration, lookup *cidr.Lookup) *Ag
 		Measurement: "Bytes",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaUnackedBytes := metric.Metadata{
+		Name:        "changefeed.unacked_bytes",
+		Help:        "The number of bytes buffered by changefeed aggregators which have not been acknowledged by the sink yet",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaUnackedMessages := metric.Metadata{
+		Name:        "changefeed.unacked_messages",
+		Help:        "The number of messages buffered by changefeed aggregators which have not been acknowledged by the sink yet",
+		Measurement: "Messages",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaChangefeedKafkaThrottlingNanos := metric.Metadata{
 		Name:        "changefeed.kafka_throttling_hist_nanos",
 		Help:        "Time spent in throttling due to exceeding kafka quota",
@@ -988,7 +1073,9 @@ func newAggregateMetrics(histogramWindow time.Duration, lookup *cidr.Lookup) *Ag
 			SigFigs:      1,
 			BucketConfig: metric.DataCount16MBuckets,
 		}),
-		FilteredMessages: b.Counter(metaChangefeedFilteredMessages),
+		FilteredMessages:    b.Counter(metaChangefeedFilteredMessages),
+		FilterErrorMessages: b.Counter(metaChangefeedFilterErrorMessages),
+		DroppedMessages:     b.Counter(metaChangefeedDroppedMessages),
 		MessageSize: b.Histogram(metric.HistogramOptions{
 			Metadata:     metaMessageSize,
 			Duration:     histogramWindow,
@@ -1057,6 +1144,8 @@ func newAggregateMetrics(histogramWindow time.Duration, lookup *cidr.Lookup) *Ag
 		LaggingRanges:             b.Gauge(metaLaggingRanges),
 		TotalRanges:               b.Gauge(metaTotalRanges),
 		CloudstorageBufferedBytes: b.Gauge(metaCloudstorageBufferedBytes),
+		UnackedBytes:              b.Gauge(metaUnackedBytes),
+		UnackedMessages:           b.Gauge(metaUnackedMessages),
 		KafkaThrottlingNanos: b.Histogram(metric.HistogramOptions{
 			Metadata:     metaChangefeedKafkaThrottlingNanos,
 			Duration:     histogramWindow,
@@ -1064,7 +1153,9 @@ func newAggregateMetrics(histogramWindow time.Duration, lookup *cidr.Lookup) *Ag
 			SigFigs:      2,
 			BucketConfig: metric.BatchProcessLatencyBuckets,
 		}),
-		NetMetrics: lookup.MakeNetMetrics(metaNetworkBytesOut, metaNetworkBytesIn, "sink"),
+		SchemaChangeBackfills:       b.Counter(metaChangefeedSchemaChangeBackfills),
+		SchemaChangeBackfillRunning: b.Gauge(metaChangefeedSchemaChangeBackfillRunning),
+		NetMetrics:                  lookup.MakeNetMetrics(metaNetworkBytesOut, metaNetworkBytesIn, "sink"),
 	}
 	a.mu.sliMetrics = make(map[string]*sliMetrics)
 	_, err := a.getOrCreateScope(defaultSLIScope)
@@ -1107,6 +1198,8 @@ func (a *AggMetrics) getOrCreateScope(scope string) (*sliMetrics, error) {
 		EmittedResolvedMessages:     a.EmittedMessages.AddChild(scope, "resolved"),
 		EmittedBatchSizes:           a.EmittedBatchSizes.AddChild(scope),
 		FilteredMessages:            a.FilteredMessages.AddChild(scope),
+		FilterErrorMessages:         a.FilterErrorMessages.AddChild(scope),
+		DroppedMessages:             a.DroppedMessages.AddChild(scope),
 		MessageSize:                 a.MessageSize.AddChild(scope),
 		EmittedBytes:                a.EmittedBytes.AddChild(scope),
 		FlushedBytes:                a.FlushedBytes.AddChild(scope),
@@ -1124,6 +1217,8 @@ func (a *AggMetrics) getOrCreateScope(scope string) (*sliMetrics, error) {
 		AdmitLatency:                a.AdmitLatency.AddChild(scope),
 		BackfillCount:               a.BackfillCount.AddChild(scope),
 		BackfillPendingRanges:       a.BackfillPendingRanges.AddChild(scope),
+		SchemaChangeBackfills:       a.SchemaChangeBackfills.AddChild(scope),
+		SchemaChangeBackfillRunning: a.SchemaChangeBackfillRunning.AddChild(scope),
 		RunningCount:                a.RunningCount.AddChild(scope),
 		BatchReductionCount:         a.BatchReductionCount.AddChild(scope),
 		InternalRetryMessageCount:   a.InternalRetryMessageCount.AddChild(scope),
@@ -1132,6 +1227,8 @@ func (a *AggMetrics) getOrCreateScope(scope string) (*sliMetrics, error) {
 		LaggingRanges:               a.LaggingRanges.AddChild(scope),
 		TotalRanges:                 a.TotalRanges.AddChild(scope),
 		CloudstorageBufferedBytes:   a.CloudstorageBufferedBytes.AddChild(scope),
+		UnackedBytes:                a.UnackedBytes.AddChild(scope),
+		UnackedMessages:             a.UnackedMessages.AddChild(scope),
 		KafkaThrottlingNanos:        a.KafkaThrottlingNanos.AddChild(scope),
 		// TODO(#130358): Again, this doesn't belong here, but it's the most
 		// convenient way to feed this metric to changefeeds.
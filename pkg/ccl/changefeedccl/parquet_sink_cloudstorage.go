@@ -143,6 +143,10 @@ func (parquetSink *parquetCloudStorageSink) EmitResolvedTimestamp(
 		return errors.Wrapf(err, "while emitting resolved timestamp")
 	}
 
+	if parquetSink.wrapped.layout == changefeedbase.OptCloudStorageLayoutIceberg {
+		return parquetSink.wrapped.commitIcebergSnapshots(ctx, resolved)
+	}
+
 	var buf bytes.Buffer
 	sch, err := parquet.NewSchema([]string{metaSentinel + "resolved"}, []*types.T{types.Decimal})
 	if err != nil {
@@ -191,7 +195,20 @@ func (parquetSink *parquetCloudStorageSink) EncodeAndEmitRow(
 	alloc kvevent.Alloc,
 ) error {
 	s := parquetSink.wrapped
-	file, err := s.getOrCreateFile(topic, mvcc)
+	var partition string
+	if s.partitionColumn != "" {
+		// Deleted rows only carry their primary key, so they are partitioned
+		// by their previous value when it is known.
+		partitionRow := updatedRow
+		if updatedRow.IsDeleted() && prevRow.IsInitialized() {
+			partitionRow = prevRow
+		}
+		var err error
+		if partition, err = icebergPartitionDir(partitionRow, s.partitionColumn); err != nil {
+			return err
+		}
+	}
+	file, err := s.getOrCreateFile(topic, partition, mvcc)
 	if err != nil {
 		return err
 	}
@@ -245,7 +262,7 @@ func (parquetSink *parquetCloudStorageSink) EncodeAndEmitRow(
 		if err = file.parquetCodec.close(); err != nil {
 			return err
 		}
-		if err := s.flushTopicVersions(ctx, file.topic, file.schemaID); err != nil {
+		if err := s.flushTopicVersions(ctx, file.topic, file.tableID, file.schemaID); err != nil {
 			return err
 		}
 	}
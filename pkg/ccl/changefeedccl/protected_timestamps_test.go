@@ -405,6 +405,116 @@ func TestChangefeedAlterPTS(t *testing.T) {
 	cdcTest(t, testFn, feedTestEnterpriseSinks)
 }
 
+// TestChangefeedProtectDataFromGCOnPause verifies that the protected timestamp
+// record of a paused changefeed is only retained when the
+// protect_data_from_gc_on_pause option is set.
+func TestChangefeedProtectDataFromGCOnPause(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH protect_data_from_gc_on_pause,
+			resolved='1s', min_checkpoint_frequency='1s'`)
+		defer closeFeed(t, foo)
+
+		jobFeed := foo.(cdctest.EnterpriseTestFeed)
+		getNumPTSRecords := func() int {
+			var n int
+			sqlDB.QueryRow(t, `SELECT count(*) FROM system.protected_ts_records`).Scan(&n)
+			return n
+		}
+		waitForPTSRecord := func() {
+			testutils.SucceedsSoon(t, func() error {
+				if n := getNumPTSRecords(); n != 1 {
+					return errors.Newf("expected 1 protected timestamp record, found %d", n)
+				}
+				return nil
+			})
+		}
+		getPTSRecordID := func() uuid.UUID {
+			progress, err := jobFeed.Progress()
+			require.NoError(t, err)
+			return progress.ProtectedTimestampRecord
+		}
+
+		waitForPTSRecord()
+
+		// With the option set, the record is retained while paused.
+		require.NoError(t, jobFeed.Pause())
+		require.Equal(t, 1, getNumPTSRecords())
+		require.NotEqual(t, uuid.Nil, getPTSRecordID())
+
+		// With the option unset, the record is released while paused.
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d UNSET protect_data_from_gc_on_pause`, jobFeed.JobID()))
+		require.NoError(t, jobFeed.Resume())
+		waitForPTSRecord()
+		require.NoError(t, jobFeed.Pause())
+		require.Equal(t, 0, getNumPTSRecords())
+		require.Equal(t, uuid.Nil, getPTSRecordID())
+
+		// The record is recreated once the changefeed resumes, and retained on
+		// the next pause after the option is set again.
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d SET protect_data_from_gc_on_pause`, jobFeed.JobID()))
+		require.NoError(t, jobFeed.Resume())
+		waitForPTSRecord()
+		require.NoError(t, jobFeed.Pause())
+		require.Equal(t, 1, getNumPTSRecords())
+		require.NotEqual(t, uuid.Nil, getPTSRecordID())
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
+// TestChangefeedPauseOnErrorReleasesPTS verifies that a changefeed paused
+// because of on_error=pause releases its protected timestamp record, just like
+// a user-initiated pause.
+func TestChangefeedPauseOnErrorReleasesPTS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+
+		var shouldFail atomic.Bool
+		knobs := s.TestingKnobs.DistSQL.(*execinfra.TestingKnobs).Changefeed.(*TestingKnobs)
+		knobs.BeforeEmitRow = func(_ context.Context) error {
+			if shouldFail.Load() {
+				return changefeedbase.WithTerminalError(errors.New("should pause"))
+			}
+			return nil
+		}
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH on_error='pause'`)
+		defer closeFeed(t, foo)
+		jobFeed := foo.(cdctest.EnterpriseTestFeed)
+
+		getNumPTSRecords := func() int {
+			var n int
+			sqlDB.QueryRow(t, `SELECT count(*) FROM system.protected_ts_records`).Scan(&n)
+			return n
+		}
+		testutils.SucceedsSoon(t, func() error {
+			if n := getNumPTSRecords(); n != 1 {
+				return errors.Newf("expected 1 protected timestamp record, found %d", n)
+			}
+			return nil
+		})
+
+		shouldFail.Store(true)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+		require.NoError(t, jobFeed.WaitForStatus(func(s jobs.Status) bool { return s == jobs.StatusPaused }))
+		require.Equal(t, 0, getNumPTSRecords())
+		progress, err := jobFeed.Progress()
+		require.NoError(t, err)
+		require.Equal(t, uuid.Nil, progress.ProtectedTimestampRecord)
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
 // TestChangefeedCanceledWhenPTSIsOld is a test for the setting
 // `kv.closed_timestamp.target_duration` which ensures that a paused changefeed
 // job holding a PTS record gets canceled if paused for too long.
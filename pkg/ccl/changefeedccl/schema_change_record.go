@@ -0,0 +1,118 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+// Schema change operations listed in the `changes` field of schema change
+// records.
+const (
+	schemaChangeOpAddColumn       = `add_column`
+	schemaChangeOpDropColumn      = `drop_column`
+	schemaChangeOpAlterColumnType = `alter_column_type`
+)
+
+// encodeSchemaChangeRecord returns the record emitted by changefeeds created
+// with ddl_only for the change of a table's schema from `before` to `after`,
+// or nil if its visible columns are unchanged. The record looks like:
+//
+//	{
+//	  "table": "foo",
+//	  "changes": [{"op": "add_column", "column": "b", "type": "STRING"}],
+//	  "before": [{"name": "a", "type": "INT8"}],
+//	  "after": [{"name": "a", "type": "INT8"}, {"name": "b", "type": "STRING"}],
+//	  "updated": "1700000000000000000.0000000000"
+//	}
+//
+// If fingerprint is set, the record also has a `fingerprint` field holding the
+// schema fingerprint of the `after` columns.
+//
+// Columns are matched by name, so a renamed column appears as a dropped and
+// an added column.
+func encodeSchemaChangeRecord(
+	before, after catalog.TableDescriptor, updated hlc.Timestamp, fingerprint bool,
+) json.JSON {
+	beforeCols, afterCols := before.VisibleColumns(), after.VisibleColumns()
+	byName := func(cols []catalog.Column) map[string]catalog.Column {
+		m := make(map[string]catalog.Column, len(cols))
+		for _, col := range cols {
+			m[col.GetName()] = col
+		}
+		return m
+	}
+	beforeByName, afterByName := byName(beforeCols), byName(afterCols)
+
+	changes := json.NewArrayBuilder(0)
+	numChanges := 0
+	addChange := func(op string, col catalog.Column, prev catalog.Column) {
+		b := json.NewObjectBuilder(4)
+		b.Add("op", json.FromString(op))
+		b.Add("column", json.FromString(col.GetName()))
+		b.Add("type", json.FromString(col.GetType().SQLString()))
+		if prev != nil {
+			b.Add("previous_type", json.FromString(prev.GetType().SQLString()))
+		}
+		changes.Add(b.Build())
+		numChanges++
+	}
+	for _, col := range beforeCols {
+		afterCol, ok := afterByName[col.GetName()]
+		if !ok {
+			addChange(schemaChangeOpDropColumn, col, nil)
+		} else if !afterCol.GetType().Identical(col.GetType()) {
+			addChange(schemaChangeOpAlterColumnType, afterCol, col)
+		}
+	}
+	for _, col := range afterCols {
+		if _, ok := beforeByName[col.GetName()]; !ok {
+			addChange(schemaChangeOpAddColumn, col, nil)
+		}
+	}
+	if numChanges == 0 {
+		return nil
+	}
+
+	columns := func(cols []catalog.Column) json.JSON {
+		b := json.NewArrayBuilder(len(cols))
+		for _, col := range cols {
+			cb := json.NewObjectBuilder(2)
+			cb.Add("name", json.FromString(col.GetName()))
+			cb.Add("type", json.FromString(col.GetType().SQLString()))
+			b.Add(cb.Build())
+		}
+		return b.Build()
+	}
+	afterJSON := columns(afterCols)
+	b := json.NewObjectBuilder(6)
+	b.Add("table", json.FromString(after.GetName()))
+	b.Add("changes", changes.Build())
+	b.Add("before", columns(beforeCols))
+	b.Add("after", afterJSON)
+	b.Add("updated", json.FromString(updated.AsOfSystemTime()))
+	if fingerprint {
+		b.Add("fingerprint", json.FromString(schemaFingerprint(afterJSON)))
+	}
+	return b.Build()
+}
+
+// schemaFingerprint returns the fingerprint of a schema given as the ordered
+// list of its columns, encoded as a JSON array of {"name", "type"} objects:
+// the FNV-1a hash of the array's canonical encoding, as 16 hex digits.
+func schemaFingerprint(columns json.JSON) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(columns.String()))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// sequenceFieldName is the field of the wrapped envelope holding the sequence
+// number of a row.
+const sequenceFieldName = `sequence`
+
+// sequenceSink delegates to another sink, adding to every row a sequence
+// number which increases by one with each row emitted to the row's topic,
+// starting at 1. The numbering is only gapless if the changefeed runs a single
+// aggregator, which is how changefeeds with the emit_sequence option are
+// planned.
+//
+// The last sequence number of each topic is reported along with the
+// aggregator's resolved spans, once the rows are flushed, and persisted in the
+// job progress with the next checkpoint. When the changefeed restarts, the
+// numbering resumes from the last checkpoint, not from the last row emitted.
+// The rows emitted after that checkpoint are emitted again, as changefeeds
+// deliver rows at least once, and they reuse the sequence numbers which were
+// given to the rows emitted after the checkpoint before the restart. A
+// re-emitted row doesn't necessarily get the same number it had before. So a
+// sequence number identifies a position in the topic's stream, not a row. A
+// consumer which receives a sequence number at or below one it has already
+// seen is seeing a replay from that position, and should discard what it
+// received from that position on. Across a restart which re-emits no rows,
+// such as a pause after a checkpoint, the numbering continues without gaps or
+// reuse.
+type sequenceSink struct {
+	wrapped    EventSink
+	topicNamer *TopicNamer
+
+	mu struct {
+		syncutil.Mutex
+		sequences map[string]int64
+	}
+}
+
+func newSequenceSink(
+	wrapped EventSink, topicNamer *TopicNamer, sequences map[string]int64,
+) *sequenceSink {
+	s := &sequenceSink{wrapped: wrapped, topicNamer: topicNamer}
+	s.mu.sequences = make(map[string]int64, len(sequences))
+	for topic, seq := range sequences {
+		s.mu.sequences[topic] = seq
+	}
+	return s
+}
+
+func (s *sequenceSink) getConcreteType() sinkType {
+	return s.wrapped.getConcreteType()
+}
+
+// EmitRow implements Sink interface.
+func (s *sequenceSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	// The lock is held while emitting the row so that rows reach the wrapped
+	// sink in the order of their sequence numbers.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name, err := s.topicNamer.Name(topic)
+	if err != nil {
+		return err
+	}
+	seq := s.mu.sequences[name] + 1
+	value, err = appendSequence(value, seq)
+	if err != nil {
+		return err
+	}
+	if err := s.wrapped.EmitRow(ctx, topic, key, value, updated, mvcc, alloc); err != nil {
+		return err
+	}
+	s.mu.sequences[name] = seq
+	return nil
+}
+
+// appendSequence returns the given value, encoded with the wrapped envelope,
+// with the given sequence number added to it.
+func appendSequence(value []byte, seq int64) ([]byte, error) {
+	envelope, err := json.ParseJSON(string(value))
+	if err != nil {
+		return nil, err
+	}
+	it, err := envelope.ObjectIter()
+	if err != nil {
+		return nil, err
+	}
+	if it == nil {
+		return nil, errors.AssertionFailedf(`expected the value to be an object, found %s`, envelope)
+	}
+	b := json.NewObjectBuilder(envelope.Len() + 1)
+	for it.Next() {
+		b.Add(it.Key(), it.Value())
+	}
+	b.Add(sequenceFieldName, json.FromInt64(seq))
+	return []byte(b.Build().String()), nil
+}
+
+// Sequences returns the last sequence number emitted to each topic.
+func (s *sequenceSink) Sequences() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sequences := make(map[string]int64, len(s.mu.sequences))
+	for topic, seq := range s.mu.sequences {
+		sequences[topic] = seq
+	}
+	return sequences
+}
+
+// Flush implements Sink interface.
+func (s *sequenceSink) Flush(ctx context.Context) error {
+	return s.wrapped.Flush(ctx)
+}
+
+// Close implements Sink interface.
+func (s *sequenceSink) Close() error {
+	return s.wrapped.Close()
+}
+
+// Dial implements Sink interface.
+func (s *sequenceSink) Dial() error {
+	return s.wrapped.Dial()
+}
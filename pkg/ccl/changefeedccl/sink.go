@@ -10,10 +10,13 @@ package changefeedccl
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"math"
 	"net/url"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -35,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metamorphic"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -60,6 +64,10 @@ const (
 	sinkTypeCloudstorage
 	sinkTypeSQL
 	sinkTypePulsar
+	sinkTypeSyslog
+	sinkTypeUnix
+	sinkTypeClickHouse
+	sinkTypeAMQP
 )
 
 // externalResource is the interface common to both EventSink and
@@ -252,12 +260,21 @@ func getSink(
 			return makeNullSink(sinkURL{URL: u}, metricsBuilder(nullIsAccounted))
 		case isKafkaSink(u):
 			return validateOptionsAndMakeSink(changefeedbase.KafkaValidOptions, func() (Sink, error) {
+				topicConfig, err := opts.GetKafkaTopicConfig()
+				if err != nil {
+					return nil, err
+				}
 				if KafkaV2Enabled.Get(&serverCfg.Settings.SV) {
 					return makeKafkaSinkV2(ctx, sinkURL{URL: u}, AllTargets(feedCfg), opts.GetKafkaConfigJSON(),
-						numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg), timeutil.DefaultTimeSource{},
-						serverCfg.Settings, metricsBuilder, kafkaSinkV2Knobs{})
+						encodingOpts.Checksum, encodingOpts.DeleteTopicSuffix, topicConfig, numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg),
+						timeutil.DefaultTimeSource{}, serverCfg.Settings, metricsBuilder, kafkaSinkV2Knobs{})
 				} else {
-					return makeKafkaSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), opts.GetKafkaConfigJSON(), serverCfg.Settings, metricsBuilder)
+					if len(topicConfig) > 0 {
+						return nil, errors.Errorf(`%s is only supported by the kafka sink enabled by %s`,
+							changefeedbase.OptKafkaTopicConfig, KafkaV2Enabled.Name())
+					}
+					return makeKafkaSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), opts.GetKafkaConfigJSON(),
+						encodingOpts.Checksum, encodingOpts.DeleteTopicSuffix, serverCfg.Settings, metricsBuilder)
 				}
 			})
 		case isPulsarSink(u):
@@ -309,11 +326,37 @@ func getSink(
 				if serverCfg.NodeID != nil {
 					nodeID = serverCfg.NodeID.SQLInstanceID()
 				}
+				if err := applyFileSizeOption(u, opts); err != nil {
+					return nil, err
+				}
+				if err := applyPartitionFormatOption(u, opts); err != nil {
+					return nil, err
+				}
 				return makeCloudStorageSink(
 					ctx, sinkURL{URL: u}, nodeID, serverCfg.Settings, encodingOpts,
 					timestampOracle, serverCfg.ExternalStorageFromURI, user, metricsBuilder, testingKnobs,
 				)
 			})
+		case isSyslogSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.SyslogValidOptions, func() (Sink, error) {
+				return makeSyslogSink(sinkURL{URL: u}, encodingOpts, AllTargets(feedCfg), metricsBuilder)
+			})
+		case isUnixSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.UnixValidOptions, func() (Sink, error) {
+				return makeUnixSink(sinkURL{URL: u}, AllTargets(feedCfg), metricsBuilder)
+			})
+		case isClickHouseSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.ClickHouseValidOptions, func() (Sink, error) {
+				return makeClickHouseSink(sinkURL{URL: u}, encodingOpts, AllTargets(feedCfg), metricsBuilder)
+			})
+		case isAMQPSink(u):
+			var testingKnobs *TestingKnobs
+			if knobs, ok := serverCfg.TestingKnobs.Changefeed.(*TestingKnobs); ok {
+				testingKnobs = knobs
+			}
+			return validateOptionsAndMakeSink(changefeedbase.AMQPValidOptions, func() (Sink, error) {
+				return makeAMQPSink(sinkURL{URL: u}, encodingOpts, AllTargets(feedCfg), metricsBuilder, testingKnobs)
+			})
 		case u.Scheme == changefeedbase.SinkSchemeExperimentalSQL:
 			return validateOptionsAndMakeSink(changefeedbase.SQLValidOptions, func() (Sink, error) {
 				return makeSQLSink(sinkURL{URL: u}, sqlSinkTableName, AllTargets(feedCfg), metricsBuilder)
@@ -429,6 +472,68 @@ func (u *sinkURL) String() string {
 	return u.URL.String()
 }
 
+// makeSinkTLSConfig consumes the TLS parameters of the sink URL, and returns
+// the TLS configuration of the connection, or nil if TLS is not enabled.
+func makeSinkTLSConfig(u *sinkURL) (*tls.Config, error) {
+	var dialConfig struct {
+		tlsEnabled    bool
+		tlsSkipVerify bool
+		caCert        []byte
+		clientCert    []byte
+		clientKey     []byte
+	}
+	if _, err := u.consumeBool(changefeedbase.SinkParamTLSEnabled, &dialConfig.tlsEnabled); err != nil {
+		return nil, err
+	}
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &dialConfig.tlsSkipVerify); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &dialConfig.caCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientCert, &dialConfig.clientCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientKey, &dialConfig.clientKey); err != nil {
+		return nil, err
+	}
+
+	if !dialConfig.tlsEnabled {
+		if dialConfig.caCert != nil {
+			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamCACert, changefeedbase.SinkParamTLSEnabled)
+		}
+		if dialConfig.clientCert != nil {
+			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamTLSEnabled)
+		}
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: dialConfig.tlsSkipVerify,
+	}
+	if dialConfig.caCert != nil {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(dialConfig.caCert) {
+			return nil, errors.Errorf("failed to parse certificate data:%s", string(dialConfig.caCert))
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	if dialConfig.clientCert != nil && dialConfig.clientKey == nil {
+		return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+	} else if dialConfig.clientKey != nil && dialConfig.clientCert == nil {
+		return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+	}
+	if dialConfig.clientCert != nil && dialConfig.clientKey != nil {
+		cert, err := tls.X509KeyPair(dialConfig.clientCert, dialConfig.clientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, `invalid client certificate data provided`)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
 // errorWrapperSink delegates to another sink and marks all returned errors as
 // retryable. During changefeed setup, we use the sink once without this to
 // verify configuration, but in the steady state, no sink error should be
@@ -502,6 +607,183 @@ func (s errorWrapperSink) Dial() error {
 	return s.wrapped.Dial()
 }
 
+// rateLimitedSink delegates to another sink, blocking each emitted row until
+// the configured rate permits it. Blocking here backpressures the event
+// consumer, and in turn the kvfeed buffer, rather than dropping rows. Resolved
+// timestamps and flushes are not throttled; since rows are always flushed
+// before a resolved timestamp is emitted, resolved timestamps still only
+// advance past rows which have been emitted.
+type rateLimitedSink struct {
+	wrapped externalResource
+	limiter *quotapool.RateLimiter
+}
+
+func newRateLimitedSink(wrapped externalResource, rowsPerSecond int) *rateLimitedSink {
+	return &rateLimitedSink{
+		wrapped: wrapped,
+		limiter: quotapool.NewRateLimiter(
+			"changefeed-max-emit-rate", quotapool.Limit(rowsPerSecond), int64(rowsPerSecond),
+		),
+	}
+}
+
+func (s *rateLimitedSink) getConcreteType() sinkType {
+	return s.wrapped.getConcreteType()
+}
+
+// EmitRow implements Sink interface.
+func (s *rateLimitedSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	if err := s.limiter.WaitN(ctx, 1); err != nil {
+		return err
+	}
+	return s.wrapped.(EventSink).EmitRow(ctx, topic, key, value, updated, mvcc, alloc)
+}
+
+// EmitResolvedTimestamp implements Sink interface.
+func (s *rateLimitedSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	return s.wrapped.(ResolvedTimestampSink).EmitResolvedTimestamp(ctx, encoder, resolved)
+}
+
+// Flush implements Sink interface.
+func (s *rateLimitedSink) Flush(ctx context.Context) error {
+	return s.wrapped.(EventSink).Flush(ctx)
+}
+
+// Close implements Sink interface.
+func (s *rateLimitedSink) Close() error {
+	return s.wrapped.Close()
+}
+
+// EncodeAndEmitRow implements SinkWithEncoder interface.
+func (s *rateLimitedSink) EncodeAndEmitRow(
+	ctx context.Context,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	encodingOpts changefeedbase.EncodingOptions,
+	alloc kvevent.Alloc,
+) error {
+	sinkWithEncoder, ok := s.wrapped.(SinkWithEncoder)
+	if !ok {
+		return errors.AssertionFailedf("Expected a sink with encoder for, found %T", s.wrapped)
+	}
+	if err := s.limiter.WaitN(ctx, 1); err != nil {
+		return err
+	}
+	return sinkWithEncoder.EncodeAndEmitRow(ctx, updatedRow, prevRow, topic, updated, mvcc, encodingOpts, alloc)
+}
+
+// Dial implements Sink interface.
+func (s *rateLimitedSink) Dial() error {
+	return s.wrapped.Dial()
+}
+
+// sortedFlushSink delegates to another sink, holding on to emitted rows until
+// the sink is flushed. On flush, the rows are emitted to the wrapped sink
+// sorted by their MVCC timestamp. Only the rows of each flush of a single
+// aggregator are sorted:
+//   - rows of different aggregators may interleave out of timestamp order;
+//   - rows are flushed before each resolved timestamp is emitted, but flushes
+//     are also forced in between, e.g. once the aggregator's buffer runs out
+//     of memory, since rows keep their allocations until they are emitted.
+//     Rows of consecutive flushes aren't sorted with respect to each other.
+type sortedFlushSink struct {
+	wrapped externalResource
+
+	mu struct {
+		syncutil.Mutex
+		rows    []sortedFlushRow
+		scratch bufalloc.ByteAllocator
+	}
+}
+
+type sortedFlushRow struct {
+	topic         TopicDescriptor
+	key, value    []byte
+	updated, mvcc hlc.Timestamp
+	alloc         kvevent.Alloc
+}
+
+func newSortedFlushSink(wrapped externalResource) *sortedFlushSink {
+	return &sortedFlushSink{wrapped: wrapped}
+}
+
+func (s *sortedFlushSink) getConcreteType() sinkType {
+	return s.wrapped.getConcreteType()
+}
+
+// EmitRow implements Sink interface.
+func (s *sortedFlushSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The encoder may reuse the key and value buffers.
+	s.mu.scratch, key = s.mu.scratch.Copy(key, 0 /* extraCap */)
+	s.mu.scratch, value = s.mu.scratch.Copy(value, 0 /* extraCap */)
+	s.mu.rows = append(s.mu.rows, sortedFlushRow{
+		topic: topic, key: key, value: value, updated: updated, mvcc: mvcc, alloc: alloc,
+	})
+	return nil
+}
+
+// EmitResolvedTimestamp implements Sink interface.
+func (s *sortedFlushSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	return s.wrapped.(ResolvedTimestampSink).EmitResolvedTimestamp(ctx, encoder, resolved)
+}
+
+// Flush implements Sink interface.
+func (s *sortedFlushSink) Flush(ctx context.Context) error {
+	rows := func() []sortedFlushRow {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		rows := s.mu.rows
+		s.mu.rows = nil
+		s.mu.scratch = nil
+		return rows
+	}()
+	// Sort stably, so that rows with the same timestamp keep their order.
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].mvcc.Less(rows[j].mvcc)
+	})
+	for i, row := range rows {
+		if err := s.wrapped.(EventSink).EmitRow(
+			ctx, row.topic, row.key, row.value, row.updated, row.mvcc, row.alloc,
+		); err != nil {
+			for _, unemitted := range rows[i+1:] {
+				unemitted.alloc.Release(ctx)
+			}
+			return err
+		}
+	}
+	return s.wrapped.(EventSink).Flush(ctx)
+}
+
+// Close implements Sink interface.
+func (s *sortedFlushSink) Close() error {
+	return s.wrapped.Close()
+}
+
+// Dial implements Sink interface.
+func (s *sortedFlushSink) Dial() error {
+	return s.wrapped.Dial()
+}
+
 // encDatumRowBuffer is a FIFO of `EncDatumRow`s.
 //
 // TODO(dan): There's some potential allocation savings here by reusing the same
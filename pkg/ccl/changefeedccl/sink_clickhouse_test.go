@@ -0,0 +1,189 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+type clickhouseTestDrainHelper struct{}
+
+func (clickhouseTestDrainHelper) IsDraining() bool { return false }
+
+func TestClickHouseSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	sinkDest := cdctest.StartMockClickHouseSink()
+	defer sinkDest.Close()
+
+	opts := changefeedbase.EncodingOptions{
+		Format:   changefeedbase.OptFormatJSON,
+		Envelope: changefeedbase.OptEnvelopeWrapped,
+		Diff:     true,
+	}
+	makeSink := func(uri string, opts changefeedbase.EncodingOptions) (Sink, error) {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		return makeClickHouseSink(sinkURL{URL: u}, opts, makeChangefeedTargets(`t`), nilMetricsRecorderBuilder)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := makeSink(`clickhouse:///db`, opts)
+		require.ErrorContains(t, err, `must specify host`)
+		_, err = makeSink(`clickhouse://localhost?foo=bar`, opts)
+		require.ErrorContains(t, err, `unknown clickhouse sink query parameters: foo`)
+		noDiff := opts
+		noDiff.Diff = false
+		_, err = makeSink(`clickhouse://localhost`, noDiff)
+		require.ErrorContains(t, err, `this sink requires the diff option`)
+		bare := opts
+		bare.Envelope = changefeedbase.OptEnvelopeBare
+		_, err = makeSink(`clickhouse://localhost`, bare)
+		require.ErrorContains(t, err, `this sink is incompatible with envelope=bare`)
+	})
+
+	sink, err := makeSink(`clickhouse://user:pass@`+sinkDest.Host()+`/analytics?table_name=events&sign_column=s`, opts)
+	require.NoError(t, err)
+	require.NoError(t, sink.Dial())
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	emit := func(value string) {
+		ts := hlc.Timestamp{WallTime: 1}
+		require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[1]`), []byte(value), ts, ts, zeroAlloc))
+	}
+
+	// Nothing is sent before the sink is flushed.
+	emit(`{"after": {"a": 1, "b": "x"}, "before": null}`)
+	emit(`{"after": {"a": 1, "b": "y"}, "before": {"a": 1, "b": "x"}}`)
+	emit(`{"after": null, "before": {"a": 1, "b": "y"}}`)
+	require.Empty(t, sinkDest.Inserts())
+	require.NoError(t, sink.Flush(ctx))
+
+	// The rows are inserted by a single request, with the state of a row
+	// followed by its cancellation.
+	require.Equal(t, []cdctest.ClickHouseInsert{{
+		Table:    `events`,
+		Database: `analytics`,
+		User:     `user`,
+		Rows: []string{
+			`{"a": 1, "b": "x", "s": 1}`,
+			`{"a": 1, "b": "x", "s": -1}`,
+			`{"a": 1, "b": "y", "s": 1}`,
+			`{"a": 1, "b": "y", "s": -1}`,
+		},
+	}}, sinkDest.Inserts())
+
+	// Resolved timestamps flush the sink.
+	emit(`{"after": {"a": 2, "b": "z"}, "before": null}`)
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, testEncoder{}, hlc.Timestamp{WallTime: 2}))
+	require.Len(t, sinkDest.Inserts(), 2)
+
+	// Server errors are retried, but client errors are terminal.
+	emit(`{"after": {"a": 3, "b": "w"}, "before": null}`)
+	sinkDest.SetStatusCodes([]int{http.StatusServiceUnavailable})
+	err = sink.Flush(ctx)
+	require.ErrorContains(t, err, `503 Service Unavailable`)
+	require.Nil(t, changefeedbase.AsTerminalError(ctx, clickhouseTestDrainHelper{}, err))
+	sinkDest.SetStatusCodes([]int{http.StatusBadRequest})
+	err = sink.Flush(ctx)
+	require.ErrorContains(t, err, `400 Bad Request`)
+	require.Equal(t, err, changefeedbase.AsTerminalError(ctx, clickhouseTestDrainHelper{}, err))
+
+	// The rows are inserted again once the server recovers.
+	sinkDest.SetStatusCodes([]int{http.StatusOK})
+	require.NoError(t, sink.Flush(ctx))
+	inserts := sinkDest.Inserts()
+	require.Equal(t, []string{`{"a": 3, "b": "w", "s": 1}`}, inserts[len(inserts)-1].Rows)
+
+	// The memory of the rows is released once they have been inserted.
+	pool := testAllocPool{}
+	ts := hlc.Timestamp{WallTime: 3}
+	value := []byte(`{"after": {"a": 4, "b": "v"}, "before": null}`)
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[4]`), value, ts, ts, pool.alloc()))
+	require.EqualValues(t, 1, pool.used())
+	sinkDest.SetStatusCodes([]int{http.StatusServiceUnavailable})
+	require.Error(t, sink.Flush(ctx))
+	require.EqualValues(t, 1, pool.used())
+	sinkDest.SetStatusCodes([]int{http.StatusOK})
+	require.NoError(t, sink.Flush(ctx))
+	require.EqualValues(t, 0, pool.used())
+
+	// The rows are inserted without waiting for a flush once they exceed
+	// clickhouseMaxInsertSize.
+	numInserts := len(sinkDest.Inserts())
+	large := []byte(`{"after": {"a": 5, "b": "` + strings.Repeat(`u`, clickhouseMaxInsertSize) + `"}, "before": null}`)
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[5]`), large, ts, ts, pool.alloc()))
+	require.Len(t, sinkDest.Inserts(), numInserts+1)
+	require.EqualValues(t, 0, pool.used())
+}
+
+func TestChangefeedClickHouseSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 0)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH diff, resolved, on_error='pause'`)
+		defer closeFeed(t, foo)
+		mockSink := foo.(*clickhouseFeed).mockSink
+		// The messages of the test feed are only returned once their rows have
+		// been inserted.
+		assertInserted := func(expected ...string) {
+			t.Helper()
+			var actual []string
+			for row, ok := mockSink.Pop(); ok; row, ok = mockSink.Pop() {
+				actual = append(actual, row.Table+`: `+row.Row)
+			}
+			require.Equal(t, expected, actual)
+		}
+
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": 0}, "before": null}`,
+		})
+		assertInserted(`foo: {"a": 1, "b": 0, "sign": 1}`)
+
+		sqlDB.Exec(t, `UPDATE foo SET b = 1 WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": 1}, "before": {"a": 1, "b": 0}}`,
+		})
+		assertInserted(`foo: {"a": 1, "b": 0, "sign": -1}`, `foo: {"a": 1, "b": 1, "sign": 1}`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": null, "before": {"a": 1, "b": 1}}`,
+		})
+		assertInserted(`foo: {"a": 1, "b": 1, "sign": -1}`)
+
+		// Inserts rejected by ClickHouse pause the changefeed.
+		mockSink.SetStatusCodes([]int{http.StatusBadRequest})
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 0)`)
+		require.NoError(t, foo.(cdctest.EnterpriseTestFeed).WaitForStatus(func(s jobs.Status) bool {
+			return s == jobs.StatusPaused
+		}))
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("clickhouse"))
+}
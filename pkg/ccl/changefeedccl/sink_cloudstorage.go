@@ -17,6 +17,7 @@ import (
 	"io"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -135,7 +137,9 @@ func (f *cloudStorageSinkFile) Write(p []byte) (int, error) {
 // sink adds some quality of life guarantees of its own.
 // 3. All rows in a file are from the same table. Further, all rows in a file are
 // from the same schema version of that table, and so all have the same schema.
-// 4. All files are partitioned into folders by the date part of the filename.
+// 4. All files are partitioned into folders by the date part of the filename,
+// unless partition_format names a column, in which case data files are
+// partitioned into folders by the value of that column in their rows.
 //
 // Two methods of the cloudStorageSink on each data emitting processor are
 // called. EmitRow is called with each row change and Flush is called before
@@ -225,6 +229,10 @@ func (f *cloudStorageSinkFile) Write(p []byte) (int, error) {
 // deleted, included in hive queries, etc). A typical user of cloudStorageSink
 // would periodically do exactly this.
 //
+// With cloudstorage_layout=iceberg, files are instead laid out as one Apache
+// Iceberg-style table per topic, and resolved timestamps are written as table
+// snapshots rather than RESOLVED files. See commitIcebergSnapshots.
+//
 // Still TODO is writing out data schemas, Avro support, bounding memory usage.
 //
 // Now what follows is a proof of why the above is correct even in the presence
@@ -313,6 +321,13 @@ type cloudStorageSink struct {
 	partitionFormat   string
 	topicNamer        *TopicNamer
 
+	// layout is how the files written by the sink are laid out. Data files
+	// are partitioned by the value of partitionColumn, if set, either within
+	// the topic's data directory, if layout is OptCloudStorageLayoutIceberg,
+	// or in place of the date-based partition otherwise.
+	layout          changefeedbase.CloudStorageLayout
+	partitionColumn string
+
 	ext          string
 	rowDelimiter []byte
 
@@ -378,6 +393,54 @@ var defaultPartitionFormat = partitionDateFormats["daily"]
 // a queue of 2.5GB of outstanding flush data.
 const flushQueueDepth = 256
 
+// applyFileSizeOption copies the file_size option, if specified in the WITH
+// clause, into the sink URI so that it takes effect the same way as the
+// file_size URI parameter. Specifying conflicting sizes in both places is an
+// error.
+func applyFileSizeOption(u *url.URL, opts changefeedbase.StatementOptions) error {
+	fileSize, ok, err := opts.GetFileSize()
+	if err != nil || !ok {
+		return err
+	}
+	q := u.Query()
+	if param := q.Get(changefeedbase.SinkParamFileSize); param != `` {
+		paramSize, err := humanizeutil.ParseBytes(param)
+		if err != nil {
+			return pgerror.Wrapf(err, pgcode.Syntax, `parsing %s`, param)
+		}
+		if paramSize != fileSize {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				`option %s conflicts with sink parameter %s`,
+				changefeedbase.OptFileSize, changefeedbase.SinkParamFileSize)
+		}
+	}
+	q.Set(changefeedbase.SinkParamFileSize, strconv.FormatInt(fileSize, 10))
+	u.RawQuery = q.Encode()
+	return nil
+}
+
+// applyPartitionFormatOption copies the partition_format option, if specified
+// in the WITH clause, into the sink URI so that it takes effect the same way
+// as the partition_format URI parameter. Unlike the URI parameter, the option
+// can be changed with ALTER CHANGEFEED: files written after the changefeed
+// resumes are partitioned the new way, while files written before remain
+// where they are. Specifying conflicting formats in both places is an error.
+func applyPartitionFormatOption(u *url.URL, opts changefeedbase.StatementOptions) error {
+	format, ok := opts.GetPartitionFormat()
+	if !ok {
+		return nil
+	}
+	q := u.Query()
+	if param := q.Get(changefeedbase.SinkParamPartitionFormat); param != `` && param != format {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			`option %s conflicts with sink parameter %s`,
+			changefeedbase.OptPartitionFormat, changefeedbase.SinkParamPartitionFormat)
+	}
+	q.Set(changefeedbase.SinkParamPartitionFormat, format)
+	u.RawQuery = q.Encode()
+	return nil
+}
+
 func makeCloudStorageSink(
 	ctx context.Context,
 	u sinkURL,
@@ -406,10 +469,20 @@ func makeCloudStorageSink(
 		return nil, err
 	}
 
+	// The rows of the tables mapped to the same topic by topic_override are
+	// written into the same topic, and so, with the iceberg layout, into the
+	// same directory. Files written before the mapping changed remain where
+	// they are, and are still committed by the next iceberg snapshot of their
+	// topic.
+	topicOverrides, err := changefeedbase.ParseTopicOverride(encodingOpts.TopicOverride)
+	if err != nil {
+		return nil, err
+	}
+
 	// Using + rather than . here because some consumers may be relying on there being exactly
 	// one '.' in the filepath, and '+' shares with '-' the useful property of being
 	// lexicographically earlier than '.'.
-	tn, err := MakeTopicNamer(changefeedbase.Targets{}, WithJoinByte('+'))
+	tn, err := MakeTopicNamer(changefeedbase.Targets{}, WithJoinByte('+'), WithOverrides(topicOverrides))
 	if err != nil {
 		return nil, err
 	}
@@ -421,6 +494,8 @@ func makeCloudStorageSink(
 		targetMaxFileSize: targetMaxFileSize,
 		files:             btree.New(8),
 		partitionFormat:   defaultPartitionFormat,
+		layout:            encodingOpts.CloudStorageLayout,
+		partitionColumn:   encodingOpts.CloudStoragePartitionColumn,
 		timestampOracle:   timestampOracle,
 		// TODO(dan,ajwerner): Use the jobs framework's session ID once that's available.
 		jobSessionID:     sessID,
@@ -435,12 +510,34 @@ func makeCloudStorageSink(
 	s.flushGroup.GoCtx(s.asyncFlusher)
 
 	if partitionFormat := u.consumeParam(changefeedbase.SinkParamPartitionFormat); partitionFormat != "" {
-		dateFormat, ok := partitionDateFormats[partitionFormat]
-		if !ok {
-			return nil, errors.Errorf("invalid partition_format of %s", partitionFormat)
-		}
+		if column, ok := changefeedbase.ParsePartitionFormatColumn(partitionFormat); ok {
+			// Data files are placed in a Hive-style directory named after the
+			// value of the column in their rows, which only the parquet sink,
+			// encoding rows itself, can tell.
+			if column == "" {
+				return nil, errors.Errorf("invalid partition_format of %s", partitionFormat)
+			}
+			if encodingOpts.Format != changefeedbase.OptFormatParquet {
+				return nil, errors.Errorf(`partition_format=%s%s is only usable with %s=%s`,
+					changefeedbase.PartitionFormatColumnPrefix, column,
+					changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+			}
+			if s.layout == changefeedbase.OptCloudStorageLayoutIceberg {
+				return nil, errors.Errorf(`partition_format=%s%s is not usable with %s=%s, use %s instead`,
+					changefeedbase.PartitionFormatColumnPrefix, column,
+					changefeedbase.OptCloudStorageLayout, changefeedbase.OptCloudStorageLayoutIceberg,
+					changefeedbase.OptCloudStoragePartitionColumn)
+			}
+			s.partitionFormat = partitionDateFormats["flat"]
+			s.partitionColumn = column
+		} else {
+			dateFormat, ok := partitionDateFormats[partitionFormat]
+			if !ok {
+				return nil, errors.Errorf("invalid partition_format of %s", partitionFormat)
+			}
 
-		s.partitionFormat = dateFormat
+			s.partitionFormat = dateFormat
+		}
 	}
 
 	if s.timestampOracle != nil {
@@ -458,6 +555,9 @@ func makeCloudStorageSink(
 		// would require a bit of refactoring.
 		s.ext = `.csv`
 		s.rowDelimiter = []byte{'\n'}
+	case changefeedbase.OptFormatSQL:
+		s.ext = `.sql`
+		s.rowDelimiter = []byte{'\n'}
 	case changefeedbase.OptFormatParquet:
 		s.ext = `.parquet`
 		s.rowDelimiter = nil
@@ -513,11 +613,16 @@ func makeCloudStorageSink(
 	return s, nil
 }
 
+// getOrCreateFile returns the file buffering rows of the given topic version
+// which belong to the given partition. The partition is empty unless the sink
+// partitions data files by a column.
 func (s *cloudStorageSink) getOrCreateFile(
-	topic TopicDescriptor, eventMVCC hlc.Timestamp,
+	topic TopicDescriptor, partition string, eventMVCC hlc.Timestamp,
 ) (*cloudStorageSinkFile, error) {
 	name, _ := s.topicNamer.Name(topic)
-	key := cloudStorageSinkKey{name, int64(topic.GetVersion())}
+	key := cloudStorageSinkKey{
+		name, topic.GetTopicIdentifier().TableID, int64(topic.GetVersion()), partition,
+	}
 	if item := s.files.Get(key); item != nil {
 		f := item.(*cloudStorageSinkFile)
 		if eventMVCC.Less(f.oldestMVCC) {
@@ -573,7 +678,7 @@ func (s *cloudStorageSink) EmitRow(
 	}()
 
 	s.metrics.recordMessageSize(int64(len(key) + len(value)))
-	file, err := s.getOrCreateFile(topic, mvcc)
+	file, err := s.getOrCreateFile(topic, "" /* partition */, mvcc)
 	if err != nil {
 		return err
 	}
@@ -589,7 +694,7 @@ func (s *cloudStorageSink) EmitRow(
 
 	if int64(file.buf.Len()) > s.targetMaxFileSize {
 		s.metrics.recordSizeBasedFlush()
-		if err := s.flushTopicVersions(ctx, file.topic, file.schemaID); err != nil {
+		if err := s.flushTopicVersions(ctx, file.topic, file.tableID, file.schemaID); err != nil {
 			return err
 		}
 	}
@@ -629,8 +734,8 @@ func (s *cloudStorageSink) EmitResolvedTimestamp(
 	return cloud.WriteFile(ctx, s.es, filepath.Join(part, filename), bytes.NewReader(payload))
 }
 
-// flushTopicVersions flushes all open files for the provided topic up to and
-// including maxVersionToFlush.
+// flushTopicVersions flushes all open files for the provided table's topic up
+// to and including maxVersionToFlush.
 //
 // To understand why we need to do this, consider the following example in case
 // we didn't have this logic:
@@ -646,16 +751,16 @@ func (s *cloudStorageSink) EmitResolvedTimestamp(
 // schema 2 file, leading to a violation of our ordering guarantees (see comment
 // on cloudStorageSink)
 func (s *cloudStorageSink) flushTopicVersions(
-	ctx context.Context, topic string, maxVersionToFlush int64,
+	ctx context.Context, topic string, tableID descpb.ID, maxVersionToFlush int64,
 ) (err error) {
-	var toRemoveAlloc [2]int64    // generally avoid allocating
-	toRemove := toRemoveAlloc[:0] // schemaIDs of flushed files
-	gte := cloudStorageSinkKey{topic: topic}
-	lt := cloudStorageSinkKey{topic: topic, schemaID: maxVersionToFlush + 1}
+	var toRemoveAlloc [2]cloudStorageSinkKey // generally avoid allocating
+	toRemove := toRemoveAlloc[:0]            // keys of flushed files
+	gte := cloudStorageSinkKey{topic: topic, tableID: tableID}
+	lt := cloudStorageSinkKey{topic: topic, tableID: tableID, schemaID: maxVersionToFlush + 1}
 	s.files.AscendRange(gte, lt, func(i btree.Item) (wantMore bool) {
 		f := i.(*cloudStorageSinkFile)
 		if err = s.flushFile(ctx, f); err == nil {
-			toRemove = append(toRemove, f.schemaID)
+			toRemove = append(toRemove, f.cloudStorageSinkKey)
 		}
 		return err == nil
 	})
@@ -680,8 +785,8 @@ func (s *cloudStorageSink) flushTopicVersions(
 
 	// Files need to be cleared after the flush completes, otherwise file
 	// resources may be leaked.
-	for _, v := range toRemove {
-		s.files.Delete(cloudStorageSinkKey{topic: topic, schemaID: v})
+	for _, k := range toRemove {
+		s.files.Delete(k)
 	}
 	return err
 }
@@ -801,7 +906,10 @@ func (s *cloudStorageSink) flushFile(ctx context.Context, file *cloudStorageSink
 		return err
 	}
 	s.prevFilename = filename
-	dest := filepath.Join(s.dataFilePartition, filename)
+	dest := filepath.Join(s.dataFilePartition, file.partition, filename)
+	if s.layout == changefeedbase.OptCloudStorageLayoutIceberg {
+		dest = filepath.Join(file.topic, icebergDataDir, file.partition, filename)
+	}
 
 	if !asyncFlushEnabled {
 		return file.flushToStorage(ctx, s.es, dest, s.metrics)
@@ -937,8 +1045,14 @@ func (s *cloudStorageSink) Dial() error {
 }
 
 type cloudStorageSinkKey struct {
-	topic    string
+	topic string
+	// tableID keeps the files of tables which topic_override maps to the same
+	// topic apart, since their schema versions are unrelated.
+	tableID  descpb.ID
 	schemaID int64
+	// partition is the directory of the partition of the file's rows when
+	// the sink partitions data files by a column, and is empty otherwise.
+	partition string
 }
 
 func (k cloudStorageSinkKey) Less(other btree.Item) bool {
@@ -953,10 +1067,16 @@ func (k cloudStorageSinkKey) Less(other btree.Item) bool {
 }
 
 func keyLess(a, b cloudStorageSinkKey) bool {
-	if a.topic == b.topic {
+	if a.topic != b.topic {
+		return a.topic < b.topic
+	}
+	if a.tableID != b.tableID {
+		return a.tableID < b.tableID
+	}
+	if a.schemaID != b.schemaID {
 		return a.schemaID < b.schemaID
 	}
-	return a.topic < b.topic
+	return a.partition < b.partition
 }
 
 // generateChangefeedSessionID generates a unique string that is used to
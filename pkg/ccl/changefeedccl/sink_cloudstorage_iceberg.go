@@ -0,0 +1,307 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// With cloudstorage_layout=iceberg, the cloudstorage sink writes each topic as
+// an Apache Iceberg-style table rooted at the topic's directory:
+//
+//	<topic>/data/[<column>=<value>/]<data file>.parquet
+//	<topic>/metadata/snap-<timestamp>.manifest.json
+//	<topic>/metadata/<timestamp>.metadata.json
+//
+// Data files are named as described on cloudStorageSink and, if
+// cloudstorage_partition_column is set, are placed in a Hive-style directory
+// named after the value of that column in their rows.
+//
+// Instead of RESOLVED files, every resolved timestamp commits a snapshot of
+// each table that has new data files. A snapshot consists of a manifest, which
+// lists the data files added by the snapshot, followed by a table metadata
+// file, which lists the manifests of all the snapshots up to and including
+// it. The metadata file with the lexically greatest name describes the current
+// state of the table. Consumers must only read data files that are listed by
+// a manifest, since rows within a snapshot are not ordered across data files.
+const (
+	icebergDataDir        = "data"
+	icebergMetadataDir    = "metadata"
+	icebergManifestPrefix = "snap-"
+	icebergManifestSuffix = ".manifest.json"
+	icebergMetadataSuffix = ".metadata.json"
+
+	// icebergNullPartition is the name of the partition of rows in which the
+	// partition column is NULL.
+	icebergNullPartition = "__HIVE_DEFAULT_PARTITION__"
+)
+
+// icebergPartitionDir returns the name of the directory holding the data files
+// of the rows whose partition column has the same value as in the given row.
+func icebergPartitionDir(row cdcevent.Row, column string) (string, error) {
+	it, err := row.DatumNamed(column)
+	if err != nil {
+		return "", errors.Newf("partition column %q does not exist in %s", column, row.TableName)
+	}
+	value := icebergNullPartition
+	if err := it.Datum(func(d tree.Datum, _ cdcevent.ResultColumn) error {
+		if d != tree.DNull {
+			value = tree.AsStringWithFlags(d, tree.FmtBareStrings)
+		}
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	return url.QueryEscape(column) + "=" + url.QueryEscape(value), nil
+}
+
+// icebergPartitionValues returns the partition values encoded in the directory
+// of the given data file, which is relative to the table's data directory.
+func icebergPartitionValues(dataFile string) map[string]*string {
+	dir := path.Dir(dataFile)
+	if dir == "." {
+		return nil
+	}
+	values := make(map[string]*string)
+	for _, part := range strings.Split(dir, "/") {
+		column, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		if c, err := url.QueryUnescape(column); err == nil {
+			column = c
+		}
+		if value == icebergNullPartition {
+			values[column] = nil
+			continue
+		}
+		if v, err := url.QueryUnescape(value); err == nil {
+			value = v
+		}
+		values[column] = &value
+	}
+	return values
+}
+
+// icebergDataFile describes a data file in a manifest.
+type icebergDataFile struct {
+	FilePath   string             `json:"file_path"`
+	FileFormat string             `json:"file_format"`
+	Partition  map[string]*string `json:"partition,omitempty"`
+}
+
+// icebergManifestEntry is an entry of a manifest.
+type icebergManifestEntry struct {
+	Status     string          `json:"status"`
+	SnapshotID int64           `json:"snapshot_id"`
+	DataFile   icebergDataFile `json:"data_file"`
+}
+
+// icebergManifest lists the data files added by a snapshot.
+type icebergManifest struct {
+	Entries []icebergManifestEntry `json:"entries"`
+}
+
+// icebergPartitionField describes how a table is partitioned by a column.
+type icebergPartitionField struct {
+	Name      string `json:"name"`
+	SourceCol string `json:"source-column"`
+	Transform string `json:"transform"`
+}
+
+// icebergSnapshot describes a snapshot in a table metadata file.
+type icebergSnapshot struct {
+	SnapshotID       int64    `json:"snapshot-id"`
+	ParentSnapshotID int64    `json:"parent-snapshot-id,omitempty"`
+	TimestampMs      int64    `json:"timestamp-ms"`
+	Manifests        []string `json:"manifests"`
+}
+
+// icebergTableMetadata is the content of a table metadata file.
+type icebergTableMetadata struct {
+	FormatVersion     int                     `json:"format-version"`
+	Location          string                  `json:"location"`
+	LastUpdatedMs     int64                   `json:"last-updated-ms"`
+	PartitionSpec     []icebergPartitionField `json:"partition-spec"`
+	CurrentSnapshotID int64                   `json:"current-snapshot-id"`
+	Snapshots         []icebergSnapshot       `json:"snapshots"`
+}
+
+// icebergTable holds the files of a table, relative to the table's data and
+// metadata directories respectively.
+type icebergTable struct {
+	dataFiles []string
+	manifests []string
+}
+
+// commitIcebergSnapshots commits a snapshot of every table which has data
+// files, written at or before the resolved timestamp, that no snapshot has
+// committed yet.
+//
+// The data files of a snapshot are found by listing the sink's storage. This
+// relies on the same invariants as RESOLVED files do: once the resolved
+// timestamp is emitted, no data file named with a timestamp at or before it
+// will be written. Since snapshots are named after their resolved timestamp,
+// the set of files not yet committed can be recovered after a restart.
+func (s *cloudStorageSink) commitIcebergSnapshots(
+	ctx context.Context, resolved hlc.Timestamp,
+) error {
+	// TODO(cdc): Listing the whole sink gets slower as files accumulate. We
+	// could list the data directories of known topics only.
+	tables := make(map[string]*icebergTable)
+	getTable := func(topic string) *icebergTable {
+		t, ok := tables[topic]
+		if !ok {
+			t = &icebergTable{}
+			tables[topic] = t
+		}
+		return t
+	}
+	if err := s.es.List(ctx, "", "", func(name string) error {
+		name = strings.TrimPrefix(name, "/")
+		if topic, file, ok := cutIcebergPath(name, icebergDataDir); ok {
+			getTable(topic).dataFiles = append(getTable(topic).dataFiles, file)
+		} else if topic, file, ok := cutIcebergPath(name, icebergMetadataDir); ok &&
+			strings.HasPrefix(file, icebergManifestPrefix) && strings.HasSuffix(file, icebergManifestSuffix) {
+			getTable(topic).manifests = append(getTable(topic).manifests, file)
+		}
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "listing iceberg tables")
+	}
+
+	topics := make([]string, 0, len(tables))
+	for topic := range tables {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	for _, topic := range topics {
+		if err := s.commitIcebergSnapshot(ctx, topic, tables[topic], resolved); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cutIcebergPath splits the name of a file in the given directory of a table
+// into the table's topic and the file's path relative to the directory.
+func cutIcebergPath(name, dir string) (topic, file string, ok bool) {
+	// Partition directories always contain a '=', so they can't be mistaken
+	// for the data or metadata directories.
+	sep := "/" + dir + "/"
+	i := strings.LastIndex(name, sep)
+	if i <= 0 {
+		return "", "", false
+	}
+	return name[:i], name[i+len(sep):], true
+}
+
+// commitIcebergSnapshot commits a snapshot of the given table at the resolved
+// timestamp, if the table has data files which no snapshot has committed yet.
+func (s *cloudStorageSink) commitIcebergSnapshot(
+	ctx context.Context, topic string, table *icebergTable, resolved hlc.Timestamp,
+) error {
+	resolvedTs := cloudStorageFormatTime(resolved)
+	sort.Strings(table.manifests)
+	var lastCommittedTs string
+	if n := len(table.manifests); n > 0 {
+		lastCommittedTs = strings.TrimSuffix(
+			strings.TrimPrefix(table.manifests[n-1], icebergManifestPrefix), icebergManifestSuffix)
+	}
+	if lastCommittedTs >= resolvedTs {
+		return nil
+	}
+
+	snapshotID := int64(len(table.manifests) + 1)
+	var manifest icebergManifest
+	sort.Strings(table.dataFiles)
+	for _, f := range table.dataFiles {
+		base := path.Base(f)
+		if len(base) < len(resolvedTs) {
+			continue
+		}
+		if ts := base[:len(resolvedTs)]; ts <= lastCommittedTs || ts > resolvedTs {
+			continue
+		}
+		manifest.Entries = append(manifest.Entries, icebergManifestEntry{
+			Status:     "ADDED",
+			SnapshotID: snapshotID,
+			DataFile: icebergDataFile{
+				FilePath:   path.Join(icebergDataDir, f),
+				FileFormat: "PARQUET",
+				Partition:  icebergPartitionValues(f),
+			},
+		})
+	}
+	if len(manifest.Entries) == 0 {
+		return nil
+	}
+
+	manifestName := icebergManifestPrefix + resolvedTs + icebergManifestSuffix
+	if err := s.writeIcebergFile(ctx, path.Join(topic, icebergMetadataDir, manifestName), manifest); err != nil {
+		return err
+	}
+
+	manifests := make([]string, 0, len(table.manifests)+1)
+	for _, m := range append(table.manifests, manifestName) {
+		manifests = append(manifests, path.Join(icebergMetadataDir, m))
+	}
+	metadata := icebergTableMetadata{
+		FormatVersion:     2,
+		Location:          topic,
+		LastUpdatedMs:     resolved.GoTime().UnixMilli(),
+		PartitionSpec:     []icebergPartitionField{},
+		CurrentSnapshotID: snapshotID,
+		Snapshots: []icebergSnapshot{{
+			SnapshotID:       snapshotID,
+			ParentSnapshotID: snapshotID - 1,
+			TimestampMs:      resolved.GoTime().UnixMilli(),
+			Manifests:        manifests,
+		}},
+	}
+	if s.partitionColumn != "" {
+		metadata.PartitionSpec = append(metadata.PartitionSpec, icebergPartitionField{
+			Name:      s.partitionColumn,
+			SourceCol: s.partitionColumn,
+			Transform: "identity",
+		})
+	}
+	// The metadata file is written last, since it is what makes the snapshot
+	// visible to consumers.
+	metadataName := resolvedTs + icebergMetadataSuffix
+	return s.writeIcebergFile(ctx, path.Join(topic, icebergMetadataDir, metadataName), metadata)
+}
+
+// writeIcebergFile writes the JSON encoding of v to the named file.
+func (s *cloudStorageSink) writeIcebergFile(ctx context.Context, name string, v interface{}) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if log.V(1) {
+		log.Infof(ctx, "writing file %s", name)
+	}
+	if err := cloud.WriteFile(ctx, s.es, name, bytes.NewReader(content)); err != nil {
+		return errors.Wrapf(err, "writing %s", name)
+	}
+	return nil
+}
@@ -932,7 +932,8 @@ func TestCloudStorageSinkFastGzip(t *testing.T) {
 
 		// Flush the files and close the sink. Any leaks should be caught after the
 		// test by leaktest.
-		_ = s.(*cloudStorageSink).flushTopicVersions(ctx, newTopic.GetTableName(), int64(newTopic.GetVersion()))
+		_ = s.(*cloudStorageSink).flushTopicVersions(
+			ctx, newTopic.GetTableName(), newTopic.GetTopicIdentifier().TableID, int64(newTopic.GetVersion()))
 		_ = s.Close()
 	})
 }
@@ -44,6 +44,18 @@ import (
 	"golang.org/x/oauth2/clientcredentials"
 )
 
+// kafkaChecksumHeader is the header of kafka messages holding the checksum of
+// their value with emit_checksum.
+const kafkaChecksumHeader = `crc32`
+
+// emitsChecksumHeader returns whether the checksums of a changefeed created
+// with emit_checksum are emitted in message headers by its sink, rather than
+// in the envelope.
+func emitsChecksumHeader(sinkURI string) bool {
+	u, err := url.Parse(sinkURI)
+	return err == nil && isKafkaSink(u)
+}
+
 func isKafkaSink(u *url.URL) bool {
 	switch u.Scheme {
 	case changefeedbase.SinkSchemeConfluentKafka, changefeedbase.SinkSchemeAzureKafka,
@@ -152,6 +164,9 @@ type kafkaSink struct {
 	}
 
 	disableInternalRetry bool
+
+	// checksum adds the kafkaChecksumHeader header to each row message.
+	checksum bool
 }
 
 func (s *kafkaSink) getConcreteType() sinkType {
@@ -379,6 +394,11 @@ func (s *kafkaSink) EmitRow(
 		Value:    sarama.ByteEncoder(value),
 		Metadata: messageMetadata{alloc: alloc, mvcc: mvcc, updateMetrics: s.metrics.recordOneMessage()},
 	}
+	if s.checksum {
+		msg.Headers = []sarama.RecordHeader{
+			{Key: []byte(kafkaChecksumHeader), Value: []byte(messageChecksum(value))},
+		}
+	}
 	s.stats.startMessage(int64(msg.Key.Length() + msg.Value.Length()))
 	return s.emitMessage(ctx, msg)
 }
@@ -1350,6 +1370,8 @@ func makeKafkaSink(
 	u sinkURL,
 	targets changefeedbase.Targets,
 	jsonStr changefeedbase.SinkSpecificJSONConfig,
+	checksum changefeedbase.ChecksumAlgorithm,
+	deleteTopicSuffix string,
 	settings *cluster.Settings,
 	mb metricsRecorderBuilder,
 ) (Sink, error) {
@@ -1367,7 +1389,8 @@ func makeKafkaSink(
 
 	topics, err := MakeTopicNamer(
 		targets,
-		WithPrefix(kafkaTopicPrefix), WithSingleName(kafkaTopicName), WithSanitizeFn(SQLNameToKafkaName))
+		WithPrefix(kafkaTopicPrefix), WithSingleName(kafkaTopicName), WithDeleteSuffix(deleteTopicSuffix),
+		WithSanitizeFn(SQLNameToKafkaName))
 
 	if err != nil {
 		return nil, err
@@ -1382,6 +1405,7 @@ func makeKafkaSink(
 		metrics:              m,
 		topics:               topics,
 		disableInternalRetry: !internalRetryEnabled,
+		checksum:             checksum == changefeedbase.OptChecksumCRC32,
 	}
 
 	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
@@ -50,10 +50,17 @@ type kafkaSinkClientV2 struct {
 	client      KafkaClientV2
 	adminClient KafkaAdminClientV2
 
+	// topicClients are used instead of client to produce to the topics whose
+	// producer settings are overridden by kafka_topic_config.
+	topicClients map[string]KafkaClientV2
+
 	knobs          kafkaSinkV2Knobs
 	canTryResizing bool
 	recordResize   func(numRecords int64)
 
+	// checksum adds the kafkaChecksumHeader header to each row message.
+	checksum bool
+
 	topicsForConnectionCheck []string
 
 	// we need to fetch and keep track of this ourselves since kgo doesnt expose metadata to us
@@ -66,10 +73,12 @@ type kafkaSinkClientV2 struct {
 
 // newKafkaSinkClientV2 creates a new kafka sink client. It is a thin wrapper
 // around the kgo client for use by the batching sink. It's not meant to be
-// invoked on its own, but rather through makeKafkaSinkV2.
+// invoked on its own, but rather through makeKafkaSinkV2. The topicOpts are
+// appended to the clientOpts of the clients producing to their topics.
 func newKafkaSinkClientV2(
 	ctx context.Context,
 	clientOpts []kgo.Opt,
+	topicOpts map[string][]kgo.Opt,
 	batchCfg sinkBatchConfig,
 	bootstrapAddrs string,
 	settings *cluster.Settings,
@@ -126,9 +135,30 @@ func newKafkaSinkClientV2(
 		adminClient = kadm.NewClient(client.(*kgo.Client))
 	}
 
+	var topicClients map[string]KafkaClientV2
+	if len(topicOpts) > 0 {
+		topicClients = make(map[string]KafkaClientV2, len(topicOpts))
+	}
+	for topic, opts := range topicOpts {
+		// Later options take precedence over earlier ones.
+		opts = append(clientOpts[:len(clientOpts):len(clientOpts)], opts...)
+		var topicClient KafkaClientV2
+		if knobs.OverrideClient != nil {
+			topicClient, _ = knobs.OverrideClient(opts)
+		} else if topicClient, err = kgo.NewClient(opts...); err != nil {
+			client.Close()
+			for _, c := range topicClients {
+				c.Close()
+			}
+			return nil, err
+		}
+		topicClients[topic] = topicClient
+	}
+
 	c := &kafkaSinkClientV2{
 		client:                   client,
 		adminClient:              adminClient,
+		topicClients:             topicClients,
 		knobs:                    knobs,
 		batchCfg:                 batchCfg,
 		canTryResizing:           changefeedbase.BatchReductionRetryEnabled.Get(&settings.SV),
@@ -143,6 +173,37 @@ func newKafkaSinkClientV2(
 // Close implements SinkClient.
 func (k *kafkaSinkClientV2) Close() error {
 	k.client.Close()
+	for _, c := range k.topicClients {
+		c.Close()
+	}
+	return nil
+}
+
+// produceSync produces the messages with the clients of their topics, and
+// returns the first error encountered.
+func (k *kafkaSinkClientV2) produceSync(ctx context.Context, msgs []*kgo.Record) error {
+	if len(k.topicClients) == 0 {
+		return k.client.ProduceSync(ctx, msgs...).FirstErr()
+	}
+	// Batches only hold messages for a single topic, except for resolved
+	// timestamps, which are produced to every topic.
+	byClient := make(map[KafkaClientV2][]*kgo.Record)
+	var clients []KafkaClientV2
+	for _, msg := range msgs {
+		c, ok := k.topicClients[msg.Topic]
+		if !ok {
+			c = k.client
+		}
+		if _, ok := byClient[c]; !ok {
+			clients = append(clients, c)
+		}
+		byClient[c] = append(byClient[c], msg)
+	}
+	for _, c := range clients {
+		if err := c.ProduceSync(ctx, byClient[c]...).FirstErr(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -152,7 +213,7 @@ func (k *kafkaSinkClientV2) Flush(ctx context.Context, payload SinkPayload) (ret
 
 	var flushMsgs func(msgs []*kgo.Record) error
 	flushMsgs = func(msgs []*kgo.Record) error {
-		if err := k.client.ProduceSync(ctx, msgs...).FirstErr(); err != nil {
+		if err := k.produceSync(ctx, msgs); err != nil {
 			if k.shouldTryResizing(err, msgs) {
 				a, b := msgs[0:len(msgs)/2], msgs[len(msgs)/2:]
 				// Recurse. This is a little odd because the client's batch
@@ -269,7 +330,7 @@ func (k *kafkaSinkClientV2) maybeUpdateTopicPartitions(
 
 // MakeBatchBuffer implements SinkClient.
 func (k *kafkaSinkClientV2) MakeBatchBuffer(topic string) BatchBuffer {
-	return &kafkaBuffer{topic: topic, batchCfg: k.batchCfg}
+	return &kafkaBuffer{topic: topic, batchCfg: k.batchCfg, checksum: k.checksum}
 }
 
 func (k *kafkaSinkClientV2) shouldTryResizing(err error, msgs []*kgo.Record) bool {
@@ -308,6 +369,7 @@ type kafkaBuffer struct {
 	byteCount int
 
 	batchCfg sinkBatchConfig
+	checksum bool
 }
 
 func (b *kafkaBuffer) Append(key []byte, value []byte, _ attributes) {
@@ -317,7 +379,13 @@ func (b *kafkaBuffer) Append(key []byte, value []byte, _ attributes) {
 		key = []byte{}
 	}
 
-	b.messages = append(b.messages, &kgo.Record{Key: key, Value: value, Topic: b.topic})
+	msg := &kgo.Record{Key: key, Value: value, Topic: b.topic}
+	if b.checksum {
+		msg.Headers = []kgo.RecordHeader{
+			{Key: kafkaChecksumHeader, Value: []byte(messageChecksum(value))},
+		}
+	}
+	b.messages = append(b.messages, msg)
 	b.byteCount += len(value)
 }
 
@@ -336,6 +404,9 @@ func makeKafkaSinkV2(
 	u sinkURL,
 	targets changefeedbase.Targets,
 	jsonConfig changefeedbase.SinkSpecificJSONConfig,
+	checksum changefeedbase.ChecksumAlgorithm,
+	deleteTopicSuffix string,
+	topicConfig map[string]changefeedbase.KafkaTopicConfig,
 	parallelism int,
 	pacerFactory func() *admission.Pacer,
 	timeSource timeutil.TimeSource,
@@ -369,7 +440,8 @@ func makeKafkaSinkV2(
 
 	topicNamer, err := MakeTopicNamer(
 		targets,
-		WithPrefix(kafkaTopicPrefix), WithSingleName(kafkaTopicName), WithSanitizeFn(SQLNameToKafkaName))
+		WithPrefix(kafkaTopicPrefix), WithSingleName(kafkaTopicName), WithDeleteSuffix(deleteTopicSuffix),
+		WithSanitizeFn(SQLNameToKafkaName))
 
 	if err != nil {
 		return nil, err
@@ -381,10 +453,16 @@ func makeKafkaSinkV2(
 	}
 
 	topicsForConnectionCheck := topicNamer.DisplayNamesSlice()
-	client, err := newKafkaSinkClientV2(ctx, clientOpts, batchCfg, u.Host, settings, knobs, mb, topicsForConnectionCheck)
+	topicOpts, err := buildKgoTopicConfigs(topicConfig, topicsForConnectionCheck)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newKafkaSinkClientV2(ctx, clientOpts, topicOpts, batchCfg, u.Host, settings, knobs, mb, topicsForConnectionCheck)
 	if err != nil {
 		return nil, err
 	}
+	client.checksum = checksum == changefeedbase.OptChecksumCRC32
 
 	return makeBatchingSink(ctx, sinkTypeKafka, client, time.Duration(batchCfg.Frequency), retryOpts,
 		parallelism, topicNamer, pacerFactory, timeSource, mb(true), settings), nil
@@ -565,6 +643,45 @@ func buildKgoConfig(
 	return opts, nil
 }
 
+// buildKgoTopicConfigs returns the client options overriding the producer
+// settings of each topic configured by kafka_topic_config. Every configured
+// topic must be one of the given topics emitted to by the sink.
+func buildKgoTopicConfigs(
+	topicConfig map[string]changefeedbase.KafkaTopicConfig, topics []string,
+) (map[string][]kgo.Opt, error) {
+	if len(topicConfig) == 0 {
+		return nil, nil
+	}
+	emitted := make(map[string]struct{}, len(topics))
+	for _, topic := range topics {
+		emitted[topic] = struct{}{}
+	}
+	topicOpts := make(map[string][]kgo.Opt, len(topicConfig))
+	for topic, cfg := range topicConfig {
+		if _, ok := emitted[topic]; !ok {
+			return nil, errors.Errorf(`option %s references topic %s, which the changefeed does not emit to`,
+				changefeedbase.OptKafkaTopicConfig, topic)
+		}
+		var opts []kgo.Opt
+		switch strings.ToUpper(cfg.Acks) {
+		case ``:
+		case `ONE`, `1`:
+			opts = append(opts, kgo.RequiredAcks(kgo.LeaderAck()))
+		case `ALL`, `-1`:
+			opts = append(opts, kgo.RequiredAcks(kgo.AllISRAcks()))
+		case `NONE`, `0`:
+			opts = append(opts, kgo.RequiredAcks(kgo.NoAck()))
+		default:
+			return nil, errors.Errorf(`unknown required acks value: %s`, cfg.Acks)
+		}
+		if cfg.Retries != nil {
+			opts = append(opts, kgo.RecordRetries(*cfg.Retries))
+		}
+		topicOpts[topic] = opts
+	}
+	return topicOpts, nil
+}
+
 // NOTE: kgo will ignore invalid compression levels, but the v1 sinks will fail validations. So we have to validate these ourselves.
 func validateCompressionLevel(compressionType compressionCodec, level int) error {
 	switch sarama.CompressionCodec(compressionType) {
@@ -383,6 +383,43 @@ func TestKafkaSinkClientV2_Opts(t *testing.T) {
 	}
 }
 
+func TestKafkaSinkClientV2_TopicConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	topicConfig, err := changefeedbase.ParseKafkaTopicConfig(`foo:{"acks":"all","retries":10},bar:{"acks":"0"}`)
+	require.NoError(t, err)
+	fx := newKafkaSinkV2Fx(t, withTargets([]string{"foo", "bar", "baz"}),
+		withTopicConfig(topicConfig), withRealClient())
+	defer fx.close()
+
+	sinkClient := fx.bs.client.(*kafkaSinkClientV2)
+	for topic, expectedOpts := range map[string]map[string]any{
+		"foo": {"RequiredAcks": kgo.AllISRAcks(), "RecordRetries": int64(10)},
+		"bar": {"RequiredAcks": kgo.NoAck(), "RecordRetries": int64(5)},
+	} {
+		require.Contains(t, sinkClient.topicClients, topic)
+		client := sinkClient.topicClients[topic].(*kgo.Client)
+		for k, v := range expectedOpts {
+			val := client.OptValue(k)
+			assert.Equal(t, v, val, "opt %q of topic %s has value %+#v, expected %+#v", k, topic, val, v)
+		}
+		// The settings which aren't overridden are the sink's.
+		assert.Equal(t, "CockroachDB", client.OptValue("ClientID"))
+	}
+
+	// Topics without overrides are produced to with the sink's settings.
+	require.NotContains(t, sinkClient.topicClients, "baz")
+	assert.Equal(t, kgo.LeaderAck(), sinkClient.client.(*kgo.Client).OptValue("RequiredAcks"))
+
+	var createErr error
+	errFx := newKafkaSinkV2Fx(t, withTopicConfig(map[string]changefeedbase.KafkaTopicConfig{"qux": {}}),
+		withRealClient(), withCreateClientErrorCb(func(err error) { createErr = err }))
+	defer errFx.close()
+	require.ErrorContains(t, createErr,
+		"option kafka_topic_config references topic qux, which the changefeed does not emit to")
+}
+
 func shallowMerge(a, b map[string]any) map[string]any {
 	res := make(map[string]any, len(a))
 	for k, v := range a {
@@ -581,6 +618,7 @@ type kafkaSinkV2Fx struct {
 	topicOverride       string
 	topicPrefix         string
 	sinkJSONConfig      changefeedbase.SinkSpecificJSONConfig
+	topicConfig         map[string]changefeedbase.KafkaTopicConfig
 	batchConfig         sinkBatchConfig
 	realClient          bool
 	additionalKOpts     []kgo.Opt
@@ -622,6 +660,12 @@ func withJSONConfig(cfg string) fxOpt {
 	}
 }
 
+func withTopicConfig(cfg map[string]changefeedbase.KafkaTopicConfig) fxOpt {
+	return func(fx *kafkaSinkV2Fx) {
+		fx.topicConfig = cfg
+	}
+}
+
 func withRealClient() fxOpt {
 	return func(fx *kafkaSinkV2Fx) {
 		fx.realClient = true
@@ -671,7 +715,7 @@ func newKafkaSinkV2Fx(t *testing.T, opts ...fxOpt) *kafkaSinkV2Fx {
 	}
 
 	var err error
-	fx.sink, err = newKafkaSinkClientV2(ctx, fx.additionalKOpts, fx.batchConfig, "no addrs", settings, knobs, nilMetricsRecorderBuilder, nil)
+	fx.sink, err = newKafkaSinkClientV2(ctx, fx.additionalKOpts, nil /* topicOpts */, fx.batchConfig, "no addrs", settings, knobs, nilMetricsRecorderBuilder, nil)
 	if err != nil && fx.createClientErrorCb != nil {
 		fx.createClientErrorCb(err)
 		return fx
@@ -692,7 +736,7 @@ func newKafkaSinkV2Fx(t *testing.T, opts ...fxOpt) *kafkaSinkV2Fx {
 	}
 	u.RawQuery = q.Encode()
 
-	bs, err := makeKafkaSinkV2(ctx, sinkURL{URL: u}, targets, fx.sinkJSONConfig, 1, nilPacerFactory, timeutil.DefaultTimeSource{}, settings, nilMetricsRecorderBuilder, knobs)
+	bs, err := makeKafkaSinkV2(ctx, sinkURL{URL: u}, targets, fx.sinkJSONConfig, "" /* checksum */, "" /* deleteTopicSuffix */, fx.topicConfig, 1, nilPacerFactory, timeutil.DefaultTimeSource{}, settings, nilMetricsRecorderBuilder, knobs)
 	if err != nil && fx.createClientErrorCb != nil {
 		fx.createClientErrorCb(err)
 		return fx
@@ -0,0 +1,133 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyslogSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	sinkDest, err := cdctest.StartMockSyslogSink(nil /* certificate */)
+	require.NoError(t, err)
+	defer sinkDest.Close()
+
+	makeSink := func(uri string) (Sink, error) {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		return makeSyslogSink(sinkURL{URL: u},
+			changefeedbase.EncodingOptions{Format: changefeedbase.OptFormatJSON},
+			makeChangefeedTargets(`t`), nilMetricsRecorderBuilder)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := makeSink(`syslog://localhost`)
+		require.ErrorContains(t, err, `must specify port`)
+		_, err = makeSink(`syslog://localhost:514?ca_cert=Zm9v`)
+		require.ErrorContains(t, err, `ca_cert requires tls_enabled=true`)
+		_, err = makeSink(`syslog://localhost:514?foo=bar`)
+		require.ErrorContains(t, err, `unknown syslog sink query parameters: foo`)
+	})
+
+	sink, err := makeSink(`syslog://` + sinkDest.Addr())
+	require.NoError(t, err)
+	require.NoError(t, sink.Dial())
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	// Nothing is sent before the sink is flushed.
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`["a]\"b"]`), []byte(`{"after": {"a": 1}}`),
+		ts, ts, zeroAlloc))
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, testEncoder{}, ts))
+	require.Equal(t, "", sinkDest.Pop())
+	require.NoError(t, sink.Flush(ctx))
+
+	pop := func() cdctest.SyslogMessage {
+		var msg string
+		testutils.SucceedsSoon(t, func() error {
+			if msg = sinkDest.Pop(); msg == "" {
+				return errors.New("waiting for message")
+			}
+			return nil
+		})
+		parsed, err := cdctest.ParseSyslogMessage(msg)
+		require.NoError(t, err)
+		require.Equal(t, syslogPriority, parsed.Priority)
+		require.Equal(t, syslogAppName, parsed.AppName)
+		require.Equal(t, "-", parsed.ProcID)
+		return parsed
+	}
+
+	row := pop()
+	require.Equal(t, syslogMsgIDRow, row.MsgID)
+	require.Equal(t, map[string]map[string]string{
+		syslogSDID: {
+			`topic`:   `t`,
+			`key`:     `["a]\"b"]`,
+			`updated`: `1.0000000002`,
+			`mvcc`:    `1.0000000002`,
+		},
+	}, row.StructuredData)
+	require.Equal(t, `{"after": {"a": 1}}`, string(row.Msg))
+
+	resolved := pop()
+	require.Equal(t, syslogMsgIDResolved, resolved.MsgID)
+	require.Equal(t, map[string]map[string]string{
+		syslogSDID: {
+			`topic`:    `t`,
+			`resolved`: `1.0000000002`,
+		},
+	}, resolved.StructuredData)
+	require.Equal(t, ts.String(), string(resolved.Msg))
+	require.NoError(t, sinkDest.Err())
+}
+
+func TestChangefeedSyslogSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH resolved`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+		})
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": null}`,
+		})
+		expectResolvedTimestamp(t, foo)
+
+		expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH format=csv, initial_scan='only'`,
+			`this sink is incompatible with format=csv`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("syslog"))
+}
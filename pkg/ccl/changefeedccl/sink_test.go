@@ -10,7 +10,10 @@ package changefeedccl
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -21,6 +24,7 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -849,6 +853,62 @@ func TestSaramaConfigOptionParsing(t *testing.T) {
 	})
 }
 
+func TestSinkClientCertTLSConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	caCert, _, err := cdctest.NewCACertBase64Encoded()
+	require.NoError(t, err)
+	clientCertPEM, clientKeyPEM, err := cdctest.GenerateClientCertAndKey(caCert)
+	require.NoError(t, err)
+	block, _ := pem.Decode(clientCertPEM)
+	require.NotNil(t, block)
+
+	params := url.Values{}
+	params.Add(changefeedbase.SinkParamClientCert, base64.StdEncoding.EncodeToString(clientCertPEM))
+	params.Add(changefeedbase.SinkParamClientKey, base64.StdEncoding.EncodeToString(clientKeyPEM))
+	makeSinkURL := func(t *testing.T, uri string) sinkURL {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		return sinkURL{URL: u}
+	}
+
+	t.Run("kafka", func(t *testing.T) {
+		cfg, err := buildKafkaConfig(ctx,
+			makeSinkURL(t, `kafka://nope/?tls_enabled=true&`+params.Encode()),
+			``, nil /* kafkaThrottlingMetrics */, nil /* netMetrics */)
+		require.NoError(t, err)
+		require.True(t, cfg.Net.TLS.Enable)
+		require.Len(t, cfg.Net.TLS.Config.Certificates, 1)
+		require.Equal(t, block.Bytes, cfg.Net.TLS.Config.Certificates[0].Certificate[0])
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		client, err := makeWebhookClient(
+			makeSinkURL(t, `webhook-https://fake-host?`+params.Encode()),
+			time.Second, 1 /* parallelism */, nil /* nm */)
+		require.NoError(t, err)
+		tlsCfg := client.Transport.(*http.Transport).TLSClientConfig
+		require.Len(t, tlsCfg.Certificates, 1)
+		require.Equal(t, block.Bytes, tlsCfg.Certificates[0].Certificate[0])
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		invalid := url.Values{}
+		invalid.Add(changefeedbase.SinkParamClientCert, base64.StdEncoding.EncodeToString(clientCertPEM))
+		invalid.Add(changefeedbase.SinkParamClientKey, base64.StdEncoding.EncodeToString([]byte(`not a key`)))
+		_, err := buildKafkaConfig(ctx,
+			makeSinkURL(t, `kafka://nope/?tls_enabled=true&`+invalid.Encode()),
+			``, nil /* kafkaThrottlingMetrics */, nil /* netMetrics */)
+		require.ErrorContains(t, err, `invalid client certificate data provided`)
+		_, err = makeWebhookClient(
+			makeSinkURL(t, `webhook-https://fake-host?`+invalid.Encode()),
+			time.Second, 1 /* parallelism */, nil /* nm */)
+		require.ErrorContains(t, err, `invalid client certificate data provided`)
+	})
+}
+
 func TestKafkaSinkTracksMemory(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -0,0 +1,253 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The unix sink emits each message to a local consumer, such as a sidecar,
+// over a Unix domain socket. Each message is written as a frame of three
+// fields, each prefixed by its length as a 4-byte big-endian integer:
+//
+//	<topic> <key> <value>
+//
+// Resolved timestamps are emitted to every topic with an empty key and the
+// encoded resolved timestamp as the value.
+//
+// Messages are buffered until the sink is flushed, a resolved timestamp is
+// emitted, or the buffered messages exceed unixMaxBufferSize. If the consumer
+// has closed its end of the socket, the sink reconnects and writes the
+// buffered messages again, so messages may be delivered more than once.
+
+const (
+	// unixDialTimeout bounds the time spent connecting to the socket.
+	unixDialTimeout = 30 * time.Second
+	// unixWriteTimeout bounds the time spent writing the buffered messages, so
+	// that a consumer which stopped reading doesn't block the changefeed.
+	unixWriteTimeout = time.Minute
+	// unixMaxBufferSize is the size of the buffered messages past which they
+	// are written without waiting for the next flush.
+	unixMaxBufferSize = 16 << 20 // 16MB
+)
+
+func isUnixSink(u *url.URL) bool {
+	return u.Scheme == changefeedbase.SinkSchemeUnix
+}
+
+type unixSink struct {
+	path       string
+	topicNamer *TopicNamer
+	metrics    metricsRecorder
+
+	// Initialized after Dial()ing. Reset to nil if a write fails, in which
+	// case the next flush dials again.
+	conn net.Conn
+
+	// buf holds the frames emitted since they were last written.
+	buf bytes.Buffer
+	// alloc holds the memory reserved for the frames in buf, which is released
+	// once they have been written.
+	alloc kvevent.Alloc
+}
+
+func (s *unixSink) getConcreteType() sinkType {
+	return sinkTypeUnix
+}
+
+func makeUnixSink(
+	u sinkURL, targets changefeedbase.Targets, mb metricsRecorderBuilder,
+) (Sink, error) {
+	if u.Host != `` || !strings.HasPrefix(u.Path, `/`) {
+		return nil, errors.Errorf(`must specify an absolute socket path, e.g. unix:///path/to/socket`)
+	}
+	if q := u.Query(); len(q) > 0 {
+		unknownParams := make([]string, 0, len(q))
+		for p := range q {
+			unknownParams = append(unknownParams, p)
+		}
+		sort.Strings(unknownParams)
+		return nil, errors.Errorf(
+			`unknown unix sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	topicNamer, err := MakeTopicNamer(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &unixSink{
+		path:       u.Path,
+		topicNamer: topicNamer,
+		metrics:    mb(requiresResourceAccounting),
+	}, nil
+}
+
+// Dial implements the Sink interface.
+func (s *unixSink) Dial() error {
+	ctx, cancel := context.WithTimeout(context.Background(), unixDialTimeout)
+	defer cancel()
+
+	dial := s.metrics.netMetrics().Wrap((&net.Dialer{}).DialContext, "unix")
+	conn, err := dial(ctx, "unix", s.path)
+	if err != nil {
+		return errors.Wrapf(err, `dialing unix sink %s`, s.path)
+	}
+	s.conn = conn
+	return nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *unixSink) EmitRow(
+	ctx context.Context,
+	topicDescr TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	defer s.metrics.recordOneMessage()(mvcc, len(key)+len(value), sinkDoesNotCompress)
+
+	topic, err := s.topicNamer.Name(topicDescr)
+	if err != nil {
+		alloc.Release(ctx)
+		return err
+	}
+	s.alloc.Merge(&alloc)
+	s.writeFrame(topic, key, value)
+
+	if s.buf.Len() > unixMaxBufferSize {
+		s.metrics.recordSizeBasedFlush()
+		return s.write(ctx)
+	}
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *unixSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+
+	if err := s.topicNamer.Each(func(topic string) error {
+		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+		if err != nil {
+			return err
+		}
+		s.writeFrame(topic, nil /* key */, payload)
+		return nil
+	}); err != nil {
+		return err
+	}
+	// Resolved timestamps aren't held back until the next flush.
+	return s.write(ctx)
+}
+
+// Topics gives the names of all topics that have been initialized
+// and will receive resolved timestamps.
+func (s *unixSink) Topics() []string {
+	return s.topicNamer.DisplayNamesSlice()
+}
+
+// writeFrame appends a message to the buffer of frames to be written.
+func (s *unixSink) writeFrame(topic string, key, value []byte) {
+	var length [4]byte
+	for _, field := range [][]byte{[]byte(topic), key, value} {
+		binary.BigEndian.PutUint32(length[:], uint32(len(field)))
+		s.buf.Write(length[:])
+		s.buf.Write(field)
+	}
+}
+
+// Flush implements the Sink interface.
+func (s *unixSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+	return s.write(ctx)
+}
+
+// write writes the buffered frames to the socket, and releases their memory
+// once they have been written.
+func (s *unixSink) write(ctx context.Context) error {
+	if s.buf.Len() == 0 {
+		return nil
+	}
+	err := s.writeBuffer(ctx)
+	if isUnixSinkDisconnect(err) {
+		// The consumer went away, e.g. because it was restarted. Reconnect, and
+		// write the buffered messages again.
+		s.resetConn()
+		err = s.writeBuffer(ctx)
+	}
+	if err != nil {
+		// Some of the frames may have been written, so the consumer can't make
+		// sense of the frames written next on this connection.
+		s.resetConn()
+		return errors.Wrap(err, `flushing unix sink`)
+	}
+	s.buf.Reset()
+	s.alloc.Release(ctx)
+	return nil
+}
+
+// writeBuffer writes the buffered frames to the socket, dialing it first if
+// needed.
+func (s *unixSink) writeBuffer(ctx context.Context) error {
+	if s.conn == nil {
+		if err := s.Dial(); err != nil {
+			return err
+		}
+	}
+	deadline := timeutil.Now().Add(unixWriteTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := s.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	_, err := s.conn.Write(s.buf.Bytes())
+	return err
+}
+
+// resetConn closes the connection to the socket, if any.
+func (s *unixSink) resetConn() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// isUnixSinkDisconnect returns whether err indicates that the consumer closed
+// its end of the socket.
+func isUnixSinkDisconnect(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// Close implements the Sink interface.
+func (s *unixSink) Close() error {
+	s.alloc.Release(context.Background())
+	// Close() may be called before Dial(), so perform a nil check.
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnixSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	sinkDest, err := cdctest.StartMockUnixSink()
+	require.NoError(t, err)
+	defer sinkDest.Close()
+
+	makeSink := func(uri string) (Sink, error) {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		return makeUnixSink(sinkURL{URL: u}, makeChangefeedTargets(`t`), nilMetricsRecorderBuilder)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := makeSink(`unix://localhost/tmp/sink.sock`)
+		require.ErrorContains(t, err, `must specify an absolute socket path`)
+		_, err = makeSink(`unix://tmp/sink.sock`)
+		require.ErrorContains(t, err, `must specify an absolute socket path`)
+		_, err = makeSink(`unix:///tmp/sink.sock?foo=bar`)
+		require.ErrorContains(t, err, `unknown unix sink query parameters: foo`)
+	})
+
+	sink, err := makeSink(`unix://` + sinkDest.Path())
+	require.NoError(t, err)
+	require.NoError(t, sink.Dial())
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	pop := func() cdctest.UnixSinkMessage {
+		var msg cdctest.UnixSinkMessage
+		testutils.SucceedsSoon(t, func() error {
+			var ok bool
+			if msg, ok = sinkDest.Pop(); !ok {
+				return errors.New("waiting for message")
+			}
+			return nil
+		})
+		return msg
+	}
+
+	emitRows := func(from, to int) {
+		for i := from; i < to; i++ {
+			ts := hlc.Timestamp{WallTime: int64(i + 1)}
+			require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(fmt.Sprintf(`[%d]`, i)),
+				[]byte(fmt.Sprintf(`{"after": {"a": %d}}`, i)), ts, ts, zeroAlloc))
+		}
+	}
+	expectRows := func(from, to int) {
+		for i := from; i < to; i++ {
+			require.Equal(t, cdctest.UnixSinkMessage{
+				Topic: `t`,
+				Key:   []byte(fmt.Sprintf(`[%d]`, i)),
+				Value: []byte(fmt.Sprintf(`{"after": {"a": %d}}`, i)),
+			}, pop())
+		}
+	}
+
+	// Nothing is sent before the sink is flushed, or a resolved timestamp is
+	// emitted.
+	emitRows(0, 10)
+	_, ok := sinkDest.Pop()
+	require.False(t, ok)
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, testEncoder{}, ts))
+
+	// Messages are received in the order they were emitted, and resolved
+	// timestamps have an empty key.
+	expectRows(0, 10)
+	resolved := pop()
+	require.Equal(t, `t`, resolved.Topic)
+	require.Empty(t, resolved.Key)
+	require.Equal(t, ts.String(), string(resolved.Value))
+
+	// The sink reconnects if the consumer goes away.
+	sinkDest.DropConnections()
+	emitRows(10, 20)
+	require.NoError(t, sink.Flush(ctx))
+	expectRows(10, 20)
+
+	// The memory of the messages is released once they have been written.
+	pool := testAllocPool{}
+	ts = hlc.Timestamp{WallTime: 21}
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[20]`), []byte(`{}`), ts, ts, pool.alloc()))
+	require.EqualValues(t, 1, pool.used())
+	require.NoError(t, sink.Flush(ctx))
+	require.EqualValues(t, 0, pool.used())
+	pop()
+
+	// The messages are written without waiting for a flush once they exceed
+	// unixMaxBufferSize.
+	large := []byte(strings.Repeat(`v`, unixMaxBufferSize))
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[21]`), large, ts, ts, pool.alloc()))
+	require.EqualValues(t, 0, pool.used())
+	require.Equal(t, large, pop().Value)
+}
+
+func TestChangefeedUnixSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 0)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH resolved, on_error='pause'`)
+		defer closeFeed(t, foo)
+
+		for i := 1; i <= 5; i++ {
+			sqlDB.Exec(t, `UPDATE foo SET b = $1 WHERE a = 1`, i)
+		}
+		assertPayloadsPerKeyOrderedStripTs(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": 0}}`,
+			`foo: [1]->{"after": {"a": 1, "b": 1}}`,
+			`foo: [1]->{"after": {"a": 1, "b": 2}}`,
+			`foo: [1]->{"after": {"a": 1, "b": 3}}`,
+			`foo: [1]->{"after": {"a": 1, "b": 4}}`,
+			`foo: [1]->{"after": {"a": 1, "b": 5}}`,
+		})
+		expectResolvedTimestamp(t, foo)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("unix"))
+}
@@ -44,9 +44,10 @@ type deprecatedWebhookSink struct {
 	format      changefeedbase.FormatType
 
 	// Webhook destination.
-	url        sinkURL
-	authHeader string
-	client     *httputil.Client
+	url         sinkURL
+	authHeader  string
+	client      *httputil.Client
+	compression compressionAlgo
 
 	// messages are written onto batch channel
 	// which batches matches based on batching configuration.
@@ -283,6 +284,11 @@ func makeDeprecatedWebhookSink(
 		return nil, errors.Wrapf(err, "error processing option %s", changefeedbase.OptWebhookSinkConfig)
 	}
 
+	sink.compression, err = webhookCompressionFromOpts(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// TODO(yevgeniy): Establish HTTP connection in Dial().
 	sink.client, err = deprecatedMakeWebhookClient(u, connTimeout, m.netMetrics())
 	if err != nil {
@@ -561,6 +567,12 @@ func (s *deprecatedWebhookSink) workerLoop(workerIndex int) {
 }
 
 func (s *deprecatedWebhookSink) sendMessageWithRetries(ctx context.Context, reqBody []byte) error {
+	if s.compression.enabled() {
+		var err error
+		if reqBody, err = compressWebhookBody(s.compression, reqBody); err != nil {
+			return err
+		}
+	}
 	requestFunc := func() error {
 		return s.sendMessage(ctx, reqBody)
 	}
@@ -579,6 +591,10 @@ func (s *deprecatedWebhookSink) sendMessage(ctx context.Context, reqBody []byte)
 		req.Header.Set("Content-Type", applicationTypeCSV)
 	}
 
+	if s.compression.enabled() {
+		req.Header.Set(contentEncodingHeader, string(s.compression))
+	}
+
 	if s.authHeader != "" {
 		req.Header.Set(authorizationHeader, s.authHeader)
 	}
@@ -9,9 +9,11 @@
 package changefeedccl
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -766,3 +768,48 @@ func TestWebhookSinkRetry(t *testing.T) {
 	sinkDest.Close()
 	require.NoError(t, sinkSrc.Close())
 }
+
+func TestWebhookSinkCompression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	opts := getGenericWebhookSinkOptions(struct {
+		key   string
+		value string
+	}{
+		key:   changefeedbase.OptWebhookCompression,
+		value: "gzip",
+	})
+	encodingOpts, err := opts.GetEncodingOptions()
+	require.NoError(t, err)
+	webhookOpts, err := opts.GetWebhookSinkOptions()
+	require.NoError(t, err)
+
+	u, err := url.Parse("webhook-https://localhost:8080")
+	require.NoError(t, err)
+	sc, err := makeWebhookSinkClient(ctx, sinkURL{URL: u}, encodingOpts, webhookOpts,
+		sinkBatchConfig{}, 1 /* parallelism */, nilMetricsRecorderBuilder(false))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sc.Close()) }()
+
+	buf := sc.MakeBatchBuffer("foo")
+	buf.Append([]byte("[1001]"), []byte("{\"after\":{\"col1\":\"val1\",\"rowid\":1000},\"key\":[1001],\"topic:\":\"foo\"}"), attributes{})
+	payload, err := buf.Close()
+	require.NoError(t, err)
+
+	req := payload.(*http.Request)
+	require.Equal(t, "gzip", req.Header.Get("Content-Encoding"))
+	require.Equal(t, applicationTypeJSON, req.Header.Get("Content-Type"))
+
+	gr, err := gzip.NewReader(req.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(gr)
+	require.NoError(t, err)
+	require.Equal(t, "{\"payload\":[{\"after\":{\"col1\":\"val1\",\"rowid\":1000},\"key\":[1001],\"topic:\":\"foo\"}],\"length\":1}", string(body))
+
+	// Any other algorithm is rejected.
+	webhookOpts.Compression = "zstd"
+	_, err = makeWebhookSinkClient(ctx, sinkURL{URL: u}, encodingOpts, webhookOpts,
+		sinkBatchConfig{}, 1 /* parallelism */, nilMetricsRecorderBuilder(false))
+	require.ErrorContains(t, err, "this sink is incompatible with webhook_compression=zstd")
+}
@@ -10,6 +10,7 @@ package changefeedccl
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -32,9 +33,10 @@ import (
 )
 
 const (
-	applicationTypeJSON = `application/json`
-	applicationTypeCSV  = `text/csv`
-	authorizationHeader = `Authorization`
+	applicationTypeJSON   = `application/json`
+	applicationTypeCSV    = `text/csv`
+	authorizationHeader   = `Authorization`
+	contentEncodingHeader = `Content-Encoding`
 )
 
 func isWebhookSink(u *url.URL) bool {
@@ -54,6 +56,9 @@ type webhookSinkClient struct {
 	authHeader string
 	batchCfg   sinkBatchConfig
 	client     *httputil.Client
+	// compression, if enabled, is the algorithm used to compress request
+	// bodies. The endpoint is expected to accept it; it is not negotiated.
+	compression compressionAlgo
 }
 
 var _ SinkClient = (*webhookSinkClient)(nil)
@@ -82,6 +87,11 @@ func makeWebhookSinkClient(
 		batchCfg:   batchCfg,
 	}
 
+	sinkClient.compression, err = webhookCompressionFromOpts(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	var connTimeout time.Duration
 	if opts.ClientTimeout != nil {
 		connTimeout = *opts.ClientTimeout
@@ -180,7 +190,43 @@ func makeWebhookClient(
 	return client, nil
 }
 
+// webhookCompressionFromOpts returns the algorithm the webhook sink should
+// use to compress request bodies, if any.
+func webhookCompressionFromOpts(opts changefeedbase.WebhookSinkOptions) (compressionAlgo, error) {
+	if opts.Compression == "" {
+		return "", nil
+	}
+	if !strings.EqualFold(opts.Compression, string(sinkCompressionGzip)) {
+		return "", errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptWebhookCompression, opts.Compression)
+	}
+	return sinkCompressionGzip, nil
+}
+
+// compressWebhookBody compresses a webhook request body with the specified
+// algorithm.
+func compressWebhookBody(algo compressionAlgo, body []byte) ([]byte, error) {
+	if algo != sinkCompressionGzip {
+		return nil, errors.AssertionFailedf("unsupported webhook compression algorithm %q", algo)
+	}
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func (sc *webhookSinkClient) makePayloadForBytes(body []byte) (SinkPayload, error) {
+	if sc.compression.enabled() {
+		var err error
+		if body, err = compressWebhookBody(sc.compression, body); err != nil {
+			return nil, err
+		}
+	}
 	req, err := http.NewRequestWithContext(sc.ctx, http.MethodPost, sc.url.String(), bytes.NewReader(body))
 	if err != nil {
 		return nil, err
@@ -192,6 +238,10 @@ func (sc *webhookSinkClient) makePayloadForBytes(body []byte) (SinkPayload, erro
 		req.Header.Set("Content-Type", applicationTypeCSV)
 	}
 
+	if sc.compression.enabled() {
+		req.Header.Set(contentEncodingHeader, string(sc.compression))
+	}
+
 	if sc.authHeader != "" {
 		req.Header.Set(authorizationHeader, sc.authHeader)
 	}
@@ -65,6 +65,7 @@ import (
 	"github.com/cockroachdb/redact"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v4"
+	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
 	"google.golang.org/api/option"
@@ -1786,10 +1787,15 @@ func (s *fakeKafkaSinkV2) Dial() error {
 			if m.Key != nil {
 				key = sarama.ByteEncoder(m.Key)
 			}
+			var headers []sarama.RecordHeader
+			for _, h := range m.Headers {
+				headers = append(headers, sarama.RecordHeader{Key: []byte(h.Key), Value: h.Value})
+			}
 			s.feedCh <- &sarama.ProducerMessage{
 				Topic:     m.Topic,
 				Key:       key,
 				Value:     sarama.ByteEncoder(m.Value),
+				Headers:   headers,
 				Partition: m.Partition,
 			}
 		}
@@ -1988,8 +1994,9 @@ func (k *kafkaFeed) Next() (*cdctest.TestFeedMessage, error) {
 		}
 
 		fm := &cdctest.TestFeedMessage{
-			Topic:     msg.Topic,
-			Partition: `kafka`, // TODO(yevgeniy): support multiple partitions.
+			Topic:      msg.Topic,
+			Partition:  `kafka`, // TODO(yevgeniy): support multiple partitions.
+			RawMessage: msg,
 		}
 
 		decode := func(encoded sarama.Encoder, dest *[]byte) error {
@@ -2324,6 +2331,497 @@ func (f *webhookFeed) Close() error {
 	return nil
 }
 
+type syslogFeedFactory struct {
+	enterpriseFeedFactory
+	useTLS bool
+}
+
+var _ cdctest.TestFeedFactory = (*syslogFeedFactory)(nil)
+
+// makeSyslogFeedFactory returns a TestFeedFactory implementation using the `syslog` uri.
+func makeSyslogFeedFactory(srvOrCluster interface{}, rootDB *gosql.DB) cdctest.TestFeedFactory {
+	s, injectables := getInjectables(srvOrCluster)
+	return &syslogFeedFactory{
+		enterpriseFeedFactory: enterpriseFeedFactory{
+			s:      s,
+			db:     rootDB,
+			rootDB: rootDB,
+			di:     newDepInjector(injectables...),
+		},
+		useTLS: rand.Float32() < 0.5,
+	}
+}
+
+// Feed implements cdctest.TestFeedFactory
+func (f *syslogFeedFactory) Feed(create string, args ...interface{}) (cdctest.TestFeed, error) {
+	parsed, err := parser.ParseOne(create)
+	if err != nil {
+		return nil, err
+	}
+	createStmt := parsed.AST.(*tree.CreateChangefeed)
+
+	var sinkDest *cdctest.MockSyslogSink
+	var uri string
+	if f.useTLS {
+		cert, _, err := cdctest.NewCACertBase64Encoded()
+		if err != nil {
+			return nil, err
+		}
+		if sinkDest, err = cdctest.StartMockSyslogSink(cert); err != nil {
+			return nil, err
+		}
+		uri = fmt.Sprintf("%s://%s?tls_enabled=true&insecure_tls_skip_verify=true",
+			changefeedbase.SinkSchemeSyslog, sinkDest.Addr())
+	} else {
+		if sinkDest, err = cdctest.StartMockSyslogSink(nil /* certificate */); err != nil {
+			return nil, err
+		}
+		uri = fmt.Sprintf("%s://%s", changefeedbase.SinkSchemeSyslog, sinkDest.Addr())
+	}
+	if err := setURI(createStmt, uri, true, &args); err != nil {
+		sinkDest.Close()
+		return nil, err
+	}
+
+	ss := &sinkSynchronizer{}
+	wrapSink := func(s Sink) Sink {
+		return &notifyFlushSink{Sink: s, sync: ss}
+	}
+
+	c := &syslogFeed{
+		jobFeed:        newJobFeed(f.jobsTableConn(), wrapSink),
+		seenTrackerMap: make(map[string]struct{}),
+		ss:             ss,
+		mockSink:       sinkDest,
+	}
+	if err := f.startFeedJob(c.jobFeed, tree.AsStringWithFlags(createStmt, tree.FmtShowPasswords), args...); err != nil {
+		sinkDest.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Server implements TestFeedFactory
+func (f *syslogFeedFactory) Server() serverutils.ApplicationLayerInterface {
+	return f.s
+}
+
+type syslogFeed struct {
+	*jobFeed
+	seenTrackerMap
+	ss       *sinkSynchronizer
+	mockSink *cdctest.MockSyslogSink
+}
+
+var _ cdctest.TestFeed = (*syslogFeed)(nil)
+
+// Partitions implements TestFeed
+func (f *syslogFeed) Partitions() []string {
+	return []string{``}
+}
+
+// Next implements TestFeed
+func (f *syslogFeed) Next() (*cdctest.TestFeedMessage, error) {
+	for {
+		if msg := f.mockSink.Pop(); msg != "" {
+			parsed, err := cdctest.ParseSyslogMessage(msg)
+			if err != nil {
+				return nil, err
+			}
+			params := parsed.StructuredData[syslogSDID]
+			m := &cdctest.TestFeedMessage{
+				Topic:      params[`topic`],
+				RawMessage: parsed,
+			}
+			if parsed.MsgID == syslogMsgIDResolved {
+				m.Resolved = parsed.Msg
+				return m, nil
+			}
+			m.Key, m.Value = []byte(params[`key`]), parsed.Msg
+			if isNew := f.markSeen(m); !isNew {
+				continue
+			}
+			return m, nil
+		}
+		if err := f.mockSink.Err(); err != nil {
+			return nil, err
+		}
+
+		if err := timeutil.RunWithTimeout(
+			context.Background(), timeoutOp("syslog.Next", f.jobID), timeout(),
+			func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-f.ss.eventReady():
+					return nil
+				case <-f.mockSink.NotifyMessage():
+					return nil
+				case <-f.shutdown:
+					return f.terminalJobError()
+				}
+			},
+		); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Close implements TestFeed
+func (f *syslogFeed) Close() error {
+	err := f.jobFeed.Close()
+	if err != nil {
+		return err
+	}
+	f.mockSink.Close()
+	return nil
+}
+
+type unixFeedFactory struct {
+	enterpriseFeedFactory
+}
+
+var _ cdctest.TestFeedFactory = (*unixFeedFactory)(nil)
+
+// makeUnixFeedFactory returns a TestFeedFactory implementation using the `unix` uri.
+func makeUnixFeedFactory(srvOrCluster interface{}, rootDB *gosql.DB) cdctest.TestFeedFactory {
+	s, injectables := getInjectables(srvOrCluster)
+	return &unixFeedFactory{
+		enterpriseFeedFactory: enterpriseFeedFactory{
+			s:      s,
+			db:     rootDB,
+			rootDB: rootDB,
+			di:     newDepInjector(injectables...),
+		},
+	}
+}
+
+// Feed implements cdctest.TestFeedFactory
+func (f *unixFeedFactory) Feed(create string, args ...interface{}) (cdctest.TestFeed, error) {
+	parsed, err := parser.ParseOne(create)
+	if err != nil {
+		return nil, err
+	}
+	createStmt := parsed.AST.(*tree.CreateChangefeed)
+
+	sinkDest, err := cdctest.StartMockUnixSink()
+	if err != nil {
+		return nil, err
+	}
+	uri := fmt.Sprintf("%s://%s", changefeedbase.SinkSchemeUnix, sinkDest.Path())
+	if err := setURI(createStmt, uri, true, &args); err != nil {
+		sinkDest.Close()
+		return nil, err
+	}
+
+	ss := &sinkSynchronizer{}
+	wrapSink := func(s Sink) Sink {
+		return &notifyFlushSink{Sink: s, sync: ss}
+	}
+
+	c := &unixFeed{
+		jobFeed:        newJobFeed(f.jobsTableConn(), wrapSink),
+		seenTrackerMap: make(map[string]struct{}),
+		ss:             ss,
+		mockSink:       sinkDest,
+	}
+	if err := f.startFeedJob(c.jobFeed, tree.AsStringWithFlags(createStmt, tree.FmtShowPasswords), args...); err != nil {
+		sinkDest.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Server implements TestFeedFactory
+func (f *unixFeedFactory) Server() serverutils.ApplicationLayerInterface {
+	return f.s
+}
+
+type unixFeed struct {
+	*jobFeed
+	seenTrackerMap
+	ss       *sinkSynchronizer
+	mockSink *cdctest.MockUnixSink
+}
+
+var _ cdctest.TestFeed = (*unixFeed)(nil)
+
+// Partitions implements TestFeed
+func (f *unixFeed) Partitions() []string {
+	return []string{``}
+}
+
+// Next implements TestFeed
+func (f *unixFeed) Next() (*cdctest.TestFeedMessage, error) {
+	for {
+		if msg, ok := f.mockSink.Pop(); ok {
+			m := &cdctest.TestFeedMessage{
+				Topic:      msg.Topic,
+				RawMessage: msg,
+			}
+			if len(msg.Key) == 0 {
+				m.Resolved = msg.Value
+				return m, nil
+			}
+			m.Key, m.Value = msg.Key, msg.Value
+			if isNew := f.markSeen(m); !isNew {
+				continue
+			}
+			return m, nil
+		}
+
+		if err := timeutil.RunWithTimeout(
+			context.Background(), timeoutOp("unix.Next", f.jobID), timeout(),
+			func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-f.ss.eventReady():
+					return nil
+				case <-f.mockSink.NotifyMessage():
+					return nil
+				case <-f.shutdown:
+					return f.terminalJobError()
+				}
+			},
+		); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Close implements TestFeed
+func (f *unixFeed) Close() error {
+	err := f.jobFeed.Close()
+	if err != nil {
+		return err
+	}
+	f.mockSink.Close()
+	return nil
+}
+
+type clickhouseFeedFactory struct {
+	enterpriseFeedFactory
+}
+
+var _ cdctest.TestFeedFactory = (*clickhouseFeedFactory)(nil)
+
+// makeClickHouseFeedFactory returns a TestFeedFactory implementation using the `clickhouse` uri.
+func makeClickHouseFeedFactory(srvOrCluster interface{}, rootDB *gosql.DB) cdctest.TestFeedFactory {
+	s, injectables := getInjectables(srvOrCluster)
+	return &clickhouseFeedFactory{
+		enterpriseFeedFactory: enterpriseFeedFactory{
+			s:      s,
+			db:     rootDB,
+			rootDB: rootDB,
+			di:     newDepInjector(injectables...),
+		},
+	}
+}
+
+// Feed implements cdctest.TestFeedFactory
+func (f *clickhouseFeedFactory) Feed(create string, args ...interface{}) (cdctest.TestFeed, error) {
+	parsed, err := parser.ParseOne(create)
+	if err != nil {
+		return nil, err
+	}
+	createStmt := parsed.AST.(*tree.CreateChangefeed)
+
+	// The sink requires the diff option. The statement is used as is, so
+	// tests of this sink must specify it.
+	hasDiff := false
+	for _, opt := range createStmt.Options {
+		if opt.Key == changefeedbase.OptDiff {
+			hasDiff = true
+		}
+	}
+	if !hasDiff {
+		return nil, errors.Newf(`clickhouse test feeds must specify %s`, changefeedbase.OptDiff)
+	}
+
+	sinkDest := cdctest.StartMockClickHouseSink()
+	uri := fmt.Sprintf("%s://%s", changefeedbase.SinkSchemeClickHouse, sinkDest.Host())
+	if err := setURI(createStmt, uri, true, &args); err != nil {
+		sinkDest.Close()
+		return nil, err
+	}
+
+	ss := &sinkSynchronizer{}
+	messages := &clickhouseMessages{}
+	wrapSink := func(s Sink) Sink {
+		return &clickhouseRecordingSink{
+			notifyFlushSink: notifyFlushSink{Sink: s, sync: ss},
+			messages:        messages,
+		}
+	}
+
+	c := &clickhouseFeed{
+		jobFeed:        newJobFeed(f.jobsTableConn(), wrapSink),
+		seenTrackerMap: make(map[string]struct{}),
+		ss:             ss,
+		messages:       messages,
+		mockSink:       sinkDest,
+	}
+	if err := f.startFeedJob(c.jobFeed, tree.AsStringWithFlags(createStmt, tree.FmtShowPasswords), args...); err != nil {
+		sinkDest.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Server implements TestFeedFactory
+func (f *clickhouseFeedFactory) Server() serverutils.ApplicationLayerInterface {
+	return f.s
+}
+
+// clickhouseMessages holds the messages of a clickhouse test feed.
+type clickhouseMessages struct {
+	syncutil.Mutex
+	inserted []*cdctest.TestFeedMessage
+}
+
+func (m *clickhouseMessages) add(msgs ...*cdctest.TestFeedMessage) {
+	m.Lock()
+	defer m.Unlock()
+	m.inserted = append(m.inserted, msgs...)
+}
+
+func (m *clickhouseMessages) pop() *cdctest.TestFeedMessage {
+	m.Lock()
+	defer m.Unlock()
+	if len(m.inserted) == 0 {
+		return nil
+	}
+	msg := m.inserted[0]
+	m.inserted = m.inserted[1:]
+	return msg
+}
+
+// clickhouseRecordingSink records the rows emitted to a clickhouse sink, and
+// makes them messages of the test feed once the sink has inserted them. The
+// resolved timestamps, which the sink doesn't insert, are recorded as well.
+type clickhouseRecordingSink struct {
+	notifyFlushSink
+	messages *clickhouseMessages
+	// pending holds the rows emitted since the last flush.
+	pending []*cdctest.TestFeedMessage
+}
+
+// EmitRow implements the Sink interface.
+func (s *clickhouseRecordingSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	table, err := s.Sink.(*clickhouseSink).topicNamer.Name(topic)
+	if err != nil {
+		return err
+	}
+	m := &cdctest.TestFeedMessage{
+		Topic: table,
+		Key:   append([]byte(nil), key...),
+		Value: append([]byte(nil), value...),
+	}
+	if err := s.Sink.EmitRow(ctx, topic, key, value, updated, mvcc, alloc); err != nil {
+		return err
+	}
+	s.pending = append(s.pending, m)
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *clickhouseRecordingSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	if err := s.Sink.EmitResolvedTimestamp(ctx, encoder, resolved); err != nil {
+		return err
+	}
+	s.messages.add(s.pending...)
+	s.pending = nil
+	for _, topic := range s.Sink.(*clickhouseSink).Topics() {
+		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+		if err != nil {
+			return err
+		}
+		s.messages.add(&cdctest.TestFeedMessage{Topic: topic, Resolved: payload})
+	}
+	s.sync.addFlush()
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (s *clickhouseRecordingSink) Flush(ctx context.Context) error {
+	defer s.sync.addFlush()
+	if err := s.Sink.Flush(ctx); err != nil {
+		return err
+	}
+	s.messages.add(s.pending...)
+	s.pending = nil
+	return nil
+}
+
+// clickhouseFeed is the TestFeed of a clickhouse sink. Its messages are the
+// rows emitted to the sink, along with its resolved timestamps, once the sink
+// has inserted them into ClickHouse. The rows inserted into the mock
+// ClickHouse server are available from mockSink.
+type clickhouseFeed struct {
+	*jobFeed
+	seenTrackerMap
+	ss       *sinkSynchronizer
+	messages *clickhouseMessages
+	mockSink *cdctest.MockClickHouseSink
+}
+
+var _ cdctest.TestFeed = (*clickhouseFeed)(nil)
+
+// Partitions implements TestFeed
+func (f *clickhouseFeed) Partitions() []string {
+	return []string{``}
+}
+
+// Next implements TestFeed
+func (f *clickhouseFeed) Next() (*cdctest.TestFeedMessage, error) {
+	for {
+		if m := f.messages.pop(); m != nil {
+			if m.Resolved != nil {
+				return m, nil
+			}
+			if isNew := f.markSeen(m); !isNew {
+				continue
+			}
+			return m, nil
+		}
+
+		if err := timeutil.RunWithTimeout(
+			context.Background(), timeoutOp("clickhouse.Next", f.jobID), timeout(),
+			func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-f.ss.eventReady():
+					return nil
+				case <-f.shutdown:
+					return f.terminalJobError()
+				}
+			},
+		); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Close implements TestFeed
+func (f *clickhouseFeed) Close() error {
+	err := f.jobFeed.Close()
+	if err != nil {
+		return err
+	}
+	f.mockSink.Close()
+	return nil
+}
+
 type mockPubsubMessage struct {
 	data string
 	// attributes are only populated for the non-deprecated pubsub sink.
@@ -2960,3 +3458,321 @@ func stopFeedWhenDone(ctx context.Context, f cdctest.TestFeed) func() {
 		wg.Wait()
 	}
 }
+
+// mockAMQPMessage is a message published to a mockAMQPBroker.
+type mockAMQPMessage struct {
+	Exchange   string
+	RoutingKey string
+	amqp.Publishing
+}
+
+// mockAMQPBroker stands in for an AMQP broker, storing the messages published
+// on its channels to the exchanges declared on it.
+type mockAMQPBroker struct {
+	mu struct {
+		syncutil.Mutex
+		exchanges map[string]struct{}
+		reject    bool
+		messages  []mockAMQPMessage
+		notify    chan struct{}
+	}
+}
+
+func makeMockAMQPBroker(exchanges ...string) *mockAMQPBroker {
+	b := &mockAMQPBroker{}
+	// The default exchange always exists.
+	b.mu.exchanges = map[string]struct{}{``: {}}
+	for _, exchange := range exchanges {
+		b.mu.exchanges[exchange] = struct{}{}
+	}
+	return b
+}
+
+// deleteExchange deletes an exchange, so that publishing to it closes the
+// channel, as RabbitMQ does.
+func (b *mockAMQPBroker) deleteExchange(exchange string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.mu.exchanges, exchange)
+}
+
+// setReject sets whether the messages published are rejected.
+func (b *mockAMQPBroker) setReject(reject bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mu.reject = reject
+}
+
+// pop returns the oldest message published, if any.
+func (b *mockAMQPBroker) pop() (mockAMQPMessage, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.mu.messages) == 0 {
+		return mockAMQPMessage{}, false
+	}
+	oldest := b.mu.messages[0]
+	b.mu.messages = b.mu.messages[1:]
+	return oldest, true
+}
+
+// notifyMessage arranges for channel to be closed when message arrives.
+func (b *mockAMQPBroker) notifyMessage() chan struct{} {
+	c := make(chan struct{})
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.mu.messages) > 0 {
+		close(c)
+	} else {
+		b.mu.notify = c
+	}
+	return c
+}
+
+// publish stores a message, and returns whether it was accepted, or the error
+// with which the channel is closed.
+func (b *mockAMQPBroker) publish(m mockAMQPMessage) (bool, *amqp.Error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.mu.exchanges[m.Exchange]; !ok {
+		return false, &amqp.Error{
+			Code:   amqp.NotFound,
+			Reason: fmt.Sprintf(`NOT_FOUND - no exchange '%s' in vhost '/'`, m.Exchange),
+			Server: true,
+		}
+	}
+	if b.mu.reject {
+		return false, nil
+	}
+	b.mu.messages = append(b.mu.messages, m)
+	if b.mu.notify != nil {
+		close(b.mu.notify)
+		b.mu.notify = nil
+	}
+	return true, nil
+}
+
+// mockAMQPChannel is a channel, in confirm mode, of a mockAMQPBroker.
+type mockAMQPChannel struct {
+	broker *mockAMQPBroker
+	mu     struct {
+		syncutil.Mutex
+		published uint64
+		confirms  []chan amqp.Confirmation
+		closes    []chan *amqp.Error
+		closed    bool
+	}
+}
+
+var _ AMQPChannel = (*mockAMQPChannel)(nil)
+
+// PublishWithContext implements the AMQPChannel interface.
+func (c *mockAMQPChannel) PublishWithContext(
+	_ context.Context, exchange, key string, _, _ bool, msg amqp.Publishing,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.closed {
+		return amqp.ErrClosed
+	}
+	c.mu.published++
+	ack, closeErr := c.broker.publish(mockAMQPMessage{Exchange: exchange, RoutingKey: key, Publishing: msg})
+	if closeErr != nil {
+		// The broker closes the channel asynchronously, after the message has
+		// been published.
+		c.closeLocked(closeErr)
+		return nil
+	}
+	for _, confirms := range c.mu.confirms {
+		confirms <- amqp.Confirmation{DeliveryTag: c.mu.published, Ack: ack}
+	}
+	return nil
+}
+
+// GetNextPublishSeqNo implements the AMQPChannel interface.
+func (c *mockAMQPChannel) GetNextPublishSeqNo() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.published + 1
+}
+
+// NotifyPublish implements the AMQPChannel interface.
+func (c *mockAMQPChannel) NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.closed {
+		close(confirm)
+	} else {
+		c.mu.confirms = append(c.mu.confirms, confirm)
+	}
+	return confirm
+}
+
+// NotifyClose implements the AMQPChannel interface.
+func (c *mockAMQPChannel) NotifyClose(closes chan *amqp.Error) chan *amqp.Error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.closed {
+		close(closes)
+	} else {
+		c.mu.closes = append(c.mu.closes, closes)
+	}
+	return closes
+}
+
+// Close implements the AMQPChannel interface.
+func (c *mockAMQPChannel) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.closed {
+		return amqp.ErrClosed
+	}
+	c.closeLocked(nil)
+	return nil
+}
+
+// closeLocked closes the channel, notifying the listeners of the error it
+// was closed with, if any, in the order amqp.Channel does.
+func (c *mockAMQPChannel) closeLocked(err *amqp.Error) {
+	c.mu.closed = true
+	for _, closes := range c.mu.closes {
+		if err != nil {
+			closes <- err
+		}
+		close(closes)
+	}
+	for _, confirms := range c.mu.confirms {
+		close(confirms)
+	}
+	c.mu.closes, c.mu.confirms = nil, nil
+}
+
+type amqpFeedFactory struct {
+	enterpriseFeedFactory
+}
+
+var _ cdctest.TestFeedFactory = (*amqpFeedFactory)(nil)
+
+// amqpFeedExchange is the exchange the changefeeds of amqpFeedFactory publish
+// to.
+const amqpFeedExchange = `changefeed`
+
+// makeAMQPFeedFactory returns a TestFeedFactory implementation using the `amqp` uri.
+func makeAMQPFeedFactory(srvOrCluster interface{}, rootDB *gosql.DB) cdctest.TestFeedFactory {
+	s, injectables := getInjectables(srvOrCluster)
+
+	switch t := srvOrCluster.(type) {
+	case serverutils.ApplicationLayerInterface:
+		t.DistSQLServer().(*distsql.ServerImpl).TestingKnobs.Changefeed.(*TestingKnobs).AMQPChannelSkipCreation = true
+	case serverutils.TestClusterInterface:
+		for i := 0; i < t.NumServers(); i++ {
+			t.Server(i).DistSQLServer().(*distsql.ServerImpl).TestingKnobs.Changefeed.(*TestingKnobs).AMQPChannelSkipCreation = true
+		}
+	}
+
+	return &amqpFeedFactory{
+		enterpriseFeedFactory: enterpriseFeedFactory{
+			s:      s,
+			db:     rootDB,
+			rootDB: rootDB,
+			di:     newDepInjector(injectables...),
+		},
+	}
+}
+
+// Feed implements cdctest.TestFeedFactory
+func (f *amqpFeedFactory) Feed(create string, args ...interface{}) (cdctest.TestFeed, error) {
+	parsed, err := parser.ParseOne(create)
+	if err != nil {
+		return nil, err
+	}
+	createStmt := parsed.AST.(*tree.CreateChangefeed)
+
+	uri := fmt.Sprintf("%s://testfeed?%s=%s", changefeedbase.SinkSchemeAMQP,
+		changefeedbase.SinkParamAMQPExchange, amqpFeedExchange)
+	if err := setURI(createStmt, uri, true, &args); err != nil {
+		return nil, err
+	}
+
+	broker := makeMockAMQPBroker(amqpFeedExchange)
+	ss := &sinkSynchronizer{}
+	wrapSink := func(s Sink) Sink {
+		s.(*amqpSink).initChannel(&mockAMQPChannel{broker: broker})
+		return &notifyFlushSink{Sink: s, sync: ss}
+	}
+
+	c := &amqpFeed{
+		jobFeed:        newJobFeed(f.jobsTableConn(), wrapSink),
+		seenTrackerMap: make(map[string]struct{}),
+		ss:             ss,
+		broker:         broker,
+	}
+	if err := f.startFeedJob(c.jobFeed, tree.AsStringWithFlags(createStmt, tree.FmtShowPasswords), args...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Server implements TestFeedFactory
+func (f *amqpFeedFactory) Server() serverutils.ApplicationLayerInterface {
+	return f.s
+}
+
+type amqpFeed struct {
+	*jobFeed
+	seenTrackerMap
+	ss     *sinkSynchronizer
+	broker *mockAMQPBroker
+}
+
+var _ cdctest.TestFeed = (*amqpFeed)(nil)
+
+// Partitions implements TestFeed
+func (f *amqpFeed) Partitions() []string {
+	return []string{``}
+}
+
+// Next implements TestFeed
+func (f *amqpFeed) Next() (*cdctest.TestFeedMessage, error) {
+	for {
+		if msg, ok := f.broker.pop(); ok {
+			topic, _ := msg.Headers[`topic`].(string)
+			m := &cdctest.TestFeedMessage{
+				Topic:      topic,
+				RawMessage: msg,
+			}
+			if msg.RoutingKey == amqpDefaultResolvedRoutingKey {
+				m.Resolved = msg.Body
+				return m, nil
+			}
+			key, _ := msg.Headers[`key`].(string)
+			m.Key, m.Value = []byte(key), msg.Body
+			if isNew := f.markSeen(m); !isNew {
+				continue
+			}
+			return m, nil
+		}
+
+		if err := timeutil.RunWithTimeout(
+			context.Background(), timeoutOp("amqp.Next", f.jobID), timeout(),
+			func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-f.ss.eventReady():
+					return nil
+				case <-f.broker.notifyMessage():
+					return nil
+				case <-f.shutdown:
+					return f.terminalJobError()
+				}
+			},
+		); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Close implements TestFeed
+func (f *amqpFeed) Close() error {
+	return f.jobFeed.Close()
+}
@@ -25,6 +25,9 @@ import (
 type TestingKnobs struct {
 	// BeforeEmitRow is called before every sink emit row operation.
 	BeforeEmitRow func(context.Context) error
+	// BeforeEncodeRow is called before every row is encoded. Errors it returns
+	// are treated as errors encoding the row.
+	BeforeEncodeRow func(context.Context) error
 	// MemMonitor, if non-nil, overrides memory monitor to use for changefeed..
 	MemMonitor *mon.BytesMonitor
 	// BeforeDistChangefeed invoked before dist changefeed starts.
@@ -91,6 +94,10 @@ type TestingKnobs struct {
 	// dialing.
 	PulsarClientSkipCreation bool
 
+	// AMQPChannelSkipCreation skips connecting to the broker, and creating the
+	// channel of the amqp sink, when dialing.
+	AMQPChannelSkipCreation bool
+
 	// TimeSource is used to override the time source used by the changefeed (currently only used by the usage metric goroutine).
 	TimeSource timeutil.TimeSource
 
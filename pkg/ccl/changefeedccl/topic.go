@@ -43,6 +43,9 @@ type TopicDescriptor interface {
 type TopicIdentifier struct {
 	TableID  descpb.ID
 	FamilyID descpb.FamilyID
+	// Deletes is set for the topic receiving the deletes of the table (or
+	// family) when they are routed to a topic of their own.
+	Deletes bool
 }
 
 // TopicNamer generates and caches the strings used as topic keys by sinks,
@@ -52,6 +55,10 @@ type TopicNamer struct {
 	prefix     string
 	singleName string
 	sanitize   func(string) string
+	// overrides maps statement time names to the names which replace them.
+	overrides map[string]string
+	// deleteSuffix, if set, is appended to the names of the topics of deletes.
+	deleteSuffix string
 
 	// DisplayNames are initialized once from specs and may contain placeholder strings.
 	DisplayNames map[changefeedbase.Target]string
@@ -113,9 +120,34 @@ func WithSanitizeFn(fn func(string) string) TopicNameOption {
 	return optSanitize(fn)
 }
 
+type optOverrides map[string]string
+
+func (o optOverrides) set(tn *TopicNamer) {
+	tn.overrides = o
+}
+
+// WithOverrides replaces the statement time names of the tables in the given
+// map with the names they map to. Several tables may share the same name.
+func WithOverrides(overrides map[string]string) TopicNameOption {
+	return optOverrides(overrides)
+}
+
+type optDeleteSuffix string
+
+func (o optDeleteSuffix) set(tn *TopicNamer) {
+	tn.deleteSuffix = string(o)
+}
+
+// WithDeleteSuffix causes the topics of deletes to be named after the topics
+// of the other events with the given suffix appended.
+func WithDeleteSuffix(s string) TopicNameOption {
+	return optDeleteSuffix(s)
+}
+
 // MakeTopicNamer creates a TopicNamer.
 // specs are used to populate DisplayNames and the values iterated over in Each.
-// Add options using WithJoinByte, WithPrefix, WithSingleName, and/or WithSanitizeFn.
+// Add options using WithJoinByte, WithPrefix, WithSingleName, WithOverrides,
+// WithDeleteSuffix, and/or WithSanitizeFn.
 func MakeTopicNamer(targets changefeedbase.Targets, opts ...TopicNameOption) (*TopicNamer, error) {
 	tn := &TopicNamer{
 		join:         '.',
@@ -146,6 +178,9 @@ func (tn *TopicNamer) Name(td TopicDescriptor) (string, error) {
 		return name, nil
 	}
 	name, err := tn.makeName(td.GetTargetSpecification(), td)
+	if td.GetTopicIdentifier().Deletes {
+		name = tn.deleteName(name)
+	}
 	tn.FullNames[td.GetTopicIdentifier()] = name
 	return name, err
 }
@@ -158,6 +193,9 @@ func (tn *TopicNamer) DisplayNamesSlice() []string {
 	}
 	for _, n := range tn.DisplayNames {
 		tn.sliceCache = append(tn.sliceCache, n)
+		if tn.deleteSuffix != "" {
+			tn.sliceCache = append(tn.sliceCache, tn.deleteName(n))
+		}
 		if tn.singleName != "" {
 			return tn.sliceCache
 		}
@@ -169,6 +207,9 @@ func (tn *TopicNamer) DisplayNamesSlice() []string {
 func (tn *TopicNamer) Each(fn func(string) error) error {
 	for _, name := range tn.DisplayNames {
 		err := fn(name)
+		if err == nil && tn.deleteSuffix != "" {
+			err = fn(tn.deleteName(name))
+		}
 		if tn.singleName != "" || err != nil {
 			return err
 		}
@@ -176,6 +217,15 @@ func (tn *TopicNamer) Each(fn func(string) error) error {
 	return nil
 }
 
+// deleteName returns the name of the topic of the deletes emitted to the
+// given topic.
+func (tn *TopicNamer) deleteName(name string) string {
+	if tn.sanitize != nil {
+		return name + tn.sanitize(tn.deleteSuffix)
+	}
+	return name + tn.deleteSuffix
+}
+
 // A nil topic descriptor means we're building solely from the spec
 // and should use placeholders if necessary. Only necessary in the
 // EACH_FAMILY case as in the COLUMN_FAMILY case we know the name from
@@ -211,6 +261,9 @@ func (tn *TopicNamer) nameFromComponents(
 	if tn.singleName != "" {
 		b.WriteString(tn.singleName)
 	} else {
+		if override, ok := tn.overrides[string(name)]; ok {
+			name = changefeedbase.StatementTimeName(override)
+		}
 		b.WriteString(string(name))
 		for _, c := range components {
 			b.WriteByte(tn.join)
@@ -303,6 +356,21 @@ func (cft *columnFamilyTopic) GetTableName() string {
 
 var _ TopicDescriptor = &columnFamilyTopic{}
 
+// deletesTopic is the topic of the deletes of the wrapped topic, when they
+// are routed to a topic of their own.
+type deletesTopic struct {
+	TopicDescriptor
+}
+
+// GetTopicIdentifier implements the TopicDescriptor interface
+func (dt deletesTopic) GetTopicIdentifier() TopicIdentifier {
+	id := dt.TopicDescriptor.GetTopicIdentifier()
+	id.Deletes = true
+	return id
+}
+
+var _ TopicDescriptor = deletesTopic{}
+
 type noTopic struct{}
 
 var noStatementTimeName changefeedbase.StatementTimeName = ""
@@ -24,6 +24,11 @@ var proxyContext sqlproxyccl.ProxyOptions
 
 func setProxyContextDefaults() {
 	proxyContext.Denylist = ""
+	proxyContext.ConnectionTracingFile = ""
+	proxyContext.DisallowedStartupParams = nil
+	proxyContext.ClusterIdentifierParam = ""
+	proxyContext.RoutingTagParamPrefix = ""
+	proxyContext.NodeID = ""
 	proxyContext.ListenAddr = "127.0.0.1:46257"
 	proxyContext.ListenCert = ""
 	proxyContext.ListenKey = ""
@@ -36,7 +41,22 @@ func setProxyContextDefaults() {
 	proxyContext.ValidateAccessInterval = 30 * time.Second
 	proxyContext.PollConfigInterval = 30 * time.Second
 	proxyContext.ThrottleBaseDelay = time.Second
+	proxyContext.ConnectionRateLimit = 0
+	proxyContext.ConnectionRateBurst = 1
+	proxyContext.ShutdownDrainTimeout = 0
+	proxyContext.KeepAliveInterval = 0
+	proxyContext.BackendDialTimeout = 5 * time.Second
+	proxyContext.BackendBreakerThreshold = 0
+	proxyContext.BackendBreakerWindow = time.Minute
+	proxyContext.BackendBreakerCooldown = 10 * time.Second
+	proxyContext.MaxConcurrentHandshakes = 0
+	proxyContext.HandshakeQueueTimeout = 0
+	proxyContext.HandshakeAdmitTimeout = 10 * time.Second
+	proxyContext.SlowHandshakeThreshold = 0
+	proxyContext.TerminateDeletedTenantConnections = false
 	proxyContext.DisableConnectionRebalancing = false
+	proxyContext.CanaryPodVersion = ""
+	proxyContext.CanaryPodPercent = 0
 	proxyContext.RequireProxyProtocol = false
 }
 
@@ -68,6 +68,11 @@ func init() {
 		f := mtStartSQLProxyCmd.Flags()
 		cliflagcfg.StringFlag(f, &proxyContext.Denylist, cliflags.DenyList)
 		cliflagcfg.StringFlag(f, &proxyContext.Allowlist, cliflags.AllowList)
+		cliflagcfg.StringFlag(f, &proxyContext.ConnectionTracingFile, cliflags.ConnectionTracingFile)
+		cliflagcfg.StringSliceFlag(f, &proxyContext.DisallowedStartupParams, cliflags.DisallowedStartupParams)
+		cliflagcfg.StringFlag(f, &proxyContext.ClusterIdentifierParam, cliflags.ClusterIdentifierParam)
+		cliflagcfg.StringFlag(f, &proxyContext.RoutingTagParamPrefix, cliflags.RoutingTagParamPrefix)
+		cliflagcfg.StringFlag(f, &proxyContext.NodeID, cliflags.ProxyNodeID)
 		cliflagcfg.StringFlag(f, &proxyContext.ListenAddr, cliflags.ProxyListenAddr)
 		cliflagcfg.StringFlag(f, &proxyContext.ProxyProtocolListenAddr, cliflags.ProxyProtocolListenAddr)
 		cliflagcfg.StringFlag(f, &proxyContext.ListenCert, cliflags.ListenCert)
@@ -80,7 +85,22 @@ func init() {
 		cliflagcfg.DurationFlag(f, &proxyContext.ValidateAccessInterval, cliflags.ValidateAccessInterval)
 		cliflagcfg.DurationFlag(f, &proxyContext.PollConfigInterval, cliflags.PollConfigInterval)
 		cliflagcfg.DurationFlag(f, &proxyContext.ThrottleBaseDelay, cliflags.ThrottleBaseDelay)
+		cliflagcfg.Float64Flag(f, &proxyContext.ConnectionRateLimit, cliflags.ConnectionRateLimit)
+		cliflagcfg.IntFlag(f, &proxyContext.ConnectionRateBurst, cliflags.ConnectionRateBurst)
+		cliflagcfg.DurationFlag(f, &proxyContext.ShutdownDrainTimeout, cliflags.ShutdownDrainTimeout)
+		cliflagcfg.DurationFlag(f, &proxyContext.KeepAliveInterval, cliflags.KeepAliveInterval)
+		cliflagcfg.DurationFlag(f, &proxyContext.BackendDialTimeout, cliflags.BackendDialTimeout)
+		cliflagcfg.IntFlag(f, &proxyContext.BackendBreakerThreshold, cliflags.BackendBreakerThreshold)
+		cliflagcfg.DurationFlag(f, &proxyContext.BackendBreakerWindow, cliflags.BackendBreakerWindow)
+		cliflagcfg.DurationFlag(f, &proxyContext.BackendBreakerCooldown, cliflags.BackendBreakerCooldown)
+		cliflagcfg.IntFlag(f, &proxyContext.MaxConcurrentHandshakes, cliflags.MaxConcurrentHandshakes)
+		cliflagcfg.DurationFlag(f, &proxyContext.HandshakeQueueTimeout, cliflags.HandshakeQueueTimeout)
+		cliflagcfg.DurationFlag(f, &proxyContext.HandshakeAdmitTimeout, cliflags.HandshakeAdmitTimeout)
+		cliflagcfg.DurationFlag(f, &proxyContext.SlowHandshakeThreshold, cliflags.SlowHandshakeThreshold)
+		cliflagcfg.BoolFlag(f, &proxyContext.TerminateDeletedTenantConnections, cliflags.TerminateDeletedTenantConnections)
 		cliflagcfg.BoolFlag(f, &proxyContext.DisableConnectionRebalancing, cliflags.DisableConnectionRebalancing)
+		cliflagcfg.StringFlag(f, &proxyContext.CanaryPodVersion, cliflags.CanaryPodVersion)
+		cliflagcfg.IntFlag(f, &proxyContext.CanaryPodPercent, cliflags.CanaryPodPercent)
 		cliflagcfg.BoolFlag(f, &proxyContext.RequireProxyProtocol, cliflags.RequireProxyProtocol)
 	}
 
@@ -10,6 +10,7 @@ package acl
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,8 +29,38 @@ type DenylistFile struct {
 // Denylist represents an in-memory cache for the current denylist.
 // It also handles the logic of deciding what to be denied.
 type Denylist struct {
-	entries    map[DenyEntity]*DenyEntry
-	timeSource timeutil.TimeSource
+	entries map[DenyEntity]*DenyEntry
+	// tenantRanges contains the cluster entries which denote a range of
+	// tenant IDs (e.g. "1000-2000") rather than a single tenant ID.
+	tenantRanges []tenantIDRange
+	timeSource   timeutil.TimeSource
+}
+
+// tenantIDRange is an inclusive range of tenant IDs denied by entry.
+type tenantIDRange struct {
+	start, end uint64
+	entry      *DenyEntry
+}
+
+// parseTenantIDRange parses a tenant ID range of the form "<start>-<end>",
+// where both bounds are inclusive. ok is false if item is not a range.
+func parseTenantIDRange(item string) (start, end uint64, ok bool, _ error) {
+	startStr, endStr, found := strings.Cut(item, "-")
+	if !found {
+		return 0, 0, false, nil
+	}
+	start, err := strconv.ParseUint(strings.TrimSpace(startStr), 10, 64)
+	if err != nil {
+		return 0, 0, false, errors.Wrapf(err, "invalid tenant ID range '%s'", item)
+	}
+	end, err = strconv.ParseUint(strings.TrimSpace(endStr), 10, 64)
+	if err != nil {
+		return 0, 0, false, errors.Wrapf(err, "invalid tenant ID range '%s'", item)
+	}
+	if start > end {
+		return 0, 0, false, errors.Newf("invalid tenant ID range '%s': start is greater than end", item)
+	}
+	return start, end, true, nil
 }
 
 var _ AccessController = &Denylist{}
@@ -42,7 +73,18 @@ func (dl *Denylist) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return err
 	}
 	dl.entries = make(map[DenyEntity]*DenyEntry)
+	dl.tenantRanges = nil
 	for _, entry := range f.Denylist {
+		if entry.Entity.Type == ClusterType {
+			start, end, ok, err := parseTenantIDRange(entry.Entity.Item)
+			if err != nil {
+				return err
+			}
+			if ok {
+				dl.tenantRanges = append(dl.tenantRanges, tenantIDRange{start: start, end: end, entry: entry})
+				continue
+			}
+		}
 		dl.entries[entry.Entity] = entry
 	}
 
@@ -59,14 +101,30 @@ func (dl *Denylist) CheckConnection(ctx context.Context, connection ConnectionTa
 	if err := dl.denied(cluster); err != nil {
 		return errors.Wrapf(err, "connection cluster '%v' denied", cluster.Item)
 	}
+	tenID := connection.TenantID.ToUint64()
+	for _, r := range dl.tenantRanges {
+		if tenID < r.start || tenID > r.end {
+			continue
+		}
+		if err := dl.deniedEntry(r.entry); err != nil {
+			return errors.Wrapf(err, "connection cluster '%v' denied", cluster.Item)
+		}
+	}
 	return nil
 }
 
 // denied returns an error if the entity is denied access. The error message
 // describes the reason for the denial.
 func (dl *Denylist) denied(entity DenyEntity) error {
-	if ent, ok := dl.entries[entity]; ok &&
-		(ent.Expiration.IsZero() || !ent.Expiration.Before(dl.timeSource.Now())) {
+	if ent, ok := dl.entries[entity]; ok {
+		return dl.deniedEntry(ent)
+	}
+	return nil
+}
+
+// deniedEntry returns an error if the entry has not expired yet.
+func (dl *Denylist) deniedEntry(ent *DenyEntry) error {
+	if ent.Expiration.IsZero() || !ent.Expiration.Before(dl.timeSource.Now()) {
 		return errors.Newf("%s", ent.Reason)
 	}
 	return nil
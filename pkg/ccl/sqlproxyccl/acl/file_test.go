@@ -227,6 +227,31 @@ denylist:
 				{ConnectionTags{"1.2.3.5", roachpb.MustMakeTenantID(100), ""}, ""},
 			},
 		},
+		// Blocks a range of tenant clusters.
+		{
+			"block_tenant_range",
+			fmt.Sprintf(`
+SequenceNumber: 11
+denylist:
+- entity: {"item": "1000-2000", "type": "Cluster"}
+  expiration: %s
+  reason: deprecated shard
+- entity: {"item": 61, "type": "Cluster"}
+  expiration: %s
+  reason: splunk pipeline`,
+				longExpirationTimeString,
+				longExpirationTimeString,
+			),
+			nil,
+			[]denyIOSpec{
+				{ConnectionTags{"1.1.1.1", roachpb.MustMakeTenantID(1000), ""}, "connection cluster '1000' denied: deprecated shard"},
+				{ConnectionTags{"1.1.1.1", roachpb.MustMakeTenantID(1500), ""}, "connection cluster '1500' denied: deprecated shard"},
+				{ConnectionTags{"1.1.1.1", roachpb.MustMakeTenantID(2000), ""}, "connection cluster '2000' denied: deprecated shard"},
+				{ConnectionTags{"1.1.1.1", roachpb.MustMakeTenantID(61), ""}, "connection cluster '61' denied: splunk pipeline"},
+				{ConnectionTags{"1.1.1.1", roachpb.MustMakeTenantID(999), ""}, ""},
+				{ConnectionTags{"1.1.1.1", roachpb.MustMakeTenantID(2001), ""}, ""},
+			},
+		},
 		// Entry without any expiration.
 		{
 			"entry_without_expiration",
@@ -11,6 +11,7 @@ package sqlproxyccl
 import (
 	"net"
 	"slices"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/interceptor"
 	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/throttler"
@@ -25,16 +26,27 @@ var pgPostAuthErrorCodes = []string{
 	pgcode.TooManyConnections.String(),
 }
 
+// authenticationGSSMsg is the encoding of an AuthenticationGSS message. It's
+// written as is, because pgproto3 encodes the message with the wrong length.
+var authenticationGSSMsg = []byte{'R', 0, 0, 0, 8, 0, 0, 0, byte(pgproto3.AuthTypeGSS)}
+
 // authenticate handles the startup of the pgwire protocol to the point where
 // the connections is considered authenticated. If that doesn't happen, it
 // returns an error.
+//
+// The responses of the client to the authentication requests of the server
+// are relayed without being decoded, so that multi-round exchanges such as
+// SCRAM-SHA-256 and GSSAPI pass through the proxy unchanged.
 var authenticate = func(
 	clientConn, crdbConn net.Conn,
 	proxyBackendKeyData *pgproto3.BackendKeyData,
 	throttleHook func(throttler.AttemptStatus) error,
-) (crdbBackendKeyData *pgproto3.BackendKeyData, _ error) {
+) (crdbBackendKeyData *pgproto3.BackendKeyData, retErr error) {
+	// fe is only used to send messages to the client. Messages from the client
+	// are read by clientMsgs.
 	fe := pgproto3.NewBackend(pgproto3.NewChunkReader(clientConn), clientConn)
 	be := pgproto3.NewFrontend(pgproto3.NewChunkReader(crdbConn), crdbConn)
+	clientMsgs := interceptor.NewBackendConn(clientConn)
 
 	feSend := func(msg pgproto3.BackendMessage) error {
 		err := fe.Send(msg)
@@ -46,6 +58,43 @@ var authenticate = func(
 		return nil
 	}
 
+	// relayClientMsg relays the next message of the client to the backend as
+	// is.
+	relayClientMsg := func() error {
+		// Peek the message first, so that errors reading from the client
+		// aren't mistaken for errors writing to the backend.
+		if _, _, err := clientMsgs.PeekMsg(); err != nil {
+			return withCode(
+				errors.Wrap(err, "unable to receive message from client"),
+				codeClientReadFailed)
+		}
+		if _, err := clientMsgs.ForwardMsg(crdbConn); err != nil {
+			return withCode(
+				errors.Wrap(err, "unable to send message to backend"),
+				codeBackendWriteFailed)
+		}
+		return nil
+	}
+
+	// gssRelay, if non-nil, relays the messages of the client to the backend
+	// during a GSSAPI exchange. Whether the client responds to an
+	// AuthenticationGSSContinue message depends on the state of its security
+	// context, which the proxy can't know without interpreting the tokens, so
+	// messages are relayed in the background until the backend ends the
+	// exchange.
+	var gssRelay *backgroundRelay
+	stopGSSRelay := func() error {
+		if gssRelay == nil {
+			return nil
+		}
+		err := gssRelay.stop()
+		gssRelay = nil
+		return err
+	}
+	defer func() {
+		retErr = errors.CombineErrors(retErr, stopGSSRelay())
+	}()
+
 	// The auth step should require only a few back and forths so 20 iterations
 	// should be enough.
 	var i int
@@ -59,6 +108,15 @@ var authenticate = func(
 				codeBackendReadFailed)
 		}
 
+		switch backendMsg.(type) {
+		case *pgproto3.AuthenticationGSS, *pgproto3.AuthenticationGSSContinue:
+		default:
+			// The backend has ended the GSSAPI exchange, if any.
+			if err := stopGSSRelay(); err != nil {
+				return nil, err
+			}
+		}
+
 		// The cases in this switch are roughly sorted in the order the server will send them.
 		switch tp := backendMsg.(type) {
 
@@ -73,32 +131,31 @@ var authenticate = func(
 			if err = feSend(backendMsg); err != nil {
 				return nil, err
 			}
-			switch backendMsg.(type) {
-			case *pgproto3.AuthenticationCleartextPassword:
-				_ = fe.SetAuthType(pgproto3.AuthTypeCleartextPassword)
-			case *pgproto3.AuthenticationMD5Password:
-				_ = fe.SetAuthType(pgproto3.AuthTypeMD5Password)
-			case *pgproto3.AuthenticationSASLContinue:
-				_ = fe.SetAuthType(pgproto3.AuthTypeSASLContinue)
-			case *pgproto3.AuthenticationSASL:
-				_ = fe.SetAuthType(pgproto3.AuthTypeSASL)
-			case *pgproto3.AuthenticationSASLFinal:
+			if _, ok := backendMsg.(*pgproto3.AuthenticationSASLFinal); ok {
 				// Final SCRAM message. Nothing more to expect from the
 				// client: the next message will be from the server and be
 				// AuthenticationOk or AuthenticationFail.
 				continue
 			}
-			fntMsg, err := fe.Receive()
-			if err != nil {
-				return nil, withCode(
-					errors.Wrap(err, "unable to receive message from client"),
-					codeClientReadFailed)
+			if err := relayClientMsg(); err != nil {
+				return nil, err
+			}
+
+		// The backend is requesting the user to authenticate with GSSAPI, or
+		// continuing the exchange. The client's messages are relayed in the
+		// background.
+		case *pgproto3.AuthenticationGSS, *pgproto3.AuthenticationGSSContinue:
+			if _, ok := backendMsg.(*pgproto3.AuthenticationGSS); ok {
+				if _, err = clientConn.Write(authenticationGSSMsg); err != nil {
+					return nil, withCode(
+						errors.Wrap(err, "unable to send message AuthenticationGSS to client"),
+						codeClientWriteFailed)
+				}
+			} else if err = feSend(backendMsg); err != nil {
+				return nil, err
 			}
-			err = be.Send(fntMsg)
-			if err != nil {
-				return nil, withCode(
-					errors.Wrapf(err, "unable to send message %v to backend", fntMsg),
-					codeBackendWriteFailed)
+			if gssRelay == nil {
+				gssRelay = startBackgroundRelay(clientConn, relayClientMsg)
 			}
 
 		// Server has authenticated the connection; keep reading messages until
@@ -176,6 +233,52 @@ var authenticate = func(
 		codeBackendDisconnected)
 }
 
+// backgroundRelay calls relay in a loop in the background, until it fails or
+// the relay is stopped.
+type backgroundRelay struct {
+	conn net.Conn
+	done chan struct{}
+	// err is the error relay failed with. It's only read once done is closed.
+	err error
+}
+
+// startBackgroundRelay starts relaying messages read from conn with the given
+// function.
+func startBackgroundRelay(conn net.Conn, relay func() error) *backgroundRelay {
+	r := &backgroundRelay{conn: conn, done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		for {
+			if err := relay(); err != nil {
+				r.err = err
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// stop interrupts the pending read from conn and waits for the relay to exit.
+// It returns the error the relay failed with before being stopped, if any.
+func (r *backgroundRelay) stop() error {
+	if err := r.conn.SetReadDeadline(aLongTimeAgo); err != nil {
+		// The relay can't be interrupted, so close the connection to ensure
+		// that it exits.
+		_ = r.conn.Close()
+	}
+	<-r.done
+	if err := r.conn.SetReadDeadline(time.Time{}); err != nil {
+		return withCode(
+			errors.Wrap(err, "unable to reset client read deadline"),
+			codeClientReadFailed)
+	}
+	var netErr net.Error
+	if errors.As(r.err, &netErr) && netErr.Timeout() {
+		return nil
+	}
+	return r.err
+}
+
 // readTokenAuthResult reads the result for the token-based authentication, and
 // assumes that the connection credentials have already been transmitted to the
 // server (as part of the startup message). If authentication fails, this will
@@ -99,6 +99,158 @@ func TestAuthenticateClearText(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAuthenticateSCRAM(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+
+	// Messages of the SCRAM-SHA-256 exchange from RFC 7677.
+	const (
+		clientFirst = "n,,n=user,r=rOprNGfwEbeRWgbNEkqO"
+		serverFirst = "r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0," +
+			"s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"
+		clientFinal = "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0," +
+			"p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ="
+		serverFinal = "v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4="
+	)
+
+	server := func(t *testing.T, be *pgproto3.Backend) {
+		require.NoError(t, be.Send(&pgproto3.AuthenticationSASL{
+			AuthMechanisms: []string{"SCRAM-SHA-256"},
+		}))
+		require.NoError(t, be.SetAuthType(pgproto3.AuthTypeSASL))
+		msg, err := be.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.SASLInitialResponse{
+			AuthMechanism: "SCRAM-SHA-256",
+			Data:          []byte(clientFirst),
+		}, msg)
+
+		require.NoError(t, be.Send(&pgproto3.AuthenticationSASLContinue{Data: []byte(serverFirst)}))
+		require.NoError(t, be.SetAuthType(pgproto3.AuthTypeSASLContinue))
+		msg, err = be.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.SASLResponse{Data: []byte(clientFinal)}, msg)
+
+		require.NoError(t, be.Send(&pgproto3.AuthenticationSASLFinal{Data: []byte(serverFinal)}))
+		require.NoError(t, be.Send(&pgproto3.AuthenticationOk{}))
+		require.NoError(t, be.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'}))
+	}
+
+	client := func(t *testing.T, fe *pgproto3.Frontend) {
+		msg, err := fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.AuthenticationSASL{
+			AuthMechanisms: []string{"SCRAM-SHA-256"},
+		}, msg)
+		require.NoError(t, fe.Send(&pgproto3.SASLInitialResponse{
+			AuthMechanism: "SCRAM-SHA-256",
+			Data:          []byte(clientFirst),
+		}))
+
+		msg, err = fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.AuthenticationSASLContinue{Data: []byte(serverFirst)}, msg)
+		require.NoError(t, fe.Send(&pgproto3.SASLResponse{Data: []byte(clientFinal)}))
+
+		msg, err = fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.AuthenticationSASLFinal{Data: []byte(serverFinal)}, msg)
+		msg, err = fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.AuthenticationOk{}, msg)
+		msg, err = fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.ReadyForQuery{TxStatus: 'I'}, msg)
+	}
+
+	proxyToServer, serverToProxy := net.Pipe()
+	proxyToClient, clientToProxy := net.Pipe()
+	defer proxyToServer.Close()
+	defer proxyToClient.Close()
+	sqlServer := pgproto3.NewBackend(pgproto3.NewChunkReader(serverToProxy), serverToProxy)
+	sqlClient := pgproto3.NewFrontend(pgproto3.NewChunkReader(clientToProxy), clientToProxy)
+
+	go server(t, sqlServer)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := authenticate(proxyToClient, proxyToServer, nil, /* proxyBackendKeyData */
+			func(status throttler.AttemptStatus) error {
+				require.Equal(t, throttler.AttemptOK, status)
+				return nil
+			})
+		errCh <- err
+	}()
+
+	client(t, sqlClient)
+	require.NoError(t, <-errCh)
+}
+
+func TestAuthenticateGSS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+
+	// The server sends a last token once its security context is established,
+	// which the client doesn't respond to.
+	server := func(t *testing.T, conn net.Conn, be *pgproto3.Backend) {
+		// pgproto3 encodes AuthenticationGSS with the wrong length.
+		_, err := conn.Write(authenticationGSSMsg)
+		require.NoError(t, err)
+		require.NoError(t, be.SetAuthType(pgproto3.AuthTypeGSS))
+		msg, err := be.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.GSSResponse{Data: []byte("client token 1")}, msg)
+
+		require.NoError(t, be.Send(&pgproto3.AuthenticationGSSContinue{Data: []byte("server token 1")}))
+		msg, err = be.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.GSSResponse{Data: []byte("client token 2")}, msg)
+
+		require.NoError(t, be.Send(&pgproto3.AuthenticationGSSContinue{Data: []byte("server token 2")}))
+		require.NoError(t, be.Send(&pgproto3.AuthenticationOk{}))
+		require.NoError(t, be.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'}))
+	}
+
+	client := func(t *testing.T, fe *pgproto3.Frontend) {
+		msg, err := fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.AuthenticationGSS{}, msg)
+		require.NoError(t, fe.Send(&pgproto3.GSSResponse{Data: []byte("client token 1")}))
+
+		msg, err = fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.AuthenticationGSSContinue{Data: []byte("server token 1")}, msg)
+		require.NoError(t, fe.Send(&pgproto3.GSSResponse{Data: []byte("client token 2")}))
+
+		msg, err = fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.AuthenticationGSSContinue{Data: []byte("server token 2")}, msg)
+		msg, err = fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.AuthenticationOk{}, msg)
+		msg, err = fe.Receive()
+		require.NoError(t, err)
+		require.Equal(t, &pgproto3.ReadyForQuery{TxStatus: 'I'}, msg)
+	}
+
+	proxyToServer, serverToProxy := net.Pipe()
+	proxyToClient, clientToProxy := net.Pipe()
+	defer proxyToServer.Close()
+	defer proxyToClient.Close()
+	sqlServer := pgproto3.NewBackend(pgproto3.NewChunkReader(serverToProxy), serverToProxy)
+	sqlClient := pgproto3.NewFrontend(pgproto3.NewChunkReader(clientToProxy), clientToProxy)
+
+	go server(t, serverToProxy, sqlServer)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := authenticate(proxyToClient, proxyToServer, nil, /* proxyBackendKeyData */
+			nilThrottleHook)
+		errCh <- err
+	}()
+
+	client(t, sqlClient)
+	require.NoError(t, <-errCh)
+}
+
 func TestAuthenticateThrottled(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	testutilsccl.ServerlessOnly(t)
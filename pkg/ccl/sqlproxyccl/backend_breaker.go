@@ -0,0 +1,158 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// defaultBackendBreakerWindow is the default window within which
+	// consecutive backend dial failures are counted.
+	defaultBackendBreakerWindow = time.Minute
+
+	// defaultBackendBreakerCooldown is the default amount of time an open
+	// breaker fast-fails connections before letting a probe through.
+	defaultBackendBreakerCooldown = 10 * time.Second
+)
+
+// backendBreakers maintains a circuit breaker per tenant, which trips after
+// threshold consecutive backend dial failures within window. While a tenant's
+// breaker is open, new connections to that tenant fail fast instead of
+// retrying. Once cooldown has elapsed, a single connection is let through as
+// a probe: the breaker closes if it succeeds, and is re-opened otherwise.
+//
+// All of backendBreakers' methods are thread safe.
+type backendBreakers struct {
+	threshold  int
+	window     time.Duration
+	cooldown   time.Duration
+	timeSource timeutil.TimeSource
+
+	mu struct {
+		syncutil.Mutex
+		// tenants only contains tenants which recently had failures.
+		tenants map[roachpb.TenantID]*backendBreaker
+	}
+}
+
+// backendBreaker is the state of the breaker for a single tenant.
+type backendBreaker struct {
+	// failures is the number of consecutive failures since firstFailure.
+	failures     int
+	firstFailure time.Time
+	// openedAt is the time at which the breaker was opened, or zero if the
+	// breaker is closed.
+	openedAt time.Time
+	// probing is true if a probe connection is in flight.
+	probing bool
+}
+
+func newBackendBreakers(
+	threshold int, window, cooldown time.Duration, timeSource timeutil.TimeSource,
+) *backendBreakers {
+	if window == 0 {
+		window = defaultBackendBreakerWindow
+	}
+	if cooldown == 0 {
+		cooldown = defaultBackendBreakerCooldown
+	}
+	b := &backendBreakers{
+		threshold:  threshold,
+		window:     window,
+		cooldown:   cooldown,
+		timeSource: timeSource,
+	}
+	b.mu.tenants = make(map[roachpb.TenantID]*backendBreaker)
+	return b
+}
+
+// errBackendBreakerOpen returns the error sent to clients whose connections
+// are fast-failed because the tenant's breaker is open.
+func errBackendBreakerOpen(tenID roachpb.TenantID) error {
+	return withCode(
+		errors.WithHint(
+			errors.Newf("cluster %d is unavailable: too many failed attempts to reach its SQL servers",
+				tenID.ToUint64()),
+			"Retry the connection later."),
+		codeBackendDialFailed)
+}
+
+// admit returns an error if connections to the given tenant should fail
+// fast. isProbe is true if the caller is the half-open probe, in which case it
+// must call endProbe once it is done.
+func (b *backendBreakers) admit(tenID roachpb.TenantID) (isProbe bool, _ error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	br, ok := b.mu.tenants[tenID]
+	if !ok || br.openedAt.IsZero() {
+		return false, nil
+	}
+	if br.probing || b.timeSource.Since(br.openedAt) < b.cooldown {
+		return false, errBackendBreakerOpen(tenID)
+	}
+	br.probing = true
+	return true, nil
+}
+
+// endProbe marks the probe for the given tenant as done. If the probe
+// neither succeeded nor failed (e.g. it was canceled), the next connection
+// becomes the probe.
+func (b *backendBreakers) endProbe(tenID roachpb.TenantID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if br, ok := b.mu.tenants[tenID]; ok {
+		br.probing = false
+	}
+}
+
+// reportFailure records a backend dial failure for the given tenant, and
+// returns true if the tenant's breaker is open.
+func (b *backendBreakers) reportFailure(tenID roachpb.TenantID) (open bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.timeSource.Now()
+	br, ok := b.mu.tenants[tenID]
+	if !ok {
+		br = &backendBreaker{}
+		b.mu.tenants[tenID] = br
+	}
+	if !br.openedAt.IsZero() {
+		// A failed probe, or a connection which started retrying before the
+		// breaker was opened. Either way, keep the breaker open.
+		if br.probing {
+			br.probing = false
+			br.openedAt = now
+		}
+		return true
+	}
+	if br.failures == 0 || now.Sub(br.firstFailure) > b.window {
+		br.failures = 0
+		br.firstFailure = now
+	}
+	br.failures++
+	if br.failures >= b.threshold {
+		br.openedAt = now
+		return true
+	}
+	return false
+}
+
+// reportSuccess records a successful backend dial for the given tenant, which
+// closes its breaker.
+func (b *backendBreakers) reportSuccess(tenID roachpb.TenantID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.mu.tenants, tenID)
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/balancer"
+	"github.com/cockroachdb/cockroach/pkg/ccl/testutilsccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendBreakers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	timeSource := timeutil.NewManualTime(timeutil.Unix(0, 0))
+	b := newBackendBreakers(3 /* threshold */, time.Minute, 10*time.Second, timeSource)
+	ten10, ten20 := roachpb.MustMakeTenantID(10), roachpb.MustMakeTenantID(20)
+
+	requireAdmit := func(tenID roachpb.TenantID, expProbe bool) {
+		t.Helper()
+		isProbe, err := b.admit(tenID)
+		require.NoError(t, err)
+		require.Equal(t, expProbe, isProbe)
+	}
+	requireFastFail := func(tenID roachpb.TenantID) {
+		t.Helper()
+		_, err := b.admit(tenID)
+		require.Error(t, err)
+		require.Equal(t, codeBackendDialFailed, getErrorCode(err))
+	}
+
+	// Failures spread out beyond the window do not trip the breaker.
+	require.False(t, b.reportFailure(ten10))
+	require.False(t, b.reportFailure(ten10))
+	timeSource.Advance(2 * time.Minute)
+	require.False(t, b.reportFailure(ten10))
+	requireAdmit(ten10, false)
+
+	// A success resets the failure count.
+	b.reportSuccess(ten10)
+	require.False(t, b.reportFailure(ten10))
+	require.False(t, b.reportFailure(ten10))
+	require.True(t, b.reportFailure(ten10))
+
+	// The breaker is open, and only affects tenant 10.
+	requireFastFail(ten10)
+	requireAdmit(ten20, false)
+
+	// After the cooldown, a single probe is let through. A failed probe
+	// re-opens the breaker.
+	timeSource.Advance(10 * time.Second)
+	requireAdmit(ten10, true)
+	requireFastFail(ten10)
+	require.True(t, b.reportFailure(ten10))
+	b.endProbe(ten10)
+	requireFastFail(ten10)
+
+	// A successful probe closes the breaker.
+	timeSource.Advance(10 * time.Second)
+	requireAdmit(ten10, true)
+	b.reportSuccess(ten10)
+	b.endProbe(ten10)
+	requireAdmit(ten10, false)
+}
+
+func TestConnector_dialTenantCluster_BackendBreaker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	timeSource := timeutil.NewManualTime(timeutil.Unix(0, 0))
+	c := &connector{
+		TenantID: roachpb.MustMakeTenantID(42),
+		DirectoryCache: &testTenantDirectoryCache{
+			reportFailureFn: func(context.Context, roachpb.TenantID, string) error {
+				return nil
+			},
+		},
+		BackendBreakers: newBackendBreakers(3 /* threshold */, time.Minute, 10*time.Second, timeSource),
+	}
+	b, err := balancer.NewBalancer(
+		ctx,
+		stopper,
+		balancer.NewMetrics(),
+		c.DirectoryCache,
+		balancer.NoRebalanceLoop(),
+	)
+	require.NoError(t, err)
+	c.Balancer = b
+
+	c.testingKnobs.lookupAddr = func(ctx context.Context) (string, error) {
+		return "127.0.0.10:42", nil
+	}
+	var dialSQLServerCount int
+	backendUp := false
+	crdbConn, _ := net.Pipe()
+	defer crdbConn.Close()
+	c.testingKnobs.dialSQLServer = func(serverAssignment *balancer.ServerAssignment) (net.Conn, error) {
+		dialSQLServerCount++
+		if backendUp {
+			return crdbConn, nil
+		}
+		return nil, markAsRetriableConnectorError(errors.New("backend down"))
+	}
+
+	// The first connection retries until the breaker trips, rather than
+	// looping forever.
+	conn, err := c.dialTenantCluster(ctx, nil /* requester */)
+	require.Nil(t, conn)
+	require.Regexp(t, "cluster 42 is unavailable", err)
+	require.Equal(t, 3, dialSQLServerCount)
+
+	// Subsequent connections fail fast without dialing.
+	for i := 0; i < 5; i++ {
+		conn, err = c.dialTenantCluster(ctx, nil /* requester */)
+		require.Nil(t, conn)
+		require.Regexp(t, "cluster 42 is unavailable", err)
+	}
+	require.Equal(t, 3, dialSQLServerCount)
+
+	// Once the cooldown elapses, a probe is attempted, which fails and
+	// re-opens the breaker.
+	timeSource.Advance(10 * time.Second)
+	conn, err = c.dialTenantCluster(ctx, nil /* requester */)
+	require.Nil(t, conn)
+	require.Regexp(t, "cluster 42 is unavailable", err)
+	require.Equal(t, 4, dialSQLServerCount)
+	_, err = c.dialTenantCluster(ctx, nil /* requester */)
+	require.Regexp(t, "cluster 42 is unavailable", err)
+	require.Equal(t, 4, dialSQLServerCount)
+
+	// Once the backend is back up, a successful probe closes the breaker.
+	backendUp = true
+	timeSource.Advance(10 * time.Second)
+	for i := 0; i < 3; i++ {
+		conn, err = c.dialTenantCluster(ctx, nil /* requester */)
+		require.NoError(t, err)
+		require.Equal(t, crdbConn, conn)
+	}
+	require.Equal(t, 7, dialSQLServerCount)
+}
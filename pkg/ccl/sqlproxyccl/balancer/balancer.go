@@ -87,6 +87,8 @@ type balancerOptions struct {
 	rebalanceRate           float32
 	rebalanceDelay          time.Duration
 	disableRebalancing      bool
+	canaryVersion           string
+	canaryPercent           int
 }
 
 // Option defines an option that can be passed to NewBalancer in order to
@@ -147,6 +149,22 @@ func DisableRebalancing() Option {
 	}
 }
 
+// CanaryVersion routes approximately percent% of new connections to a tenant
+// to its pods running the given version, as long as the tenant has pods running
+// both that version and other versions. This is used to canary a new version
+// during a tenant's rolling upgrade. The percentage must be between 0 and 100
+// inclusive.
+//
+// Note that this only applies to the initial placement of connections. The
+// rebalancer does not account for pod versions, so rebalancing should
+// generally be disabled while canarying.
+func CanaryVersion(version string, percent int) Option {
+	return func(opts *balancerOptions) {
+		opts.canaryVersion = version
+		opts.canaryPercent = percent
+	}
+}
+
 // Balancer handles load balancing of SQL connections within the proxy.
 // All methods on the Balancer instance are thread-safe.
 type Balancer struct {
@@ -190,6 +208,11 @@ type Balancer struct {
 	// be disabled.
 	disableRebalancing bool
 
+	// canaryVersion is the pod version which should receive canaryPercent% of
+	// new connections. If empty, pod versions are not taken into account.
+	canaryVersion string
+	canaryPercent int
+
 	// lastRebalance is the last time the tenants are rebalanced. This is used
 	// to rate limit the number of rebalances per tenant. Synchronization is
 	// needed since rebalance operations can be triggered by the rebalance loop,
@@ -224,6 +247,10 @@ func NewBalancer(
 	if options.disableRebalancing {
 		options.noRebalanceLoop = true
 	}
+	if options.canaryPercent < 0 || options.canaryPercent > 100 {
+		return nil, errors.Newf(
+			"canary percentage must be between 0 and 100, but got %d", options.canaryPercent)
+	}
 
 	// Ensure that ctx gets cancelled on stopper's quiescing.
 	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
@@ -243,6 +270,8 @@ func NewBalancer(
 		rebalanceRate:      options.rebalanceRate,
 		rebalanceDelay:     options.rebalanceDelay,
 		disableRebalancing: options.disableRebalancing,
+		canaryVersion:      options.canaryVersion,
+		canaryPercent:      options.canaryPercent,
 	}
 	b.lastRebalance.tenants = make(map[roachpb.TenantID]time.Time)
 
@@ -322,6 +351,9 @@ func (b *Balancer) SelectTenantPod(pods []*tenant.Pod) (*tenant.Pod, error) {
 	}
 	tenantID := roachpb.MustMakeTenantID(pods[0].TenantID)
 	tenantEntry := b.connTracker.getEntry(tenantID, true /* allowCreate */)
+	if b.canaryVersion != "" {
+		pods = b.selectCanaryPartition(pods)
+	}
 	pod := selectTenantPod(pods, tenantEntry)
 	if pod == nil {
 		return nil, ErrNoAvailablePods
@@ -329,6 +361,28 @@ func (b *Balancer) SelectTenantPod(pods []*tenant.Pod) (*tenant.Pod, error) {
 	return pod, nil
 }
 
+// selectCanaryPartition splits the given pods into those running the canary
+// version, and those which are not, and randomly picks one of the two lists
+// based on the canary percentage. If either list is empty, all pods are
+// returned.
+func (b *Balancer) selectCanaryPartition(pods []*tenant.Pod) []*tenant.Pod {
+	var canary, others []*tenant.Pod
+	for _, pod := range pods {
+		if pod.Version == b.canaryVersion {
+			canary = append(canary, pod)
+		} else {
+			others = append(others, pod)
+		}
+	}
+	if len(canary) == 0 || len(others) == 0 {
+		return pods
+	}
+	if rand.Intn(100) < b.canaryPercent {
+		return canary
+	}
+	return others
+}
+
 // GetTracker returns the tracker associated with the balancer.
 //
 // TODO(jaylim-crl): Remove GetTracker entirely once SelectTenantPod returns
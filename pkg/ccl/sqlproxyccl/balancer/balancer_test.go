@@ -64,6 +64,62 @@ func TestBalancer_SelectTenantPod(t *testing.T) {
 	})
 }
 
+func TestBalancer_SelectTenantPod_CanaryVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	_, err := NewBalancer(
+		ctx,
+		stopper,
+		nil, /* metrics */
+		nil, /* directoryCache */
+		NoRebalanceLoop(),
+		CanaryVersion("v2", 101),
+	)
+	require.EqualError(t, err, "canary percentage must be between 0 and 100, but got 101")
+
+	b, err := NewBalancer(
+		ctx,
+		stopper,
+		nil, /* metrics */
+		nil, /* directoryCache */
+		NoRebalanceLoop(),
+		CanaryVersion("v2", 10),
+	)
+	require.NoError(t, err)
+
+	pods := []*tenant.Pod{
+		{TenantID: 10, Addr: "1", Version: "v1"},
+		{TenantID: 10, Addr: "2", Version: "v1"},
+		{TenantID: 10, Addr: "3", Version: "v2"},
+	}
+
+	const numSelections = 10000
+	var canaryCount int
+	for i := 0; i < numSelections; i++ {
+		pod, err := b.SelectTenantPod(pods)
+		require.NoError(t, err)
+		if pod.Version == "v2" {
+			canaryCount++
+		}
+	}
+	// Roughly 10% of the connections should go to the canary version. The
+	// bounds are loose enough to make flakes practically impossible.
+	require.InDelta(t, 0.10, float64(canaryCount)/numSelections, 0.03)
+
+	// Pods are selected regardless of version if only one version exists.
+	pod, err := b.SelectTenantPod(pods[:2])
+	require.NoError(t, err)
+	require.Equal(t, "v1", pod.Version)
+	pod, err = b.SelectTenantPod(pods[2:])
+	require.NoError(t, err)
+	require.Equal(t, "v2", pod.Version)
+}
+
 func TestRebalancer_processQueue(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	testutilsccl.ServerlessOnly(t)
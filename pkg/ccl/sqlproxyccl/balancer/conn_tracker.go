@@ -95,6 +95,22 @@ func (t *ConnTracker) unregisterAssignment(tenantID roachpb.TenantID, sa *Server
 	}
 }
 
+// GetConnCounts returns a snapshot of the number of connections of every
+// tenant that has at least one connection registered with the connection
+// tracker.
+func (t *ConnTracker) GetConnCounts() map[roachpb.TenantID]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts := make(map[roachpb.TenantID]int, len(t.mu.tenants))
+	for tenantID, entry := range t.mu.tenants {
+		if n := entry.assignmentsCount(); n > 0 {
+			counts[tenantID] = n
+		}
+	}
+	return counts
+}
+
 // getTenantIDs returns a list of tenant IDs that have at least one connection
 // registered with the connection tracker.
 func (t *ConnTracker) getTenantIDs() []roachpb.TenantID {
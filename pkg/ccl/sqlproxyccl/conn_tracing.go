@@ -0,0 +1,211 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+	"gopkg.in/yaml.v2"
+)
+
+// connTracingFile represents the on-disk version of the connection tracing
+// config. This also serves as a spec of the expected yaml file format.
+type connTracingFile struct {
+	// Tenants is the list of tenant IDs whose connections should be traced.
+	Tenants []uint64 `yaml:"tenants"`
+	// IPs is the list of client IP addresses whose connections should be
+	// traced.
+	IPs []string `yaml:"ips"`
+}
+
+// connTracingConfig is the in-memory version of connTracingFile.
+type connTracingConfig struct {
+	tenants map[roachpb.TenantID]struct{}
+	ips     map[string]struct{}
+}
+
+// connTracer decides which connections should have their pgwire message types
+// logged. Its config is read from a file which is polled for changes. Changes
+// only apply to connections established after they are picked up.
+//
+// All of connTracer's methods are thread safe.
+type connTracer struct {
+	mu struct {
+		syncutil.Mutex
+		config *connTracingConfig
+	}
+}
+
+// newConnTracer reads the connection tracing config from filename, and starts
+// a background goroutine polling it for changes every pollingInterval. The
+// goroutine stops on ctx cancellation.
+func newConnTracer(
+	ctx context.Context,
+	filename string,
+	timeSource timeutil.TimeSource,
+	pollingInterval time.Duration,
+) (*connTracer, error) {
+	config, err := readConnTracingFile(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading connection tracing file %s", filename)
+	}
+	t := &connTracer{}
+	t.mu.config = config
+
+	go func() {
+		timer := timeSource.NewTimer()
+		defer timer.Stop()
+		for {
+			timer.Reset(pollingInterval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.Ch():
+				timer.MarkRead()
+				config, err := readConnTracingFile(filename)
+				if err != nil {
+					log.Errorf(ctx, "could not read connection tracing file %s: %v", filename, err)
+					continue
+				}
+				t.mu.Lock()
+				t.mu.config = config
+				t.mu.Unlock()
+			}
+		}
+	}()
+	return t, nil
+}
+
+func readConnTracingFile(filename string) (*connTracingConfig, error) {
+	handle, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer handle.Close()
+
+	var f connTracingFile
+	if err := yaml.NewDecoder(handle).Decode(&f); err != nil {
+		return nil, err
+	}
+	config := &connTracingConfig{
+		tenants: make(map[roachpb.TenantID]struct{}, len(f.Tenants)),
+		ips:     make(map[string]struct{}, len(f.IPs)),
+	}
+	for _, id := range f.Tenants {
+		tenID, err := roachpb.MakeTenantID(id)
+		if err != nil {
+			return nil, err
+		}
+		config.tenants[tenID] = struct{}{}
+	}
+	for _, ip := range f.IPs {
+		config.ips[ip] = struct{}{}
+	}
+	return config, nil
+}
+
+// shouldTrace returns true if connections for the given tenant, or from the
+// given client IP address, should be traced.
+func (t *connTracer) shouldTrace(tenID roachpb.TenantID, ip string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.mu.config.tenants[tenID]; ok {
+		return true
+	}
+	_, ok := t.mu.config.ips[ip]
+	return ok
+}
+
+// tracingConn is a net.Conn decorator which logs the type of every pgwire
+// message read from or written to the wrapped client connection. The message
+// contents are never logged.
+type tracingConn struct {
+	net.Conn
+	readTracer  msgTypeTracer
+	writeTracer msgTypeTracer
+}
+
+var _ net.Conn = &tracingConn{}
+
+// newTracingConn wraps conn, a client connection which has already completed
+// its startup phase, in a tracingConn that logs to ctx.
+func newTracingConn(ctx context.Context, conn net.Conn) *tracingConn {
+	return &tracingConn{
+		Conn:        conn,
+		readTracer:  msgTypeTracer{ctx: ctx, direction: "client->proxy"},
+		writeTracer: msgTypeTracer{ctx: ctx, direction: "proxy->client"},
+	}
+}
+
+// Read implements the net.Conn interface.
+func (c *tracingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.readTracer.observe(b[:n])
+	return n, err
+}
+
+// Write implements the net.Conn interface.
+func (c *tracingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.writeTracer.observe(b[:n])
+	return n, err
+}
+
+// msgTypeTracer follows the message boundaries of a stream of pgwire messages
+// and logs the type of each message it encounters. It is not thread safe.
+type msgTypeTracer struct {
+	ctx       context.Context
+	direction redact.SafeString
+
+	// header accumulates the type byte and length of the next message.
+	header    [5]byte
+	headerLen int
+	// remaining is the number of bytes left in the body of the current
+	// message.
+	remaining int
+}
+
+func (t *msgTypeTracer) observe(b []byte) {
+	for len(b) > 0 {
+		if t.remaining > 0 {
+			n := t.remaining
+			if n > len(b) {
+				n = len(b)
+			}
+			t.remaining -= n
+			b = b[n:]
+			continue
+		}
+		n := copy(t.header[t.headerLen:], b)
+		t.headerLen += n
+		b = b[n:]
+		if t.headerLen < len(t.header) {
+			return
+		}
+		// The length includes itself, but not the message type.
+		size := int(binary.BigEndian.Uint32(t.header[1:])) - 4
+		if size < 0 {
+			size = 0
+		}
+		log.Infof(t.ctx, "%s message type %s, body size %d",
+			t.direction, redact.SafeRune(t.header[0]), size)
+		t.headerLen = 0
+		t.remaining = size
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"context"
+	"io"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnTracer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	filename := filepath.Join(t.TempDir(), "tracing.yaml")
+	writeFile := func(contents string) {
+		require.NoError(t, os.WriteFile(filename, []byte(contents), 0644))
+	}
+	writeFile("tenants: [10]\nips: [\"10.0.0.1\"]\n")
+
+	tracer, err := newConnTracer(ctx, filename, timeutil.DefaultTimeSource{}, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	tenant10, tenant20 := roachpb.MustMakeTenantID(10), roachpb.MustMakeTenantID(20)
+	require.True(t, tracer.shouldTrace(tenant10, "127.0.0.1"))
+	require.True(t, tracer.shouldTrace(tenant20, "10.0.0.1"))
+	require.False(t, tracer.shouldTrace(tenant20, "127.0.0.1"))
+
+	// Changes to the file are picked up by polling.
+	writeFile("tenants: [20]\n")
+	testutils.SucceedsSoon(t, func() error {
+		if tracer.shouldTrace(tenant10, "127.0.0.1") || !tracer.shouldTrace(tenant20, "127.0.0.1") {
+			return errors.New("config not updated yet")
+		}
+		return nil
+	})
+
+	// Invalid files are rejected upfront.
+	writeFile("tenants: [0]\n")
+	_, err = newConnTracer(ctx, filename, timeutil.DefaultTimeSource{}, time.Minute)
+	require.Error(t, err)
+}
+
+func TestTracingConn(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// sendQuery sends a Query message through a connection, which is only
+	// wrapped in a tracingConn if traced is true.
+	sendQuery := func(name string, traced bool) {
+		ctx := logtags.AddTag(context.Background(), "conn", name)
+		client, server := net.Pipe()
+		defer client.Close()
+		defer server.Close()
+
+		conn := server
+		if traced {
+			conn = newTracingConn(ctx, server)
+		}
+
+		errCh := make(chan error, 1)
+		go func() {
+			// Write the message in small chunks to ensure that message
+			// boundaries are tracked across reads.
+			buf := (&pgproto3.Query{String: "SELECT 1"}).Encode(nil)
+			buf = (&pgproto3.Sync{}).Encode(buf)
+			for len(buf) > 0 {
+				n := 3
+				if n > len(buf) {
+					n = len(buf)
+				}
+				if _, err := client.Write(buf[:n]); err != nil {
+					errCh <- err
+					return
+				}
+				buf = buf[n:]
+			}
+			_, err := io.Copy(io.Discard, client)
+			errCh <- err
+		}()
+
+		fe := pgproto3.NewBackend(pgproto3.NewChunkReader(conn), conn)
+		msg, err := fe.Receive()
+		require.NoError(t, err)
+		require.IsType(t, &pgproto3.Query{}, msg)
+		msg, err = fe.Receive()
+		require.NoError(t, err)
+		require.IsType(t, &pgproto3.Sync{}, msg)
+		require.NoError(t, fe.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'}))
+		require.NoError(t, conn.Close())
+		require.NoError(t, <-errCh)
+	}
+
+	sendQuery("matched", true /* traced */)
+	sendQuery("unmatched", false /* traced */)
+
+	log.FlushFiles()
+	entries, err := log.FetchEntriesFromFiles(0, math.MaxInt64, 100,
+		regexp.MustCompile(`message type`), log.WithFlattenedSensitiveData)
+	require.NoError(t, err)
+
+	var messages []string
+	for _, e := range entries {
+		require.True(t, strings.Contains(e.Tags, "conn=matched"), "unexpected entry %v", e)
+		messages = append(messages, e.Message)
+	}
+	require.ElementsMatch(t, []string{
+		"client->proxy message type Q, body size 9",
+		"client->proxy message type S, body size 0",
+		"proxy->client message type Z, body size 1",
+	}, messages)
+}
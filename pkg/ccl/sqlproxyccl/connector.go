@@ -37,6 +37,10 @@ const sessionRevivalTokenStartupParam = "crdb:session_revival_token_base64"
 // remoteAddrStartupParam contains the remote address of the original client.
 const remoteAddrStartupParam = "crdb:remote_addr"
 
+// defaultBackendDialTimeout is the timeout of a single attempt at dialing a SQL
+// pod when the connector's BackendDialTimeout is unset.
+const defaultBackendDialTimeout = 5 * time.Second
+
 // connector is a per-session tenant-associated component that can be used to
 // obtain a connection to the tenant cluster. This will also handle the
 // authentication phase. All connections returned by the connector should
@@ -82,10 +86,28 @@ type connector struct {
 	// DialTenantRetries counts how often dialing a tenant is retried.
 	DialTenantRetries *metric.Counter
 
+	// BackendBreakers, if set, is used to fast-fail connections to tenants
+	// whose SQL servers are repeatedly unreachable, instead of retrying
+	// indefinitely.
+	//
+	// NOTE: This field is optional.
+	BackendBreakers *backendBreakers
+
+	// BackendDialTimeout is the maximum amount of time a single attempt at
+	// dialing a SQL pod may take. Attempts which time out are retried. If
+	// zero, defaultBackendDialTimeout is used.
+	//
+	// NOTE: This field is optional.
+	BackendDialTimeout time.Duration
+
 	// CancelInfo contains the data used to implement pgwire query cancellation.
 	// It is only populated after authenticating the connection.
 	CancelInfo *cancelInfo
 
+	// dialDuration is the time spent dialing the tenant cluster by the last
+	// call to OpenTenantConnWithAuth, as opposed to authenticating with it.
+	dialDuration time.Duration
+
 	// Testing knobs for internal connector calls. If specified, these will
 	// be called instead of the actual logic.
 	testingKnobs struct {
@@ -151,7 +173,9 @@ func (c *connector) OpenTenantConnWithAuth(
 	// previously, but that wouldn't happen based on the current proxy logic.
 	delete(c.StartupMsg.Parameters, sessionRevivalTokenStartupParam)
 
+	dialStart := timeutil.Now()
 	serverConn, err := c.dialTenantCluster(ctx, requester)
+	c.dialDuration = timeutil.Since(dialStart)
 	if err != nil {
 		return nil, false, err
 	}
@@ -187,6 +211,16 @@ func (c *connector) dialTenantCluster(
 		defer func() { c.DialTenantLatency.RecordValue(timeutil.Since(start).Nanoseconds()) }()
 	}
 
+	if c.BackendBreakers != nil {
+		isProbe, err := c.BackendBreakers.admit(c.TenantID)
+		if err != nil {
+			return nil, err
+		}
+		if isProbe {
+			defer c.BackendBreakers.endProbe(c.TenantID)
+		}
+	}
+
 	// Repeatedly try to make a connection until context is canceled, or until
 	// we get a non-retriable error. This is preferable to terminating client
 	// connections, because in most cases those connections will simply be
@@ -266,10 +300,20 @@ func (c *connector) dialTenantCluster(
 					// nolint:errwrap
 					err = errors.Wrapf(err, "reporting failure: %s", reportErr.Error())
 				}
+
+				// Stop retrying if the tenant's SQL servers appear to be
+				// down altogether.
+				if c.BackendBreakers != nil && c.BackendBreakers.reportFailure(c.TenantID) {
+					log.Ops.Errorf(ctx, "too many failed attempts to dial SQL server: %v", err)
+					return nil, errBackendBreakerOpen(c.TenantID)
+				}
 				continue
 			}
 			return nil, err
 		}
+		if c.BackendBreakers != nil {
+			c.BackendBreakers.reportSuccess(c.TenantID)
+		}
 		return crdbConn, nil
 	}
 
@@ -363,12 +407,16 @@ func (c *connector) dialSQLServer(
 		}
 	}
 
-	// TODO(JeffSwenson): The five second time out is pretty mediocre. It's too
+	// TODO(JeffSwenson): The fixed time out is pretty mediocre. It's too
 	// short if the sql server is overloaded and too long if everything is
 	// working the way it should. Ideally the fixed the timeout would be replaced
 	// by an adaptive timeout or maybe speculative retries on a different server.
+	dialTimeout := c.BackendDialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = defaultBackendDialTimeout
+	}
 	var conn net.Conn
-	err := timeutil.RunWithTimeout(ctx, "backend-dial", time.Second*5, func(ctx context.Context) error {
+	err := timeutil.RunWithTimeout(ctx, "backend-dial", dialTimeout, func(ctx context.Context) error {
 		var err error
 		conn, err = BackendDial(ctx, c.StartupMsg, serverAssignment.Addr(), tlsConf)
 		return err
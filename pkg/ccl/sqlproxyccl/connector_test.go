@@ -905,6 +905,24 @@ func TestConnector_dialSQLServer(t *testing.T) {
 		require.True(t, isRetriableConnectorError(err))
 		require.Nil(t, conn)
 	})
+
+	t.Run("dial timeout", func(t *testing.T) {
+		c := &connector{
+			StartupMsg:         &pgproto3.StartupMessage{},
+			BackendDialTimeout: 100 * time.Millisecond,
+		}
+		// 10.255.255.1 is unroutable, so dials to it hang until they are
+		// cancelled.
+		sa := balancer.NewServerAssignment(tenantID, tracker, nil, "10.255.255.1:26257")
+		defer sa.Close()
+
+		start := timeutil.Now()
+		conn, err := c.dialSQLServer(ctx, sa)
+		require.Error(t, err)
+		require.True(t, isRetriableConnectorError(err))
+		require.Nil(t, conn)
+		require.Less(t, timeutil.Since(start), defaultBackendDialTimeout)
+	})
 }
 
 func TestRetriableConnectorError(t *testing.T) {
@@ -0,0 +1,74 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// failoverConn is a grpc.ClientConnInterface over a list of connections to
+// directory servers, in order of preference. RPCs are sent to the first
+// connection that is not known to be unhealthy, and fall back to the next
+// connection if the server turns out to be unavailable.
+type failoverConn struct {
+	conns []*grpc.ClientConn
+}
+
+var _ grpc.ClientConnInterface = (*failoverConn)(nil)
+
+// Invoke implements the grpc.ClientConnInterface interface.
+func (c *failoverConn) Invoke(
+	ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption,
+) error {
+	var err error
+	for _, conn := range c.candidates() {
+		err = conn.Invoke(ctx, method, args, reply, opts...)
+		if status.Code(err) != codes.Unavailable {
+			return err
+		}
+	}
+	return err
+}
+
+// NewStream implements the grpc.ClientConnInterface interface.
+func (c *failoverConn) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	var err error
+	for _, conn := range c.candidates() {
+		var stream grpc.ClientStream
+		stream, err = conn.NewStream(ctx, desc, method, opts...)
+		if status.Code(err) != codes.Unavailable {
+			return stream, err
+		}
+	}
+	return nil, err
+}
+
+// candidates returns the connections in the order in which they should be
+// tried. Connections which are known to be unhealthy are moved to the end,
+// so that RPCs do not have to wait for them to fail.
+func (c *failoverConn) candidates() []*grpc.ClientConn {
+	healthy := make([]*grpc.ClientConn, 0, len(c.conns))
+	var unhealthy []*grpc.ClientConn
+	for _, conn := range c.conns {
+		switch conn.GetState() {
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			unhealthy = append(unhealthy, conn)
+		default:
+			healthy = append(healthy, conn)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/tenant"
+	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/tenantdirsvr"
+	"github.com/cockroachdb/cockroach/pkg/ccl/testutilsccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestFailoverConn(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	// The primary directory server is unreachable.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	primaryAddr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+	primary, err := grpc.Dial(primaryAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer func() { _ = primary.Close() }() // nolint:grpcconnclose
+
+	// The secondary directory server is up.
+	tds := tenantdirsvr.NewTestStaticDirectoryServer(stopper, nil /* timeSource */)
+	require.NoError(t, tds.Start(ctx))
+	secondary, err := grpc.DialContext(
+		ctx,
+		"",
+		grpc.WithContextDialer(tds.DialerFunc),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer func() { _ = secondary.Close() }() // nolint:grpcconnclose
+
+	tenantID := roachpb.MustMakeTenantID(10)
+	tds.CreateTenant(tenantID, &tenant.Tenant{
+		Version:     "001",
+		TenantID:    tenantID.ToUint64(),
+		ClusterName: "my-tenant",
+	})
+
+	client := tenant.NewDirectoryClient(&failoverConn{
+		conns: []*grpc.ClientConn{primary, secondary},
+	})
+	dir, err := tenant.NewDirectoryCache(ctx, stopper, client)
+	require.NoError(t, err)
+
+	// Tenant resolution succeeds through the secondary.
+	tenantObj, err := dir.LookupTenant(ctx, tenantID)
+	require.NoError(t, err)
+	require.Equal(t, "my-tenant", tenantObj.ClusterName)
+}
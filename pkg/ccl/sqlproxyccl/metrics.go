@@ -21,6 +21,7 @@ type metrics struct {
 	BackendDownCount       *metric.Counter
 	ClientDisconnectCount  *metric.Counter
 	CurConnCount           *metric.Gauge
+	InFlightHandshakes     *metric.Gauge
 	RoutingErrCount        *metric.Counter
 	AcceptedConnCount      *metric.Counter
 	RefusedConnCount       *metric.Counter
@@ -51,6 +52,7 @@ type metrics struct {
 	SNIRoutingMethodCount           *aggmetric.Counter
 	DatabaseRoutingMethodCount      *aggmetric.Counter
 	ClusterOptionRoutingMethodCount *aggmetric.Counter
+	ClusterParamRoutingMethodCount  *aggmetric.Counter
 }
 
 // MetricStruct implements the metrics.Struct interface.
@@ -75,6 +77,12 @@ var (
 		Measurement: "Connections",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaInFlightHandshakes = metric.Metadata{
+		Name:        "proxy.sql.in_flight_handshakes",
+		Help:        "Number of connections performing TLS and authentication handshakes",
+		Measurement: "Connections",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaRoutingErrCount = metric.Metadata{
 		Name:        "proxy.err.routing",
 		Help:        "Number of errors encountered when attempting to route clients",
@@ -240,6 +248,7 @@ func makeProxyMetrics() metrics {
 		BackendDownCount:       metric.NewCounter(metaBackendDownCount),
 		ClientDisconnectCount:  metric.NewCounter(metaClientDisconnectCount),
 		CurConnCount:           metric.NewGauge(metaCurConnCount),
+		InFlightHandshakes:     metric.NewGauge(metaInFlightHandshakes),
 		RoutingErrCount:        metric.NewCounter(metaRoutingErrCount),
 		AcceptedConnCount:      metric.NewCounter(metaAcceptedConnCount),
 		RefusedConnCount:       metric.NewCounter(metaRefusedConnCount),
@@ -291,6 +300,7 @@ func makeProxyMetrics() metrics {
 	m.SNIRoutingMethodCount = m.RoutingMethodCount.AddChild("sni")
 	m.DatabaseRoutingMethodCount = m.RoutingMethodCount.AddChild("database")
 	m.ClusterOptionRoutingMethodCount = m.RoutingMethodCount.AddChild("cluster_option")
+	m.ClusterParamRoutingMethodCount = m.RoutingMethodCount.AddChild("cluster_param")
 	return *m
 }
 
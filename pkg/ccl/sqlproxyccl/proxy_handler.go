@@ -12,6 +12,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"net"
 	"net/http"
 	"regexp"
@@ -37,6 +38,7 @@ import (
 	proxyproto "github.com/pires/go-proxyproto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 )
@@ -71,6 +73,28 @@ type ProxyOptions struct {
 	Allowlist string
 	// Denylist file to limit access to IP addresses and tenant ids.
 	Denylist string
+	// DisallowedStartupParams is a list of startup parameter keys (e.g.
+	// "replication") which are rejected by the proxy. Connections sending any
+	// of these parameters are refused before reaching a backend.
+	DisallowedStartupParams []string
+	// ClusterIdentifierParam, if set, is the name of a startup parameter (e.g.
+	// "crdb_cluster") from which the cluster identifier is extracted, for
+	// clients which can neither embed it in the database parameter nor in the
+	// options parameter. The parameter is not forwarded to the backend.
+	ClusterIdentifierParam string
+	// RoutingTagParamPrefix, if set, makes the proxy annotate the startup
+	// message forwarded to the backend with the resolved cluster name and the
+	// proxy's NodeID, under the "<prefix>cluster_name" and
+	// "<prefix>proxy_node_id" parameters respectively. Connections from
+	// clients which already send either parameter are refused.
+	RoutingTagParamPrefix string
+	// NodeID identifies this proxy instance in the routing tag. It is omitted
+	// from the tag if empty.
+	NodeID string
+	// ConnectionTracingFile is an optional config file listing tenant ids and
+	// client IP addresses whose connections should have the types of their
+	// pgwire messages logged. It is polled every PollConfigInterval.
+	ConnectionTracingFile string
 	// ListenAddr is the listen address for incoming connections.
 	ListenAddr string
 	// ProxyProtocolListenAddr is the optional listen address for incoming
@@ -99,7 +123,9 @@ type ProxyOptions struct {
 	RoutingRule string
 	// DirectoryAddr specified optional {HOSTNAME}:{PORT} for service that does
 	// the resolution from backend id to IP address. If specified - it will be
-	// used instead of the routing rule above.
+	// used instead of the routing rule above. A comma-separated list of
+	// addresses may be given, in which case the proxy fails over to the next
+	// directory server whenever the preceding ones are unavailable.
 	DirectoryAddr string
 	// RatelimitBaseDelay is the initial backoff after a failed login attempt.
 	// Set to 0 to disable rate limiting.
@@ -113,8 +139,48 @@ type ProxyOptions struct {
 	// ThrottleBaseDelay is the initial exponential backoff triggered in
 	// response to the first connection failure.
 	ThrottleBaseDelay time.Duration
+	// ConnectionRateLimit is the maximum rate, in connections per second, at
+	// which new connections to a single tenant are accepted. Connections
+	// beyond the rate are refused. Set to 0 to disable.
+	ConnectionRateLimit float64
+	// ConnectionRateBurst is the number of connections to a single tenant
+	// that may be accepted at once, above ConnectionRateLimit. It defaults
+	// to 1 if unset.
+	ConnectionRateBurst int
 	// DisableConnectionRebalancing disables connection rebalancing for tenants.
 	DisableConnectionRebalancing bool
+	// BackendBreakerThreshold is the number of consecutive failures to dial a
+	// tenant's SQL servers within BackendBreakerWindow after which new
+	// connections to that tenant fail fast instead of retrying. Connections
+	// are let through again once a probe connection, attempted every
+	// BackendBreakerCooldown, succeeds. Set to 0 to disable.
+	BackendBreakerThreshold int
+	// BackendBreakerWindow defaults to one minute if unset.
+	BackendBreakerWindow time.Duration
+	// BackendBreakerCooldown defaults to 10 seconds if unset.
+	BackendBreakerCooldown time.Duration
+	// CanaryPodVersion, if set, is the SQL pod version which should receive
+	// CanaryPodPercent% of new connections to tenants with pods running both
+	// that version and other versions.
+	CanaryPodVersion string
+	// CanaryPodPercent is the percentage of new connections routed to pods
+	// running CanaryPodVersion. It must be between 0 and 100.
+	CanaryPodPercent int
+	// ShutdownDrainTimeout is how long in-flight connections are allowed to
+	// continue once the proxy starts quiescing. No new connections are
+	// accepted during that time. If zero, connections are closed as soon as
+	// the proxy starts quiescing.
+	ShutdownDrainTimeout time.Duration
+	// KeepAliveInterval, if non-zero, is the period between TCP keepalive
+	// probes sent on client and backend connections. This keeps idle
+	// connections alive through NAT gateways which would otherwise silently
+	// drop them.
+	KeepAliveInterval time.Duration
+	// BackendDialTimeout is the maximum amount of time a single attempt at
+	// dialing a SQL pod may take, so that a black-holed pod does not stall the
+	// connection. Attempts which time out are retried against a newly resolved
+	// pod. Defaults to 5 seconds if unset.
+	BackendDialTimeout time.Duration
 	// RequireProxyProtocol changes the server's behavior to support the PROXY
 	// protocol (SQL=required, HTTP=best-effort). With this set to true, the
 	// PROXY info from upstream will be trusted on both HTTP and SQL (on the
@@ -122,6 +188,30 @@ type ProxyOptions struct {
 	// port, if specified, will require the proxy protocol regardless of
 	// RequireProxyProtocol.
 	RequireProxyProtocol bool
+	// MaxConcurrentHandshakes, if non-zero, bounds the number of connections
+	// that may be in the TLS and authentication phase at the same time.
+	// Connections beyond the bound wait for up to HandshakeQueueTimeout for a
+	// slot to free up before being refused.
+	MaxConcurrentHandshakes int
+	// HandshakeQueueTimeout is the maximum amount of time that a connection
+	// waits for a handshake slot when MaxConcurrentHandshakes is reached. If
+	// zero, excess connections are refused immediately.
+	HandshakeQueueTimeout time.Duration
+	// HandshakeAdmitTimeout is the maximum amount of time that a connection
+	// holding a handshake slot may take to negotiate TLS and send its startup
+	// message, so that stalled clients cannot hold on to the slots. It only
+	// applies if MaxConcurrentHandshakes is set, and defaults to 10 seconds
+	// if unset.
+	HandshakeAdmitTimeout time.Duration
+	// SlowHandshakeThreshold, if non-zero, is the duration after which the
+	// handshake of a connection, from the moment it is received until the
+	// client is authenticated by the SQL pod, is logged as slow along with the
+	// time spent in each of its phases.
+	SlowHandshakeThreshold time.Duration
+	// TerminateDeletedTenantConnections, if set, makes the proxy close all
+	// connections to a tenant as soon as the directory reports that the
+	// tenant was deleted, rather than waiting for the SQL pod to drop them.
+	TerminateDeletedTenantConnections bool
 
 	// testingKnobs are knobs used for testing.
 	testingKnobs struct {
@@ -141,6 +231,14 @@ type ProxyOptions struct {
 		validateProxyHeader proxyproto.Validator
 
 		httpCancelErrHandler func(err error)
+
+		// afterSetKeepAlive is called with the underlying TCP connection
+		// whenever keepalive is enabled on a connection.
+		afterSetKeepAlive func(conn *net.TCPConn)
+
+		// afterHandshakeSlotAcquired is called whenever a connection acquires
+		// a handshake slot, with the resulting number of in-flight handshakes.
+		afterHandshakeSlotAcquired func(inFlight int64)
 	}
 }
 
@@ -164,6 +262,10 @@ type proxyHandler struct {
 	// throttleService will do throttling of incoming connection requests.
 	throttleService throttler.Service
 
+	// directoryConns are the connections to the directory servers, in order
+	// of preference.
+	directoryConns []*grpc.ClientConn
+
 	// directoryCache is used to resolve tenants to their IP addresses.
 	directoryCache tenant.DirectoryCache
 
@@ -175,6 +277,29 @@ type proxyHandler struct {
 
 	// cancelInfoMap keeps track of all the cancel request keys for this proxy.
 	cancelInfoMap *cancelInfoMap
+
+	// connTracer decides which connections should be traced. It is nil if no
+	// ConnectionTracingFile was specified.
+	connTracer *connTracer
+
+	// backendBreakers fast-fails connections to tenants whose SQL servers are
+	// down. It is nil if BackendBreakerThreshold is 0.
+	backendBreakers *backendBreakers
+
+	// handshakeSem bounds the number of in-progress handshakes. It is nil if
+	// MaxConcurrentHandshakes is 0.
+	handshakeSem chan struct{}
+
+	// tenantDeletion notifies connections that their tenant was deleted. It is
+	// nil unless TerminateDeletedTenantConnections is set.
+	tenantDeletion *tenantDeletionWatcher
+
+	// drainCtx is canceled once the proxy has been quiescing for
+	// ShutdownDrainTimeout, or once no connections remain, whichever happens
+	// first.
+	// Client connections are served under a context bound to drainCtx rather
+	// than to the stopper, so that they survive the drain period.
+	drainCtx context.Context
 }
 
 const throttledErrorHint string = `Connection throttling is triggered by repeated authentication failure. Make
@@ -186,6 +311,31 @@ var authThrottledError = errors.WithHint(
 		"too many failed authentication attempts"), codeProxyRefusedConnection),
 	throttledErrorHint)
 
+const connRateLimitedErrorHint string = `Connection rate limiting is triggered by opening too many new connections to
+the cluster in a short period of time. Retry later, or reuse connections through a connection pool.
+`
+
+var connRateLimitedError = errors.WithHint(
+	withCode(errors.New(
+		"too many new connections to the cluster"), codeProxyRefusedConnection),
+	connRateLimitedErrorHint)
+
+const tooManyHandshakesErrorHint string = `The proxy is busy establishing other connections. Retry later, or reuse
+connections through a connection pool.
+`
+
+var tooManyHandshakesError = errors.WithHint(
+	withCode(errors.New(
+		"too many connections being established"), codeProxyRefusedConnection),
+	tooManyHandshakesErrorHint)
+
+// drainPollInterval is how often the number of open connections is checked
+// while draining, so that the drain ends once they are all closed.
+const drainPollInterval = 100 * time.Millisecond
+
+// defaultHandshakeAdmitTimeout is used when HandshakeAdmitTimeout is unset.
+const defaultHandshakeAdmitTimeout = 10 * time.Second
+
 // newProxyHandler will create a new proxy handler with configuration based on
 // the provided options.
 func newProxyHandler(
@@ -204,14 +354,50 @@ func newProxyHandler(
 		certManager:   certmgr.NewCertManager(ctx),
 		cancelInfoMap: makeCancelInfoMap(),
 	}
+	if handler.MaxConcurrentHandshakes > 0 {
+		handler.handshakeSem = make(chan struct{}, handler.MaxConcurrentHandshakes)
+		if handler.HandshakeAdmitTimeout <= 0 {
+			handler.HandshakeAdmitTimeout = defaultHandshakeAdmitTimeout
+		}
+	}
 
 	err := handler.setupIncomingCert(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var cancelDrain context.CancelFunc
+	handler.drainCtx, cancelDrain = context.WithCancel(context.Background())
+	if err := stopper.RunAsyncTask(ctx, "drain-connections", func(ctx context.Context) {
+		defer cancelDrain()
+		<-stopper.ShouldQuiesce()
+		if handler.ShutdownDrainTimeout <= 0 {
+			return
+		}
+		log.Infof(ctx, "draining connections for up to %s", handler.ShutdownDrainTimeout)
+		timer := timeutil.NewTimer()
+		defer timer.Stop()
+		timer.Reset(handler.ShutdownDrainTimeout)
+		poll := timeutil.NewTimer()
+		defer poll.Stop()
+		for handler.metrics.CurConnCount.Value() > 0 {
+			poll.Reset(drainPollInterval)
+			select {
+			case <-timer.C:
+				timer.Read = true
+				return
+			case <-poll.C:
+				poll.Read = true
+			}
+		}
+	}); err != nil {
+		cancelDrain()
+		return nil, err
+	}
+
 	handler.throttleService = throttler.NewLocalService(
 		throttler.WithBaseDelay(handler.ThrottleBaseDelay),
+		throttler.WithConnectionRateLimit(handler.ConnectionRateLimit, handler.ConnectionRateBurst),
 	)
 
 	// TODO(jaylim-crl): Clean up how we start different types of directory
@@ -221,6 +407,7 @@ func newProxyHandler(
 	// on an actual network address, but there are no plans to support that at
 	// the moment.
 	var conn *grpc.ClientConn
+	var secondaryConns []*grpc.ClientConn
 	if handler.testingKnobs.directoryServer != nil {
 		// TODO(jaylim-crl): For now, only support the static version. We should
 		// make this part of a LocalDirectoryServer interface for us to grab the
@@ -239,12 +426,19 @@ func newProxyHandler(
 			return nil, err
 		}
 	} else if handler.DirectoryAddr != "" {
-		conn, err = grpc.Dial(
-			handler.DirectoryAddr,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-		)
-		if err != nil {
-			return nil, err
+		for i, directoryAddr := range strings.Split(handler.DirectoryAddr, ",") {
+			c, err := grpc.Dial(
+				strings.TrimSpace(directoryAddr),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				conn = c
+			} else {
+				secondaryConns = append(secondaryConns, c)
+			}
 		}
 	} else {
 		// If no directory address was specified, assume routing rule, and
@@ -269,18 +463,32 @@ func newProxyHandler(
 			return nil, err
 		}
 	}
+	handler.directoryConns = append([]*grpc.ClientConn{conn}, secondaryConns...)
 	stopper.AddCloser(stop.CloserFn(func() {
-		_ = conn.Close() // nolint:grpcconnclose
+		for _, c := range handler.directoryConns {
+			_ = c.Close() // nolint:grpcconnclose
+		}
 	}))
 
 	var dirOpts []tenant.DirOption
 	podWatcher := make(chan *tenant.Pod)
 	dirOpts = append(dirOpts, tenant.PodWatcher(podWatcher))
+	var tenantWatcher chan *tenant.WatchTenantsResponse
+	if handler.TerminateDeletedTenantConnections {
+		handler.tenantDeletion = newTenantDeletionWatcher()
+		tenantWatcher = make(chan *tenant.WatchTenantsResponse)
+		dirOpts = append(dirOpts, tenant.TenantWatcher(tenantWatcher))
+	}
 	if handler.testingKnobs.dirOpts != nil {
 		dirOpts = append(dirOpts, handler.testingKnobs.dirOpts...)
 	}
 
-	client := tenant.NewDirectoryClient(conn)
+	var client tenant.DirectoryClient
+	if len(handler.directoryConns) > 1 {
+		client = tenant.NewDirectoryClient(&failoverConn{conns: handler.directoryConns})
+	} else {
+		client = tenant.NewDirectoryClient(conn)
+	}
 	handler.directoryCache, err = tenant.NewDirectoryCache(ctx, stopper, client, dirOpts...)
 	if err != nil {
 		return nil, err
@@ -298,12 +506,37 @@ func newProxyHandler(
 		return nil, err
 	}
 
+	if options.BackendBreakerThreshold > 0 {
+		handler.backendBreakers = newBackendBreakers(
+			options.BackendBreakerThreshold,
+			options.BackendBreakerWindow,
+			options.BackendBreakerCooldown,
+			timeutil.DefaultTimeSource{},
+		)
+	}
+
+	if options.ConnectionTracingFile != "" {
+		handler.connTracer, err = newConnTracer(
+			ctx,
+			options.ConnectionTracingFile,
+			timeutil.DefaultTimeSource{},
+			options.PollConfigInterval,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	balancerMetrics := balancer.NewMetrics()
 	registry.AddMetricStruct(balancerMetrics)
 	var balancerOpts []balancer.Option
 	if handler.DisableConnectionRebalancing {
 		balancerOpts = append(balancerOpts, balancer.DisableRebalancing())
 	}
+	if handler.CanaryPodVersion != "" {
+		balancerOpts = append(balancerOpts,
+			balancer.CanaryVersion(handler.CanaryPodVersion, handler.CanaryPodPercent))
+	}
 	if handler.testingKnobs.balancerOpts != nil {
 		balancerOpts = append(balancerOpts, handler.testingKnobs.balancerOpts...)
 	}
@@ -315,6 +548,9 @@ func newProxyHandler(
 	// Only start the pod watcher once everything has been initialized. This
 	// will depend on the balancer eventually.
 	go handler.startPodWatcher(ctx, podWatcher)
+	if tenantWatcher != nil {
+		go handler.startTenantWatcher(ctx, tenantWatcher)
+	}
 
 	return &handler, nil
 }
@@ -326,6 +562,8 @@ func (handler *proxyHandler) handle(
 ) error {
 	connReceivedTime := timeutil.Now()
 
+	handler.setKeepAlive(ctx, incomingConn)
+
 	// Parse headers before admitting the connection since the connection may
 	// be upgraded to TLS.
 	var endpointID string
@@ -338,8 +576,32 @@ func (handler *proxyHandler) handle(
 		}
 	}
 
+	// Bound the number of connections doing TLS and authentication at the
+	// same time, since those are CPU intensive.
+	releaseHandshake, err := handler.acquireHandshakeSlot(ctx)
+	if err != nil {
+		log.Errorf(ctx, "refusing connection: %v", err.Error())
+		updateMetricsAndSendErrToClient(err, incomingConn, handler.metrics)
+		return err
+	}
+	defer releaseHandshake()
+
+	// Reads and writes on the connection, including those of the TLS
+	// connection wrapping it, fail once the deadline passes. That bounds how
+	// long a client which stalls before sending its startup message holds its
+	// handshake slot.
+	if handler.handshakeSem != nil {
+		if err := incomingConn.SetDeadline(timeutil.Now().Add(handler.HandshakeAdmitTimeout)); err != nil {
+			return err
+		}
+	}
 	fe := FrontendAdmit(incomingConn, handler.incomingTLSConfig())
 	defer func() { _ = fe.Conn.Close() }()
+	if handler.handshakeSem != nil {
+		if err := incomingConn.SetDeadline(time.Time{}); err != nil {
+			return err
+		}
+	}
 	if fe.Err != nil {
 		// If a startup message cannot be read at all, assume TCP probe, and
 		// return silently.
@@ -349,12 +611,14 @@ func (handler *proxyHandler) handle(
 		SendErrToClient(fe.Conn, fe.Err)
 		return fe.Err
 	}
+	connAdmittedTime := timeutil.Now()
 
 	// Cancel requests are sent on a separate connection, and have no response,
 	// so we can close the connection immediately, then handle the request. This
 	// prevents the client from using latency to learn if we are processing the
 	// request or not.
 	if cr := fe.CancelRequest; cr != nil {
+		releaseHandshake()
 		_ = incomingConn.Close()
 		if err := handler.handleCancelRequest(cr, true /* allowForward */); err != nil {
 			// Lots of noise from this log indicates that somebody is spamming
@@ -373,9 +637,18 @@ func (handler *proxyHandler) handle(
 		return nil
 	}
 
+	if err := checkStartupParams(fe.Msg, handler.DisallowedStartupParams); err != nil {
+		clientErr := withCode(err, codeProxyRefusedConnection)
+		log.Errorf(ctx, "rejecting startup message: %s", err.Error())
+		updateMetricsAndSendErrToClient(clientErr, fe.Conn, handler.metrics)
+		return clientErr
+	}
+
 	// NOTE: Errors returned from this function are user-facing errors so we
 	// should be careful with the details that we want to expose.
-	backendStartupMsg, clusterName, tenID, err := clusterNameAndTenantFromParams(ctx, fe, handler.metrics)
+	backendStartupMsg, clusterName, tenID, err := clusterNameAndTenantFromParams(
+		ctx, fe, handler.metrics, handler.ClusterIdentifierParam,
+	)
 	if err != nil {
 		clientErr := withCode(err, codeParamsRoutingFailed)
 		log.Errorf(ctx, "unable to extract cluster name and tenant id: %s", err.Error())
@@ -386,6 +659,17 @@ func (handler *proxyHandler) handle(
 	ctx = logtags.AddTag(ctx, "cluster", clusterName)
 	ctx = logtags.AddTag(ctx, "tenant", tenID)
 
+	if handler.RoutingTagParamPrefix != "" {
+		if err := addRoutingTagParams(
+			backendStartupMsg, handler.RoutingTagParamPrefix, clusterName, handler.NodeID,
+		); err != nil {
+			clientErr := withCode(err, codeProxyRefusedConnection)
+			log.Errorf(ctx, "rejecting startup message: %s", err.Error())
+			updateMetricsAndSendErrToClient(clientErr, fe.Conn, handler.metrics)
+			return clientErr
+		}
+	}
+
 	// Use an empty string as the default port as we only care about the
 	// correctly parsing the IP address here.
 	ipAddr, _, err := addr.SplitHostPort(fe.Conn.RemoteAddr().String(), "")
@@ -436,7 +720,25 @@ func (handler *proxyHandler) handle(
 	}
 	defer removeListener()
 
+	if handler.tenantDeletion != nil {
+		removeDeletionListener := handler.tenantDeletion.listen(tenID, func() {
+			err := withCode(errors.New("cluster was deleted"), codeExpiredClientConnection)
+			select {
+			case errConnection <- err: /* error reported */
+			default: /* the channel already contains an error */
+			}
+		})
+		defer removeDeletionListener()
+	}
+
 	throttleTags := throttler.ConnectionTags{IP: ipAddr, TenantID: tenID.String()}
+	if err := handler.throttleService.RateCheck(throttleTags); err != nil {
+		log.Errorf(ctx, "throttler refused connection: %v", err.Error())
+		err = connRateLimitedError
+		updateMetricsAndSendErrToClient(err, fe.Conn, handler.metrics)
+		return err
+	}
+
 	throttleTime, err := handler.throttleService.LoginCheck(throttleTags)
 	if err != nil {
 		log.Errorf(ctx, "throttler refused connection: %v", err.Error())
@@ -446,14 +748,16 @@ func (handler *proxyHandler) handle(
 	}
 
 	connector := &connector{
-		ClusterName:       clusterName,
-		TenantID:          tenID,
-		DirectoryCache:    handler.directoryCache,
-		Balancer:          handler.balancer,
-		StartupMsg:        backendStartupMsg,
-		DialTenantLatency: handler.metrics.DialTenantLatency,
-		DialTenantRetries: handler.metrics.DialTenantRetries,
-		CancelInfo:        makeCancelInfo(incomingConn.LocalAddr(), incomingConn.RemoteAddr()),
+		ClusterName:        clusterName,
+		TenantID:           tenID,
+		DirectoryCache:     handler.directoryCache,
+		Balancer:           handler.balancer,
+		StartupMsg:         backendStartupMsg,
+		DialTenantLatency:  handler.metrics.DialTenantLatency,
+		DialTenantRetries:  handler.metrics.DialTenantRetries,
+		BackendBreakers:    handler.backendBreakers,
+		BackendDialTimeout: handler.BackendDialTimeout,
+		CancelInfo:         makeCancelInfo(incomingConn.LocalAddr(), incomingConn.RemoteAddr()),
 	}
 
 	// TLS options for the proxy are split into Insecure and SkipVerify.
@@ -469,6 +773,7 @@ func (handler *proxyHandler) handle(
 	f := newForwarder(ctx, connector, handler.metrics, nil /* timeSource */)
 	defer f.Close()
 
+	connectStartTime := timeutil.Now()
 	crdbConn, sentToClient, err := connector.OpenTenantConnWithAuth(ctx, f, fe.Conn,
 		func(status throttler.AttemptStatus) error {
 			if err := handler.throttleService.ReportAttempt(
@@ -480,6 +785,13 @@ func (handler *proxyHandler) handle(
 			return nil
 		},
 	)
+	releaseHandshake()
+	handler.maybeLogSlowHandshake(ctx, handshakeTimings{
+		admit: connAdmittedTime.Sub(connReceivedTime),
+		setup: connectStartTime.Sub(connAdmittedTime),
+		dial:  connector.dialDuration,
+		auth:  timeutil.Since(connectStartTime) - connector.dialDuration,
+	})
 	if err != nil {
 		log.Errorf(ctx, "could not connect to cluster: %v", err.Error())
 		if sentToClient {
@@ -491,6 +803,8 @@ func (handler *proxyHandler) handle(
 	}
 	defer func() { _ = crdbConn.Close() }()
 
+	handler.setKeepAlive(ctx, crdbConn)
+
 	// Update the cancel info.
 	handler.cancelInfoMap.addCancelInfo(connector.CancelInfo.proxySecretID(), connector.CancelInfo)
 
@@ -509,12 +823,20 @@ func (handler *proxyHandler) handle(
 	// wrapper must be inside the errorSourceConn and not the other way around.
 	// The TLS connection attempts to cast errors to a net.Err and will behave
 	// incorrectly if handed a marked error.
-	clientConn := &errorSourceConn{
+	var clientConn net.Conn = &errorSourceConn{
 		Conn:           fe.Conn,
 		readErrMarker:  errClientRead,
 		writeErrMarker: errClientWrite,
 	}
 
+	// Log the types of all pgwire messages for connections that have been
+	// selected for tracing. The tracer wraps the errorSourceConn, so errors
+	// are passed through unchanged.
+	if handler.connTracer != nil && handler.connTracer.shouldTrace(tenID, ipAddr) {
+		log.Infof(ctx, "tracing pgwire messages for connection")
+		clientConn = newTracingConn(ctx, clientConn)
+	}
+
 	// Pass ownership of conn and crdbConn to the forwarder.
 	if err := f.run(clientConn, crdbConn); err != nil {
 		// Don't send to the client here for the same reason below.
@@ -543,16 +865,142 @@ func (handler *proxyHandler) handle(
 	case err := <-f.errCh: // From forwarder.
 		handler.metrics.updateForError(err)
 		return err
-	case err := <-errConnection: // From aclWatcher.
+	case err := <-errConnection: // From aclWatcher or tenantDeletion.
 		handler.metrics.updateForError(err)
 		return err
-	case <-handler.stopper.ShouldQuiesce():
+	case <-handler.drainCtx.Done():
+		// The drain period has elapsed.
 		err := context.Canceled
 		handler.metrics.updateForError(err)
 		return err
 	}
 }
 
+// connContext returns a context for serving a single client connection. It
+// carries the values of ctx, but is not canceled when ctx is. Instead, it is
+// canceled once the drain period that follows quiescing has elapsed, which
+// lets in-flight connections continue while the proxy shuts down. The
+// returned cancel function must be called once the connection is done.
+func (handler *proxyHandler) connContext(
+	ctx context.Context,
+) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(handler.drainCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// handshakeTimings breaks down the time spent handshaking a connection.
+type handshakeTimings struct {
+	// admit is the time spent before the startup message is read, including
+	// waiting for a handshake slot and the TLS handshake.
+	admit time.Duration
+	// setup is the time spent routing, validating and throttling the
+	// connection.
+	setup time.Duration
+	// dial is the time spent dialing the SQL pod.
+	dial time.Duration
+	// auth is the time spent authenticating the client with the SQL pod.
+	auth time.Duration
+}
+
+func (t handshakeTimings) total() time.Duration {
+	return t.admit + t.setup + t.dial + t.auth
+}
+
+// maybeLogSlowHandshake logs a warning with the breakdown of the handshake of
+// a connection if it took longer than SlowHandshakeThreshold.
+func (handler *proxyHandler) maybeLogSlowHandshake(ctx context.Context, t handshakeTimings) {
+	if handler.SlowHandshakeThreshold == 0 || t.total() <= handler.SlowHandshakeThreshold {
+		return
+	}
+	log.Warningf(ctx, "slow handshake took %s (admit=%s, setup=%s, dial=%s, auth=%s)",
+		t.total(), t.admit, t.setup, t.dial, t.auth)
+}
+
+// acquireHandshakeSlot reserves one of the MaxConcurrentHandshakes slots for
+// the calling connection, waiting for up to HandshakeQueueTimeout if none is
+// available. The returned function releases the slot, and may be called more
+// than once.
+func (handler *proxyHandler) acquireHandshakeSlot(ctx context.Context) (func(), error) {
+	if handler.handshakeSem != nil {
+		select {
+		case handler.handshakeSem <- struct{}{}:
+		default:
+			if handler.HandshakeQueueTimeout <= 0 {
+				return nil, tooManyHandshakesError
+			}
+			timer := time.NewTimer(handler.HandshakeQueueTimeout)
+			defer timer.Stop()
+			select {
+			case handler.handshakeSem <- struct{}{}:
+			case <-timer.C:
+				return nil, tooManyHandshakesError
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	handler.metrics.InFlightHandshakes.Inc(1)
+	if fn := handler.testingKnobs.afterHandshakeSlotAcquired; fn != nil {
+		fn(handler.metrics.InFlightHandshakes.Value())
+	}
+	var released bool
+	return func() {
+		if released {
+			return
+		}
+		released = true
+		handler.metrics.InFlightHandshakes.Dec(1)
+		if handler.handshakeSem != nil {
+			<-handler.handshakeSem
+		}
+	}, nil
+}
+
+// setKeepAlive enables TCP keepalive probes on conn if KeepAliveInterval is
+// set. Failures are logged, but do not affect the connection.
+func (handler *proxyHandler) setKeepAlive(ctx context.Context, conn net.Conn) {
+	if handler.KeepAliveInterval <= 0 {
+		return
+	}
+	tcpConn, err := setKeepAlive(conn, handler.KeepAliveInterval)
+	if err != nil {
+		log.Warningf(ctx, "could not enable TCP keepalive: %v", err)
+		return
+	}
+	if tcpConn != nil && handler.testingKnobs.afterSetKeepAlive != nil {
+		handler.testingKnobs.afterSetKeepAlive(tcpConn)
+	}
+}
+
+// setKeepAlive enables TCP keepalive probes with the given period on conn,
+// unwrapping TLS and PROXY protocol connections to get to the underlying TCP
+// connection. The TCP connection is returned, or nil if conn is not backed by
+// one (e.g. in-memory connections used in tests).
+func setKeepAlive(conn net.Conn, period time.Duration) (*net.TCPConn, error) {
+	for {
+		switch c := conn.(type) {
+		case *net.TCPConn:
+			if err := c.SetKeepAlive(true); err != nil {
+				return nil, err
+			}
+			if err := c.SetKeepAlivePeriod(period); err != nil {
+				return nil, err
+			}
+			return c, nil
+		case *tls.Conn:
+			conn = c.NetConn()
+		case *proxyproto.Conn:
+			conn = c.Raw()
+		default:
+			return nil, nil
+		}
+	}
+}
+
 // validateRequest validates the incoming connection by ensuring that the SQL
 // connection knows some additional information about the tenant (i.e. the
 // cluster name) before being allowed to connect.
@@ -650,6 +1098,91 @@ func (handler *proxyHandler) startPodWatcher(ctx context.Context, podWatcher cha
 	}
 }
 
+// startTenantWatcher runs on a background goroutine and listens to tenant
+// change notifications. When a tenant is deleted, all of its connections are
+// terminated.
+func (handler *proxyHandler) startTenantWatcher(
+	ctx context.Context, tenantWatcher chan *tenant.WatchTenantsResponse,
+) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case resp := <-tenantWatcher:
+			if resp.Type != tenant.EVENT_DELETED || resp.Tenant == nil || resp.Tenant.TenantID == 0 {
+				continue
+			}
+			tenID := roachpb.MustMakeTenantID(resp.Tenant.TenantID)
+			log.Infof(ctx, "terminating connections to deleted tenant %s", tenID)
+			handler.tenantDeletion.notifyDeleted(tenID)
+		}
+	}
+}
+
+// checkReady returns an error if the proxy is not ready to serve client
+// connections, i.e. if the incoming cert is invalid, or if the directory
+// server cannot be reached.
+func (handler *proxyHandler) checkReady(ctx context.Context) error {
+	if err := handler.checkIncomingCert(); err != nil {
+		return errors.Wrap(err, "incoming cert")
+	}
+	return handler.checkDirectoryConn(ctx)
+}
+
+// checkIncomingCert returns an error if the incoming cert failed to load, or
+// has expired.
+func (handler *proxyHandler) checkIncomingCert() error {
+	if handler.incomingCert == nil {
+		// Incoming connections are unencrypted.
+		return nil
+	}
+	if err := handler.incomingCert.Err(); err != nil {
+		return err
+	}
+	cert := handler.incomingCert.TLSCert()
+	if cert == nil || len(cert.Certificate) == 0 {
+		return errors.New("no certificate loaded")
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return err
+	}
+	if now := timeutil.Now(); now.After(leaf.NotAfter) {
+		return errors.Newf("certificate expired at %s", leaf.NotAfter)
+	}
+	return nil
+}
+
+// directoryReadyTimeout is the maximum amount of time checkDirectoryConn waits
+// for the directory connection to become ready.
+const directoryReadyTimeout = 500 * time.Millisecond
+
+// checkDirectoryConn returns an error if none of the connections to the
+// directory servers becomes ready within directoryReadyTimeout.
+func (handler *proxyHandler) checkDirectoryConn(ctx context.Context) error {
+	var err error
+	for _, conn := range handler.directoryConns {
+		if err = waitForConnReady(ctx, conn); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// waitForConnReady returns an error if conn does not become ready within
+// directoryReadyTimeout. An idle connection is woken up first.
+func waitForConnReady(ctx context.Context, conn *grpc.ClientConn) error {
+	ctx, cancel := context.WithTimeout(ctx, directoryReadyTimeout)
+	defer cancel()
+	conn.Connect()
+	for state := conn.GetState(); state != connectivity.Ready; state = conn.GetState() {
+		if !conn.WaitForStateChange(ctx, state) {
+			return errors.Newf("directory connection is %s", strings.ToLower(state.String()))
+		}
+	}
+	return nil
+}
+
 // incomingTLSConfig gets back the current TLS config for the incoming client
 // connection endpoint.
 func (handler *proxyHandler) incomingTLSConfig() *tls.Config {
@@ -703,7 +1236,7 @@ func (handler *proxyHandler) setupIncomingCert(ctx context.Context) error {
 // the connection parameters, and rewrites the database and options parameters,
 // if necessary.
 //
-// We currently support embedding the cluster identifier in three ways:
+// We currently support embedding the cluster identifier in four ways:
 //
 //   - Through server name identification (SNI) when using TLS connections
 //     (e.g. happy-koala-3.5xj.gcp-us-central1.cockroachlabs.cloud)
@@ -714,8 +1247,11 @@ func (handler *proxyHandler) setupIncomingCert(ctx context.Context) error {
 //     PostgreSQL supports three different ways to set a run-time parameter
 //     through its command-line options, i.e. "-c NAME=VALUE", "-cNAME=VALUE", and
 //     "--NAME=VALUE".
+//
+//   - Within the startup param named by clusterParam, if set
+//     (e.g. "crdb_cluster=happy-koala-5").
 func clusterNameAndTenantFromParams(
-	ctx context.Context, fe *FrontendAdmitInfo, metrics *metrics,
+	ctx context.Context, fe *FrontendAdmitInfo, metrics *metrics, clusterParam string,
 ) (*pgproto3.StartupMessage, string, roachpb.TenantID, error) {
 	clusterIdentifierDB, databaseName, err := parseDatabaseParam(fe.Msg.Parameters["database"])
 	if err != nil {
@@ -727,10 +1263,19 @@ func clusterNameAndTenantFromParams(
 		return fe.Msg, "", roachpb.MaxTenantID, err
 	}
 
+	var clusterIdentifierParam string
+	if clusterParam != "" {
+		var ok bool
+		clusterIdentifierParam, ok = fe.Msg.Parameters[clusterParam]
+		if ok && clusterIdentifierParam == "" {
+			return fe.Msg, "", roachpb.MaxTenantID, errors.Newf("invalid %s param", clusterParam)
+		}
+	}
+
 	var clusterName string
 	var tenID roachpb.TenantID
 	// No cluster identifiers were specified.
-	if clusterIdentifierDB == "" && clusterIdentifierOpt == "" {
+	if clusterIdentifierDB == "" && clusterIdentifierOpt == "" && clusterIdentifierParam == "" {
 		var clusterIdentifierSNI string
 		if i := strings.Index(fe.SniServerName, "."); i >= 0 {
 			clusterIdentifierSNI = fe.SniServerName[:i]
@@ -758,30 +1303,35 @@ func clusterNameAndTenantFromParams(
 	}
 
 	// Ambiguous cluster identifiers.
-	if clusterIdentifierDB != "" && clusterIdentifierOpt != "" &&
-		clusterIdentifierDB != clusterIdentifierOpt {
-		err := errors.New("multiple different cluster identifiers provided")
-		err = errors.WithHintf(err,
-			"Is '%s' or '%s' the identifier for the cluster that you're connecting to?",
-			clusterIdentifierDB, clusterIdentifierOpt)
-		err = errors.WithHint(err, clusterIdentifierHint)
-		return fe.Msg, "", roachpb.MaxTenantID, err
+	var clusterIdentifier string
+	for _, id := range []string{clusterIdentifierDB, clusterIdentifierOpt, clusterIdentifierParam} {
+		if id == "" {
+			continue
+		}
+		if clusterIdentifier != "" && clusterIdentifier != id {
+			err := errors.New("multiple different cluster identifiers provided")
+			err = errors.WithHintf(err,
+				"Is '%s' or '%s' the identifier for the cluster that you're connecting to?",
+				clusterIdentifier, id)
+			err = errors.WithHint(err, clusterIdentifierHint)
+			return fe.Msg, "", roachpb.MaxTenantID, err
+		}
+		clusterIdentifier = id
 	}
 
-	if clusterIdentifierDB != "" {
-		clusterName, tenID, err = parseClusterIdentifier(ctx, clusterIdentifierDB)
-	} else {
-		clusterName, tenID, err = parseClusterIdentifier(ctx, clusterIdentifierOpt)
-	}
+	clusterName, tenID, err = parseClusterIdentifier(ctx, clusterIdentifier)
 	if err != nil {
 		return fe.Msg, "", roachpb.MaxTenantID, err
 	}
 
 	// Make and return a copy of the startup msg so the original is not modified.
-	// We will rewrite database and options in the new startup message.
+	// We will rewrite database and options in the new startup message, and
+	// strip the cluster identifier param.
 	paramsOut := map[string]string{}
 	for key, value := range fe.Msg.Parameters {
-		if key == "database" {
+		if clusterParam != "" && key == clusterParam {
+			continue
+		} else if key == "database" {
 			paramsOut[key] = databaseName
 		} else if key == "options" {
 			if newOptionsParam != "" {
@@ -801,6 +1351,9 @@ func clusterNameAndTenantFromParams(
 	if clusterIdentifierOpt != "" {
 		metrics.ClusterOptionRoutingMethodCount.Inc(1)
 	}
+	if clusterIdentifierParam != "" {
+		metrics.ClusterParamRoutingMethodCount.Inc(1)
+	}
 	outMsg := &pgproto3.StartupMessage{
 		ProtocolVersion: fe.Msg.ProtocolVersion,
 		Parameters:      paramsOut,
@@ -808,6 +1361,43 @@ func clusterNameAndTenantFromParams(
 	return outMsg, clusterName, tenID, nil
 }
 
+// checkStartupParams returns an error if the startup message contains any of
+// the disallowed parameters. Parameter keys are compared case-insensitively.
+func checkStartupParams(msg *pgproto3.StartupMessage, disallowed []string) error {
+	for _, key := range disallowed {
+		for param := range msg.Parameters {
+			if strings.EqualFold(param, key) {
+				return errors.Newf("startup parameter %q is not allowed", param)
+			}
+		}
+	}
+	return nil
+}
+
+// addRoutingTagParams adds the routing tag parameters, i.e. the cluster name
+// and proxy node ID, to the startup message that is forwarded to the backend.
+// An error is returned if the client already sent any of these parameters.
+// The node ID parameter is skipped if nodeID is empty.
+func addRoutingTagParams(
+	msg *pgproto3.StartupMessage, prefix, clusterName, nodeID string,
+) error {
+	tags := map[string]string{prefix + "cluster_name": clusterName}
+	if nodeID != "" {
+		tags[prefix+"proxy_node_id"] = nodeID
+	}
+	for key := range tags {
+		for param := range msg.Parameters {
+			if strings.EqualFold(param, key) {
+				return errors.Newf("startup parameter %q is not allowed", param)
+			}
+		}
+	}
+	for key, value := range tags {
+		msg.Parameters[key] = value
+	}
+	return nil
+}
+
 // parseClusterIdentifier will parse an identifier received via DB, opts or SNI
 // and extract the tenant cluster name and tenant ID.
 func parseClusterIdentifier(
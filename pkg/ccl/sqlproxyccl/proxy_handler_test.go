@@ -15,9 +15,11 @@ import (
 	gosql "database/sql"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
 	"os"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -605,6 +607,263 @@ func TestLongDBName(t *testing.T) {
 	require.Equal(t, int64(1), s.metrics.RoutingErrCount.Count())
 }
 
+func TestDisallowedStartupParams(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	te := newTester()
+	defer te.Close()
+
+	defer testutils.TestingHook(&BackendDial, func(
+		_ context.Context, _ *pgproto3.StartupMessage, _ string, _ *tls.Config,
+	) (net.Conn, error) {
+		return nil, withCode(errors.New("boom"), codeParamsRoutingFailed)
+	})()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	s, addrs := newSecureProxyServer(ctx, t, stopper, &ProxyOptions{
+		RoutingRule:             "127.0.0.1:26257",
+		DisallowedStartupParams: []string{"replication"},
+	})
+
+	pgurl := fmt.Sprintf("postgres://unused:unused@%s/defaultdb?options=--cluster=tenant-cluster-28&sslmode=require&replication=database", addrs.listenAddr)
+	_ = te.TestConnectErr(ctx, t, pgurl, codeProxyRefusedConnection, `startup parameter "replication" is not allowed`)
+	require.Equal(t, int64(1), s.metrics.RefusedConnCount.Count())
+
+	// Connections without the parameter go through to the backend.
+	pgurl = fmt.Sprintf("postgres://unused:unused@%s/defaultdb?options=--cluster=tenant-cluster-28&sslmode=require", addrs.listenAddr)
+	_ = te.TestConnectErr(ctx, t, pgurl, codeParamsRoutingFailed, "boom")
+}
+
+func TestSlowHandshakeLogging(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	te := newTester()
+	defer te.Close()
+
+	const dialDelay = 200 * time.Millisecond
+	defer testutils.TestingHook(&BackendDial, func(
+		_ context.Context, _ *pgproto3.StartupMessage, _ string, _ *tls.Config,
+	) (net.Conn, error) {
+		time.Sleep(dialDelay)
+		return nil, withCode(errors.New("boom"), codeParamsRoutingFailed)
+	})()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	_, addrs := newSecureProxyServer(ctx, t, stopper, &ProxyOptions{
+		RoutingRule:            "127.0.0.1:26257",
+		SlowHandshakeThreshold: dialDelay / 2,
+	})
+
+	pgurl := fmt.Sprintf("postgres://unused:unused@%s/defaultdb?options=--cluster=tenant-cluster-28&sslmode=require", addrs.listenAddr)
+	_ = te.TestConnectErr(ctx, t, pgurl, codeParamsRoutingFailed, "boom")
+
+	testutils.SucceedsSoon(t, func() error {
+		log.FlushFiles()
+		entries, err := log.FetchEntriesFromFiles(0, math.MaxInt64, 1,
+			regexp.MustCompile(`slow handshake`), log.WithFlattenedSensitiveData)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return errors.New("slow handshake not logged")
+		}
+		re := regexp.MustCompile(`slow handshake took (\S+) \(admit=\S+, setup=\S+, dial=(\S+), auth=\S+\)`)
+		m := re.FindStringSubmatch(entries[0].Message)
+		require.NotNil(t, m, "unexpected entry %v", entries[0])
+		dial, err := time.ParseDuration(m[2])
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, dial, dialDelay)
+		return nil
+	})
+}
+
+func TestRoutingTagParams(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	te := newTester()
+	defer te.Close()
+
+	backendParams := make(chan map[string]string, 1)
+	defer testutils.TestingHook(&BackendDial, func(
+		_ context.Context, msg *pgproto3.StartupMessage, _ string, _ *tls.Config,
+	) (net.Conn, error) {
+		backendParams <- msg.Parameters
+		return nil, withCode(errors.New("boom"), codeParamsRoutingFailed)
+	})()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	s, addrs := newSecureProxyServer(ctx, t, stopper, &ProxyOptions{
+		RoutingRule:           "127.0.0.1:26257",
+		RoutingTagParamPrefix: "crdb.proxy.",
+		NodeID:                "proxy-1",
+	})
+
+	// The backend receives the routing tag.
+	pgurl := fmt.Sprintf("postgres://unused:unused@%s/defaultdb?options=--cluster=tenant-cluster-28&sslmode=require", addrs.listenAddr)
+	_ = te.TestConnectErr(ctx, t, pgurl, codeParamsRoutingFailed, "boom")
+	params := <-backendParams
+	require.Equal(t, "tenant-cluster", params["crdb.proxy.cluster_name"])
+	require.Equal(t, "proxy-1", params["crdb.proxy.proxy_node_id"])
+
+	// Clients cannot supply the routing tag themselves.
+	pgurl = fmt.Sprintf("postgres://unused:unused@%s/defaultdb?options=--cluster=tenant-cluster-28&sslmode=require&crdb.proxy.cluster_name=other", addrs.listenAddr)
+	_ = te.TestConnectErr(ctx, t, pgurl, codeProxyRefusedConnection, `startup parameter "crdb.proxy.cluster_name" is not allowed`)
+	require.Equal(t, int64(1), s.metrics.RefusedConnCount.Count())
+}
+
+func TestKeepAlive(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	te := newTester()
+	defer te.Close()
+
+	defer testutils.TestingHook(&BackendDial, func(
+		_ context.Context, _ *pgproto3.StartupMessage, _ string, _ *tls.Config,
+	) (net.Conn, error) {
+		return nil, withCode(errors.New("boom"), codeParamsRoutingFailed)
+	})()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	opts := &ProxyOptions{
+		RoutingRule:       "127.0.0.1:26257",
+		KeepAliveInterval: 10 * time.Second,
+	}
+	keepAliveConns := make(chan *net.TCPConn, 1)
+	opts.testingKnobs.afterSetKeepAlive = func(conn *net.TCPConn) {
+		keepAliveConns <- conn
+	}
+	_, addrs := newSecureProxyServer(ctx, t, stopper, opts)
+
+	pgurl := fmt.Sprintf("postgres://unused:unused@%s/defaultdb?options=--cluster=tenant-cluster-28&sslmode=require", addrs.listenAddr)
+	_ = te.TestConnectErr(ctx, t, pgurl, codeParamsRoutingFailed, "boom")
+
+	// Keepalive was enabled on the accepted client connection.
+	conn := <-keepAliveConns
+	require.Equal(t, addrs.listenAddr, conn.LocalAddr().String())
+}
+
+func TestMaxConcurrentHandshakes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	te := newTester()
+	defer te.Close()
+
+	// Hold each handshake open for a little while so that connections pile
+	// up behind the bound.
+	defer testutils.TestingHook(&BackendDial, func(
+		_ context.Context, _ *pgproto3.StartupMessage, _ string, _ *tls.Config,
+	) (net.Conn, error) {
+		time.Sleep(10 * time.Millisecond)
+		return nil, withCode(errors.New("boom"), codeParamsRoutingFailed)
+	})()
+
+	const maxHandshakes = 3
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	opts := &ProxyOptions{
+		RoutingRule:             "127.0.0.1:26257",
+		MaxConcurrentHandshakes: maxHandshakes,
+		HandshakeQueueTimeout:   time.Minute,
+	}
+	var maxInFlight int64
+	var mu syncutil.Mutex
+	opts.testingKnobs.afterHandshakeSlotAcquired = func(inFlight int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+	}
+	s, addrs := newSecureProxyServer(ctx, t, stopper, opts)
+
+	pgurl := fmt.Sprintf("postgres://unused:unused@%s/defaultdb?options=--cluster=tenant-cluster-28&sslmode=require", addrs.listenAddr)
+	const numConns = 20
+	var wg sync.WaitGroup
+	wg.Add(numConns)
+	for i := 0; i < numConns; i++ {
+		go func() {
+			defer wg.Done()
+			conn, err := pgx.Connect(ctx, pgurl)
+			if err == nil {
+				_ = conn.Close(ctx)
+			}
+			assert.Error(t, err)
+			assert.Regexp(t, "boom", err)
+		}()
+	}
+	wg.Wait()
+
+	// All connections went through the handshake, but never more than the
+	// bound at once.
+	mu.Lock()
+	defer mu.Unlock()
+	require.Greater(t, maxInFlight, int64(0))
+	require.LessOrEqual(t, maxInFlight, int64(maxHandshakes))
+	require.Equal(t, int64(0), s.metrics.InFlightHandshakes.Value())
+}
+
+// TestHandshakeAdmitTimeout verifies that a client which stalls before sending
+// its startup message gives up its handshake slot after HandshakeAdmitTimeout.
+func TestHandshakeAdmitTimeout(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	te := newTester()
+	defer te.Close()
+
+	defer testutils.TestingHook(&BackendDial, func(
+		_ context.Context, _ *pgproto3.StartupMessage, _ string, _ *tls.Config,
+	) (net.Conn, error) {
+		return nil, withCode(errors.New("boom"), codeParamsRoutingFailed)
+	})()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	_, addrs := newSecureProxyServer(ctx, t, stopper, &ProxyOptions{
+		RoutingRule:             "127.0.0.1:26257",
+		MaxConcurrentHandshakes: 1,
+		HandshakeQueueTimeout:   time.Minute,
+		HandshakeAdmitTimeout:   100 * time.Millisecond,
+	})
+
+	// This connection takes the only handshake slot, and never sends a
+	// startup message.
+	stalled, err := net.Dial("tcp", addrs.listenAddr)
+	require.NoError(t, err)
+	defer stalled.Close()
+
+	// The next connection gets the slot once the stalled one times out.
+	pgurl := fmt.Sprintf("postgres://unused:unused@%s/defaultdb?options=--cluster=tenant-cluster-28&sslmode=require", addrs.listenAddr)
+	_ = te.TestConnectErr(ctx, t, pgurl, codeParamsRoutingFailed, "boom")
+
+	// The proxy closed the stalled connection, possibly after sending it an
+	// error.
+	require.NoError(t, stalled.SetReadDeadline(timeutil.Now().Add(10*time.Second)))
+	_, err = io.ReadAll(stalled)
+	require.NoError(t, err)
+}
+
 // TestBackendDownRetry tries to connect to a unavailable backend. After 3
 // failed attempts, a "tenant not found" error simulates the tenant being
 // deleted.
@@ -855,6 +1114,52 @@ func TestProxyAgainstSecureCRDB(t *testing.T) {
 	require.Equal(t, int64(1), s.metrics.RoutingErrCount.Count())
 }
 
+func TestProxyConnectionRateLimit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	te := newTester()
+	defer te.Close()
+
+	sql, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestRequiresExplicitSQLConnection,
+	})
+	defer sql.Stopper().Stop(ctx)
+
+	ts := sql.ApplicationLayer()
+	ts.PGPreServer().(*pgwire.PreServeConnHandler).TestingSetTrustClientProvidedRemoteAddr(true)
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE USER bob WITH PASSWORD 'builder'`)
+
+	// Allow a burst of two connections, after which the rate is slow enough
+	// that no further connections are admitted during the test.
+	_, addrs := newSecureProxyServer(ctx, t, sql.Stopper(), &ProxyOptions{
+		RoutingRule:         ts.AdvSQLAddr(),
+		SkipVerify:          true,
+		ConnectionRateLimit: 0.001,
+		ConnectionRateBurst: 2,
+	})
+
+	url := fmt.Sprintf("postgres://bob:builder@%s/tenant-cluster-28.defaultdb?sslmode=require", addrs.listenAddr)
+	for i := 0; i < 2; i++ {
+		te.TestConnect(ctx, t, url, func(conn *pgx.Conn) {
+			require.NoError(t, runTestQuery(ctx, conn))
+		})
+	}
+	for i := 0; i < 3; i++ {
+		_ = te.TestConnectErr(ctx, t, url, codeProxyRefusedConnection, "too many new connections to the cluster")
+	}
+
+	// Other tenants are unaffected.
+	url = fmt.Sprintf("postgres://bob:builder@%s/tenant-cluster-29.defaultdb?sslmode=require", addrs.listenAddr)
+	te.TestConnect(ctx, t, url, func(conn *pgx.Conn) {
+		require.NoError(t, runTestQuery(ctx, conn))
+	})
+}
+
 func TestProxyTLSConf(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	testutilsccl.ServerlessOnly(t)
@@ -1356,6 +1661,108 @@ func TestDenylistUpdate(t *testing.T) {
 	require.Equal(t, int64(1), s.metrics.ExpiredClientConnCount.Count())
 }
 
+// TestDenylistProxyProtocol verifies that when the proxy sits behind a load
+// balancer speaking the PROXY protocol, the denylist is keyed on the client IP
+// carried in the PROXY header rather than on the load balancer's address.
+func TestDenylistProxyProtocol(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	te := newTester()
+	defer te.Close()
+
+	// Deny the client IP, which is only visible through the PROXY header.
+	denyList, err := os.CreateTemp("", "*_denylist.yml")
+	require.NoError(t, err)
+	defer func() { _ = os.Remove(denyList.Name()) }()
+	dlf := acl.DenylistFile{
+		Seq: 0,
+		Denylist: []*acl.DenyEntry{
+			{
+				Entity:     acl.DenyEntity{Type: acl.IPAddrType, Item: "10.20.30.40"},
+				Expiration: timeutil.Now().Add(time.Hour),
+				Reason:     "test-denied",
+			},
+		},
+	}
+	bytes, err := yaml.Marshal(&dlf)
+	require.NoError(t, err)
+	_, err = denyList.Write(bytes)
+	require.NoError(t, err)
+
+	sql, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestRequiresExplicitSQLConnection,
+	})
+	defer sql.Stopper().Stop(ctx)
+
+	ts := sql.ApplicationLayer()
+	ts.PGPreServer().(*pgwire.PreServeConnHandler).TestingSetTrustClientProvidedRemoteAddr(true)
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE USER bob WITH PASSWORD 'builder'`)
+
+	options := &ProxyOptions{
+		RoutingRule:          ts.AdvSQLAddr(),
+		SkipVerify:           true,
+		RequireProxyProtocol: true,
+		Denylist:             denyList.Name(),
+		PollConfigInterval:   10 * time.Millisecond,
+	}
+	_, addrs := newSecureProxyServer(ctx, t, sql.Stopper(), options)
+
+	timeout := 3 * time.Second
+	proxyDialer := func(sourceIP string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := (&net.Dialer{Timeout: timeout}).Dial(network, addr)
+			if err != nil {
+				return nil, err
+			}
+			header := &proxyproto.Header{
+				Version:           2,
+				Command:           proxyproto.PROXY,
+				TransportProtocol: proxyproto.TCPv4,
+				SourceAddr: &net.TCPAddr{
+					IP:   net.ParseIP(sourceIP),
+					Port: 4242,
+				},
+				DestinationAddr: conn.RemoteAddr(),
+			}
+			if err := conn.SetWriteDeadline(timeutil.Now().Add(timeout)); err != nil {
+				return nil, err
+			}
+			if _, err := header.WriteTo(conn); err != nil {
+				return nil, err
+			}
+			return conn, nil
+		}
+	}
+
+	url := fmt.Sprintf("postgres://bob:builder@%s/tenant-cluster-42.defaultdb?sslmode=require", addrs.listenAddr)
+
+	// The denied client is refused, even though it connects through the same
+	// address as every other client.
+	_ = te.TestConnectErrWithPGConfig(
+		ctx, t, url,
+		func(c *pgx.ConnConfig) {
+			c.DialFunc = proxyDialer("10.20.30.40")
+		},
+		codeProxyRefusedConnection, "connection refused",
+	)
+
+	// Other clients behind the same load balancer are unaffected.
+	te.TestConnectWithPGConfig(
+		ctx, t, url,
+		func(c *pgx.ConnConfig) {
+			c.DialFunc = proxyDialer("10.20.30.41")
+		},
+		func(conn *pgx.Conn) {
+			require.NoError(t, runTestQuery(ctx, conn))
+		},
+	)
+}
+
 func TestDirectoryConnect(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	testutilsccl.ServerlessOnly(t)
@@ -2512,6 +2919,169 @@ func TestCurConnCountMetric(t *testing.T) {
 	})
 }
 
+func TestShutdownDrain(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	// Start KV server.
+	s := serverutils.StartServerOnly(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestControlsTenantsExplicitly,
+	})
+	defer s.Stopper().Stop(ctx)
+
+	// Start a single SQL pod.
+	tenantID := serverutils.TestTenantID()
+	tenants := startTestTenantPods(ctx, t, s, tenantID, 1, base.TestingKnobs{})
+
+	// Register the SQL pod in the directory server.
+	tds := tenantdirsvr.NewTestStaticDirectoryServer(s.Stopper(), nil /* timeSource */)
+	tds.CreateTenant(tenantID, &tenant.Tenant{
+		TenantID:          tenantID.ToUint64(),
+		ClusterName:       "tenant-cluster",
+		AllowedCIDRRanges: []string{"0.0.0.0/0"},
+	})
+	tds.AddPod(tenantID, &tenant.Pod{
+		TenantID:       tenantID.ToUint64(),
+		Addr:           tenants[0].SQLAddr(),
+		State:          tenant.RUNNING,
+		StateTimestamp: timeutil.Now(),
+	})
+	require.NoError(t, tds.Start(ctx))
+
+	// Use a separate stopper for the proxy so that it can be stopped
+	// independently of the SQL pod.
+	proxyStopper := stop.NewStopper()
+	defer proxyStopper.Stop(ctx)
+
+	const drainTimeout = 3 * time.Second
+	opts := &ProxyOptions{
+		SkipVerify:                   true,
+		DisableConnectionRebalancing: true,
+		ShutdownDrainTimeout:         drainTimeout,
+	}
+	opts.testingKnobs.directoryServer = tds
+	_, addrs := newSecureProxyServer(ctx, t, proxyStopper, opts)
+	connectionString := fmt.Sprintf("postgres://testuser:hunter2@%s/?sslmode=require&options=--cluster=tenant-cluster-%s", addrs.listenAddr, tenantID)
+
+	conn, err := pgx.Connect(ctx, connectionString)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(ctx) }()
+	require.NoError(t, runTestQuery(ctx, conn))
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		proxyStopper.Stop(ctx)
+	}()
+	<-proxyStopper.ShouldQuiesce()
+	quiesceTime := timeutil.Now()
+
+	// No new connections are accepted.
+	testutils.SucceedsSoon(t, func() error {
+		newConn, err := pgx.Connect(ctx, connectionString)
+		if err == nil {
+			_ = newConn.Close(ctx)
+			return errors.New("proxy still accepting connections")
+		}
+		return nil
+	})
+
+	// The in-flight connection continues to work during the drain period.
+	require.NoError(t, runTestQuery(ctx, conn))
+	select {
+	case <-stopped:
+		t.Fatal("proxy stopped before the drain period elapsed")
+	default:
+	}
+
+	// Once the drain period has elapsed, the connection is closed, and the
+	// proxy stops.
+	select {
+	case <-stopped:
+	case <-time.After(testutils.DefaultSucceedsSoonDuration):
+		t.Fatal("proxy did not stop after the drain period")
+	}
+	require.GreaterOrEqual(t, timeutil.Since(quiesceTime), drainTimeout)
+	require.Error(t, runTestQuery(ctx, conn))
+}
+
+func TestTerminateDeletedTenantConnections(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	sql, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestRequiresExplicitSQLConnection,
+	})
+	defer sql.Stopper().Stop(ctx)
+
+	// Create a default user.
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE USER bob WITH PASSWORD 'builder'`)
+
+	// Create the directory server, with two tenants mapping to the same pod.
+	tds := tenantdirsvr.NewTestStaticDirectoryServer(sql.Stopper(), nil /* timeSource */)
+	tenant10 := roachpb.MustMakeTenantID(10)
+	tenant20 := roachpb.MustMakeTenantID(20)
+	for _, tenID := range []roachpb.TenantID{tenant10, tenant20} {
+		tds.CreateTenant(tenID, &tenant.Tenant{
+			Version:           "001",
+			TenantID:          tenID.ToUint64(),
+			ClusterName:       "my-tenant",
+			AllowedCIDRRanges: []string{"0.0.0.0/0"},
+		})
+		tds.AddPod(tenID, &tenant.Pod{
+			TenantID:       tenID.ToUint64(),
+			Addr:           sql.ApplicationLayer().AdvSQLAddr(),
+			State:          tenant.RUNNING,
+			StateTimestamp: timeutil.Now(),
+		})
+	}
+	require.NoError(t, tds.Start(ctx))
+
+	options := &ProxyOptions{
+		SkipVerify:                        true,
+		TerminateDeletedTenantConnections: true,
+	}
+	options.testingKnobs.directoryServer = tds
+	s, addrs := newSecureProxyServer(ctx, t, sql.Stopper(), options)
+
+	connect := func(tenID roachpb.TenantID) *pgx.Conn {
+		url := fmt.Sprintf("postgres://bob:builder@%s/my-tenant-%d.defaultdb?sslmode=require",
+			addrs.listenAddr, tenID.ToUint64())
+		conn, err := pgx.Connect(ctx, url)
+		require.NoError(t, err)
+		require.NoError(t, runTestQuery(ctx, conn))
+		return conn
+	}
+	conn10 := connect(tenant10)
+	defer func() { _ = conn10.Close(ctx) }()
+	conn20 := connect(tenant20)
+	defer func() { _ = conn20.Close(ctx) }()
+	require.Equal(t, int64(2), s.metrics.CurConnCount.Value())
+
+	// Deleting a tenant terminates its connections only.
+	tds.DeleteTenant(tenant10)
+	var err error
+	require.Eventually(
+		t,
+		func() bool {
+			_, err = conn10.Exec(ctx, "SELECT 1")
+			return err != nil
+		},
+		testutils.DefaultSucceedsSoonDuration, 5*time.Millisecond,
+		"Expected the connection to eventually fail",
+	)
+	require.Regexp(t, "connection reset by peer|unexpected EOF", err.Error())
+	require.Equal(t, int64(1), s.metrics.ExpiredClientConnCount.Count())
+	require.NoError(t, runTestQuery(ctx, conn20))
+}
+
 func TestClusterNameAndTenantFromParams(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	testutilsccl.ServerlessOnly(t)
@@ -2522,6 +3092,7 @@ func TestClusterNameAndTenantFromParams(t *testing.T) {
 	testCases := []struct {
 		name                string
 		sniServerName       string
+		clusterParam        string
 		params              map[string]string
 		expectedClusterName string
 		expectedTenantID    uint64
@@ -2780,6 +3351,67 @@ func TestClusterNameAndTenantFromParams(t *testing.T) {
 				require.Equal(t, int64(1), m.ClusterOptionRoutingMethodCount.Value())
 			},
 		},
+		{
+			name:         "cluster identifier in custom param",
+			clusterParam: "crdb_cluster",
+			params: map[string]string{
+				"crdb_cluster": "happy-koala-7",
+				"database":     "defaultdb",
+			},
+			expectedClusterName: "happy-koala",
+			expectedTenantID:    7,
+			expectedParams:      map[string]string{"database": "defaultdb"},
+			expectedMetrics: func(t *testing.T, m *metrics) {
+				require.Equal(t, int64(1), m.RoutingMethodCount.Count())
+				require.Equal(t, int64(1), m.ClusterParamRoutingMethodCount.Value())
+			},
+		},
+		{
+			name:         "custom param and database param agree",
+			clusterParam: "crdb_cluster",
+			params: map[string]string{
+				"crdb_cluster": "happy-koala-7",
+				"database":     "happy-koala-7.defaultdb",
+			},
+			expectedClusterName: "happy-koala",
+			expectedTenantID:    7,
+			expectedParams:      map[string]string{"database": "defaultdb"},
+			expectedMetrics: func(t *testing.T, m *metrics) {
+				require.Equal(t, int64(2), m.RoutingMethodCount.Count())
+				require.Equal(t, int64(1), m.DatabaseRoutingMethodCount.Value())
+				require.Equal(t, int64(1), m.ClusterParamRoutingMethodCount.Value())
+			},
+		},
+		{
+			name:         "custom param conflicts with database param",
+			clusterParam: "crdb_cluster",
+			params: map[string]string{
+				"crdb_cluster": "happy-tiger-8",
+				"database":     "happy-koala-7.defaultdb",
+			},
+			expectedError: "multiple different cluster identifiers provided",
+			expectedHint: "Is 'happy-koala-7' or 'happy-tiger-8' the identifier for the cluster that you're connecting to?\n--\n" +
+				clusterIdentifierHint,
+		},
+		{
+			name:          "empty custom param",
+			clusterParam:  "crdb_cluster",
+			params:        map[string]string{"crdb_cluster": ""},
+			expectedError: "invalid crdb_cluster param",
+		},
+		{
+			name:          "invalid cluster identifier in custom param",
+			clusterParam:  "crdb_cluster",
+			params:        map[string]string{"crdb_cluster": "happy-koala-0"},
+			expectedError: "invalid cluster identifier 'happy-koala-0'",
+			expectedHint:  "Tenant ID 0 is invalid.",
+		},
+		{
+			name:          "custom param is ignored unless configured",
+			params:        map[string]string{"crdb_cluster": "happy-koala-7"},
+			expectedError: "missing cluster identifier",
+			expectedHint:  clusterIdentifierHint,
+		},
 		{
 			name:                "leading 0s are ok",
 			params:              map[string]string{"database": "happy-koala-0-07.defaultdb"},
@@ -2803,7 +3435,7 @@ func TestClusterNameAndTenantFromParams(t *testing.T) {
 			}
 
 			fe := &FrontendAdmitInfo{Msg: msg, SniServerName: tc.sniServerName}
-			outMsg, clusterName, tenantID, err := clusterNameAndTenantFromParams(ctx, fe, &m)
+			outMsg, clusterName, tenantID, err := clusterNameAndTenantFromParams(ctx, fe, &m, tc.clusterParam)
 			if tc.expectedError == "" {
 				require.NoErrorf(t, err, "failed test case\n%+v", tc)
 
@@ -2829,6 +3461,7 @@ func TestClusterNameAndTenantFromParams(t *testing.T) {
 				require.Zero(t, m.SNIRoutingMethodCount.Value())
 				require.Zero(t, m.DatabaseRoutingMethodCount.Value())
 				require.Zero(t, m.ClusterOptionRoutingMethodCount.Value())
+				require.Zero(t, m.ClusterParamRoutingMethodCount.Value())
 			}
 		})
 	}
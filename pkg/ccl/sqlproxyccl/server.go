@@ -10,10 +10,12 @@ package sqlproxyccl
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"sort"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
@@ -74,6 +76,12 @@ func NewServer(ctx context.Context, stopper *stop.Stopper, options ProxyOptions)
 	mux.HandleFunc("/_status/vars/", s.handleVars)
 	mux.HandleFunc("/_status/healthz/", s.handleHealth)
 	mux.HandleFunc("/_status/cancel/", s.handleCancel)
+	mux.HandleFunc("/_status/connections", s.handleConnections)
+
+	// /health and /ready are meant to be used as liveness and readiness
+	// probes respectively.
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/ready", s.handleReady)
 
 	// Taken from pprof's `init()` method. See:
 	// https://golang.org/src/net/http/pprof/pprof.go
@@ -106,6 +114,22 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte("OK"))
 }
 
+// handleReady reports whether the proxy is ready to serve client connections,
+// i.e. whether the directory server is reachable, and the incoming cert is
+// valid. Unlike handleHealth, a failure here should only take the proxy out of
+// rotation, and not result in it being restarted.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if err := s.handler.checkReady(r.Context()); err != nil {
+		log.Warningf(r.Context(), "proxy is not ready: %v", err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	// Explicitly ignore any errors from writing our body as there's
+	// nothing to be done if the write fails.
+	_, _ = w.Write([]byte("OK"))
+}
+
 func (s *Server) handleVars(w http.ResponseWriter, r *http.Request) {
 	contentType := expfmt.Negotiate(r.Header)
 	w.Header().Set(httputil.ContentTypeHeader, string(contentType))
@@ -160,10 +184,39 @@ func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
 	retErr = s.handler.handleCancelRequest(p, false /* allowForward */)
 }
 
+// tenantConnections is the number of active connections of a single tenant,
+// as reported by the /_status/connections endpoint.
+type tenantConnections struct {
+	TenantID    uint64 `json:"tenant_id"`
+	Connections int    `json:"connections"`
+}
+
+// handleConnections reports the number of active connections of every tenant
+// with at least one connection to this proxy, sorted by tenant ID.
+func (s *Server) handleConnections(w http.ResponseWriter, r *http.Request) {
+	counts := s.handler.balancer.GetTracker().GetConnCounts()
+	res := make([]tenantConnections, 0, len(counts))
+	for tenantID, n := range counts {
+		res = append(res, tenantConnections{
+			TenantID:    tenantID.ToUint64(),
+			Connections: n,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].TenantID < res[j].TenantID
+	})
+	w.Header().Set(httputil.ContentTypeHeader, httputil.JSONContentType)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Errorf(r.Context(), "%v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 // ServeHTTP starts the proxy's HTTP server on the given listener.
 // The server provides Prometheus metrics at /_status/vars,
-// a health check endpoint at /_status/healthz, and pprof debug
-// endpoints at /debug/pprof.
+// health check endpoints at /_status/healthz and /health, a readiness
+// check endpoint at /ready, per-tenant connection counts at
+// /_status/connections, and pprof debug endpoints at /debug/pprof.
 func (s *Server) ServeHTTP(ctx context.Context, ln net.Listener) error {
 	if s.handler.RequireProxyProtocol {
 		ln = &proxyproto.Listener{
@@ -273,6 +326,10 @@ func (s *Server) serve(ctx context.Context, ln net.Listener, requireProxyProtoco
 			defer func() { _ = conn.Close() }()
 			s.metrics.CurConnCount.Inc(1)
 			defer s.metrics.CurConnCount.Dec(1)
+			// Serve the connection under a context which survives quiescing
+			// until the drain period has elapsed.
+			ctx, cancel := s.handler.connContext(ctx)
+			defer cancel()
 			remoteAddr := conn.RemoteAddr()
 			ctxWithTag := logtags.AddTag(ctx, "client", log.SafeOperational(remoteAddr))
 			if err := s.handler.handle(ctxWithTag, conn, requireProxyProtocol); err != nil {
@@ -280,6 +337,8 @@ func (s *Server) serve(ctx context.Context, ln net.Listener, requireProxyProtoco
 			}
 		})
 		if err != nil {
+			// The stopper is quiescing, so no new connections are accepted.
+			_ = conn.Close()
 			return err
 		}
 	}
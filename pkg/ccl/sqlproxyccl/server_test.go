@@ -10,16 +10,22 @@ package sqlproxyccl
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/balancer"
+	"github.com/cockroachdb/cockroach/pkg/ccl/sqlproxyccl/tenantdirsvr"
 	"github.com/cockroachdb/cockroach/pkg/ccl/testutilsccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -45,6 +51,94 @@ func TestHandleHealth(t *testing.T) {
 	require.Equal(t, []byte("OK"), out)
 }
 
+func TestHandleConnections(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	proxyServer, err := NewServer(ctx, stopper, ProxyOptions{})
+	require.NoError(t, err)
+
+	getConnections := func() []tenantConnections {
+		rw := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/_status/connections", nil)
+		proxyServer.mux.ServeHTTP(rw, r)
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		var res []tenantConnections
+		require.NoError(t, json.NewDecoder(rw.Body).Decode(&res))
+		return res
+	}
+
+	// No connections.
+	require.Empty(t, getConnections())
+
+	// Open two connections to tenant 20, and one to tenant 10.
+	tracker := proxyServer.handler.balancer.GetTracker()
+	tenant10, tenant20 := roachpb.MustMakeTenantID(10), roachpb.MustMakeTenantID(20)
+	sa1 := balancer.NewServerAssignment(tenant20, tracker, nil /* owner */, "127.0.0.10:80")
+	sa2 := balancer.NewServerAssignment(tenant20, tracker, nil /* owner */, "127.0.0.20:80")
+	sa3 := balancer.NewServerAssignment(tenant10, tracker, nil /* owner */, "127.0.0.30:80")
+	defer sa2.Close()
+	defer sa3.Close()
+
+	require.Equal(t, []tenantConnections{
+		{TenantID: 10, Connections: 1},
+		{TenantID: 20, Connections: 2},
+	}, getConnections())
+
+	// Close one of tenant 20's connections.
+	sa1.Close()
+	require.Equal(t, []tenantConnections{
+		{TenantID: 10, Connections: 1},
+		{TenantID: 20, Connections: 1},
+	}, getConnections())
+}
+
+func TestHandleReady(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	tds := tenantdirsvr.NewTestStaticDirectoryServer(stopper, nil /* timeSource */)
+	require.NoError(t, tds.Start(ctx))
+
+	opts := ProxyOptions{}
+	opts.testingKnobs.directoryServer = tds
+	proxyServer, err := NewServer(ctx, stopper, opts)
+	require.NoError(t, err)
+
+	probe := func(path string) int {
+		rw := httptest.NewRecorder()
+		proxyServer.mux.ServeHTTP(rw, httptest.NewRequest("GET", path, nil))
+		return rw.Code
+	}
+	waitForReady := func(expected int) {
+		testutils.SucceedsSoon(t, func() error {
+			if code := probe("/ready"); code != expected {
+				return errors.Newf("expected /ready to return %d, but got %d", expected, code)
+			}
+			return nil
+		})
+		// Liveness is not affected by the directory's availability.
+		require.Equal(t, http.StatusOK, probe("/health"))
+	}
+
+	waitForReady(http.StatusOK)
+
+	// Take the directory server down.
+	tds.Stop(ctx)
+	waitForReady(http.StatusServiceUnavailable)
+
+	// Bring the directory server back up.
+	require.NoError(t, tds.Start(ctx))
+	waitForReady(http.StatusOK)
+}
+
 func TestHandleVars(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	testutilsccl.ServerlessOnly(t)
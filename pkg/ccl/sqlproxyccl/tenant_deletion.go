@@ -0,0 +1,68 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// tenantDeletionWatcher notifies connections when their tenant gets deleted,
+// so that they can be terminated promptly rather than lingering until the
+// SQL pod drops them.
+type tenantDeletionWatcher struct {
+	mu struct {
+		syncutil.Mutex
+		nextID int64
+		// listeners holds the callbacks of each tenant's connections, by
+		// listener ID.
+		listeners map[roachpb.TenantID]map[int64]func()
+	}
+}
+
+func newTenantDeletionWatcher() *tenantDeletionWatcher {
+	w := &tenantDeletionWatcher{}
+	w.mu.listeners = make(map[roachpb.TenantID]map[int64]func())
+	return w
+}
+
+// listen registers callback to be called once the given tenant is deleted.
+// The returned function removes the listener; callback is not called after it
+// returns. Callbacks must not block.
+func (w *tenantDeletionWatcher) listen(tenantID roachpb.TenantID, callback func()) func() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.mu.nextID
+	w.mu.nextID++
+	listeners, ok := w.mu.listeners[tenantID]
+	if !ok {
+		listeners = make(map[int64]func())
+		w.mu.listeners[tenantID] = listeners
+	}
+	listeners[id] = callback
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.mu.listeners[tenantID], id)
+		if len(w.mu.listeners[tenantID]) == 0 {
+			delete(w.mu.listeners, tenantID)
+		}
+	}
+}
+
+// notifyDeleted calls the callbacks of all the listeners of the given tenant.
+func (w *tenantDeletionWatcher) notifyDeleted(tenantID roachpb.TenantID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, callback := range w.mu.listeners[tenantID] {
+		callback()
+	}
+}
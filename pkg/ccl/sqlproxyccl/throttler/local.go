@@ -20,6 +20,8 @@ import (
 
 var errRequestDenied = errors.New("request denied")
 
+var errRateLimited = errors.New("connection rate limit exceeded")
+
 type timeNow func() time.Time
 
 // localService is an throttler service that manages state purely in local
@@ -30,16 +32,25 @@ type timeNow func() time.Time
 // limit for an (ip, tenant) is removed once there is a successful connection between
 // the ip address and the tenant. The primary intent of this mechanism is to limit
 // the number of credential guesses an ip address can make.
+//
+// localService can also limit the rate of new connections to each tenant
+// using a token bucket, independently of whether they authenticate
+// successfully.
 type localService struct {
 	clock        timeNow
 	maxCacheSize int
 	baseDelay    time.Duration
 	maxDelay     time.Duration
+	connRate     float64
+	connBurst    int
 
 	mu struct {
 		syncutil.Mutex
 		// throttleCache is effectively a map[ConnectionTags]*throttle
 		throttleCache *cache.UnorderedCache
+		// rateCache is effectively a map[string]*tokenBucket, keyed by tenant
+		// ID.
+		rateCache *cache.UnorderedCache
 	}
 }
 
@@ -53,6 +64,16 @@ func WithBaseDelay(d time.Duration) LocalOption {
 	}
 }
 
+// WithConnectionRateLimit limits new connections to each tenant to rate per
+// second, with bursts of up to burst connections. A rate of zero disables the
+// limit.
+func WithConnectionRateLimit(rate float64, burst int) LocalOption {
+	return func(s *localService) {
+		s.connRate = rate
+		s.connBurst = burst
+	}
+}
+
 // NewLocalService returns an throttler service that manages state purely in
 // local memory.
 func NewLocalService(opts ...LocalOption) Service {
@@ -67,6 +88,7 @@ func NewLocalService(opts ...LocalOption) Service {
 		ShouldEvict: func(size int, key, value interface{}) bool { return s.maxCacheSize < size },
 	}
 	s.mu.throttleCache = cache.NewUnorderedCache(cacheConfig)
+	s.mu.rateCache = cache.NewUnorderedCache(cacheConfig)
 
 	for _, opt := range opts {
 		opt(s)
@@ -128,3 +150,25 @@ func (s *localService) ReportAttempt(
 
 	return nil
 }
+
+func (s *localService) RateCheck(connection ConnectionTags) error {
+	if s.connRate <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock()
+	var bucket *tokenBucket
+	if b, ok := s.mu.rateCache.Get(connection.TenantID); ok && b != nil {
+		bucket = b.(*tokenBucket)
+	} else {
+		bucket = newTokenBucket(s.connRate, s.connBurst, now)
+		s.mu.rateCache.Add(connection.TenantID, bucket)
+	}
+	if !bucket.tryTake(now) {
+		return errRateLimited
+	}
+	return nil
+}
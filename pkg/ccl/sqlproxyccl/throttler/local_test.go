@@ -144,3 +144,45 @@ func TestRacingRequests(t *testing.T) {
 		require.Equal(t, l.nextTime, nextTime)
 	}
 }
+
+func TestRateCheckLimitsConnectionRate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testutilsccl.ServerlessOnly(t)
+
+	throttle := newTestLocalService(WithConnectionRateLimit(4 /* rate */, 5 /* burst */))
+	tenant1 := ConnectionTags{IP: "1.1.1.1", TenantID: "1"}
+	tenant2 := ConnectionTags{IP: "1.1.1.1", TenantID: "2"}
+
+	// countAdmitted opens n connections spaced step apart and returns how
+	// many were admitted.
+	countAdmitted := func(connection ConnectionTags, n int, step time.Duration) int {
+		count := 0
+		for i := 0; i < n; i++ {
+			throttle.clock.advance(step)
+			if err := throttle.RateCheck(connection); err == nil {
+				count++
+			} else {
+				require.Equal(t, errRateLimited, err)
+			}
+		}
+		return count
+	}
+
+	// A burst of connections is admitted up to the burst size, and the excess
+	// is refused.
+	require.Equal(t, 5, countAdmitted(tenant1, 100, 0))
+
+	// The limit is per tenant.
+	require.Equal(t, 5, countAdmitted(tenant2, 100, 0))
+
+	// Connections opened faster than the rate are refused, while connections
+	// within the rate are admitted.
+	require.Equal(t, 25, countAdmitted(tenant1, 100, 62500*time.Microsecond))
+	require.Equal(t, 10, countAdmitted(tenant1, 10, 250*time.Millisecond))
+
+	// A zero rate disables the limit.
+	unlimited := newTestLocalService()
+	for i := 0; i < 100; i++ {
+		require.NoError(t, unlimited.RateCheck(tenant1))
+	}
+}
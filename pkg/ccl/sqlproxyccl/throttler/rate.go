@@ -0,0 +1,49 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package throttler
+
+import (
+	"math"
+	"time"
+)
+
+// tokenBucket is a token bucket which refills at rate tokens per second, up
+// to burst tokens. Each admitted operation takes one token.
+type tokenBucket struct {
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastTime time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:     rate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastTime: now,
+	}
+}
+
+// tryTake takes a token from the bucket if one is available at the given
+// time, and returns whether it did.
+func (b *tokenBucket) tryTake(now time.Time) bool {
+	if elapsed := now.Sub(b.lastTime); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.lastTime = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -50,4 +50,9 @@ type Service interface {
 	// information a malicious user gets from using racing requests to guess
 	// multiple passwords in one throttle window.
 	ReportAttempt(context context.Context, connection ConnectionTags, throttleTime time.Time, status AttemptStatus) error
+
+	// RateCheck determines whether a new connection should be allowed to
+	// proceed. Unlike LoginCheck, it limits the rate of all new connections
+	// to a tenant, regardless of whether they authenticate successfully.
+	RateCheck(connection ConnectionTags) error
 }
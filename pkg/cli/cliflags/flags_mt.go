@@ -37,6 +37,37 @@ wait for the tenant id to be fully written to the file (with a newline character
 		Description: "Denylist file to limit access to IP addresses and tenant ids.",
 	}
 
+	DisallowedStartupParams = FlagInfo{
+		Name: "disallowed-startup-params",
+		Description: `Comma-separated list of pgwire startup parameters (e.g.
+replication) which cause connections to be rejected by the proxy.`,
+	}
+
+	ClusterIdentifierParam = FlagInfo{
+		Name: "cluster-identifier-param",
+		Description: `If set, the name of a pgwire startup parameter (e.g.
+crdb_cluster) from which the proxy also extracts the cluster identifier.`,
+	}
+
+	RoutingTagParamPrefix = FlagInfo{
+		Name: "routing-tag-param-prefix",
+		Description: `If set, the proxy adds the resolved cluster name and the
+proxy's node ID to the startup message forwarded to the backend, as the
+<prefix>cluster_name and <prefix>proxy_node_id parameters.`,
+	}
+
+	ProxyNodeID = FlagInfo{
+		Name:        "node-id",
+		Description: "Identifier of this proxy instance, used in the routing tag.",
+	}
+
+	ConnectionTracingFile = FlagInfo{
+		Name: "connection-tracing-file",
+		Description: `Config file listing tenant ids and IP addresses whose
+connections should have the types of their pgwire messages logged. Used for
+debugging.`,
+	}
+
 	AllowList = FlagInfo{
 		Name:        "allowlist-file",
 		Description: "Allow file to limit access to tenants based on IP addresses.",
@@ -78,8 +109,11 @@ wait for the tenant id to be fully written to the file (with a newline character
 	}
 
 	DirectoryAddr = FlagInfo{
-		Name:        "directory",
-		Description: "Directory address of the service doing resolution of tenants to their IP addresses.",
+		Name: "directory",
+		Description: `Directory address of the service doing resolution of tenants
+to their IP addresses. A comma-separated list of addresses may be given, in
+which case the proxy fails over to the next address when the preceding
+directory servers are unavailable.`,
 	}
 
 	// TODO(chrisseto): Remove skip-verify as a CLI option. It should only be
@@ -99,6 +133,17 @@ wait for the tenant id to be fully written to the file (with a newline character
 		Description: "If true, proxy will not attempt to rebalance connections.",
 	}
 
+	CanaryPodVersion = FlagInfo{
+		Name: "canary-pod-version",
+		Description: `SQL pod version which should receive --canary-pod-percent
+percent of new connections to tenants running multiple pod versions.`,
+	}
+
+	CanaryPodPercent = FlagInfo{
+		Name:        "canary-pod-percent",
+		Description: "Percentage of new connections routed to pods running --canary-pod-version.",
+	}
+
 	// TODO(joel): Remove this flag, and use --listen-addr for a non-proxy
 	// protocol listener, and use --proxy-protocol-listen-addr for a proxy
 	// protocol listener.
@@ -125,6 +170,90 @@ listeners, if the headers are allowed.`,
 		Description: "Polling interval changes in config file.",
 	}
 
+	ShutdownDrainTimeout = FlagInfo{
+		Name: "shutdown-drain-timeout",
+		Description: `Time for which in-flight connections are allowed to continue
+once the proxy is stopping. No new connections are accepted during that time.`,
+	}
+
+	KeepAliveInterval = FlagInfo{
+		Name: "keepalive-interval",
+		Description: `Interval between TCP keepalive probes on client and backend
+connections. If zero, the system defaults are used.`,
+	}
+
+	BackendDialTimeout = FlagInfo{
+		Name: "backend-dial-timeout",
+		Description: `Maximum time a single attempt at dialing a SQL pod may take
+before it is abandoned and retried.`,
+	}
+
+	ConnectionRateLimit = FlagInfo{
+		Name: "connection-rate-limit",
+		Description: `Maximum rate, in connections per second, at which new
+connections to a single tenant are accepted. Connections beyond the rate are
+refused. If zero, connections are not rate limited.`,
+	}
+
+	ConnectionRateBurst = FlagInfo{
+		Name: "connection-rate-burst",
+		Description: `Number of connections to a single tenant that may be
+accepted at once, above --connection-rate-limit.`,
+	}
+
+	BackendBreakerThreshold = FlagInfo{
+		Name: "backend-breaker-threshold",
+		Description: `Number of consecutive failures to dial a tenant's SQL pods
+within --backend-breaker-window after which new connections to that tenant fail
+fast. If zero, connections are never failed fast.`,
+	}
+
+	BackendBreakerWindow = FlagInfo{
+		Name: "backend-breaker-window",
+		Description: `Time window within which consecutive dial failures count
+towards --backend-breaker-threshold.`,
+	}
+
+	BackendBreakerCooldown = FlagInfo{
+		Name: "backend-breaker-cooldown",
+		Description: `Interval between probe connections to a tenant whose
+connections are failing fast. Connections are let through again once a probe
+succeeds.`,
+	}
+
+	MaxConcurrentHandshakes = FlagInfo{
+		Name: "max-concurrent-handshakes",
+		Description: `Maximum number of connections that may be performing TLS and
+authentication handshakes at the same time. If zero, there is no limit.`,
+	}
+
+	HandshakeQueueTimeout = FlagInfo{
+		Name: "handshake-queue-timeout",
+		Description: `Maximum time a new connection waits for a handshake slot once
+max-concurrent-handshakes is reached. If zero, excess connections are refused
+immediately.`,
+	}
+
+	HandshakeAdmitTimeout = FlagInfo{
+		Name: "handshake-admit-timeout",
+		Description: `Maximum time a new connection holding a handshake slot may take
+to negotiate TLS and send its startup message. Only applies if
+max-concurrent-handshakes is set.`,
+	}
+
+	SlowHandshakeThreshold = FlagInfo{
+		Name: "slow-handshake-threshold",
+		Description: `Duration after which the handshake of a new connection is
+logged as slow, along with the time spent in each of its phases. If zero, slow
+handshakes are not logged.`,
+	}
+
+	TerminateDeletedTenantConnections = FlagInfo{
+		Name: "terminate-deleted-tenant-connections",
+		Description: `If true, connections to a tenant are closed as soon as the
+directory reports that the tenant was deleted.`,
+	}
+
 	TestDirectoryListenPort = FlagInfo{
 		Name:        "port",
 		Description: "Test directory server binds and listens on this port.",
@@ -381,7 +381,7 @@ var specs = []stmtSpec{
 	{
 		name:    "alter_changefeed",
 		stmt:    "alter_changefeed_stmt",
-		replace: map[string]string{"a_expr": "job_id", "alter_changefeed_cmds": "( 'ADD' target ( ( ',' target ) )* ( 'WITH' ( initial_scan | no_initial_scan ) )? | 'DROP' target ( ( ',' target ) )* | ( 'SET' | 'UNSET' ) option ( ( ',' option ) )* )+"},
+		replace: map[string]string{"a_expr": "job_id", "alter_changefeed_cmds": "( 'ADD' target ( ( ',' target ) )* ( 'WITH' ( initial_scan | no_initial_scan ) )? | 'ADD' '(' 'SELECT' target_list 'FROM' target ( 'WHERE' a_expr )? ')' | 'DROP' target ( ( ',' target ) )* | ( 'SET' | 'UNSET' ) option ( ( ',' option ) )* )+"},
 		unlink:  []string{"job_id", "target", "option", "initial_scan", "no_initial_scan"},
 	},
 	{
@@ -1295,10 +1295,45 @@ func (r *Registry) PauseRequested(
 	ctx context.Context, txn isql.Txn, id jobspb.JobID, reason string,
 ) error {
 	return r.UpdateJobWithTxn(ctx, id, txn, func(txn isql.Txn, md JobMetadata, ju *JobUpdater) error {
-		return ju.PauseRequestedWithFunc(ctx, txn, md, nil /* fn */, reason)
+		return r.PauseRequestedWithHook(ctx, txn, md, ju, reason)
 	})
 }
 
+// PauseRequestedWithHook marks the job described by md as pause-requested
+// using ju. If the job's resumer implements PauseRequester, its
+// OnPauseRequest hook is run in txn first, and any changes it makes to the
+// job's progress are persisted along with the new status.
+func (r *Registry) PauseRequestedWithHook(
+	ctx context.Context, txn isql.Txn, md JobMetadata, ju *JobUpdater, reason string,
+) error {
+	if md.Status == StatusPauseRequested || md.Status == StatusPaused {
+		return nil
+	}
+	job := &Job{registry: r, id: md.ID}
+	job.mu.payload = *md.Payload
+	if md.Progress != nil {
+		job.mu.progress = *md.Progress
+	}
+	resumer, err := r.createResumer(job)
+	if err != nil {
+		return err
+	}
+	pr, ok := resumer.(PauseRequester)
+	if !ok || md.Progress == nil {
+		return ju.PauseRequestedWithFunc(ctx, txn, md, nil /* fn */, reason)
+	}
+	onPauseRequest := func(ctx context.Context, md JobMetadata, ju *JobUpdater) error {
+		execCtx, cleanup := r.execCtx(ctx, "pause-request", md.Payload.UsernameProto.Decode())
+		defer cleanup()
+		if err := pr.OnPauseRequest(ctx, execCtx, txn, md.Progress); err != nil {
+			return err
+		}
+		ju.UpdateProgress(md.Progress)
+		return nil
+	}
+	return ju.PauseRequestedWithFunc(ctx, txn, md, onPauseRequest, reason)
+}
+
 // Unpause changes the paused job with id to running or reverting using the
 // specified txn (may be nil).
 func (r *Registry) Unpause(ctx context.Context, txn isql.Txn, id jobspb.JobID) error {
@@ -1373,6 +1408,17 @@ type Resumer interface {
 	CollectProfile(ctx context.Context, execCtx interface{}) error
 }
 
+// PauseRequester is an extension of Resumer which allows job implementers to
+// inject logic during the transition to pause-requested.
+type PauseRequester interface {
+	Resumer
+
+	// OnPauseRequest is called in the transaction that moves a job to
+	// pause-requested. If an error is returned, the pause request fails.
+	// execCtx is a sql.JobExecCtx. Changes made to progress are persisted.
+	OnPauseRequest(ctx context.Context, execCtx interface{}, txn isql.Txn, progress *jobspb.Progress) error
+}
+
 // RegisterOption is the template for options passed to the RegisterConstructor
 // function.
 type RegisterOption func(opts *registerOptions)
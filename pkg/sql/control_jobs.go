@@ -79,7 +79,7 @@ func (n *controlJobsNode) startExec(params runParams) error {
 				}
 				switch n.desiredStatus {
 				case jobs.StatusPaused:
-					return ju.PauseRequested(params.ctx, txn, md, n.reason)
+					return reg.PauseRequestedWithHook(params.ctx, txn, md, ju, n.reason)
 				case jobs.StatusRunning:
 					return ju.Unpaused(params.ctx, md)
 				case jobs.StatusCanceled:
@@ -1686,6 +1686,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 
 %type <tree.ChangefeedTargets> changefeed_targets
 %type <tree.ChangefeedTarget> changefeed_target
+%type <tree.ChangefeedTargets> alter_changefeed_add_targets alter_changefeed_add_target
 %type <tree.BackupTargetList> backup_targets
 %type <*tree.BackupTargetList> opt_backup_targets
 
@@ -6446,6 +6447,12 @@ explain_option_list:
 // %Category: CCL
 // %Text:
 // ALTER CHANGEFEED <job_id> {{ADD|DROP <targets...>} | SET <options...>}...
+//
+// A target added with ADD may list several column families of a table:
+//   ALTER CHANGEFEED <job_id> ADD <table> FAMILY (<family>, ...)
+//
+// or be a changefeed expression projecting and filtering the rows of a table:
+//   ALTER CHANGEFEED <job_id> ADD (SELECT <targets> FROM <table> [WHERE <expr>])
 alter_changefeed_stmt:
   ALTER CHANGEFEED a_expr alter_changefeed_cmds
   {
@@ -6468,13 +6475,31 @@ alter_changefeed_cmds:
 
 alter_changefeed_cmd:
   // ALTER CHANGEFEED <job_id> ADD [TABLE] ...
-  ADD changefeed_targets opt_with_options
+  ADD alter_changefeed_add_targets opt_with_options
   {
     $$.val = &tree.AlterChangefeedAddTarget{
       Targets: $2.changefeedTargets(),
       Options: $3.kvOptions(),
     }
   }
+  // ALTER CHANGEFEED <job_id> ADD (SELECT ... FROM <table> [WHERE ...])
+| ADD '(' SELECT /*$4=*/target_list FROM /*$6=*/changefeed_target_expr /*$7=*/opt_where_clause ')' /*$9=*/opt_with_options
+  {
+    target, err := tree.ChangefeedTargetFromTableExpr($6.tblExpr())
+    if err != nil {
+      return setErr(sqllex, err)
+    }
+
+    $$.val = &tree.AlterChangefeedAddTarget{
+      Targets: tree.ChangefeedTargets{target},
+      Options: $9.kvOptions(),
+      Select:  &tree.SelectClause{
+         Exprs: $4.selExprs(),
+         From:  tree.From{Tables: tree.TableExprs{$6.tblExpr()}},
+         Where: tree.NewWhere(tree.AstWhere, $7.expr()),
+      },
+    }
+  }
   // ALTER CHANGEFEED <job_id> DROP [TABLE] ...
 | DROP changefeed_targets
   {
@@ -6495,6 +6520,36 @@ alter_changefeed_cmd:
     }
   }
 
+alter_changefeed_add_targets:
+  alter_changefeed_add_target
+  {
+    $$.val = $1.changefeedTargets()
+  }
+| alter_changefeed_add_targets ',' alter_changefeed_add_target
+  {
+    $$.val = append($1.changefeedTargets(), $3.changefeedTargets()...)
+  }
+
+alter_changefeed_add_target:
+  changefeed_target
+  {
+    $$.val = tree.ChangefeedTargets{$1.changefeedTarget()}
+  }
+  // ALTER CHANGEFEED <job_id> ADD [TABLE] <table> FAMILY (<family>, ...)
+  // adds one target per listed column family.
+| opt_table_prefix table_name FAMILY '(' name_list ')'
+  {
+    families := $5.nameList()
+    targets := make(tree.ChangefeedTargets, 0, len(families))
+    for _, family := range families {
+      targets = append(targets, tree.ChangefeedTarget{
+        TableName:  $2.unresolvedObjectName().ToUnresolvedName(),
+        FamilyName: family,
+      })
+    }
+    $$.val = targets
+  }
+
 // %Help: ALTER BACKUP - alter an existing backup's encryption keys
 // %Category: CCL
 // %Text:
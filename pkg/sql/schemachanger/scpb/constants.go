@@ -73,3 +73,24 @@ func AsTargetStatus(s Status) TargetStatus {
 		return InvalidTarget
 	}
 }
+
+// ValidStatusTransition returns false if an element with the current status
+// can never reach the target status, and true otherwise. Elements on the
+// dropping path can't be made public again, and only elements targeting
+// TRANSIENT_ABSENT may be in a TRANSIENT_ status.
+func ValidStatusTransition(current, target Status) bool {
+	if current == Status_UNKNOWN {
+		return false
+	}
+	_, isTransient := GetNonTransientEquivalent(current)
+	switch AsTargetStatus(target) {
+	case ToPublic:
+		return !isTransient && current != Status_DROPPED && current != Status_TXN_DROPPED
+	case ToAbsent:
+		return !isTransient
+	case Transient:
+		return current != Status_DROPPED && current != Status_TXN_DROPPED
+	default:
+		return false
+	}
+}
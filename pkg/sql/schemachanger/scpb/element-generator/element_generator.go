@@ -17,6 +17,7 @@ import (
 	"os"
 	"regexp"
 	"sort"
+	"strings"
 	"text/template"
 
 	"github.com/cockroachdb/cockroach/pkg/cli/exit"
@@ -43,8 +44,16 @@ func run(in, out string) error {
 	if err != nil {
 		return err
 	}
+	elementIDs, err := getElementIDFields(in, elementNames)
+	if err != nil {
+		return err
+	}
+	funcs := template.FuncMap{
+		"descIDField": func(name string) string { return elementIDs[name].descID },
+		"subIDFields": func(name string) []string { return elementIDs[name].subIDs },
+	}
 	var buf bytes.Buffer
-	if err := template.Must(template.New("templ").Parse(`{{- /**/ -}}
+	if err := template.Must(template.New("templ").Funcs(funcs).Parse(`{{- /**/ -}}
 // Copyright 2021 The Cockroach Authors.
 //
 // Use of this software is governed by the Business Source License
@@ -59,7 +68,12 @@ func run(in, out string) error {
 
 package scpb
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
 {{ range . }}
 
 func (e {{ . }}) element() {}
@@ -113,6 +127,61 @@ func (e* ElementProto) SetElement(element Element) {
 	}
 }
 //
+// CloneElement returns a deep copy of the element.
+func CloneElement(elem Element) Element {
+	switch t := elem.(type) {
+		default:
+			panic(fmt.Sprintf("unknown type %T", t))
+{{ range . }}
+		case *{{ . }}:
+			return protoutil.Clone(t).(*{{ . }})
+{{- end -}}
+	}
+}
+//
+// ElementsEqual returns true if both elements are of the same type and are
+// structurally equal.
+func ElementsEqual(a, b Element) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch t := a.(type) {
+		default:
+			panic(fmt.Sprintf("unknown type %T", t))
+{{ range . }}
+		case *{{ . }}:
+			o, ok := b.(*{{ . }})
+			return ok && t.Equal(o)
+{{- end -}}
+	}
+}
+//
+// ElementIDs returns the ID of the descriptor to which the element belongs,
+// along with the IDs of the columns, indexes, constraints or families which
+// identify it within that descriptor, if any.
+func ElementIDs(elem Element) (descID catid.DescID, subIDs []uint32) {
+	switch t := elem.(type) {
+		default:
+			panic(fmt.Sprintf("unknown type %T", t))
+{{ range . }}
+		case *{{ . }}:
+			return t.{{ descIDField . }}, {{ with subIDFields . }}[]uint32{ {{- range $i, $f := . }}{{ if $i }}, {{ end }}uint32(t.{{ $f }}){{ end -}} }{{ else }}nil{{ end }}
+{{- end -}}
+	}
+}
+//
+// ElementByTypeName returns a zero-valued instance of the element type with
+// the given name, or nil if there is no such element type.
+func ElementByTypeName(name string) Element {
+	switch name {
+{{ range . }}
+		case "{{ . }}":
+			return &{{ . }}{}
+{{- end -}}
+	}
+	return nil
+}
+//
 // GetElementOneOfProtos returns all one of protos.
 func GetElementOneOfProtos() []interface{} {
 	return []interface{} {
@@ -188,3 +257,156 @@ func getElementNames(inProtoFile string) (names []string, _ error) {
 	sort.Strings(names)
 	return names, nil
 }
+
+// elementIDFields holds the names of the fields of an element type which
+// hold the IDs returned by ElementIDs.
+type elementIDFields struct {
+	descID string
+	subIDs []string
+}
+
+// protoField is a field of a message in the input proto file.
+type protoField struct {
+	typ, name string
+	repeated  bool
+	embedded  bool
+	// idKind is the name of the catid type the field is cast to, if any.
+	idKind string
+}
+
+var (
+	protoFieldRegexp = regexp.MustCompile(
+		`^(?P<repeated>repeated\s+)?(?P<type>[\w\.]+)\s+\w+\s*=\s*\d+\s*(?P<options>\[.*\])?$`)
+	customNameRegexp = regexp.MustCompile(`\(gogoproto\.customname\)\s*=\s*"(\w+)"`)
+	embedRegexp      = regexp.MustCompile(`\(gogoproto\.embed\)\s*=\s*true`)
+	idKindRegexp     = regexp.MustCompile(`sem/catid\.(\w+)"`)
+	// lineCommentRegexp matches a comment up to the end of its line.
+	lineCommentRegexp = regexp.MustCompile("//[^\n]*\n")
+)
+
+// getElementIDFields determines, for each element type, the fields returned
+// by ElementIDs. These are found among the leading fields of the element,
+// including those of leading embedded messages, which hold descriptor,
+// column, index, constraint or family IDs. The sub-object IDs are those
+// following the descriptor ID which directly precedes them. In the absence
+// of sub-object IDs, the descriptor ID is the DescriptorID field, if any, or
+// else the first descriptor ID.
+func getElementIDFields(
+	inProtoFile string, elementNames []string,
+) (map[string]elementIDFields, error) {
+	got, err := os.ReadFile(inProtoFile)
+	if err != nil {
+		return nil, err
+	}
+	messages := parseProtoMessages(string(got))
+	ret := make(map[string]elementIDFields, len(elementNames))
+	for _, name := range elementNames {
+		var descIDs []string
+		var ids elementIDFields
+		var collect func(message string) bool
+		collect = func(message string) bool {
+			for _, f := range messages[message] {
+				switch {
+				case f.repeated:
+					return false
+				case f.embedded && messages[f.typ] != nil:
+					if !collect(f.typ) {
+						return false
+					}
+				case f.idKind == "DescID":
+					if len(ids.subIDs) > 0 {
+						return false
+					}
+					descIDs = append(descIDs, f.name)
+				case f.idKind == "ColumnID", f.idKind == "IndexID",
+					f.idKind == "ConstraintID", f.idKind == "FamilyID":
+					if len(descIDs) == 0 {
+						return false
+					}
+					if len(ids.subIDs) == 0 {
+						ids.descID = descIDs[len(descIDs)-1]
+					}
+					ids.subIDs = append(ids.subIDs, f.name)
+				default:
+					return false
+				}
+			}
+			return true
+		}
+		collect(name)
+		if len(descIDs) == 0 {
+			return nil, fmt.Errorf("element %s has no leading descriptor ID field", name)
+		}
+		if ids.descID == "" {
+			ids.descID = descIDs[0]
+			for _, id := range descIDs {
+				if id == "DescriptorID" {
+					ids.descID = id
+				}
+			}
+		}
+		ret[name] = ids
+	}
+	return ret, nil
+}
+
+// parseProtoMessages returns the fields of each top-level message in the
+// given proto file contents, in order of declaration. Nested messages and
+// enums are skipped.
+func parseProtoMessages(contents string) map[string][]protoField {
+	contents = lineCommentRegexp.ReplaceAllString(contents, "\n")
+	messages := make(map[string][]protoField)
+	var message string
+	var stmt strings.Builder
+	depth := 0
+	for _, r := range contents {
+		switch r {
+		case '{':
+			if depth == 0 {
+				if fields := strings.Fields(stmt.String()); len(fields) == 2 && fields[0] == "message" {
+					message = fields[1]
+					messages[message] = nil
+				}
+			}
+			depth++
+			stmt.Reset()
+		case '}':
+			depth--
+			stmt.Reset()
+		case ';':
+			if depth == 1 && message != "" {
+				if f, ok := parseProtoField(stmt.String()); ok {
+					messages[message] = append(messages[message], f)
+				}
+			}
+			stmt.Reset()
+		default:
+			stmt.WriteRune(r)
+		}
+		if depth == 0 {
+			message = ""
+		}
+	}
+	return messages
+}
+
+// parseProtoField parses a field declaration, stripped of its terminating
+// semicolon.
+func parseProtoField(stmt string) (f protoField, ok bool) {
+	stmt = strings.Join(strings.Fields(stmt), " ")
+	m := protoFieldRegexp.FindStringSubmatch(stmt)
+	if m == nil {
+		return f, false
+	}
+	options := m[protoFieldRegexp.SubexpIndex("options")]
+	f.typ = m[protoFieldRegexp.SubexpIndex("type")]
+	f.repeated = m[protoFieldRegexp.SubexpIndex("repeated")] != ""
+	f.embedded = embedRegexp.MatchString(options)
+	if cm := customNameRegexp.FindStringSubmatch(options); cm != nil {
+		f.name = cm[1]
+	}
+	if km := idKindRegexp.FindStringSubmatch(options); km != nil {
+		f.idKind = km[1]
+	}
+	return f, true
+}
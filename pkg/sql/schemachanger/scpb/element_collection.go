@@ -11,6 +11,9 @@
 package scpb
 
 import (
+	"reflect"
+	"strings"
+
 	"github.com/cockroachdb/cockroach/pkg/util/debugutil"
 	"github.com/cockroachdb/errors"
 )
@@ -141,6 +144,136 @@ func (c *ElementCollection[E]) genericFilter(
 	return ret.decorate()
 }
 
+// PartitionElements classifies the elements in `g` in a single pass, by
+// target status: elements targeting PUBLIC are added, elements targeting
+// ABSENT are dropped. Elements targeting any other status are excluded.
+func PartitionElements(g ElementCollectionGetter) (adds, drops []Element) {
+	if g == nil {
+		return nil, nil
+	}
+	for i, n := 0, g.Size(); i < n; i++ {
+		_, target, e := g.Get(i)
+		switch target {
+		case ToPublic:
+			adds = append(adds, e)
+		case ToAbsent:
+			drops = append(drops, e)
+		}
+	}
+	return adds, drops
+}
+
+// IndexElementsByType groups the elements in `g` in a single pass, by type
+// name, preserving their order within each group. Type names are those
+// accepted by ElementByTypeName.
+func IndexElementsByType(g ElementCollectionGetter) map[string][]Element {
+	ret := make(map[string][]Element)
+	if g == nil {
+		return ret
+	}
+	for i, n := 0, g.Size(); i < n; i++ {
+		_, _, e := g.Get(i)
+		name := reflect.TypeOf(e).Elem().Name()
+		ret[name] = append(ret[name], e)
+	}
+	return ret
+}
+
+// StatusHistogram counts the elements in `g` in a single pass, by current
+// status. Statuses which no element is in are omitted.
+func StatusHistogram(g ElementCollectionGetter) map[Status]int {
+	ret := make(map[Status]int)
+	if g == nil {
+		return ret
+	}
+	for i, n := 0, g.Size(); i < n; i++ {
+		current, _, _ := g.Get(i)
+		ret[current]++
+	}
+	return ret
+}
+
+// ElementStatus is a (current status, target status, element) tuple of an
+// element collection.
+type ElementStatus struct {
+	Current Status
+	Target  TargetStatus
+	Element Element
+}
+
+// CollectElementStatuses materializes the tuples in `g` into a slice, in
+// order, which can be iterated over multiple times or sorted.
+func CollectElementStatuses(g ElementCollectionGetter) []ElementStatus {
+	if g == nil || g.Size() == 0 {
+		return nil
+	}
+	ret := make([]ElementStatus, g.Size())
+	for i := range ret {
+		ret[i].Current, ret[i].Target, ret[i].Element = g.Get(i)
+	}
+	return ret
+}
+
+// AssertNoElementsOfType returns an assertion error listing the elements in
+// `g` whose type is one of `typeNames`, or nil if there are none. Type names
+// are those accepted by ElementByTypeName.
+func AssertNoElementsOfType(g ElementCollectionGetter, typeNames ...string) error {
+	disallowed := make(map[reflect.Type]struct{}, len(typeNames))
+	for _, name := range typeNames {
+		e := ElementByTypeName(name)
+		if e == nil {
+			return errors.AssertionFailedf("unknown element type %q", name)
+		}
+		disallowed[reflect.TypeOf(e)] = struct{}{}
+	}
+	if g == nil {
+		return nil
+	}
+	var found []string
+	for i, n := 0, g.Size(); i < n; i++ {
+		current, target, e := g.Get(i)
+		if _, ok := disallowed[reflect.TypeOf(e)]; !ok {
+			continue
+		}
+		found = append(found, describeElementStatus(current, target, e))
+	}
+	if len(found) > 0 {
+		return errors.AssertionFailedf("found %d element(s) of disallowed type: %s",
+			len(found), strings.Join(found, ", "))
+	}
+	return nil
+}
+
+// describeElementStatus formats an element and its statuses for assertion
+// errors, as in "Column:{...} PUBLIC -> ABSENT".
+func describeElementStatus(current Status, target TargetStatus, e Element) string {
+	return reflect.TypeOf(e).Elem().Name() + ":{" + e.String() + "} " +
+		current.String() + " -> " + target.Status().String()
+}
+
+// ValidateTransitions returns an assertion error listing the elements in `g`
+// whose current status can't reach their target status, per
+// ValidStatusTransition, or nil if there are none. Elements without a target
+// are ignored.
+func ValidateTransitions(g ElementCollectionGetter) error {
+	if g == nil {
+		return nil
+	}
+	var invalid []string
+	for i, n := 0, g.Size(); i < n; i++ {
+		current, target, e := g.Get(i)
+		if target == InvalidTarget || ValidStatusTransition(current, target.Status()) {
+			continue
+		}
+		invalid = append(invalid, describeElementStatus(current, target, e))
+	}
+	if len(invalid) > 0 {
+		return errors.AssertionFailedf("found %d element(s) with invalid status transitions: %s",
+			len(invalid), strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
 // ForEach iterates through the collection and applies fn
 // on each tuple.
 func (c *ElementCollection[E]) ForEach(fn func(current Status, target TargetStatus, e E)) {
@@ -151,6 +151,178 @@ func TestElementCollection(t *testing.T) {
 	})
 }
 
+func TestPartitionElements(t *testing.T) {
+	g := testGetter([]struct {
+		current Status
+		target  TargetStatus
+		element Element
+	}{
+		{current: Status_ABSENT, target: ToPublic, element: &Column{TableID: 104, ColumnID: 1}},
+		{current: Status_PUBLIC, target: ToAbsent, element: &Column{TableID: 104, ColumnID: 2}},
+		{current: Status_ABSENT, target: Transient, element: &Column{TableID: 104, ColumnID: 3}},
+		{current: Status_PUBLIC, target: InvalidTarget, element: &Schema{SchemaID: 101}},
+		{current: Status_ABSENT, target: ToPublic, element: &PrimaryIndex{Index: Index{TableID: 104, IndexID: 2}}},
+		{current: Status_PUBLIC, target: ToAbsent, element: &PrimaryIndex{Index: Index{TableID: 104, IndexID: 1}}},
+	})
+	adds, drops := PartitionElements(newTestCollection(g))
+	require.Equal(t, []Element{g[0].element, g[4].element}, adds)
+	require.Equal(t, []Element{g[1].element, g[5].element}, drops)
+
+	// Empty collections have no adds nor drops.
+	adds, drops = PartitionElements(newTestCollection(nil))
+	require.Empty(t, adds)
+	require.Empty(t, drops)
+}
+
+func TestIndexElementsByType(t *testing.T) {
+	g := testGetter([]struct {
+		current Status
+		target  TargetStatus
+		element Element
+	}{
+		{current: Status_ABSENT, target: ToPublic, element: &Column{TableID: 104, ColumnID: 1}},
+		{current: Status_PUBLIC, target: ToAbsent, element: &PrimaryIndex{Index: Index{TableID: 104, IndexID: 1}}},
+		{current: Status_PUBLIC, target: InvalidTarget, element: &Schema{SchemaID: 101}},
+		{current: Status_ABSENT, target: ToPublic, element: &Column{TableID: 104, ColumnID: 2}},
+		{current: Status_ABSENT, target: ToPublic, element: &PrimaryIndex{Index: Index{TableID: 104, IndexID: 2}}},
+		{current: Status_ABSENT, target: Transient, element: &Column{TableID: 104, ColumnID: 3}},
+	})
+	idx := IndexElementsByType(newTestCollection(g))
+	require.Len(t, idx, 3)
+	require.Equal(t, []Element{g[0].element, g[3].element, g[5].element}, idx["Column"])
+	require.Equal(t, []Element{g[1].element, g[4].element}, idx["PrimaryIndex"])
+	require.Equal(t, []Element{g[2].element}, idx["Schema"])
+	require.Empty(t, idx["SecondaryIndex"])
+
+	// Empty collections yield an empty index.
+	require.Empty(t, IndexElementsByType(newTestCollection(nil)))
+}
+
+func TestStatusHistogram(t *testing.T) {
+	g := testGetter([]struct {
+		current Status
+		target  TargetStatus
+		element Element
+	}{
+		{current: Status_ABSENT, target: ToPublic, element: &Column{TableID: 104, ColumnID: 1}},
+		{current: Status_PUBLIC, target: ToAbsent, element: &PrimaryIndex{Index: Index{TableID: 104, IndexID: 1}}},
+		{current: Status_PUBLIC, target: InvalidTarget, element: &Schema{SchemaID: 101}},
+		{current: Status_WRITE_ONLY, target: ToPublic, element: &Column{TableID: 104, ColumnID: 2}},
+		{current: Status_ABSENT, target: ToPublic, element: &PrimaryIndex{Index: Index{TableID: 104, IndexID: 2}}},
+		{current: Status_ABSENT, target: Transient, element: &Column{TableID: 104, ColumnID: 3}},
+	})
+	h := StatusHistogram(newTestCollection(g))
+	require.Equal(t, map[Status]int{
+		Status_ABSENT:     3,
+		Status_PUBLIC:     2,
+		Status_WRITE_ONLY: 1,
+	}, h)
+	var total int
+	for _, n := range h {
+		total += n
+	}
+	require.Equal(t, len(g), total)
+
+	// Empty collections yield an empty histogram.
+	require.Empty(t, StatusHistogram(newTestCollection(nil)))
+}
+
+func TestCollectElementStatuses(t *testing.T) {
+	g := testGetter([]struct {
+		current Status
+		target  TargetStatus
+		element Element
+	}{
+		{current: Status_ABSENT, target: ToPublic, element: &Column{TableID: 104, ColumnID: 1}},
+		{current: Status_PUBLIC, target: ToAbsent, element: &PrimaryIndex{Index: Index{TableID: 104, IndexID: 1}}},
+		{current: Status_PUBLIC, target: InvalidTarget, element: &Schema{SchemaID: 101}},
+		{current: Status_WRITE_ONLY, target: ToPublic, element: &Column{TableID: 104, ColumnID: 2}},
+	})
+	c := newTestCollection(g)
+	var expected []ElementStatus
+	c.ForEach(func(current Status, target TargetStatus, e Element) {
+		expected = append(expected, ElementStatus{Current: current, Target: target, Element: e})
+	})
+	require.Equal(t, expected, CollectElementStatuses(c))
+
+	// The collected tuples reflect filtering.
+	toPublic := CollectElementStatuses(c.ToPublic())
+	require.Len(t, toPublic, 2)
+	for _, es := range toPublic {
+		require.Equal(t, ToPublic, es.Target)
+	}
+
+	// Empty collections yield no tuples.
+	require.Empty(t, CollectElementStatuses(newTestCollection(nil)))
+}
+
+func TestAssertNoElementsOfType(t *testing.T) {
+	g := testGetter([]struct {
+		current Status
+		target  TargetStatus
+		element Element
+	}{
+		{current: Status_PUBLIC, target: ToPublic, element: &Column{TableID: 104, ColumnID: 1}},
+		{current: Status_PUBLIC, target: ToAbsent, element: &SecondaryIndex{Index: Index{TableID: 104, IndexID: 2}}},
+	})
+	c := newTestCollection(g)
+	require.NoError(t, AssertNoElementsOfType(c, "PrimaryIndex", "TemporaryIndex"))
+	require.NoError(t, AssertNoElementsOfType(c))
+
+	err := AssertNoElementsOfType(c, "PrimaryIndex", "SecondaryIndex")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "found 1 element(s) of disallowed type")
+	require.Contains(t, err.Error(), "SecondaryIndex:{")
+	require.Contains(t, err.Error(), "PUBLIC -> ABSENT")
+
+	require.Error(t, AssertNoElementsOfType(c, "NotAnElement"))
+}
+
+func TestValidStatusTransition(t *testing.T) {
+	for _, tc := range []struct {
+		current, target Status
+		valid           bool
+	}{
+		{Status_ABSENT, Status_PUBLIC, true},
+		{Status_WRITE_ONLY, Status_PUBLIC, true},
+		{Status_PUBLIC, Status_ABSENT, true},
+		{Status_DROPPED, Status_ABSENT, true},
+		{Status_TRANSIENT_DELETE_ONLY, Status_TRANSIENT_ABSENT, true},
+		{Status_DROPPED, Status_PUBLIC, false},
+		{Status_TRANSIENT_WRITE_ONLY, Status_PUBLIC, false},
+		{Status_TRANSIENT_ABSENT, Status_ABSENT, false},
+		{Status_DROPPED, Status_TRANSIENT_ABSENT, false},
+		{Status_UNKNOWN, Status_PUBLIC, false},
+		{Status_ABSENT, Status_WRITE_ONLY, false},
+	} {
+		require.Equalf(t, tc.valid, ValidStatusTransition(tc.current, tc.target),
+			"%s -> %s", tc.current, tc.target)
+	}
+}
+
+func TestValidateTransitions(t *testing.T) {
+	g := testGetter([]struct {
+		current Status
+		target  TargetStatus
+		element Element
+	}{
+		{current: Status_ABSENT, target: ToPublic, element: &Column{TableID: 104, ColumnID: 1}},
+		{current: Status_PUBLIC, target: InvalidTarget, element: &Schema{SchemaID: 101}},
+	})
+	require.NoError(t, ValidateTransitions(newTestCollection(g)))
+
+	g = append(g, struct {
+		current Status
+		target  TargetStatus
+		element Element
+	}{current: Status_DROPPED, target: ToPublic, element: &Table{TableID: 104}})
+	err := ValidateTransitions(newTestCollection(g))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "found 1 element(s) with invalid status transitions")
+	require.Contains(t, err.Error(), "Table:{")
+	require.Contains(t, err.Error(), "DROPPED -> PUBLIC")
+}
+
 func newTestCollection(g testGetter) *ElementCollection[Element] {
 	indexes := make([]int, len(g))
 	for i := range g {
@@ -12,7 +12,12 @@
 
 package scpb
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
 
 
 func (e AliasType) element() {}
@@ -2752,6 +2757,674 @@ func (e* ElementProto) SetElement(element Element) {
 			e.ElementOneOf = &ElementProto_View{ View: t}}
 }
 //
+// CloneElement returns a deep copy of the element.
+func CloneElement(elem Element) Element {
+	switch t := elem.(type) {
+		default:
+			panic(fmt.Sprintf("unknown type %T", t))
+
+		case *AliasType:
+			return protoutil.Clone(t).(*AliasType)
+		case *CheckConstraint:
+			return protoutil.Clone(t).(*CheckConstraint)
+		case *CheckConstraintUnvalidated:
+			return protoutil.Clone(t).(*CheckConstraintUnvalidated)
+		case *Column:
+			return protoutil.Clone(t).(*Column)
+		case *ColumnComment:
+			return protoutil.Clone(t).(*ColumnComment)
+		case *ColumnComputeExpression:
+			return protoutil.Clone(t).(*ColumnComputeExpression)
+		case *ColumnDefaultExpression:
+			return protoutil.Clone(t).(*ColumnDefaultExpression)
+		case *ColumnFamily:
+			return protoutil.Clone(t).(*ColumnFamily)
+		case *ColumnName:
+			return protoutil.Clone(t).(*ColumnName)
+		case *ColumnNotNull:
+			return protoutil.Clone(t).(*ColumnNotNull)
+		case *ColumnOnUpdateExpression:
+			return protoutil.Clone(t).(*ColumnOnUpdateExpression)
+		case *ColumnType:
+			return protoutil.Clone(t).(*ColumnType)
+		case *CompositeType:
+			return protoutil.Clone(t).(*CompositeType)
+		case *CompositeTypeAttrName:
+			return protoutil.Clone(t).(*CompositeTypeAttrName)
+		case *CompositeTypeAttrType:
+			return protoutil.Clone(t).(*CompositeTypeAttrType)
+		case *ConstraintComment:
+			return protoutil.Clone(t).(*ConstraintComment)
+		case *ConstraintWithoutIndexName:
+			return protoutil.Clone(t).(*ConstraintWithoutIndexName)
+		case *Database:
+			return protoutil.Clone(t).(*Database)
+		case *DatabaseComment:
+			return protoutil.Clone(t).(*DatabaseComment)
+		case *DatabaseData:
+			return protoutil.Clone(t).(*DatabaseData)
+		case *DatabaseRegionConfig:
+			return protoutil.Clone(t).(*DatabaseRegionConfig)
+		case *DatabaseRoleSetting:
+			return protoutil.Clone(t).(*DatabaseRoleSetting)
+		case *DatabaseZoneConfig:
+			return protoutil.Clone(t).(*DatabaseZoneConfig)
+		case *EnumType:
+			return protoutil.Clone(t).(*EnumType)
+		case *EnumTypeValue:
+			return protoutil.Clone(t).(*EnumTypeValue)
+		case *ForeignKeyConstraint:
+			return protoutil.Clone(t).(*ForeignKeyConstraint)
+		case *ForeignKeyConstraintUnvalidated:
+			return protoutil.Clone(t).(*ForeignKeyConstraintUnvalidated)
+		case *Function:
+			return protoutil.Clone(t).(*Function)
+		case *FunctionBody:
+			return protoutil.Clone(t).(*FunctionBody)
+		case *FunctionLeakProof:
+			return protoutil.Clone(t).(*FunctionLeakProof)
+		case *FunctionName:
+			return protoutil.Clone(t).(*FunctionName)
+		case *FunctionNullInputBehavior:
+			return protoutil.Clone(t).(*FunctionNullInputBehavior)
+		case *FunctionSecurity:
+			return protoutil.Clone(t).(*FunctionSecurity)
+		case *FunctionVolatility:
+			return protoutil.Clone(t).(*FunctionVolatility)
+		case *IndexColumn:
+			return protoutil.Clone(t).(*IndexColumn)
+		case *IndexComment:
+			return protoutil.Clone(t).(*IndexComment)
+		case *IndexData:
+			return protoutil.Clone(t).(*IndexData)
+		case *IndexName:
+			return protoutil.Clone(t).(*IndexName)
+		case *IndexPartitioning:
+			return protoutil.Clone(t).(*IndexPartitioning)
+		case *IndexZoneConfig:
+			return protoutil.Clone(t).(*IndexZoneConfig)
+		case *LDRJobIDs:
+			return protoutil.Clone(t).(*LDRJobIDs)
+		case *Namespace:
+			return protoutil.Clone(t).(*Namespace)
+		case *Owner:
+			return protoutil.Clone(t).(*Owner)
+		case *PrimaryIndex:
+			return protoutil.Clone(t).(*PrimaryIndex)
+		case *RowLevelTTL:
+			return protoutil.Clone(t).(*RowLevelTTL)
+		case *Schema:
+			return protoutil.Clone(t).(*Schema)
+		case *SchemaChild:
+			return protoutil.Clone(t).(*SchemaChild)
+		case *SchemaComment:
+			return protoutil.Clone(t).(*SchemaComment)
+		case *SchemaParent:
+			return protoutil.Clone(t).(*SchemaParent)
+		case *SecondaryIndex:
+			return protoutil.Clone(t).(*SecondaryIndex)
+		case *SecondaryIndexPartial:
+			return protoutil.Clone(t).(*SecondaryIndexPartial)
+		case *Sequence:
+			return protoutil.Clone(t).(*Sequence)
+		case *SequenceOption:
+			return protoutil.Clone(t).(*SequenceOption)
+		case *SequenceOwner:
+			return protoutil.Clone(t).(*SequenceOwner)
+		case *Table:
+			return protoutil.Clone(t).(*Table)
+		case *TableComment:
+			return protoutil.Clone(t).(*TableComment)
+		case *TableData:
+			return protoutil.Clone(t).(*TableData)
+		case *TableLocalityGlobal:
+			return protoutil.Clone(t).(*TableLocalityGlobal)
+		case *TableLocalityPrimaryRegion:
+			return protoutil.Clone(t).(*TableLocalityPrimaryRegion)
+		case *TableLocalityRegionalByRow:
+			return protoutil.Clone(t).(*TableLocalityRegionalByRow)
+		case *TableLocalitySecondaryRegion:
+			return protoutil.Clone(t).(*TableLocalitySecondaryRegion)
+		case *TablePartitioning:
+			return protoutil.Clone(t).(*TablePartitioning)
+		case *TableSchemaLocked:
+			return protoutil.Clone(t).(*TableSchemaLocked)
+		case *TableZoneConfig:
+			return protoutil.Clone(t).(*TableZoneConfig)
+		case *TemporaryIndex:
+			return protoutil.Clone(t).(*TemporaryIndex)
+		case *TypeComment:
+			return protoutil.Clone(t).(*TypeComment)
+		case *UniqueWithoutIndexConstraint:
+			return protoutil.Clone(t).(*UniqueWithoutIndexConstraint)
+		case *UniqueWithoutIndexConstraintUnvalidated:
+			return protoutil.Clone(t).(*UniqueWithoutIndexConstraintUnvalidated)
+		case *UserPrivileges:
+			return protoutil.Clone(t).(*UserPrivileges)
+		case *View:
+			return protoutil.Clone(t).(*View)}
+}
+//
+// ElementsEqual returns true if both elements are of the same type and are
+// structurally equal.
+func ElementsEqual(a, b Element) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch t := a.(type) {
+		default:
+			panic(fmt.Sprintf("unknown type %T", t))
+
+		case *AliasType:
+			o, ok := b.(*AliasType)
+			return ok && t.Equal(o)
+		case *CheckConstraint:
+			o, ok := b.(*CheckConstraint)
+			return ok && t.Equal(o)
+		case *CheckConstraintUnvalidated:
+			o, ok := b.(*CheckConstraintUnvalidated)
+			return ok && t.Equal(o)
+		case *Column:
+			o, ok := b.(*Column)
+			return ok && t.Equal(o)
+		case *ColumnComment:
+			o, ok := b.(*ColumnComment)
+			return ok && t.Equal(o)
+		case *ColumnComputeExpression:
+			o, ok := b.(*ColumnComputeExpression)
+			return ok && t.Equal(o)
+		case *ColumnDefaultExpression:
+			o, ok := b.(*ColumnDefaultExpression)
+			return ok && t.Equal(o)
+		case *ColumnFamily:
+			o, ok := b.(*ColumnFamily)
+			return ok && t.Equal(o)
+		case *ColumnName:
+			o, ok := b.(*ColumnName)
+			return ok && t.Equal(o)
+		case *ColumnNotNull:
+			o, ok := b.(*ColumnNotNull)
+			return ok && t.Equal(o)
+		case *ColumnOnUpdateExpression:
+			o, ok := b.(*ColumnOnUpdateExpression)
+			return ok && t.Equal(o)
+		case *ColumnType:
+			o, ok := b.(*ColumnType)
+			return ok && t.Equal(o)
+		case *CompositeType:
+			o, ok := b.(*CompositeType)
+			return ok && t.Equal(o)
+		case *CompositeTypeAttrName:
+			o, ok := b.(*CompositeTypeAttrName)
+			return ok && t.Equal(o)
+		case *CompositeTypeAttrType:
+			o, ok := b.(*CompositeTypeAttrType)
+			return ok && t.Equal(o)
+		case *ConstraintComment:
+			o, ok := b.(*ConstraintComment)
+			return ok && t.Equal(o)
+		case *ConstraintWithoutIndexName:
+			o, ok := b.(*ConstraintWithoutIndexName)
+			return ok && t.Equal(o)
+		case *Database:
+			o, ok := b.(*Database)
+			return ok && t.Equal(o)
+		case *DatabaseComment:
+			o, ok := b.(*DatabaseComment)
+			return ok && t.Equal(o)
+		case *DatabaseData:
+			o, ok := b.(*DatabaseData)
+			return ok && t.Equal(o)
+		case *DatabaseRegionConfig:
+			o, ok := b.(*DatabaseRegionConfig)
+			return ok && t.Equal(o)
+		case *DatabaseRoleSetting:
+			o, ok := b.(*DatabaseRoleSetting)
+			return ok && t.Equal(o)
+		case *DatabaseZoneConfig:
+			o, ok := b.(*DatabaseZoneConfig)
+			return ok && t.Equal(o)
+		case *EnumType:
+			o, ok := b.(*EnumType)
+			return ok && t.Equal(o)
+		case *EnumTypeValue:
+			o, ok := b.(*EnumTypeValue)
+			return ok && t.Equal(o)
+		case *ForeignKeyConstraint:
+			o, ok := b.(*ForeignKeyConstraint)
+			return ok && t.Equal(o)
+		case *ForeignKeyConstraintUnvalidated:
+			o, ok := b.(*ForeignKeyConstraintUnvalidated)
+			return ok && t.Equal(o)
+		case *Function:
+			o, ok := b.(*Function)
+			return ok && t.Equal(o)
+		case *FunctionBody:
+			o, ok := b.(*FunctionBody)
+			return ok && t.Equal(o)
+		case *FunctionLeakProof:
+			o, ok := b.(*FunctionLeakProof)
+			return ok && t.Equal(o)
+		case *FunctionName:
+			o, ok := b.(*FunctionName)
+			return ok && t.Equal(o)
+		case *FunctionNullInputBehavior:
+			o, ok := b.(*FunctionNullInputBehavior)
+			return ok && t.Equal(o)
+		case *FunctionSecurity:
+			o, ok := b.(*FunctionSecurity)
+			return ok && t.Equal(o)
+		case *FunctionVolatility:
+			o, ok := b.(*FunctionVolatility)
+			return ok && t.Equal(o)
+		case *IndexColumn:
+			o, ok := b.(*IndexColumn)
+			return ok && t.Equal(o)
+		case *IndexComment:
+			o, ok := b.(*IndexComment)
+			return ok && t.Equal(o)
+		case *IndexData:
+			o, ok := b.(*IndexData)
+			return ok && t.Equal(o)
+		case *IndexName:
+			o, ok := b.(*IndexName)
+			return ok && t.Equal(o)
+		case *IndexPartitioning:
+			o, ok := b.(*IndexPartitioning)
+			return ok && t.Equal(o)
+		case *IndexZoneConfig:
+			o, ok := b.(*IndexZoneConfig)
+			return ok && t.Equal(o)
+		case *LDRJobIDs:
+			o, ok := b.(*LDRJobIDs)
+			return ok && t.Equal(o)
+		case *Namespace:
+			o, ok := b.(*Namespace)
+			return ok && t.Equal(o)
+		case *Owner:
+			o, ok := b.(*Owner)
+			return ok && t.Equal(o)
+		case *PrimaryIndex:
+			o, ok := b.(*PrimaryIndex)
+			return ok && t.Equal(o)
+		case *RowLevelTTL:
+			o, ok := b.(*RowLevelTTL)
+			return ok && t.Equal(o)
+		case *Schema:
+			o, ok := b.(*Schema)
+			return ok && t.Equal(o)
+		case *SchemaChild:
+			o, ok := b.(*SchemaChild)
+			return ok && t.Equal(o)
+		case *SchemaComment:
+			o, ok := b.(*SchemaComment)
+			return ok && t.Equal(o)
+		case *SchemaParent:
+			o, ok := b.(*SchemaParent)
+			return ok && t.Equal(o)
+		case *SecondaryIndex:
+			o, ok := b.(*SecondaryIndex)
+			return ok && t.Equal(o)
+		case *SecondaryIndexPartial:
+			o, ok := b.(*SecondaryIndexPartial)
+			return ok && t.Equal(o)
+		case *Sequence:
+			o, ok := b.(*Sequence)
+			return ok && t.Equal(o)
+		case *SequenceOption:
+			o, ok := b.(*SequenceOption)
+			return ok && t.Equal(o)
+		case *SequenceOwner:
+			o, ok := b.(*SequenceOwner)
+			return ok && t.Equal(o)
+		case *Table:
+			o, ok := b.(*Table)
+			return ok && t.Equal(o)
+		case *TableComment:
+			o, ok := b.(*TableComment)
+			return ok && t.Equal(o)
+		case *TableData:
+			o, ok := b.(*TableData)
+			return ok && t.Equal(o)
+		case *TableLocalityGlobal:
+			o, ok := b.(*TableLocalityGlobal)
+			return ok && t.Equal(o)
+		case *TableLocalityPrimaryRegion:
+			o, ok := b.(*TableLocalityPrimaryRegion)
+			return ok && t.Equal(o)
+		case *TableLocalityRegionalByRow:
+			o, ok := b.(*TableLocalityRegionalByRow)
+			return ok && t.Equal(o)
+		case *TableLocalitySecondaryRegion:
+			o, ok := b.(*TableLocalitySecondaryRegion)
+			return ok && t.Equal(o)
+		case *TablePartitioning:
+			o, ok := b.(*TablePartitioning)
+			return ok && t.Equal(o)
+		case *TableSchemaLocked:
+			o, ok := b.(*TableSchemaLocked)
+			return ok && t.Equal(o)
+		case *TableZoneConfig:
+			o, ok := b.(*TableZoneConfig)
+			return ok && t.Equal(o)
+		case *TemporaryIndex:
+			o, ok := b.(*TemporaryIndex)
+			return ok && t.Equal(o)
+		case *TypeComment:
+			o, ok := b.(*TypeComment)
+			return ok && t.Equal(o)
+		case *UniqueWithoutIndexConstraint:
+			o, ok := b.(*UniqueWithoutIndexConstraint)
+			return ok && t.Equal(o)
+		case *UniqueWithoutIndexConstraintUnvalidated:
+			o, ok := b.(*UniqueWithoutIndexConstraintUnvalidated)
+			return ok && t.Equal(o)
+		case *UserPrivileges:
+			o, ok := b.(*UserPrivileges)
+			return ok && t.Equal(o)
+		case *View:
+			o, ok := b.(*View)
+			return ok && t.Equal(o)}
+}
+//
+// ElementIDs returns the ID of the descriptor to which the element belongs,
+// along with the IDs of the columns, indexes, constraints or families which
+// identify it within that descriptor, if any.
+func ElementIDs(elem Element) (descID catid.DescID, subIDs []uint32) {
+	switch t := elem.(type) {
+		default:
+			panic(fmt.Sprintf("unknown type %T", t))
+
+		case *AliasType:
+			return t.TypeID, nil
+		case *CheckConstraint:
+			return t.TableID, []uint32{uint32(t.ConstraintID)}
+		case *CheckConstraintUnvalidated:
+			return t.TableID, []uint32{uint32(t.ConstraintID)}
+		case *Column:
+			return t.TableID, []uint32{uint32(t.ColumnID)}
+		case *ColumnComment:
+			return t.TableID, []uint32{uint32(t.ColumnID)}
+		case *ColumnComputeExpression:
+			return t.TableID, []uint32{uint32(t.ColumnID)}
+		case *ColumnDefaultExpression:
+			return t.TableID, []uint32{uint32(t.ColumnID)}
+		case *ColumnFamily:
+			return t.TableID, []uint32{uint32(t.FamilyID)}
+		case *ColumnName:
+			return t.TableID, []uint32{uint32(t.ColumnID)}
+		case *ColumnNotNull:
+			return t.TableID, []uint32{uint32(t.ColumnID), uint32(t.IndexIDForValidation)}
+		case *ColumnOnUpdateExpression:
+			return t.TableID, []uint32{uint32(t.ColumnID)}
+		case *ColumnType:
+			return t.TableID, []uint32{uint32(t.FamilyID), uint32(t.ColumnID)}
+		case *CompositeType:
+			return t.TypeID, nil
+		case *CompositeTypeAttrName:
+			return t.CompositeTypeID, nil
+		case *CompositeTypeAttrType:
+			return t.CompositeTypeID, nil
+		case *ConstraintComment:
+			return t.TableID, []uint32{uint32(t.ConstraintID)}
+		case *ConstraintWithoutIndexName:
+			return t.TableID, []uint32{uint32(t.ConstraintID)}
+		case *Database:
+			return t.DatabaseID, nil
+		case *DatabaseComment:
+			return t.DatabaseID, nil
+		case *DatabaseData:
+			return t.DatabaseID, nil
+		case *DatabaseRegionConfig:
+			return t.DatabaseID, nil
+		case *DatabaseRoleSetting:
+			return t.DatabaseID, nil
+		case *DatabaseZoneConfig:
+			return t.DatabaseID, nil
+		case *EnumType:
+			return t.TypeID, nil
+		case *EnumTypeValue:
+			return t.TypeID, nil
+		case *ForeignKeyConstraint:
+			return t.TableID, []uint32{uint32(t.ConstraintID)}
+		case *ForeignKeyConstraintUnvalidated:
+			return t.TableID, []uint32{uint32(t.ConstraintID)}
+		case *Function:
+			return t.FunctionID, nil
+		case *FunctionBody:
+			return t.FunctionID, nil
+		case *FunctionLeakProof:
+			return t.FunctionID, nil
+		case *FunctionName:
+			return t.FunctionID, nil
+		case *FunctionNullInputBehavior:
+			return t.FunctionID, nil
+		case *FunctionSecurity:
+			return t.FunctionID, nil
+		case *FunctionVolatility:
+			return t.FunctionID, nil
+		case *IndexColumn:
+			return t.TableID, []uint32{uint32(t.IndexID), uint32(t.ColumnID)}
+		case *IndexComment:
+			return t.TableID, []uint32{uint32(t.IndexID)}
+		case *IndexData:
+			return t.TableID, []uint32{uint32(t.IndexID)}
+		case *IndexName:
+			return t.TableID, []uint32{uint32(t.IndexID)}
+		case *IndexPartitioning:
+			return t.TableID, []uint32{uint32(t.IndexID)}
+		case *IndexZoneConfig:
+			return t.TableID, []uint32{uint32(t.IndexID)}
+		case *LDRJobIDs:
+			return t.TableID, nil
+		case *Namespace:
+			return t.DescriptorID, nil
+		case *Owner:
+			return t.DescriptorID, nil
+		case *PrimaryIndex:
+			return t.TableID, []uint32{uint32(t.IndexID)}
+		case *RowLevelTTL:
+			return t.TableID, nil
+		case *Schema:
+			return t.SchemaID, nil
+		case *SchemaChild:
+			return t.ChildObjectID, nil
+		case *SchemaComment:
+			return t.SchemaID, nil
+		case *SchemaParent:
+			return t.SchemaID, nil
+		case *SecondaryIndex:
+			return t.TableID, []uint32{uint32(t.IndexID)}
+		case *SecondaryIndexPartial:
+			return t.TableID, []uint32{uint32(t.IndexID)}
+		case *Sequence:
+			return t.SequenceID, nil
+		case *SequenceOption:
+			return t.SequenceID, nil
+		case *SequenceOwner:
+			return t.TableID, []uint32{uint32(t.ColumnID)}
+		case *Table:
+			return t.TableID, nil
+		case *TableComment:
+			return t.TableID, nil
+		case *TableData:
+			return t.TableID, nil
+		case *TableLocalityGlobal:
+			return t.TableID, nil
+		case *TableLocalityPrimaryRegion:
+			return t.TableID, nil
+		case *TableLocalityRegionalByRow:
+			return t.TableID, nil
+		case *TableLocalitySecondaryRegion:
+			return t.TableID, nil
+		case *TablePartitioning:
+			return t.TableID, nil
+		case *TableSchemaLocked:
+			return t.TableID, nil
+		case *TableZoneConfig:
+			return t.TableID, nil
+		case *TemporaryIndex:
+			return t.TableID, []uint32{uint32(t.IndexID)}
+		case *TypeComment:
+			return t.TypeID, nil
+		case *UniqueWithoutIndexConstraint:
+			return t.TableID, []uint32{uint32(t.ConstraintID)}
+		case *UniqueWithoutIndexConstraintUnvalidated:
+			return t.TableID, []uint32{uint32(t.ConstraintID)}
+		case *UserPrivileges:
+			return t.DescriptorID, nil
+		case *View:
+			return t.ViewID, nil}
+}
+//
+// ElementByTypeName returns a zero-valued instance of the element type with
+// the given name, or nil if there is no such element type.
+func ElementByTypeName(name string) Element {
+	switch name {
+
+		case "AliasType":
+			return &AliasType{}
+		case "CheckConstraint":
+			return &CheckConstraint{}
+		case "CheckConstraintUnvalidated":
+			return &CheckConstraintUnvalidated{}
+		case "Column":
+			return &Column{}
+		case "ColumnComment":
+			return &ColumnComment{}
+		case "ColumnComputeExpression":
+			return &ColumnComputeExpression{}
+		case "ColumnDefaultExpression":
+			return &ColumnDefaultExpression{}
+		case "ColumnFamily":
+			return &ColumnFamily{}
+		case "ColumnName":
+			return &ColumnName{}
+		case "ColumnNotNull":
+			return &ColumnNotNull{}
+		case "ColumnOnUpdateExpression":
+			return &ColumnOnUpdateExpression{}
+		case "ColumnType":
+			return &ColumnType{}
+		case "CompositeType":
+			return &CompositeType{}
+		case "CompositeTypeAttrName":
+			return &CompositeTypeAttrName{}
+		case "CompositeTypeAttrType":
+			return &CompositeTypeAttrType{}
+		case "ConstraintComment":
+			return &ConstraintComment{}
+		case "ConstraintWithoutIndexName":
+			return &ConstraintWithoutIndexName{}
+		case "Database":
+			return &Database{}
+		case "DatabaseComment":
+			return &DatabaseComment{}
+		case "DatabaseData":
+			return &DatabaseData{}
+		case "DatabaseRegionConfig":
+			return &DatabaseRegionConfig{}
+		case "DatabaseRoleSetting":
+			return &DatabaseRoleSetting{}
+		case "DatabaseZoneConfig":
+			return &DatabaseZoneConfig{}
+		case "EnumType":
+			return &EnumType{}
+		case "EnumTypeValue":
+			return &EnumTypeValue{}
+		case "ForeignKeyConstraint":
+			return &ForeignKeyConstraint{}
+		case "ForeignKeyConstraintUnvalidated":
+			return &ForeignKeyConstraintUnvalidated{}
+		case "Function":
+			return &Function{}
+		case "FunctionBody":
+			return &FunctionBody{}
+		case "FunctionLeakProof":
+			return &FunctionLeakProof{}
+		case "FunctionName":
+			return &FunctionName{}
+		case "FunctionNullInputBehavior":
+			return &FunctionNullInputBehavior{}
+		case "FunctionSecurity":
+			return &FunctionSecurity{}
+		case "FunctionVolatility":
+			return &FunctionVolatility{}
+		case "IndexColumn":
+			return &IndexColumn{}
+		case "IndexComment":
+			return &IndexComment{}
+		case "IndexData":
+			return &IndexData{}
+		case "IndexName":
+			return &IndexName{}
+		case "IndexPartitioning":
+			return &IndexPartitioning{}
+		case "IndexZoneConfig":
+			return &IndexZoneConfig{}
+		case "LDRJobIDs":
+			return &LDRJobIDs{}
+		case "Namespace":
+			return &Namespace{}
+		case "Owner":
+			return &Owner{}
+		case "PrimaryIndex":
+			return &PrimaryIndex{}
+		case "RowLevelTTL":
+			return &RowLevelTTL{}
+		case "Schema":
+			return &Schema{}
+		case "SchemaChild":
+			return &SchemaChild{}
+		case "SchemaComment":
+			return &SchemaComment{}
+		case "SchemaParent":
+			return &SchemaParent{}
+		case "SecondaryIndex":
+			return &SecondaryIndex{}
+		case "SecondaryIndexPartial":
+			return &SecondaryIndexPartial{}
+		case "Sequence":
+			return &Sequence{}
+		case "SequenceOption":
+			return &SequenceOption{}
+		case "SequenceOwner":
+			return &SequenceOwner{}
+		case "Table":
+			return &Table{}
+		case "TableComment":
+			return &TableComment{}
+		case "TableData":
+			return &TableData{}
+		case "TableLocalityGlobal":
+			return &TableLocalityGlobal{}
+		case "TableLocalityPrimaryRegion":
+			return &TableLocalityPrimaryRegion{}
+		case "TableLocalityRegionalByRow":
+			return &TableLocalityRegionalByRow{}
+		case "TableLocalitySecondaryRegion":
+			return &TableLocalitySecondaryRegion{}
+		case "TablePartitioning":
+			return &TablePartitioning{}
+		case "TableSchemaLocked":
+			return &TableSchemaLocked{}
+		case "TableZoneConfig":
+			return &TableZoneConfig{}
+		case "TemporaryIndex":
+			return &TemporaryIndex{}
+		case "TypeComment":
+			return &TypeComment{}
+		case "UniqueWithoutIndexConstraint":
+			return &UniqueWithoutIndexConstraint{}
+		case "UniqueWithoutIndexConstraintUnvalidated":
+			return &UniqueWithoutIndexConstraintUnvalidated{}
+		case "UserPrivileges":
+			return &UserPrivileges{}
+		case "View":
+			return &View{}}
+	return nil
+}
+//
 // GetElementOneOfProtos returns all one of protos.
 func GetElementOneOfProtos() []interface{} {
 	return []interface{} {
@@ -0,0 +1,99 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scpb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneElement(t *testing.T) {
+	t.Run("column", func(t *testing.T) {
+		orig := &Column{
+			TableID:                           104,
+			ColumnID:                          1,
+			GeneratedAsIdentitySequenceOption: "START 1",
+		}
+		clone := CloneElement(orig).(*Column)
+		require.Equal(t, orig, clone)
+		clone.ColumnID = 2
+		clone.GeneratedAsIdentitySequenceOption = "START 2"
+		require.Equal(t, catid.ColumnID(1), orig.ColumnID)
+		require.Equal(t, "START 1", orig.GeneratedAsIdentitySequenceOption)
+	})
+	t.Run("primary_index", func(t *testing.T) {
+		orig := &PrimaryIndex{Index: Index{
+			TableID:  104,
+			IndexID:  1,
+			IsUnique: true,
+			Sharding: &catpb.ShardedDescriptor{IsSharded: true, ShardBuckets: 8},
+		}}
+		clone := CloneElement(orig).(*PrimaryIndex)
+		require.Equal(t, orig, clone)
+		clone.IndexID = 2
+		clone.Sharding.ShardBuckets = 16
+		require.Equal(t, catid.IndexID(1), orig.IndexID)
+		require.Equal(t, int32(8), orig.Sharding.ShardBuckets)
+	})
+}
+
+func TestElementsEqual(t *testing.T) {
+	newColumn := func() *Column {
+		return &Column{
+			TableID:                           104,
+			ColumnID:                          1,
+			GeneratedAsIdentitySequenceOption: "START 1",
+		}
+	}
+	require.True(t, ElementsEqual(newColumn(), newColumn()))
+	changed := newColumn()
+	changed.GeneratedAsIdentitySequenceOption = "START 2"
+	require.False(t, ElementsEqual(newColumn(), changed))
+	require.False(t, ElementsEqual(newColumn(), &ColumnName{TableID: 104, ColumnID: 1}))
+	require.False(t, ElementsEqual(newColumn(), nil))
+	require.True(t, ElementsEqual(nil, nil))
+}
+
+func TestElementIDs(t *testing.T) {
+	descID, subIDs := ElementIDs(&Column{TableID: 104, ColumnID: 2, PgAttributeNum: 5})
+	require.Equal(t, catid.DescID(104), descID)
+	require.Equal(t, []uint32{2}, subIDs)
+
+	descID, subIDs = ElementIDs(&PrimaryIndex{Index: Index{TableID: 104, IndexID: 3, ConstraintID: 4}})
+	require.Equal(t, catid.DescID(104), descID)
+	require.Equal(t, []uint32{3}, subIDs)
+
+	descID, subIDs = ElementIDs(&Namespace{DatabaseID: 100, SchemaID: 101, DescriptorID: 104})
+	require.Equal(t, catid.DescID(104), descID)
+	require.Nil(t, subIDs)
+
+	// Every element type has a descriptor ID.
+	require.NoError(t, ForEachElementType(func(e Element) error {
+		require.NotPanics(t, func() { ElementIDs(ElementByTypeName(reflect.TypeOf(e).Elem().Name())) })
+		return nil
+	}))
+}
+
+func TestElementByTypeName(t *testing.T) {
+	require.NoError(t, ForEachElementType(func(e Element) error {
+		typ := reflect.TypeOf(e)
+		got := ElementByTypeName(typ.Elem().Name())
+		require.NotNilf(t, got, "element type %s", typ)
+		require.IsType(t, e, got)
+		require.Equal(t, reflect.New(typ.Elem()).Interface(), got)
+		return nil
+	}))
+	require.Nil(t, ElementByTypeName("NotAnElement"))
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package screl
+
+import "github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+
+// ElementDiffKind classifies an ElementDiff.
+type ElementDiffKind int
+
+const (
+	// ElementAdded indicates that an element is only present in the second
+	// collection.
+	ElementAdded ElementDiffKind = iota
+	// ElementRemoved indicates that an element is only present in the first
+	// collection.
+	ElementRemoved
+	// ElementStatusChanged indicates that an element is present in both
+	// collections, but with a different current or target status.
+	ElementStatusChanged
+)
+
+// ElementDiff describes a difference between two element collections for a
+// single element. Statuses which don't apply to the kind of difference, such
+// as the Before statuses of an added element, are left unset.
+type ElementDiff struct {
+	Kind          ElementDiffKind
+	Element       scpb.Element
+	BeforeCurrent scpb.Status
+	BeforeTarget  scpb.TargetStatus
+	AfterCurrent  scpb.Status
+	AfterTarget   scpb.TargetStatus
+}
+
+// DiffElements compares the (current status, target status, element) tuples
+// in `a` and `b`. Elements are matched by EqualElementKeys. Removed and
+// status-changed elements are reported first, in the order of `a`, followed
+// by added elements, in the order of `b`. Matching is quadratic in the size
+// of the collections, which is fine for the sizes found in tests.
+func DiffElements(a, b scpb.ElementCollectionGetter) (diffs []ElementDiff) {
+	var sizeA, sizeB int
+	if a != nil {
+		sizeA = a.Size()
+	}
+	if b != nil {
+		sizeB = b.Size()
+	}
+	matched := make([]bool, sizeB)
+	for i := 0; i < sizeA; i++ {
+		beforeCurrent, beforeTarget, e := a.Get(i)
+		j := 0
+		for ; j < sizeB; j++ {
+			if _, _, other := b.Get(j); !matched[j] && EqualElementKeys(e, other) {
+				break
+			}
+		}
+		if j == sizeB {
+			diffs = append(diffs, ElementDiff{
+				Kind:          ElementRemoved,
+				Element:       e,
+				BeforeCurrent: beforeCurrent,
+				BeforeTarget:  beforeTarget,
+			})
+			continue
+		}
+		matched[j] = true
+		afterCurrent, afterTarget, _ := b.Get(j)
+		if beforeCurrent != afterCurrent || beforeTarget != afterTarget {
+			diffs = append(diffs, ElementDiff{
+				Kind:          ElementStatusChanged,
+				Element:       e,
+				BeforeCurrent: beforeCurrent,
+				BeforeTarget:  beforeTarget,
+				AfterCurrent:  afterCurrent,
+				AfterTarget:   afterTarget,
+			})
+		}
+	}
+	for j := 0; j < sizeB; j++ {
+		if matched[j] {
+			continue
+		}
+		afterCurrent, afterTarget, e := b.Get(j)
+		diffs = append(diffs, ElementDiff{
+			Kind:         ElementAdded,
+			Element:      e,
+			AfterCurrent: afterCurrent,
+			AfterTarget:  afterTarget,
+		})
+	}
+	return diffs
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package screl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffElements(t *testing.T) {
+	before := testGetter{
+		{scpb.Status_PUBLIC, scpb.InvalidTarget, &scpb.Table{TableID: 104}},
+		{scpb.Status_PUBLIC, scpb.ToPublic, &scpb.Column{TableID: 104, ColumnID: 1}},
+		{scpb.Status_PUBLIC, scpb.ToPublic, &scpb.Column{TableID: 104, ColumnID: 2}},
+	}
+	after := testGetter{
+		{scpb.Status_PUBLIC, scpb.InvalidTarget, &scpb.Table{TableID: 104}},
+		{scpb.Status_PUBLIC, scpb.ToPublic, &scpb.Column{TableID: 104, ColumnID: 1}},
+		{scpb.Status_PUBLIC, scpb.ToAbsent, &scpb.Column{TableID: 104, ColumnID: 2}},
+	}
+
+	require.Empty(t, DiffElements(before, before))
+	require.Equal(t, []ElementDiff{{
+		Kind:          ElementStatusChanged,
+		Element:       before[2].element,
+		BeforeCurrent: scpb.Status_PUBLIC,
+		BeforeTarget:  scpb.ToPublic,
+		AfterCurrent:  scpb.Status_PUBLIC,
+		AfterTarget:   scpb.ToAbsent,
+	}}, DiffElements(before, after))
+
+	// Elements missing from either collection are reported as added or
+	// removed.
+	require.Equal(t, []ElementDiff{{
+		Kind:          ElementRemoved,
+		Element:       before[0].element,
+		BeforeCurrent: scpb.Status_PUBLIC,
+		BeforeTarget:  scpb.InvalidTarget,
+	}}, DiffElements(before[:2], after[1:2]))
+	require.Equal(t, []ElementDiff{{
+		Kind:         ElementAdded,
+		Element:      after[2].element,
+		AfterCurrent: scpb.Status_PUBLIC,
+		AfterTarget:  scpb.ToAbsent,
+	}}, DiffElements(nil, after[2:]))
+}
@@ -11,6 +11,8 @@
 package screl
 
 import (
+	"reflect"
+
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
@@ -93,6 +95,59 @@ func ContainsDescID(haystack scpb.Element, needle catid.DescID) (contains bool)
 	return contains
 }
 
+// ForEachElementForDescriptor iterates over the elements in `g` which belong
+// to the descriptor with the given ID, that is, whose DescID attribute is
+// descID. Elements which merely reference descID, such as a foreign key
+// constraint on another table, are skipped.
+func ForEachElementForDescriptor(
+	g scpb.ElementCollectionGetter,
+	descID catid.DescID,
+	fn func(current scpb.Status, target scpb.TargetStatus, e scpb.Element),
+) {
+	if g == nil {
+		return
+	}
+	for i, n := 0, g.Size(); i < n; i++ {
+		current, target, e := g.Get(i)
+		if GetDescID(e) == descID {
+			fn(current, target, e)
+		}
+	}
+}
+
+// CollectTypeReferences returns the IDs, in ascending order, of the types
+// referenced by the elements in `g` which belong to the descriptor with the
+// given ID. This includes the type closures of column and composite type
+// attribute types, the types used by expressions such as column defaults and
+// check constraints, and the types used by view and function bodies.
+func CollectTypeReferences(g scpb.ElementCollectionGetter, descID catid.DescID) []catid.DescID {
+	var ids catalog.DescriptorIDSet
+	addAll := func(typeIDs []catid.DescID) {
+		for _, id := range typeIDs {
+			ids.Add(id)
+		}
+	}
+	ForEachElementForDescriptor(g, descID, func(
+		_ scpb.Status, _ scpb.TargetStatus, e scpb.Element,
+	) {
+		switch te := e.(type) {
+		case *scpb.View:
+			addAll(te.UsesTypeIDs)
+		case *scpb.FunctionBody:
+			addAll(te.UsesTypeIDs)
+		}
+		_ = walk(reflect.TypeOf((*scpb.TypeT)(nil)), e, func(i interface{}) error {
+			addAll(i.(*scpb.TypeT).ClosedTypeIDs)
+			return nil
+		})
+		_ = walk(reflect.TypeOf((*scpb.Expression)(nil)), e, func(i interface{}) error {
+			addAll(i.(*scpb.Expression).UsesTypeIDs)
+			return nil
+		})
+	})
+	return ids.Ordered()
+}
+
 // VersionSupportsElementUse checks if an element may be used at a given version.
 func VersionSupportsElementUse(el scpb.Element, version clusterversion.ClusterVersion) bool {
 	switch el.(type) {
@@ -114,3 +114,85 @@ func TestAllDescIDsAndContainsDescID(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectTypeReferences(t *testing.T) {
+	g := makeTestGetter(
+		&scpb.Column{TableID: 104, ColumnID: 1},
+		&scpb.ColumnType{
+			TableID:  104,
+			ColumnID: 1,
+			TypeT:    scpb.TypeT{Type: types.Int},
+		},
+		// The default expression of the column references the type 106, and
+		// its array alias 107.
+		&scpb.ColumnDefaultExpression{
+			TableID:  104,
+			ColumnID: 1,
+			Expression: scpb.Expression{
+				Expr:            "foo",
+				UsesTypeIDs:     []catid.DescID{107, 106},
+				UsesSequenceIDs: []catid.DescID{108},
+			},
+		},
+		// This column type belongs to table 105.
+		&scpb.ColumnType{
+			TableID:  105,
+			ColumnID: 1,
+			TypeT: scpb.TypeT{
+				Type:          types.Any,
+				ClosedTypeIDs: []catid.DescID{109},
+			},
+		},
+	)
+	require.Equal(t, []catid.DescID{106, 107}, CollectTypeReferences(g, 104))
+	require.Equal(t, []catid.DescID{109}, CollectTypeReferences(g, 105))
+	require.Empty(t, CollectTypeReferences(g, 106))
+}
+
+// testGetter is a trivial scpb.ElementCollectionGetter.
+type testGetter []struct {
+	current scpb.Status
+	target  scpb.TargetStatus
+	element scpb.Element
+}
+
+var _ scpb.ElementCollectionGetter = testGetter(nil)
+
+// makeTestGetter returns a testGetter of elements which are ABSENT and
+// targeting PUBLIC.
+func makeTestGetter(elements ...scpb.Element) testGetter {
+	g := make(testGetter, len(elements))
+	for i, e := range elements {
+		g[i].current, g[i].target, g[i].element = scpb.Status_ABSENT, scpb.ToPublic, e
+	}
+	return g
+}
+
+// Get implements scpb.ElementCollectionGetter.
+func (t testGetter) Get(index int) (scpb.Status, scpb.TargetStatus, scpb.Element) {
+	return t[index].current, t[index].target, t[index].element
+}
+
+// Size implements scpb.ElementCollectionGetter.
+func (t testGetter) Size() int {
+	return len(t)
+}
+
+func TestForEachElementForDescriptor(t *testing.T) {
+	g := makeTestGetter(
+		&scpb.Column{TableID: 104, ColumnID: 1},
+		&scpb.Column{TableID: 105, ColumnID: 1},
+		&scpb.PrimaryIndex{Index: scpb.Index{TableID: 104, IndexID: 1}},
+		&scpb.PrimaryIndex{Index: scpb.Index{TableID: 105, IndexID: 1}},
+		// This constraint references table 104 but belongs to table 105.
+		&scpb.ForeignKeyConstraint{TableID: 105, ConstraintID: 2, ReferencedTableID: 104},
+		&scpb.ColumnName{TableID: 104, ColumnID: 1, Name: "a"},
+	)
+	var visited []scpb.Element
+	ForEachElementForDescriptor(g, 104, func(
+		_ scpb.Status, _ scpb.TargetStatus, e scpb.Element,
+	) {
+		visited = append(visited, e)
+	})
+	require.Equal(t, []scpb.Element{g[0].element, g[2].element, g[5].element}, visited)
+}
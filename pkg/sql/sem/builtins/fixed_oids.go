@@ -2609,6 +2609,10 @@ var builtinOidsArray = []string{
 	2641: `crdb_internal.clear_table_stats_cache() -> void`,
 	2642: `crdb_internal.get_fully_qualified_table_name(table_descriptor_id: int) -> string`,
 	2643: `crdb_internal.type_is_indexable(oid: oid) -> bool`,
+	2644: `crdb_internal.changefeed_checkpoint_now(job_id: int) -> decimal`,
+	2645: `crdb_internal.changefeed_replay_span(job_id: int, start_key: bytes, end_key: bytes) -> bool`,
+	2646: `crdb_internal.changefeed_sinks() -> tuple{int AS job_id, string AS sink_type, timestamptz AS last_emit_ts, string AS last_error}`,
+	2647: `crdb_internal.changefeed_skip_backfill(job_id: int, target: string) -> bool`,
 }
 
 var builtinOidsBySignature map[string]oid.Oid
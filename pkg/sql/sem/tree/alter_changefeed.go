@@ -60,12 +60,21 @@ var _ AlterChangefeedCmd = &AlterChangefeedUnsetOptions{}
 type AlterChangefeedAddTarget struct {
 	Targets ChangefeedTargets
 	Options KVOptions
+	// Select is the changefeed expression of an ADD (SELECT ...) command, whose
+	// single target is the table it selects from.
+	Select *SelectClause
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AlterChangefeedAddTarget) Format(ctx *FmtCtx) {
 	ctx.WriteString(" ADD ")
-	ctx.FormatNode(&node.Targets)
+	if node.Select != nil {
+		ctx.WriteString("(")
+		ctx.FormatNode(node.Select)
+		ctx.WriteString(")")
+	} else {
+		ctx.FormatNode(&node.Targets)
+	}
 	if node.Options != nil {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)